@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/jessevdk/go-flags"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+	"github.com/dsoprea/go-iso-bmf/type"
+)
+
+type parameters struct {
+	InputFilepath  string `short:"i" long:"input-filepath" required:"true" description:"Input file-path"`
+	OutputFilepath string `short:"o" long:"output-filepath" required:"true" description:"Output file-path"`
+	IsVerbose      bool   `short:"v" long:"verbose" description:"Print logging"`
+}
+
+var (
+	arguments = new(parameters)
+)
+
+func main() {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err := errRaw.(error)
+			log.PrintError(err)
+
+			os.Exit(-2)
+		}
+	}()
+
+	_, err := flags.Parse(arguments)
+	if err != nil {
+		os.Exit(-1)
+	}
+
+	if arguments.IsVerbose == true {
+		cla := log.NewConsoleLogAdapter()
+		log.AddAdapter("console", cla)
+
+		scp := log.NewStaticConfigurationProvider()
+		scp.SetLevelName(log.LevelNameDebug)
+
+		log.LoadConfiguration(scp)
+	}
+
+	f, err := os.Open(arguments.InputFilepath)
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	s, err := f.Stat()
+	log.PanicIf(err)
+
+	size := s.Size()
+
+	resource, err := bmfcommon.NewResource(f, size)
+	log.PanicIf(err)
+
+	g, err := os.Create(arguments.OutputFilepath)
+	log.PanicIf(err)
+
+	defer g.Close()
+
+	err = bmftype.FastStart(resource, g)
+	if err == bmftype.ErrAlreadyFastStart {
+		g.Close()
+		os.Remove(arguments.OutputFilepath)
+
+		fmt.Printf("The file is already fast-start. Nothing was written.\n")
+		os.Exit(1)
+	}
+
+	log.PanicIf(err)
+}
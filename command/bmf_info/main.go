@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 
@@ -13,6 +14,8 @@ import (
 
 type parameters struct {
 	Filepath  string `short:"f" long:"filepath" required:"true" description:"File-path of image"`
+	Select    string `short:"s" long:"select" description:"Only print the boxes matching the given path expression (e.g. 'moov/trak[hdlr=vide]')"`
+	Format    string `long:"format" default:"text" choice:"text" choice:"json" description:"Output format"`
 	IsVerbose bool   `short:"v" long:"verbose" description:"Print logging"`
 }
 
@@ -56,6 +59,45 @@ func main() {
 	file, err := bmfcommon.NewResource(f, size)
 	log.PanicIf(err)
 
+	if arguments.Format == "json" {
+		var exported interface{}
+
+		if arguments.Select != "" {
+			boxes, err := file.FindAll(arguments.Select)
+			log.PanicIf(err)
+
+			exportedBoxes := make([]bmfcommon.ExportedBox, len(boxes))
+			for i, cb := range boxes {
+				exportedBoxes[i], err = bmfcommon.ExportBox(cb)
+				log.PanicIf(err)
+			}
+
+			exported = exportedBoxes
+		} else {
+			exported, err = bmfcommon.ExportResource(file)
+			log.PanicIf(err)
+		}
+
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+
+		err = e.Encode(exported)
+		log.PanicIf(err)
+
+		return
+	}
+
+	if arguments.Select != "" {
+		boxes, err := file.FindAll(arguments.Select)
+		log.PanicIf(err)
+
+		for _, cb := range boxes {
+			bmfcommon.Dump(cb)
+		}
+
+		return
+	}
+
 	fmt.Printf("Tree:\n")
 	fmt.Printf("\n")
 
@@ -65,13 +107,13 @@ func main() {
 	fmt.Printf("Item extents:\n")
 	fmt.Printf("\n")
 
-	fbi := file.Index()
-
-	ilocCommonBox, found := fbi[bmfcommon.IndexedBoxEntry{"meta.iloc", 0}]
-	if found == false {
+	ilocCommonBox, err := file.Find("meta/iloc")
+	if err == bmfcommon.ErrNoBoxesFound {
 		fmt.Printf("No ILOC box found. No extents will be written.\n")
 		fmt.Printf("\n")
 	} else {
+		log.PanicIf(err)
+
 		iloc := ilocCommonBox.(*bmftype.IlocBox)
 
 		err = iloc.Dump()
@@ -81,7 +123,7 @@ func main() {
 	fmt.Printf("Index:\n")
 	fmt.Printf("\n")
 
-	fbi.Dump()
+	file.Index().Dump()
 
 	fmt.Printf("\n")
 }
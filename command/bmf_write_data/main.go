@@ -58,13 +58,13 @@ func main() {
 	file, err := bmfcommon.NewResource(f, size)
 	log.PanicIf(err)
 
-	fbi := file.Index()
-
-	ilocCommonBox, found := fbi[bmfcommon.IndexedBoxEntry{"meta.iloc", 0}]
-	if found == false {
+	ilocCommonBox, err := file.Find("meta/iloc")
+	if err == bmfcommon.ErrNoBoxesFound {
 		log.Panicf("Could not find ILOC in index.")
 	}
 
+	log.PanicIf(err)
+
 	iloc := ilocCommonBox.(*bmftype.IlocBox)
 
 	tempPath, err := ioutil.TempDir("", "")
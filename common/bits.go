@@ -0,0 +1,390 @@
+package bmfcommon
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+const (
+	// maxExpGolombLeadingZeros is the longest prefix of an Exp-Golomb code
+	// whose value still fits in 64 bits.
+	maxExpGolombLeadingZeros = 63
+)
+
+var (
+	// ErrBitsExhausted indicates that a bit-packed structure is shorter than
+	// its fields.
+	ErrBitsExhausted = errors.New("not enough bits")
+
+	// ErrNotByteAligned indicates that a byte-oriented operation was attempted
+	// in the middle of a byte.
+	ErrNotByteAligned = errors.New("not byte-aligned")
+)
+
+// BitReader reads big-endian (most-significant bit first) bit-fields from a
+// byte slice. This is the packing used by the ISO, ITU, and AOM codec
+// configurations. No method consumes anything if it fails.
+type BitReader struct {
+	data     []byte
+	position int
+}
+
+// NewBitReader returns a new BitReader that starts at the first bit of the
+// data.
+func NewBitReader(data []byte) *BitReader {
+	return &BitReader{
+		data: data,
+	}
+}
+
+// Position returns the number of bits that were consumed.
+func (br *BitReader) Position() int {
+	return br.position
+}
+
+// Remaining returns the number of unread bits.
+func (br *BitReader) Remaining() int {
+	return len(br.data)*8 - br.position
+}
+
+// IsByteAligned returns true if the next bit is the first bit of a byte.
+func (br *BitReader) IsByteAligned() bool {
+	return br.position%8 == 0
+}
+
+// ReadBits returns the next n (at most 64) bits as an unsigned integer.
+func (br *BitReader) ReadBits(n int) (value uint64, err error) {
+	if n < 0 || n > 64 {
+		return 0, fmt.Errorf("bit count not valid: (%d)", n)
+	} else if n > br.Remaining() {
+		return 0, ErrBitsExhausted
+	}
+
+	for n > 0 {
+		current := br.data[br.position/8]
+		available := 8 - br.position%8
+
+		take := available
+		if take > n {
+			take = n
+		}
+
+		chunk := current >> uint(available-take) & (1<<uint(take) - 1)
+		value = value<<uint(take) | uint64(chunk)
+
+		br.position += take
+		n -= take
+	}
+
+	return value, nil
+}
+
+// ReadFlag reads one bit.
+func (br *BitReader) ReadFlag() (flag bool, err error) {
+	value, err := br.ReadBits(1)
+	if err != nil {
+		return false, err
+	}
+
+	return value == 1, nil
+}
+
+// Skip consumes n bits.
+func (br *BitReader) Skip(n int) error {
+	if n < 0 {
+		return fmt.Errorf("bit count not valid: (%d)", n)
+	} else if n > br.Remaining() {
+		return ErrBitsExhausted
+	}
+
+	br.position += n
+
+	return nil
+}
+
+// SkipToByteBoundary consumes the rest of the current byte, if any.
+func (br *BitReader) SkipToByteBoundary() {
+	if br.IsByteAligned() == false {
+		br.position += 8 - br.position%8
+	}
+}
+
+// ReadBytes returns the next n bytes. The reader must be byte-aligned. The
+// returned slice shares the data that the reader was created with.
+func (br *BitReader) ReadBytes(n int) (data []byte, err error) {
+	if br.IsByteAligned() != true {
+		return nil, ErrNotByteAligned
+	} else if n < 0 {
+		return nil, fmt.Errorf("byte count not valid: (%d)", n)
+	} else if n*8 > br.Remaining() {
+		return nil, ErrBitsExhausted
+	}
+
+	start := br.position / 8
+	br.position += n * 8
+
+	return br.data[start : start+n], nil
+}
+
+// ReadUe reads an unsigned Exp-Golomb code (ue(v) in H.264 and H.265).
+func (br *BitReader) ReadUe() (value uint64, err error) {
+	start := br.position
+
+	leadingZeros := 0
+	for {
+		if br.Remaining() == 0 {
+			br.position = start
+			return 0, ErrBitsExhausted
+		}
+
+		bit := br.data[br.position/8] >> uint(7-br.position%8) & 1
+		br.position++
+
+		if bit == 1 {
+			break
+		}
+
+		leadingZeros++
+
+		if leadingZeros > maxExpGolombLeadingZeros {
+			br.position = start
+			return 0, fmt.Errorf("exp-golomb code too long: (%d) leading zeros", leadingZeros)
+		}
+	}
+
+	suffix, err := br.ReadBits(leadingZeros)
+	if err != nil {
+		br.position = start
+		return 0, err
+	}
+
+	return (1<<uint(leadingZeros) - 1) + suffix, nil
+}
+
+// ReadSe reads a signed Exp-Golomb code (se(v) in H.264 and H.265).
+func (br *BitReader) ReadSe() (value int64, err error) {
+	k, err := br.ReadUe()
+	if err != nil {
+		return 0, err
+	}
+
+	// The codes alternate between positive and negative: 1, -1, 2, -2, ...
+	if k&1 == 1 {
+		return int64(k/2 + 1), nil
+	}
+
+	return -int64(k / 2), nil
+}
+
+// MoreRbspData returns true if there is data before the RBSP trailing-bits
+// (more_rbsp_data() in H.264 and H.265). The trailing-bits are a one followed
+// by zeros until the end.
+func (br *BitReader) MoreRbspData() bool {
+	// Find the last one bit.
+	for i := len(br.data) - 1; i >= 0; i-- {
+		if br.data[i] == 0 {
+			continue
+		}
+
+		lastOne := i*8 + 7 - bits.TrailingZeros8(br.data[i])
+		return br.position < lastOne
+	}
+
+	return false
+}
+
+// BitWriter builds big-endian (most-significant bit first) bit-fields. It is
+// the counterpart of BitReader.
+type BitWriter struct {
+	data []byte
+
+	// length is the number of bits that were written.
+	length int
+}
+
+// NewBitWriter returns a new, empty BitWriter.
+func NewBitWriter() *BitWriter {
+	return &BitWriter{
+		data: make([]byte, 0),
+	}
+}
+
+// Len returns the number of bits that were written.
+func (bw *BitWriter) Len() int {
+	return bw.length
+}
+
+// IsByteAligned returns true if the next bit will be the first bit of a byte.
+func (bw *BitWriter) IsByteAligned() bool {
+	return bw.length%8 == 0
+}
+
+// Bytes returns the written data. A partial last byte is padded with zeros.
+func (bw *BitWriter) Bytes() []byte {
+	return bw.data
+}
+
+// WriteBits writes the lower n (at most 64) bits of the value. The value must
+// fit in n bits.
+func (bw *BitWriter) WriteBits(value uint64, n int) error {
+	if n < 0 || n > 64 {
+		return fmt.Errorf("bit count not valid: (%d)", n)
+	} else if n < 64 && value>>uint(n) != 0 {
+		return fmt.Errorf("value (%d) does not fit in (%d) bits", value, n)
+	}
+
+	for n > 0 {
+		if bw.length%8 == 0 {
+			bw.data = append(bw.data, 0)
+		}
+
+		available := 8 - bw.length%8
+
+		take := available
+		if take > n {
+			take = n
+		}
+
+		chunk := byte(value>>uint(n-take)) & (1<<uint(take) - 1)
+		bw.data[len(bw.data)-1] |= chunk << uint(available-take)
+
+		bw.length += take
+		n -= take
+	}
+
+	return nil
+}
+
+// WriteFlag writes one bit.
+func (bw *BitWriter) WriteFlag(flag bool) {
+	value := uint64(0)
+	if flag == true {
+		value = 1
+	}
+
+	// One bit always fits.
+	bw.WriteBits(value, 1)
+}
+
+// WriteBytes writes the data. The writer must be byte-aligned.
+func (bw *BitWriter) WriteBytes(data []byte) error {
+	if bw.IsByteAligned() != true {
+		return ErrNotByteAligned
+	}
+
+	bw.data = append(bw.data, data...)
+	bw.length += len(data) * 8
+
+	return nil
+}
+
+// PadToByteBoundary writes zeros until the writer is byte-aligned.
+func (bw *BitWriter) PadToByteBoundary() {
+	if bw.IsByteAligned() == false {
+		// The padding is already zero in the last byte.
+		bw.length += 8 - bw.length%8
+	}
+}
+
+// WriteUe writes an unsigned Exp-Golomb code (ue(v) in H.264 and H.265).
+func (bw *BitWriter) WriteUe(value uint64) error {
+	if value == math.MaxUint64 {
+		return fmt.Errorf("value too large for exp-golomb code: (%d)", value)
+	}
+
+	codeNum := value + 1
+	length := bits.Len64(codeNum)
+
+	err := bw.WriteBits(0, length-1)
+	if err != nil {
+		return err
+	}
+
+	return bw.WriteBits(codeNum, length)
+}
+
+// WriteSe writes a signed Exp-Golomb code (se(v) in H.264 and H.265).
+func (bw *BitWriter) WriteSe(value int64) error {
+	if value == math.MinInt64 {
+		return fmt.Errorf("value too small for exp-golomb code: (%d)", value)
+	}
+
+	var k uint64
+	if value > 0 {
+		k = uint64(value)*2 - 1
+	} else {
+		k = uint64(-value) * 2
+	}
+
+	return bw.WriteUe(k)
+}
+
+// WriteRbspTrailingBits writes a one followed by zeros until the writer is
+// byte-aligned (rbsp_trailing_bits() in H.264 and H.265).
+func (bw *BitWriter) WriteRbspTrailingBits() {
+	bw.WriteFlag(true)
+	bw.PadToByteBoundary()
+}
+
+// RemoveEmulationPrevention returns the RBSP of a NAL unit payload: every
+// emulation-prevention byte (the 0x03 in 0x000003) is removed. The data is
+// returned as-is if there are none.
+func RemoveEmulationPrevention(data []byte) []byte {
+	var rbsp []byte
+	zeros := 0
+
+	for i, b := range data {
+		if zeros >= 2 && b == 0x03 {
+			if rbsp == nil {
+				rbsp = make([]byte, i, len(data))
+				copy(rbsp, data[:i])
+			}
+
+			zeros = 0
+			continue
+		}
+
+		if rbsp != nil {
+			rbsp = append(rbsp, b)
+		}
+
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+	}
+
+	if rbsp == nil {
+		return data
+	}
+
+	return rbsp
+}
+
+// AddEmulationPrevention returns the NAL unit payload for an RBSP: an
+// emulation-prevention byte is inserted wherever two zeros are followed by a
+// byte that is three or less. It is the inverse of RemoveEmulationPrevention.
+func AddEmulationPrevention(rbsp []byte) []byte {
+	data := make([]byte, 0, len(rbsp))
+	zeros := 0
+
+	for _, b := range rbsp {
+		if zeros >= 2 && b <= 0x03 {
+			data = append(data, 0x03)
+			zeros = 0
+		}
+
+		data = append(data, b)
+
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,533 @@
+package bmfcommon
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+)
+
+func TestBitReader_ReadBits(t *testing.T) {
+	br := NewBitReader([]byte{0xa5, 0x3c, 0xff, 0x01})
+
+	cases := []struct {
+		n        int
+		expected uint64
+	}{
+		{1, 1},
+		{3, 0x2},
+		{4, 0x5},
+		{0, 0},
+		{6, 0x0f},
+		{12, 0x3fc},
+		{6, 0x01},
+	}
+
+	for i, c := range cases {
+		value, err := br.ReadBits(c.n)
+		log.PanicIf(err)
+
+		if value != c.expected {
+			t.Fatalf("Read (%d) of (%d) bits not correct: (0x%x) != (0x%x)", i, c.n, value, c.expected)
+		}
+	}
+
+	if br.Remaining() != 0 {
+		t.Fatalf("Remaining() not correct: (%d)", br.Remaining())
+	} else if br.Position() != 32 {
+		t.Fatalf("Position() not correct: (%d)", br.Position())
+	}
+}
+
+func TestBitReader_ReadBits_64(t *testing.T) {
+	data, _ := hex.DecodeString("f123456789abcdef01")
+
+	br := NewBitReader(data)
+
+	_, err := br.ReadBits(4)
+	log.PanicIf(err)
+
+	value, err := br.ReadBits(64)
+	log.PanicIf(err)
+
+	if value != 0x123456789abcdef0 {
+		t.Fatalf("Value not correct: (0x%x)", value)
+	}
+
+	_, err = br.ReadBits(65)
+	if err == nil {
+		t.Fatalf("Expected error for too many bits.")
+	}
+}
+
+func TestBitReader_ReadBits_Exhausted(t *testing.T) {
+	br := NewBitReader([]byte{0xff})
+
+	_, err := br.ReadBits(3)
+	log.PanicIf(err)
+
+	_, err = br.ReadBits(6)
+	if err != ErrBitsExhausted {
+		t.Fatalf("Error not correct: [%v]", err)
+	}
+
+	// Nothing was consumed by the failed read.
+	if br.Position() != 3 {
+		t.Fatalf("Position() not correct after failure.")
+	}
+
+	value, err := br.ReadBits(5)
+	log.PanicIf(err)
+
+	if value != 0x1f {
+		t.Fatalf("Value not correct.")
+	}
+
+	_, err = br.ReadFlag()
+	if err != ErrBitsExhausted {
+		t.Fatalf("ReadFlag() error not correct: [%v]", err)
+	}
+
+	err = br.Skip(1)
+	if err != ErrBitsExhausted {
+		t.Fatalf("Skip() error not correct: [%v]", err)
+	}
+}
+
+func TestBitReader_ReadFlag(t *testing.T) {
+	br := NewBitReader([]byte{0x80})
+
+	flag, err := br.ReadFlag()
+	log.PanicIf(err)
+
+	if flag != true {
+		t.Fatalf("First flag not correct.")
+	}
+
+	flag, err = br.ReadFlag()
+	log.PanicIf(err)
+
+	if flag != false {
+		t.Fatalf("Second flag not correct.")
+	}
+}
+
+func TestBitReader_Alignment(t *testing.T) {
+	br := NewBitReader([]byte{0xf0, 0x12, 0x34, 0x56})
+
+	if br.IsByteAligned() != true {
+		t.Fatalf("Should be aligned at the start.")
+	}
+
+	err := br.Skip(4)
+	log.PanicIf(err)
+
+	if br.IsByteAligned() != false {
+		t.Fatalf("Should not be aligned.")
+	}
+
+	_, err = br.ReadBytes(1)
+	if err != ErrNotByteAligned {
+		t.Fatalf("ReadBytes() error not correct: [%v]", err)
+	}
+
+	br.SkipToByteBoundary()
+
+	if br.IsByteAligned() != true || br.Position() != 8 {
+		t.Fatalf("SkipToByteBoundary() not correct.")
+	}
+
+	// Already aligned.
+	br.SkipToByteBoundary()
+
+	if br.Position() != 8 {
+		t.Fatalf("SkipToByteBoundary() should not move when aligned.")
+	}
+
+	data, err := br.ReadBytes(2)
+	log.PanicIf(err)
+
+	if bytes.Equal(data, []byte{0x12, 0x34}) != true {
+		t.Fatalf("ReadBytes() not correct.")
+	}
+
+	_, err = br.ReadBytes(2)
+	if err != ErrBitsExhausted {
+		t.Fatalf("ReadBytes() error not correct: [%v]", err)
+	}
+}
+
+func TestBitReader_ReadUe(t *testing.T) {
+	// 1, 010, 011, 00100, 00111, 0001000 (0, 1, 2, 3, 6, 7).
+	data := []byte{0xa6, 0x43, 0x88}
+
+	br := NewBitReader(data)
+
+	for _, expected := range []uint64{0, 1, 2, 3, 6, 7} {
+		value, err := br.ReadUe()
+		log.PanicIf(err)
+
+		if value != expected {
+			t.Fatalf("Value not correct: (%d) != (%d)", value, expected)
+		}
+	}
+}
+
+func TestBitReader_ReadUe_Invalid(t *testing.T) {
+	// The suffix is cut off.
+	br := NewBitReader([]byte{0x01})
+
+	_, err := br.ReadUe()
+	if err != ErrBitsExhausted {
+		t.Fatalf("Error not correct: [%v]", err)
+	} else if br.Position() != 0 {
+		t.Fatalf("Failed read should not consume.")
+	}
+
+	// There is no one bit.
+	br = NewBitReader([]byte{0x00})
+
+	_, err = br.ReadUe()
+	if err != ErrBitsExhausted {
+		t.Fatalf("Error not correct for missing terminator: [%v]", err)
+	}
+
+	// The prefix is longer than what fits in 64 bits.
+	br = NewBitReader(make([]byte, 20))
+
+	_, err = br.ReadUe()
+	if err == nil {
+		t.Fatalf("Expected error for long prefix.")
+	} else if br.Position() != 0 {
+		t.Fatalf("Failed read should not consume.")
+	}
+}
+
+func TestBitReader_ReadSe(t *testing.T) {
+	bw := NewBitWriter()
+
+	values := []int64{0, 1, -1, 2, -2, 100, -100, math.MaxInt64, math.MinInt64 + 1}
+	for _, value := range values {
+		err := bw.WriteSe(value)
+		log.PanicIf(err)
+	}
+
+	br := NewBitReader(bw.Bytes())
+
+	for _, expected := range values {
+		value, err := br.ReadSe()
+		log.PanicIf(err)
+
+		if value != expected {
+			t.Fatalf("Value not correct: (%d) != (%d)", value, expected)
+		}
+	}
+
+	// The codes for 1 and -1 are 010 and 011.
+	br = NewBitReader([]byte{0x4c})
+
+	value, err := br.ReadSe()
+	log.PanicIf(err)
+
+	if value != 1 {
+		t.Fatalf("Positive value not correct: (%d)", value)
+	}
+
+	value, err = br.ReadSe()
+	log.PanicIf(err)
+
+	if value != -1 {
+		t.Fatalf("Negative value not correct: (%d)", value)
+	}
+}
+
+func TestBitReader_MoreRbspData(t *testing.T) {
+	// Six bits of data followed by the trailing-bits.
+	br := NewBitReader([]byte{0xaa, 0x80})
+
+	err := br.Skip(7)
+	log.PanicIf(err)
+
+	if br.MoreRbspData() != true {
+		t.Fatalf("Should have more data.")
+	}
+
+	err = br.Skip(1)
+	log.PanicIf(err)
+
+	if br.MoreRbspData() != false {
+		t.Fatalf("Should not have more data.")
+	}
+
+	br = NewBitReader([]byte{0x00})
+
+	if br.MoreRbspData() != false {
+		t.Fatalf("Should not have data without trailing-bits.")
+	}
+}
+
+func TestBitWriter_WriteBits(t *testing.T) {
+	bw := NewBitWriter()
+
+	err := bw.WriteBits(1, 1)
+	log.PanicIf(err)
+
+	err = bw.WriteBits(0x2, 3)
+	log.PanicIf(err)
+
+	err = bw.WriteBits(0x5, 4)
+	log.PanicIf(err)
+
+	if bw.IsByteAligned() != true {
+		t.Fatalf("Should be aligned.")
+	}
+
+	err = bw.WriteBits(0x0f, 6)
+	log.PanicIf(err)
+
+	err = bw.WriteBits(0x3fc, 12)
+	log.PanicIf(err)
+
+	err = bw.WriteBits(0x01, 6)
+	log.PanicIf(err)
+
+	if bw.Len() != 32 {
+		t.Fatalf("Len() not correct: (%d)", bw.Len())
+	} else if bytes.Equal(bw.Bytes(), []byte{0xa5, 0x3c, 0xff, 0x01}) != true {
+		t.Fatalf("Bytes() not correct: %x", bw.Bytes())
+	}
+
+	err = bw.WriteBits(math.MaxUint64, 64)
+	log.PanicIf(err)
+
+	if bytes.Equal(bw.Bytes()[4:], bytes.Repeat([]byte{0xff}, 8)) != true {
+		t.Fatalf("64-bit value not correct: %x", bw.Bytes())
+	}
+}
+
+func TestBitWriter_WriteBits_Invalid(t *testing.T) {
+	bw := NewBitWriter()
+
+	err := bw.WriteBits(4, 2)
+	if err == nil {
+		t.Fatalf("Expected error for value that does not fit.")
+	}
+
+	err = bw.WriteBits(0, 65)
+	if err == nil {
+		t.Fatalf("Expected error for too many bits.")
+	}
+
+	if bw.Len() != 0 {
+		t.Fatalf("Failed writes should not write.")
+	}
+}
+
+func TestBitWriter_Alignment(t *testing.T) {
+	bw := NewBitWriter()
+
+	bw.WriteFlag(true)
+
+	err := bw.WriteBytes([]byte{0x12})
+	if err != ErrNotByteAligned {
+		t.Fatalf("WriteBytes() error not correct: [%v]", err)
+	}
+
+	bw.PadToByteBoundary()
+
+	err = bw.WriteBytes([]byte{0x12, 0x34})
+	log.PanicIf(err)
+
+	bw.WriteFlag(false)
+	bw.WriteFlag(true)
+
+	if bw.Len() != 26 {
+		t.Fatalf("Len() not correct: (%d)", bw.Len())
+	} else if bytes.Equal(bw.Bytes(), []byte{0x80, 0x12, 0x34, 0x40}) != true {
+		t.Fatalf("Bytes() not correct: %x", bw.Bytes())
+	}
+}
+
+func TestBitWriter_WriteUe(t *testing.T) {
+	bw := NewBitWriter()
+
+	for _, value := range []uint64{0, 1, 2, 3, 6, 7} {
+		err := bw.WriteUe(value)
+		log.PanicIf(err)
+	}
+
+	bw.PadToByteBoundary()
+
+	if bytes.Equal(bw.Bytes(), []byte{0xa6, 0x43, 0x88}) != true {
+		t.Fatalf("Bytes() not correct: %x", bw.Bytes())
+	}
+
+	// The largest value that can be encoded.
+	bw = NewBitWriter()
+
+	err := bw.WriteUe(math.MaxUint64 - 1)
+	log.PanicIf(err)
+
+	value, err := NewBitReader(bw.Bytes()).ReadUe()
+	log.PanicIf(err)
+
+	if value != math.MaxUint64-1 {
+		t.Fatalf("Largest value not correct: (%d)", value)
+	}
+
+	err = bw.WriteUe(math.MaxUint64)
+	if err == nil {
+		t.Fatalf("Expected error for value too large.")
+	}
+
+	err = bw.WriteSe(math.MinInt64)
+	if err == nil {
+		t.Fatalf("Expected error for value too small.")
+	}
+}
+
+func TestBitWriter_WriteRbspTrailingBits(t *testing.T) {
+	bw := NewBitWriter()
+
+	err := bw.WriteBits(0x5, 3)
+	log.PanicIf(err)
+
+	bw.WriteRbspTrailingBits()
+
+	if bytes.Equal(bw.Bytes(), []byte{0xb0}) != true {
+		t.Fatalf("Bytes() not correct: %x", bw.Bytes())
+	}
+
+	// When aligned, the trailing-bits are a whole byte.
+	bw.WriteRbspTrailingBits()
+
+	if bytes.Equal(bw.Bytes(), []byte{0xb0, 0x80}) != true {
+		t.Fatalf("Aligned trailing-bits not correct: %x", bw.Bytes())
+	}
+}
+
+func TestRemoveEmulationPrevention(t *testing.T) {
+	cases := []struct {
+		nal  string
+		rbsp string
+	}{
+		{"", ""},
+		{"6742c01e", "6742c01e"},
+		{"0000030100", "00000100"},
+		{"0000030000", "00000000"},
+		{"00000303", "000003"},
+		{"0000030000030000", "000000000000"},
+		{"000300000301", "0003000001"},
+	}
+
+	for _, c := range cases {
+		nal, _ := hex.DecodeString(c.nal)
+		expected, _ := hex.DecodeString(c.rbsp)
+
+		rbsp := RemoveEmulationPrevention(nal)
+		if bytes.Equal(rbsp, expected) != true {
+			t.Fatalf("RBSP for [%s] not correct: %x", c.nal, rbsp)
+		}
+
+		recovered := AddEmulationPrevention(expected)
+		if bytes.Equal(recovered, nal) != true {
+			t.Fatalf("NAL for [%s] not correct: %x", c.rbsp, recovered)
+		}
+	}
+}
+
+func TestRemoveEmulationPrevention_NoCopy(t *testing.T) {
+	data := []byte{0x67, 0x00, 0x00, 0x01}
+
+	if &RemoveEmulationPrevention(data)[0] != &data[0] {
+		t.Fatalf("Data without emulation-prevention should not be copied.")
+	}
+}
+
+func TestBitReader_SequenceParameterSet(t *testing.T) {
+	// An H.264 SPS (Baseline, level 3.0, 640x480) without the NAL header.
+	sps, _ := hex.DecodeString("42c01eda0280f640")
+
+	br := NewBitReader(RemoveEmulationPrevention(sps))
+
+	profileIdc, err := br.ReadBits(8)
+	log.PanicIf(err)
+
+	// constraint_set flags and reserved_zero_2bits
+	err = br.Skip(8)
+	log.PanicIf(err)
+
+	levelIdc, err := br.ReadBits(8)
+	log.PanicIf(err)
+
+	if profileIdc != 66 {
+		t.Fatalf("profile_idc not correct: (%d)", profileIdc)
+	} else if levelIdc != 30 {
+		t.Fatalf("level_idc not correct: (%d)", levelIdc)
+	}
+
+	fields := make([]uint64, 6)
+	for i := range fields {
+		fields[i], err = br.ReadUe()
+		log.PanicIf(err)
+
+		// gaps_in_frame_num_value_allowed_flag follows max_num_ref_frames.
+		if i == 3 {
+			err = br.Skip(1)
+			log.PanicIf(err)
+		}
+	}
+
+	// seq_parameter_set_id, log2_max_frame_num_minus4, pic_order_cnt_type,
+	// max_num_ref_frames, pic_width_in_mbs_minus1,
+	// pic_height_in_map_units_minus1
+	expected := []uint64{0, 0, 2, 1, 39, 29}
+	for i, value := range fields {
+		if value != expected[i] {
+			t.Fatalf("Field (%d) not correct: (%d) != (%d)", i, value, expected[i])
+		}
+	}
+
+	// frame_mbs_only_flag, direct_8x8_inference_flag, frame_cropping_flag,
+	// vui_parameters_present_flag
+	flags, err := br.ReadBits(4)
+	log.PanicIf(err)
+
+	if flags != 0xc {
+		t.Fatalf("Flags not correct: (0x%x)", flags)
+	} else if br.MoreRbspData() != false {
+		t.Fatalf("Should be at the trailing-bits.")
+	}
+
+	// Rebuilding it gives the same bytes.
+	bw := NewBitWriter()
+
+	err = bw.WriteBits(profileIdc, 8)
+	log.PanicIf(err)
+
+	err = bw.WriteBits(0xc0, 8)
+	log.PanicIf(err)
+
+	err = bw.WriteBits(levelIdc, 8)
+	log.PanicIf(err)
+
+	for i, value := range fields {
+		err = bw.WriteUe(value)
+		log.PanicIf(err)
+
+		if i == 3 {
+			bw.WriteFlag(false)
+		}
+	}
+
+	err = bw.WriteBits(flags, 4)
+	log.PanicIf(err)
+
+	bw.WriteRbspTrailingBits()
+
+	if bytes.Equal(AddEmulationPrevention(bw.Bytes()), sps) != true {
+		t.Fatalf("Rebuilt SPS not correct: %x", bw.Bytes())
+	}
+}
@@ -26,7 +26,18 @@ type Box struct {
 	headerSize int64
 	resource   *Resource
 
+	// extendedType is only set for "uuid" boxes.
+	extendedType ExtendedType
+
+	// implicitSize is true if the box was stored with a size of zero, meaning
+	// that it extends to the end of its container.
+	implicitSize bool
+
 	parent CommonBox
+
+	// childBoxSeriesOffset is the offset of the first child box relative to
+	// the start of the payload, or (-1) if this box does not have children.
+	childBoxSeriesOffset int64
 }
 
 // NewBox returns a new Box struct.
@@ -37,6 +48,8 @@ func NewBox(name string, start, size, headerSize int64, resource *Resource) Box
 		size:       size,
 		headerSize: headerSize,
 		resource:   resource,
+
+		childBoxSeriesOffset: -1,
 	}
 }
 
@@ -44,6 +57,12 @@ func NewBox(name string, start, size, headerSize int64, resource *Resource) Box
 func (box Box) InlineString() string {
 	parentName := GetParentBoxName(box.parent)
 
+	if box.name == UuidBoxName {
+		return fmt.Sprintf(
+			"NAME=[%s] EXTENDED-TYPE=[%s] PARENT=[%s] START=(0x%016x) SIZE=(%d)",
+			box.name, box.extendedType, parentName, box.start, box.size)
+	}
+
 	return fmt.Sprintf(
 		"NAME=[%s] PARENT=[%s] START=(0x%016x) SIZE=(%d)",
 		box.name, parentName, box.start, box.size)
@@ -54,6 +73,12 @@ func (box Box) Name() string {
 	return box.name
 }
 
+// ExtendedType returns the UUID that identifies the type of a "uuid" box. It
+// is zero for all other boxes.
+func (box Box) ExtendedType() ExtendedType {
+	return box.extendedType
+}
+
 // Start returns the box start offset.
 func (box Box) Start() int64 {
 	return box.start
@@ -64,7 +89,15 @@ func (box Box) Size() int64 {
 	return box.size
 }
 
-// HeaderSize is the effective size of the header.
+// IsSizeImplicit returns true if the box was stored with a size of zero, which
+// means that it extends to the end of its container. Size() returns the actual
+// size either way.
+func (box Box) IsSizeImplicit() bool {
+	return box.implicitSize
+}
+
+// HeaderSize is the effective size of the header. This includes the extended
+// type of "uuid" boxes.
 func (box Box) HeaderSize() int64 {
 	return box.headerSize
 }
@@ -74,6 +107,18 @@ func (box Box) Parent() CommonBox {
 	return box.parent
 }
 
+// setChildBoxSeriesOffset records where the children of this box begin. This
+// is called by the parser once the factory has returned the offset.
+func (box *Box) setChildBoxSeriesOffset(offset int64) {
+	box.childBoxSeriesOffset = offset
+}
+
+// getChildBoxSeriesOffset returns where the children of this box begin,
+// relative to the start of the payload, and whether the box has children.
+func (box Box) getChildBoxSeriesOffset() (offset int64, hasChildren bool) {
+	return box.childBoxSeriesOffset, box.childBoxSeriesOffset >= 0
+}
+
 // Index returns the FullBoxIndex for the resource. It contains all previously-
 // loaded boxes.
 func (box Box) Index() FullBoxIndex {
@@ -89,7 +134,10 @@ func (box Box) ReadBytesAt(offset int64, n int64) (b []byte, err error) {
 	}()
 
 	b, err = box.resource.readBytesAt(offset, n)
-	log.PanicIf(err)
+	if err != nil {
+		setTruncatedBox(err, box)
+		log.Panic(err)
+	}
 
 	return b, nil
 }
@@ -104,7 +152,10 @@ func (box Box) CopyBytesAt(offset int64, n int64, w io.Writer) (err error) {
 	}()
 
 	err = box.resource.copyBytesAt(offset, n, w)
-	log.PanicIf(err)
+	if err != nil {
+		setTruncatedBox(err, box)
+		log.Panic(err)
+	}
 
 	return nil
 }
@@ -151,7 +202,10 @@ func (box Box) Data() (data []byte, err error) {
 		box.start+headerSize,
 		box.size-headerSize)
 
-	log.PanicIf(err)
+	if err != nil {
+		setTruncatedBox(err, box)
+		log.Panic(err)
+	}
 
 	return data, nil
 }
@@ -278,3 +278,28 @@ func TestBoxes_Index(t *testing.T) {
 		t.Fatalf("Second indexed entry not correct: %v", index["box2"])
 	}
 }
+
+func TestBox_IsSizeImplicit(t *testing.T) {
+	box := Box{
+		implicitSize: true,
+	}
+
+	if box.IsSizeImplicit() != true {
+		t.Fatalf("IsSizeImplicit() not correct.")
+	}
+}
+
+func TestBox_ExtendedType(t *testing.T) {
+	et := ExtendedType{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+	box := NewBox(UuidBoxName, 0x1234, 0x5678, 24, nil)
+	box.extendedType = et
+
+	if box.ExtendedType() != et {
+		t.Fatalf("ExtendedType() not correct.")
+	}
+
+	if box.InlineString() != "NAME=[uuid] EXTENDED-TYPE=[01020304-0506-0708-090a-0b0c0d0e0f10] PARENT=[ROOT] START=(0x0000000000001234) SIZE=(22136)" {
+		t.Fatalf("InlineString() not correct: [%s]", box.InlineString())
+	}
+}
@@ -103,6 +103,10 @@ func (tb3 *testBox3) SetLoadedBoxIndex(boxes Boxes) {
 	tb3.LoadedBoxIndex = boxes.Index()
 }
 
+func (tb3 *testBox3) GetChildBoxes(name string) (boxes []CommonBox, err error) {
+	return tb3.GetChildBoxesOf(tb3, name)
+}
+
 type testBox3Factory struct {
 }
 
@@ -142,6 +146,14 @@ func pushUnknownBox(b *[]byte, data []byte) {
 	PushBox(b, "wxyz", data)
 }
 
+// pushImplicitSizeBox pushes a box with a size of zero, which extends to the
+// end of its container.
+func pushImplicitSizeBox(b *[]byte, name string, data []byte) {
+	PushBytes(b, uint32(0))
+	PushBytes(b, []byte(name))
+	PushBytes(b, data)
+}
+
 // testBox4 has no fields but does have children.
 type testBox4 struct {
 	// Box is the base box.
@@ -159,6 +171,53 @@ func newTestBox4(box Box, lbi LoadedBoxIndex) *testBox4 {
 	}
 }
 
+func (tb4 *testBox4) GetChildBoxes(name string) (boxes []CommonBox, err error) {
+	return tb4.GetChildBoxesOf(tb4, name)
+}
+
 func (*testBox4) InlineString() string {
 	return "TestBox4"
 }
+
+var (
+	testUuidBoxExtendedType = ExtendedType{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+)
+
+// testUuidBox is a "uuid" box that re-encodes its payload as a string.
+type testUuidBox struct {
+	testEncoderBox
+}
+
+type testUuidBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (testUuidBoxFactory) Name() string {
+	return UuidBoxName
+}
+
+// ExtendedType returns the extended type that the factory handles.
+func (testUuidBoxFactory) ExtendedType() ExtendedType {
+	return testUuidBoxExtendedType
+}
+
+// New returns a new value instance.
+func (testUuidBoxFactory) New(box Box) (cb CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := box.Data()
+	log.PanicIf(err)
+
+	tub := &testUuidBox{
+		testEncoderBox: testEncoderBox{
+			Box:   box,
+			value: string(data),
+		},
+	}
+
+	return tub, -1, nil
+}
@@ -0,0 +1,740 @@
+package bmfcommon
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+)
+
+// Descriptor tags from ISO 14496-1 and ISO 14496-14.
+const (
+	ObjectDescriptorTag        = 0x01
+	InitialObjectDescriptorTag = 0x02
+	EsDescriptorTag            = 0x03
+	DecoderConfigDescriptorTag = 0x04
+	DecoderSpecificInfoTag     = 0x05
+	SlConfigDescriptorTag      = 0x06
+	EsIdIncDescriptorTag       = 0x0e
+	EsIdRefDescriptorTag       = 0x0f
+	Mp4IodTag                  = 0x10
+	Mp4OdTag                   = 0x11
+)
+
+const (
+	// maxDescriptorSizeLength is the most bytes that the size of a descriptor
+	// may be encoded in.
+	maxDescriptorSizeLength = 4
+
+	// decoderConfigDescriptorSize is the size of the fixed fields of the
+	// DecoderConfigDescriptor.
+	decoderConfigDescriptorSize = 13
+)
+
+// Descriptor is a tag/length-encoded structure from ISO 14496-1 (MPEG-4
+// Systems), as found in "esds" and "iods" boxes.
+type Descriptor interface {
+	// DescriptorTag returns the tag that identifies the descriptor.
+	DescriptorTag() byte
+
+	// DescriptorSizeLength returns how many bytes the size should be encoded
+	// in. Zero uses the fewest possible.
+	DescriptorSizeLength() int
+
+	// EncodeBody returns the encoded descriptor without its tag and size.
+	EncodeBody() (data []byte, err error)
+}
+
+// DescriptorHeader has the tag of a descriptor and how many bytes its size was
+// encoded in. Encoders commonly pad the size to four bytes, so this is kept in
+// order to reproduce the original bytes.
+type DescriptorHeader struct {
+	Tag        byte
+	SizeLength int
+}
+
+// DescriptorSizeLength returns how many bytes the size should be encoded in.
+func (dh DescriptorHeader) DescriptorSizeLength() int {
+	return dh.SizeLength
+}
+
+// RawDescriptor is a descriptor whose tag is not modeled. Its body is kept as
+// read.
+type RawDescriptor struct {
+	DescriptorHeader
+
+	Data []byte
+}
+
+// DescriptorTag returns the tag that identifies the descriptor.
+func (rd *RawDescriptor) DescriptorTag() byte {
+	return rd.Tag
+}
+
+// EncodeBody returns the encoded descriptor without its tag and size.
+func (rd *RawDescriptor) EncodeBody() (data []byte, err error) {
+	return rd.Data, nil
+}
+
+// String returns a descriptive string.
+func (rd *RawDescriptor) String() string {
+	return fmt.Sprintf("RawDescriptor<TAG=(0x%02x) SIZE=(%d)>", rd.Tag, len(rd.Data))
+}
+
+// EsDescriptor is the ES_Descriptor, which describes an elementary stream.
+type EsDescriptor struct {
+	DescriptorHeader
+
+	EsId           uint16
+	StreamPriority uint8
+
+	StreamDependenceFlag bool
+	DependsOnEsId        uint16
+
+	UrlFlag bool
+	Url     string
+
+	OcrStreamFlag bool
+	OcrEsId       uint16
+
+	DecoderConfig *DecoderConfigDescriptor
+	SlConfig      *SlConfigDescriptor
+
+	// Descriptors are the other descriptors that were nested (e.g. IPI or
+	// language descriptors).
+	Descriptors []Descriptor
+}
+
+// DescriptorTag returns the tag that identifies the descriptor.
+func (ed *EsDescriptor) DescriptorTag() byte {
+	return EsDescriptorTag
+}
+
+// EncodeBody returns the encoded descriptor without its tag and size.
+func (ed *EsDescriptor) EncodeBody() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	flags := ed.StreamPriority & 0x1f
+
+	if ed.StreamDependenceFlag == true {
+		flags |= 0x80
+	}
+
+	if ed.UrlFlag == true {
+		flags |= 0x40
+	}
+
+	if ed.OcrStreamFlag == true {
+		flags |= 0x20
+	}
+
+	PushBytes(&data, ed.EsId)
+	PushBytes(&data, flags)
+
+	if ed.StreamDependenceFlag == true {
+		PushBytes(&data, ed.DependsOnEsId)
+	}
+
+	if ed.UrlFlag == true {
+		if len(ed.Url) > 255 {
+			log.Panicf("URL too long: (%d)", len(ed.Url))
+		}
+
+		PushBytes(&data, uint8(len(ed.Url)))
+		PushBytes(&data, []byte(ed.Url))
+	}
+
+	if ed.OcrStreamFlag == true {
+		PushBytes(&data, ed.OcrEsId)
+	}
+
+	descriptors := make([]Descriptor, 0, len(ed.Descriptors)+2)
+
+	if ed.DecoderConfig != nil {
+		descriptors = append(descriptors, ed.DecoderConfig)
+	}
+
+	if ed.SlConfig != nil {
+		descriptors = append(descriptors, ed.SlConfig)
+	}
+
+	descriptors = append(descriptors, ed.Descriptors...)
+
+	encoded, err := EncodeDescriptors(descriptors)
+	log.PanicIf(err)
+
+	PushBytes(&data, encoded)
+
+	return data, nil
+}
+
+// String returns a descriptive string.
+func (ed *EsDescriptor) String() string {
+	return fmt.Sprintf("EsDescriptor<ES-ID=(%d) PRIORITY=(%d) DESCRIPTORS=(%d)>", ed.EsId, ed.StreamPriority, len(ed.Descriptors))
+}
+
+// DecoderConfigDescriptor describes the decoder that an elementary stream
+// requires.
+type DecoderConfigDescriptor struct {
+	DescriptorHeader
+
+	ObjectTypeIndication uint8
+	StreamType           uint8
+	UpStream             bool
+	BufferSizeDb         uint32
+	MaxBitrate           uint32
+	AvgBitrate           uint32
+
+	DecoderSpecificInfo *DecoderSpecificInfo
+
+	// Descriptors are the other descriptors that were nested (e.g. profile-
+	// level indication-index descriptors).
+	Descriptors []Descriptor
+}
+
+// DescriptorTag returns the tag that identifies the descriptor.
+func (dcd *DecoderConfigDescriptor) DescriptorTag() byte {
+	return DecoderConfigDescriptorTag
+}
+
+// EncodeBody returns the encoded descriptor without its tag and size.
+func (dcd *DecoderConfigDescriptor) EncodeBody() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	// The reserved bit is one.
+	streamType := dcd.StreamType<<2 | 0x01
+
+	if dcd.UpStream == true {
+		streamType |= 0x02
+	}
+
+	PushBytes(&data, dcd.ObjectTypeIndication)
+	PushBytes(&data, uint32(streamType)<<24|dcd.BufferSizeDb&0x00ffffff)
+	PushBytes(&data, dcd.MaxBitrate)
+	PushBytes(&data, dcd.AvgBitrate)
+
+	descriptors := make([]Descriptor, 0, len(dcd.Descriptors)+1)
+
+	if dcd.DecoderSpecificInfo != nil {
+		descriptors = append(descriptors, dcd.DecoderSpecificInfo)
+	}
+
+	descriptors = append(descriptors, dcd.Descriptors...)
+
+	encoded, err := EncodeDescriptors(descriptors)
+	log.PanicIf(err)
+
+	PushBytes(&data, encoded)
+
+	return data, nil
+}
+
+// String returns a descriptive string.
+func (dcd *DecoderConfigDescriptor) String() string {
+	return fmt.Sprintf(
+		"DecoderConfigDescriptor<OTI=(0x%02x) STREAM-TYPE=(%d) MAX-BITRATE=(%d) AVG-BITRATE=(%d)>",
+		dcd.ObjectTypeIndication, dcd.StreamType, dcd.MaxBitrate, dcd.AvgBitrate)
+}
+
+// DecoderSpecificInfo has the opaque decoder configuration (e.g. the
+// AudioSpecificConfig for MPEG-4 audio).
+type DecoderSpecificInfo struct {
+	DescriptorHeader
+
+	Data []byte
+}
+
+// DescriptorTag returns the tag that identifies the descriptor.
+func (dsi *DecoderSpecificInfo) DescriptorTag() byte {
+	return DecoderSpecificInfoTag
+}
+
+// EncodeBody returns the encoded descriptor without its tag and size.
+func (dsi *DecoderSpecificInfo) EncodeBody() (data []byte, err error) {
+	return dsi.Data, nil
+}
+
+// String returns a descriptive string.
+func (dsi *DecoderSpecificInfo) String() string {
+	return fmt.Sprintf("DecoderSpecificInfo<SIZE=(%d)>", len(dsi.Data))
+}
+
+// SlConfigDescriptor configures the sync-layer. MP4 files always use the
+// predefined value two, so the custom fields are not modeled and are kept as
+// read.
+type SlConfigDescriptor struct {
+	DescriptorHeader
+
+	Predefined uint8
+
+	// Data is the custom configuration, which is only present if Predefined
+	// is zero.
+	Data []byte
+}
+
+// DescriptorTag returns the tag that identifies the descriptor.
+func (scd *SlConfigDescriptor) DescriptorTag() byte {
+	return SlConfigDescriptorTag
+}
+
+// EncodeBody returns the encoded descriptor without its tag and size.
+func (scd *SlConfigDescriptor) EncodeBody() (data []byte, err error) {
+	PushBytes(&data, scd.Predefined)
+	PushBytes(&data, scd.Data)
+
+	return data, nil
+}
+
+// String returns a descriptive string.
+func (scd *SlConfigDescriptor) String() string {
+	return fmt.Sprintf("SlConfigDescriptor<PREDEFINED=(%d)>", scd.Predefined)
+}
+
+// InitialObjectDescriptor is the descriptor that is in the "iods" box. It
+// gives the profiles and levels that are required to present the file. Both
+// the ISO 14496-1 tag and the MP4_IOD tag are parsed into this.
+type InitialObjectDescriptor struct {
+	DescriptorHeader
+
+	ObjectDescriptorId            uint16
+	IncludeInlineProfileLevelFlag bool
+
+	UrlFlag bool
+	Url     string
+
+	// The profile-level indications are only present if there is no URL.
+	OdProfileLevelIndication       uint8
+	SceneProfileLevelIndication    uint8
+	AudioProfileLevelIndication    uint8
+	VisualProfileLevelIndication   uint8
+	GraphicsProfileLevelIndication uint8
+
+	// Descriptors are the nested descriptors (e.g. ES_ID_Inc).
+	Descriptors []Descriptor
+}
+
+// DescriptorTag returns the tag that identifies the descriptor. It defaults to
+// the MP4_IOD tag.
+func (iod *InitialObjectDescriptor) DescriptorTag() byte {
+	if iod.Tag == 0 {
+		return Mp4IodTag
+	}
+
+	return iod.Tag
+}
+
+// EncodeBody returns the encoded descriptor without its tag and size.
+func (iod *InitialObjectDescriptor) EncodeBody() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	// The four reserved bits are ones.
+	packed := iod.ObjectDescriptorId<<6 | 0x0f
+
+	if iod.UrlFlag == true {
+		packed |= 0x20
+	}
+
+	if iod.IncludeInlineProfileLevelFlag == true {
+		packed |= 0x10
+	}
+
+	PushBytes(&data, packed)
+
+	if iod.UrlFlag == true {
+		if len(iod.Url) > 255 {
+			log.Panicf("URL too long: (%d)", len(iod.Url))
+		}
+
+		PushBytes(&data, uint8(len(iod.Url)))
+		PushBytes(&data, []byte(iod.Url))
+	} else {
+		PushBytes(&data, []byte{
+			iod.OdProfileLevelIndication,
+			iod.SceneProfileLevelIndication,
+			iod.AudioProfileLevelIndication,
+			iod.VisualProfileLevelIndication,
+			iod.GraphicsProfileLevelIndication,
+		})
+	}
+
+	encoded, err := EncodeDescriptors(iod.Descriptors)
+	log.PanicIf(err)
+
+	PushBytes(&data, encoded)
+
+	return data, nil
+}
+
+// String returns a descriptive string.
+func (iod *InitialObjectDescriptor) String() string {
+	return fmt.Sprintf(
+		"InitialObjectDescriptor<OD-ID=(%d) AUDIO-PLI=(0x%02x) VISUAL-PLI=(0x%02x) DESCRIPTORS=(%d)>",
+		iod.ObjectDescriptorId, iod.AudioProfileLevelIndication, iod.VisualProfileLevelIndication,
+		len(iod.Descriptors))
+}
+
+// EsIdIncDescriptor refers to a track by its ID. It is used in the initial
+// object-descriptor of MP4 files.
+type EsIdIncDescriptor struct {
+	DescriptorHeader
+
+	TrackId uint32
+}
+
+// DescriptorTag returns the tag that identifies the descriptor.
+func (eiid *EsIdIncDescriptor) DescriptorTag() byte {
+	return EsIdIncDescriptorTag
+}
+
+// EncodeBody returns the encoded descriptor without its tag and size.
+func (eiid *EsIdIncDescriptor) EncodeBody() (data []byte, err error) {
+	PushBytes(&data, eiid.TrackId)
+
+	return data, nil
+}
+
+// String returns a descriptive string.
+func (eiid *EsIdIncDescriptor) String() string {
+	return fmt.Sprintf("EsIdIncDescriptor<TRACK-ID=(%d)>", eiid.TrackId)
+}
+
+// readDescriptorHeader reads the tag and the expandable size at the start of
+// the data. The size is encoded in up to four bytes of seven bits each with
+// the high bit set on all but the last.
+func readDescriptorHeader(data []byte, offset int64) (dh DescriptorHeader, size int, headerSize int) {
+	if len(data) < 2 {
+		log.Panic(NewErrTruncated(nil, offset, 2, int64(len(data))))
+	}
+
+	dh.Tag = data[0]
+
+	for i := 1; ; i++ {
+		if i > maxDescriptorSizeLength {
+			log.Panicf("descriptor (0x%02x) size has more than (%d) bytes", dh.Tag, maxDescriptorSizeLength)
+		} else if i >= len(data) {
+			log.Panic(NewErrTruncated(nil, offset, int64(i+1), int64(len(data))))
+		}
+
+		b := data[i]
+		size = size<<7 | int(b&0x7f)
+
+		if b&0x80 == 0 {
+			dh.SizeLength = i
+			break
+		}
+	}
+
+	headerSize = 1 + dh.SizeLength
+
+	if size > len(data)-headerSize {
+		log.Panic(NewErrTruncated(nil, offset+int64(headerSize), int64(size), int64(len(data)-headerSize)))
+	}
+
+	return dh, size, headerSize
+}
+
+// ReadDescriptor parses the descriptor at the start of the data and returns it
+// with the number of bytes that it occupied. Nested descriptors are parsed as
+// well. Tags that are not modeled are returned as RawDescriptor. The offset is
+// where the data is in the resource and is only used for errors.
+func ReadDescriptor(data []byte, offset int64) (d Descriptor, size int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	dh, bodySize, headerSize := readDescriptorHeader(data, offset)
+
+	body := data[headerSize : headerSize+bodySize]
+	bodyOffset := offset + int64(headerSize)
+
+	switch dh.Tag {
+	case EsDescriptorTag:
+		d = parseEsDescriptor(dh, body, bodyOffset)
+	case DecoderConfigDescriptorTag:
+		d = parseDecoderConfigDescriptor(dh, body, bodyOffset)
+	case DecoderSpecificInfoTag:
+		d = &DecoderSpecificInfo{
+			DescriptorHeader: dh,
+			Data:             body,
+		}
+	case SlConfigDescriptorTag:
+		if len(body) < 1 {
+			log.Panic(NewErrTruncated(nil, bodyOffset, 1, 0))
+		}
+
+		d = &SlConfigDescriptor{
+			DescriptorHeader: dh,
+			Predefined:       body[0],
+			Data:             body[1:],
+		}
+	case InitialObjectDescriptorTag, Mp4IodTag:
+		d = parseInitialObjectDescriptor(dh, body, bodyOffset)
+	case EsIdIncDescriptorTag:
+		if len(body) < 4 {
+			log.Panic(NewErrTruncated(nil, bodyOffset, 4, int64(len(body))))
+		}
+
+		d = &EsIdIncDescriptor{
+			DescriptorHeader: dh,
+			TrackId:          DefaultEndianness.Uint32(body[0:4]),
+		}
+	default:
+		d = &RawDescriptor{
+			DescriptorHeader: dh,
+			Data:             body,
+		}
+	}
+
+	return d, headerSize + bodySize, nil
+}
+
+// ReadDescriptors parses a series of descriptors that fills the data.
+func ReadDescriptors(data []byte, offset int64) (descriptors []Descriptor, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	descriptors = make([]Descriptor, 0)
+
+	for len(data) > 0 {
+		d, size, err := ReadDescriptor(data, offset)
+		log.PanicIf(err)
+
+		descriptors = append(descriptors, d)
+
+		data = data[size:]
+		offset += int64(size)
+	}
+
+	return descriptors, nil
+}
+
+// readNestedDescriptors is ReadDescriptors for the parsers, which panic.
+func readNestedDescriptors(data []byte, offset int64) []Descriptor {
+	descriptors, err := ReadDescriptors(data, offset)
+	log.PanicIf(err)
+
+	return descriptors
+}
+
+// readDescriptorUrl reads a URL that is prefixed by its length.
+func readDescriptorUrl(body []byte, position int, offset int64) (url string, next int) {
+	if position >= len(body) {
+		log.Panic(NewErrTruncated(nil, offset+int64(position), 1, 0))
+	}
+
+	length := int(body[position])
+	position++
+
+	if length > len(body)-position {
+		log.Panic(NewErrTruncated(nil, offset+int64(position), int64(length), int64(len(body)-position)))
+	}
+
+	return string(body[position : position+length]), position + length
+}
+
+func parseEsDescriptor(dh DescriptorHeader, body []byte, offset int64) *EsDescriptor {
+	if len(body) < 3 {
+		log.Panic(NewErrTruncated(nil, offset, 3, int64(len(body))))
+	}
+
+	ed := &EsDescriptor{
+		DescriptorHeader:     dh,
+		EsId:                 DefaultEndianness.Uint16(body[0:2]),
+		StreamDependenceFlag: body[2]&0x80 != 0,
+		UrlFlag:              body[2]&0x40 != 0,
+		OcrStreamFlag:        body[2]&0x20 != 0,
+		StreamPriority:       body[2] & 0x1f,
+	}
+
+	position := 3
+
+	if ed.StreamDependenceFlag == true {
+		if len(body)-position < 2 {
+			log.Panic(NewErrTruncated(nil, offset+int64(position), 2, int64(len(body)-position)))
+		}
+
+		ed.DependsOnEsId = DefaultEndianness.Uint16(body[position : position+2])
+		position += 2
+	}
+
+	if ed.UrlFlag == true {
+		ed.Url, position = readDescriptorUrl(body, position, offset)
+	}
+
+	if ed.OcrStreamFlag == true {
+		if len(body)-position < 2 {
+			log.Panic(NewErrTruncated(nil, offset+int64(position), 2, int64(len(body)-position)))
+		}
+
+		ed.OcrEsId = DefaultEndianness.Uint16(body[position : position+2])
+		position += 2
+	}
+
+	ed.Descriptors = make([]Descriptor, 0)
+
+	for _, d := range readNestedDescriptors(body[position:], offset+int64(position)) {
+		if dcd, ok := d.(*DecoderConfigDescriptor); ok == true && ed.DecoderConfig == nil {
+			ed.DecoderConfig = dcd
+		} else if scd, ok := d.(*SlConfigDescriptor); ok == true && ed.SlConfig == nil {
+			ed.SlConfig = scd
+		} else {
+			ed.Descriptors = append(ed.Descriptors, d)
+		}
+	}
+
+	return ed
+}
+
+func parseDecoderConfigDescriptor(dh DescriptorHeader, body []byte, offset int64) *DecoderConfigDescriptor {
+	if len(body) < decoderConfigDescriptorSize {
+		log.Panic(NewErrTruncated(nil, offset, decoderConfigDescriptorSize, int64(len(body))))
+	}
+
+	dcd := &DecoderConfigDescriptor{
+		DescriptorHeader:     dh,
+		ObjectTypeIndication: body[0],
+		StreamType:           body[1] >> 2,
+		UpStream:             body[1]&0x02 != 0,
+		BufferSizeDb:         DefaultEndianness.Uint32(body[1:5]) & 0x00ffffff,
+		MaxBitrate:           DefaultEndianness.Uint32(body[5:9]),
+		AvgBitrate:           DefaultEndianness.Uint32(body[9:13]),
+	}
+
+	dcd.Descriptors = make([]Descriptor, 0)
+
+	nested := body[decoderConfigDescriptorSize:]
+	for _, d := range readNestedDescriptors(nested, offset+decoderConfigDescriptorSize) {
+		if dsi, ok := d.(*DecoderSpecificInfo); ok == true && dcd.DecoderSpecificInfo == nil {
+			dcd.DecoderSpecificInfo = dsi
+		} else {
+			dcd.Descriptors = append(dcd.Descriptors, d)
+		}
+	}
+
+	return dcd
+}
+
+func parseInitialObjectDescriptor(dh DescriptorHeader, body []byte, offset int64) *InitialObjectDescriptor {
+	if len(body) < 2 {
+		log.Panic(NewErrTruncated(nil, offset, 2, int64(len(body))))
+	}
+
+	packed := DefaultEndianness.Uint16(body[0:2])
+
+	iod := &InitialObjectDescriptor{
+		DescriptorHeader:              dh,
+		ObjectDescriptorId:            packed >> 6,
+		UrlFlag:                       packed&0x20 != 0,
+		IncludeInlineProfileLevelFlag: packed&0x10 != 0,
+	}
+
+	position := 2
+
+	if iod.UrlFlag == true {
+		iod.Url, position = readDescriptorUrl(body, position, offset)
+	} else {
+		if len(body)-position < 5 {
+			log.Panic(NewErrTruncated(nil, offset+int64(position), 5, int64(len(body)-position)))
+		}
+
+		iod.OdProfileLevelIndication = body[position]
+		iod.SceneProfileLevelIndication = body[position+1]
+		iod.AudioProfileLevelIndication = body[position+2]
+		iod.VisualProfileLevelIndication = body[position+3]
+		iod.GraphicsProfileLevelIndication = body[position+4]
+
+		position += 5
+	}
+
+	iod.Descriptors = readNestedDescriptors(body[position:], offset+int64(position))
+
+	return iod
+}
+
+// encodeDescriptorSize returns the expandable encoding of the size in at least
+// the given number of bytes.
+func encodeDescriptorSize(size int, minimumLength int) []byte {
+	length := 1
+	for size>>(7*uint(length)) != 0 {
+		length++
+	}
+
+	if length > maxDescriptorSizeLength {
+		log.Panicf("descriptor size too large: (%d)", size)
+	}
+
+	if minimumLength > length {
+		length = minimumLength
+	}
+
+	if length > maxDescriptorSizeLength {
+		length = maxDescriptorSizeLength
+	}
+
+	encoded := make([]byte, length)
+	for i := 0; i < length; i++ {
+		encoded[i] = byte(size>>(7*uint(length-1-i))) & 0x7f
+
+		if i < length-1 {
+			encoded[i] |= 0x80
+		}
+	}
+
+	return encoded
+}
+
+// EncodeDescriptor returns the descriptor with its tag and size.
+func EncodeDescriptor(d Descriptor) (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	body, err := d.EncodeBody()
+	log.PanicIf(err)
+
+	data = make([]byte, 0, 1+maxDescriptorSizeLength+len(body))
+
+	data = append(data, d.DescriptorTag())
+	data = append(data, encodeDescriptorSize(len(body), d.DescriptorSizeLength())...)
+	data = append(data, body...)
+
+	return data, nil
+}
+
+// EncodeDescriptors returns a series of descriptors with their tags and sizes.
+func EncodeDescriptors(descriptors []Descriptor) (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data = make([]byte, 0)
+
+	for _, d := range descriptors {
+		encoded, err := EncodeDescriptor(d)
+		log.PanicIf(err)
+
+		data = append(data, encoded...)
+	}
+
+	return data, nil
+}
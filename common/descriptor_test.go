@@ -0,0 +1,326 @@
+package bmfcommon
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+)
+
+var (
+	// testEsDescriptorData is the ES_Descriptor from the "esds" box of the
+	// tears-of-steel asset. The sizes use the padded four-byte encoding.
+	testEsDescriptorData, _ = hex.DecodeString("03808080250002000480808017401500000000" + "02e3fd0002e3fd0580808005121056e500" + "0680808001" + "02")
+)
+
+func TestReadDescriptor_EsDescriptor(t *testing.T) {
+	d, size, err := ReadDescriptor(testEsDescriptorData, 0)
+	log.PanicIf(err)
+
+	if size != len(testEsDescriptorData) {
+		t.Fatalf("Size not correct: (%d)", size)
+	}
+
+	ed, ok := d.(*EsDescriptor)
+	if ok != true {
+		t.Fatalf("Descriptor type not correct: [%T]", d)
+	}
+
+	if ed.EsId != 2 {
+		t.Fatalf("EsId not correct.")
+	} else if ed.StreamPriority != 0 {
+		t.Fatalf("StreamPriority not correct.")
+	} else if ed.StreamDependenceFlag != false || ed.UrlFlag != false || ed.OcrStreamFlag != false {
+		t.Fatalf("Flags not correct.")
+	} else if ed.DescriptorSizeLength() != 4 {
+		t.Fatalf("DescriptorSizeLength() not correct.")
+	} else if len(ed.Descriptors) != 0 {
+		t.Fatalf("Descriptors not correct.")
+	}
+
+	dcd := ed.DecoderConfig
+	if dcd == nil {
+		t.Fatalf("DecoderConfig not found.")
+	} else if dcd.ObjectTypeIndication != 0x40 {
+		t.Fatalf("ObjectTypeIndication not correct.")
+	} else if dcd.StreamType != 5 {
+		t.Fatalf("StreamType not correct.")
+	} else if dcd.UpStream != false {
+		t.Fatalf("UpStream not correct.")
+	} else if dcd.BufferSizeDb != 0 {
+		t.Fatalf("BufferSizeDb not correct.")
+	} else if dcd.MaxBitrate != 189437 || dcd.AvgBitrate != 189437 {
+		t.Fatalf("Bitrates not correct.")
+	} else if dcd.DecoderSpecificInfo == nil {
+		t.Fatalf("DecoderSpecificInfo not found.")
+	} else if bytes.Equal(dcd.DecoderSpecificInfo.Data, []byte{0x12, 0x10, 0x56, 0xe5, 0x00}) != true {
+		t.Fatalf("DecoderSpecificInfo not correct.")
+	}
+
+	if ed.SlConfig == nil {
+		t.Fatalf("SlConfig not found.")
+	} else if ed.SlConfig.Predefined != 2 {
+		t.Fatalf("SlConfig not correct.")
+	}
+
+	encoded, err := EncodeDescriptor(ed)
+	log.PanicIf(err)
+
+	if bytes.Equal(encoded, testEsDescriptorData) != true {
+		t.Fatalf("Encoded descriptor not correct:\nACTUAL: %x\nEXPECTED: %x", encoded, testEsDescriptorData)
+	}
+}
+
+func TestReadDescriptor_EsDescriptor_Flags(t *testing.T) {
+	ed := &EsDescriptor{
+		EsId:                 3,
+		StreamPriority:       7,
+		StreamDependenceFlag: true,
+		DependsOnEsId:        1,
+		UrlFlag:              true,
+		Url:                  "http://example.com/stream",
+		OcrStreamFlag:        true,
+		OcrEsId:              2,
+		DecoderConfig: &DecoderConfigDescriptor{
+			ObjectTypeIndication: 0x6b,
+			StreamType:           5,
+			BufferSizeDb:         0x123456,
+			MaxBitrate:           128000,
+			AvgBitrate:           128000,
+		},
+		SlConfig: &SlConfigDescriptor{
+			Predefined: 2,
+		},
+	}
+
+	encoded, err := EncodeDescriptor(ed)
+	log.PanicIf(err)
+
+	d, size, err := ReadDescriptor(encoded, 0)
+	log.PanicIf(err)
+
+	if size != len(encoded) {
+		t.Fatalf("Size not correct: (%d) != (%d)", size, len(encoded))
+	}
+
+	recovered := d.(*EsDescriptor)
+
+	if recovered.EsId != 3 || recovered.StreamPriority != 7 {
+		t.Fatalf("EsId or StreamPriority not correct.")
+	} else if recovered.StreamDependenceFlag != true || recovered.DependsOnEsId != 1 {
+		t.Fatalf("Dependency not correct.")
+	} else if recovered.UrlFlag != true || recovered.Url != "http://example.com/stream" {
+		t.Fatalf("URL not correct: [%s]", recovered.Url)
+	} else if recovered.OcrStreamFlag != true || recovered.OcrEsId != 2 {
+		t.Fatalf("OCR not correct.")
+	} else if recovered.DecoderConfig == nil || recovered.DecoderConfig.BufferSizeDb != 0x123456 {
+		t.Fatalf("DecoderConfig not correct.")
+	} else if recovered.DecoderConfig.DecoderSpecificInfo != nil {
+		t.Fatalf("DecoderSpecificInfo should not be present.")
+	} else if recovered.SlConfig == nil || recovered.SlConfig.Predefined != 2 {
+		t.Fatalf("SlConfig not correct.")
+	}
+
+	// The new descriptor uses the minimal size encoding, so re-encoding what
+	// was parsed must give the same bytes.
+	reencoded, err := EncodeDescriptor(recovered)
+	log.PanicIf(err)
+
+	if bytes.Equal(reencoded, encoded) != true {
+		t.Fatalf("Re-encoded descriptor not correct.")
+	}
+}
+
+func TestReadDescriptor_Raw(t *testing.T) {
+	data := []byte{0x0a, 0x03, 0x65, 0x6e, 0x67}
+
+	d, size, err := ReadDescriptor(data, 0)
+	log.PanicIf(err)
+
+	rd, ok := d.(*RawDescriptor)
+	if ok != true {
+		t.Fatalf("Descriptor type not correct: [%T]", d)
+	} else if size != 5 {
+		t.Fatalf("Size not correct.")
+	} else if rd.DescriptorTag() != 0x0a {
+		t.Fatalf("Tag not correct.")
+	} else if bytes.Equal(rd.Data, []byte("eng")) != true {
+		t.Fatalf("Data not correct.")
+	}
+
+	encoded, err := EncodeDescriptor(rd)
+	log.PanicIf(err)
+
+	if bytes.Equal(encoded, data) != true {
+		t.Fatalf("Encoded descriptor not correct.")
+	}
+}
+
+func TestReadDescriptor_InitialObjectDescriptor(t *testing.T) {
+	data, _ := hex.DecodeString("1013" + "004f" + "ffff2915ff" + "0e0400000001" + "0e0400000002")
+
+	d, _, err := ReadDescriptor(data, 0)
+	log.PanicIf(err)
+
+	iod := d.(*InitialObjectDescriptor)
+
+	if iod.DescriptorTag() != Mp4IodTag {
+		t.Fatalf("Tag not correct.")
+	} else if iod.ObjectDescriptorId != 1 {
+		t.Fatalf("ObjectDescriptorId not correct.")
+	} else if iod.UrlFlag != false || iod.IncludeInlineProfileLevelFlag != false {
+		t.Fatalf("Flags not correct.")
+	} else if iod.OdProfileLevelIndication != 0xff || iod.SceneProfileLevelIndication != 0xff {
+		t.Fatalf("OD or scene profile-level not correct.")
+	} else if iod.AudioProfileLevelIndication != 0x29 || iod.VisualProfileLevelIndication != 0x15 {
+		t.Fatalf("Audio or visual profile-level not correct.")
+	} else if iod.GraphicsProfileLevelIndication != 0xff {
+		t.Fatalf("Graphics profile-level not correct.")
+	} else if len(iod.Descriptors) != 2 {
+		t.Fatalf("Descriptors not correct.")
+	} else if iod.Descriptors[1].(*EsIdIncDescriptor).TrackId != 2 {
+		t.Fatalf("ES_ID_Inc not correct.")
+	}
+
+	encoded, err := EncodeDescriptor(iod)
+	log.PanicIf(err)
+
+	if bytes.Equal(encoded, data) != true {
+		t.Fatalf("Encoded descriptor not correct: %x", encoded)
+	}
+}
+
+func TestReadDescriptor_InitialObjectDescriptor_Url(t *testing.T) {
+	iod := &InitialObjectDescriptor{
+		DescriptorHeader: DescriptorHeader{
+			Tag: InitialObjectDescriptorTag,
+		},
+		ObjectDescriptorId: 5,
+		UrlFlag:            true,
+		Url:                "od.mp4",
+	}
+
+	encoded, err := EncodeDescriptor(iod)
+	log.PanicIf(err)
+
+	expected, _ := hex.DecodeString("0209" + "016f" + "06" + hex.EncodeToString([]byte("od.mp4")))
+
+	if bytes.Equal(encoded, expected) != true {
+		t.Fatalf("Encoded descriptor not correct: %x", encoded)
+	}
+
+	d, _, err := ReadDescriptor(encoded, 0)
+	log.PanicIf(err)
+
+	recovered := d.(*InitialObjectDescriptor)
+
+	if recovered.DescriptorTag() != InitialObjectDescriptorTag {
+		t.Fatalf("Tag not correct.")
+	} else if recovered.ObjectDescriptorId != 5 {
+		t.Fatalf("ObjectDescriptorId not correct.")
+	} else if recovered.Url != "od.mp4" {
+		t.Fatalf("Url not correct.")
+	}
+}
+
+func TestEncodeDescriptorSize(t *testing.T) {
+	cases := []struct {
+		size          int
+		minimumLength int
+		expected      []byte
+	}{
+		{0, 0, []byte{0x00}},
+		{127, 0, []byte{0x7f}},
+		{200, 0, []byte{0x81, 0x48}},
+		{5, 4, []byte{0x80, 0x80, 0x80, 0x05}},
+		{200, 4, []byte{0x80, 0x80, 0x81, 0x48}},
+		{0x0fffffff, 0, []byte{0xff, 0xff, 0xff, 0x7f}},
+	}
+
+	for _, c := range cases {
+		encoded := encodeDescriptorSize(c.size, c.minimumLength)
+
+		if bytes.Equal(encoded, c.expected) != true {
+			t.Fatalf("Size (%d) with minimum (%d) not correct: %x", c.size, c.minimumLength, encoded)
+		}
+	}
+}
+
+func TestEncodeDescriptor_TwoByteSize(t *testing.T) {
+	dsi := &DecoderSpecificInfo{
+		Data: make([]byte, 200),
+	}
+
+	encoded, err := EncodeDescriptor(dsi)
+	log.PanicIf(err)
+
+	if bytes.Equal(encoded[:3], []byte{DecoderSpecificInfoTag, 0x81, 0x48}) != true {
+		t.Fatalf("Header not correct: %x", encoded[:3])
+	} else if len(encoded) != 203 {
+		t.Fatalf("Length not correct.")
+	}
+
+	d, _, err := ReadDescriptor(encoded, 0)
+	log.PanicIf(err)
+
+	if len(d.(*DecoderSpecificInfo).Data) != 200 {
+		t.Fatalf("Data not correct.")
+	} else if d.DescriptorSizeLength() != 2 {
+		t.Fatalf("DescriptorSizeLength() not correct.")
+	}
+}
+
+func TestReadDescriptors(t *testing.T) {
+	data := []byte{0x0e, 0x04, 0, 0, 0, 1, 0x0a, 0x00}
+
+	descriptors, err := ReadDescriptors(data, 0)
+	log.PanicIf(err)
+
+	if len(descriptors) != 2 {
+		t.Fatalf("Count not correct.")
+	} else if descriptors[0].(*EsIdIncDescriptor).TrackId != 1 {
+		t.Fatalf("First descriptor not correct.")
+	} else if descriptors[1].DescriptorTag() != 0x0a {
+		t.Fatalf("Second descriptor not correct.")
+	}
+
+	encoded, err := EncodeDescriptors(descriptors)
+	log.PanicIf(err)
+
+	if bytes.Equal(encoded, data) != true {
+		t.Fatalf("Encoded descriptors not correct.")
+	}
+}
+
+func TestReadDescriptor_Truncated(t *testing.T) {
+	_, _, err := ReadDescriptor(testEsDescriptorData[:20], 100)
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+
+	var et *ErrTruncated
+	if errors.As(err, &et) != true {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	} else if et.Offset != 105 {
+		t.Fatalf("Offset not correct: (%d)", et.Offset)
+	}
+
+	// The nested descriptor claims more than its parent has.
+	data := []byte{0x03, 0x05, 0x00, 0x01, 0x00, 0x04, 0x10}
+
+	_, _, err = ReadDescriptor(data, 0)
+	if errors.As(err, &et) != true {
+		t.Fatalf("Nested error not correct: [%v]", err)
+	}
+}
+
+func TestReadDescriptor_SizeTooLong(t *testing.T) {
+	data := []byte{0x05, 0x80, 0x80, 0x80, 0x80, 0x01, 0x00}
+
+	_, _, err := ReadDescriptor(data, 0)
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+}
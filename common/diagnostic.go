@@ -0,0 +1,58 @@
+package bmfcommon
+
+import (
+	"fmt"
+)
+
+// DiagnosticSeverity describes how much of the stream was lost to an anomaly.
+type DiagnosticSeverity int
+
+const (
+	// DiagnosticSeverityWarning indicates that one box (and its children) was
+	// skipped. Parsing continued with the next sibling.
+	DiagnosticSeverityWarning DiagnosticSeverity = iota
+
+	// DiagnosticSeverityError indicates that the next sibling could not be
+	// located and the remainder of the parent box (or of the file) was
+	// skipped.
+	DiagnosticSeverityError
+)
+
+// String returns a descriptive name for the severity.
+func (ds DiagnosticSeverity) String() string {
+	switch ds {
+	case DiagnosticSeverityWarning:
+		return "WARNING"
+	case DiagnosticSeverityError:
+		return "ERROR"
+	}
+
+	return fmt.Sprintf("DiagnosticSeverity(%d)", int(ds))
+}
+
+// MarshalText encodes the severity as its name.
+func (ds DiagnosticSeverity) MarshalText() (text []byte, err error) {
+	return []byte(ds.String()), nil
+}
+
+// Diagnostic describes an anomaly that was skipped over while parsing in
+// lenient mode.
+type Diagnostic struct {
+	// Offset is the offset of the box that could not be parsed.
+	Offset int64 `json:"offset"`
+
+	// Path is the box-name fully-qualified with dots. The name of the box
+	// itself is omitted if its header could not be read.
+	Path string `json:"path"`
+
+	// Severity describes how much was skipped.
+	Severity DiagnosticSeverity `json:"severity"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// String returns a descriptive string.
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("Diagnostic<SEVERITY=[%s] OFFSET=(0x%016x) PATH=[%s] MESSAGE=[%s]>", d.Severity, d.Offset, d.Path, d.Message)
+}
@@ -0,0 +1,28 @@
+package bmfcommon
+
+import (
+	"testing"
+)
+
+func TestDiagnosticSeverity_String(t *testing.T) {
+	if DiagnosticSeverityWarning.String() != "WARNING" {
+		t.Fatalf("String() not correct for warning.")
+	} else if DiagnosticSeverityError.String() != "ERROR" {
+		t.Fatalf("String() not correct for error.")
+	} else if DiagnosticSeverity(99).String() != "DiagnosticSeverity(99)" {
+		t.Fatalf("String() not correct for unknown severity.")
+	}
+}
+
+func TestDiagnostic_String(t *testing.T) {
+	d := Diagnostic{
+		Offset:   0x10,
+		Path:     "moov.trak",
+		Severity: DiagnosticSeverityWarning,
+		Message:  "some problem",
+	}
+
+	if d.String() != "Diagnostic<SEVERITY=[WARNING] OFFSET=(0x0000000000000010) PATH=[moov.trak] MESSAGE=[some problem]>" {
+		t.Fatalf("String() not correct: [%s]", d.String())
+	}
+}
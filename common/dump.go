@@ -21,14 +21,11 @@ func dump(box interface{}, level int) {
 			fmt.Printf("%s> %s  %s\n", indent, cb.Name(), cb.InlineString())
 		}
 
-		names := t.ChildrenTypes()
-		for _, name := range names {
-			boxes, err := t.GetChildBoxes(name)
-			log.PanicIf(err)
-
-			for _, currentBox := range boxes {
-				dump(currentBox, level+1)
-			}
+		children, err := t.Children()
+		log.PanicIf(err)
+
+		for _, currentBox := range children {
+			dump(currentBox, level+1)
 		}
 	case CommonBox:
 		fmt.Printf("%s> %s  %s\n", indent, t.Name(), t.InlineString())
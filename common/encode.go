@@ -0,0 +1,335 @@
+package bmfcommon
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/dsoprea/go-logging"
+)
+
+// BoxEncoder is implemented by box types that know how to serialize
+// themselves.
+type BoxEncoder interface {
+	// EncodeData returns the payload of the box: everything after the header
+	// and before the first child box. Child boxes are encoded separately.
+	EncodeData() (data []byte, err error)
+}
+
+// childBoxSeriesOffsetSetter is satisfied by every type that embeds Box.
+type childBoxSeriesOffsetSetter interface {
+	setChildBoxSeriesOffset(offset int64)
+}
+
+// encodableBox exposes the parts of the embedded Box that the encoder needs in
+// order to pass through anything that it can not encode itself.
+type encodableBox interface {
+	CommonBox
+
+	Start() int64
+	HeaderSize() int64
+	IsSizeImplicit() bool
+	ExtendedType() ExtendedType
+	ReadBytesAt(offset int64, n int64) (b []byte, err error)
+	CopyBytesAt(offset int64, n int64, w io.Writer) (err error)
+
+	getChildBoxSeriesOffset() (offset int64, hasChildren bool)
+}
+
+// EncodeBoxHeader writes a box header for a payload of the given size. A
+// 64-bit size is written if requested or if the size does not fit in 32 bits.
+func EncodeBoxHeader(w io.Writer, name string, payloadSize int64, force64 bool) (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if len(name) != 4 {
+		log.Panicf("box name must be four bytes: [%s]", name)
+	}
+
+	var header []byte
+
+	if force64 == true || payloadSize+8 > math.MaxUint32 {
+		header = make([]byte, 16)
+
+		DefaultEndianness.PutUint32(header[0:4], 1)
+		copy(header[4:8], name)
+		DefaultEndianness.PutUint64(header[8:16], uint64(payloadSize+16))
+	} else {
+		header = make([]byte, 8)
+
+		DefaultEndianness.PutUint32(header[0:4], uint32(payloadSize+8))
+		copy(header[4:8], name)
+	}
+
+	_, err = w.Write(header)
+	log.PanicIf(err)
+
+	return nil
+}
+
+// encodeHeader writes the header for the given box in the form that it was
+// originally stored in, followed by the extended type for "uuid" boxes. An
+// implicit size is only retained if the box is still the last one in its
+// container.
+func encodeHeader(w io.Writer, eb encodableBox, payloadSize int64, isLast bool) (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	headerSize := eb.HeaderSize()
+	isUuid := eb.Name() == UuidBoxName
+
+	if isUuid == true {
+		// The extended type is counted in the size but not in the size of the
+		// basic header.
+
+		et := eb.ExtendedType()
+
+		headerSize -= int64(len(et))
+		payloadSize += int64(len(et))
+	}
+
+	if eb.IsSizeImplicit() == true && isLast == true {
+		// A size of zero means that the box extends to the end of its
+		// container.
+
+		header := make([]byte, 8)
+		copy(header[4:8], eb.Name())
+
+		_, err := w.Write(header)
+		log.PanicIf(err)
+	} else {
+		err := EncodeBoxHeader(w, eb.Name(), payloadSize, headerSize == 16)
+		log.PanicIf(err)
+	}
+
+	if isUuid == true {
+		et := eb.ExtendedType()
+
+		_, err := w.Write(et[:])
+		log.PanicIf(err)
+	}
+
+	return nil
+}
+
+// rawChildIndexer is satisfied by every type that embeds LoadedBoxIndex.
+type rawChildIndexer interface {
+	loadedBoxIndex() LoadedBoxIndex
+}
+
+// sortedChildBoxes returns all children in the order that they were
+// stored. Unless requested, lazy children are not decoded but those that
+// already were are swapped for their decoded counterparts. In lenient mode,
+// children that fail to decode are left out, as they would have been if they
+// were parsed eagerly. The failure is recorded as a diagnostic by the decode.
+func sortedChildBoxes(bci BoxChildIndexer, decode bool) (children []encodableBox) {
+	children = make([]encodableBox, 0)
+
+	rci, isRaw := bci.(rawChildIndexer)
+
+	for _, name := range bci.ChildrenTypes() {
+		var boxes []CommonBox
+
+		if isRaw == true {
+			boxes = rci.loadedBoxIndex()[name]
+		} else {
+			var err error
+
+			boxes, err = bci.GetChildBoxes(name)
+			log.PanicIf(err)
+		}
+
+		for _, child := range boxes {
+			if lb, ok := child.(*LazyBox); ok == true {
+				if decode == true {
+					decoded, err := lb.Decode()
+					if err != nil {
+						if lb.resource.options.Strict == true {
+							log.Panic(err)
+						}
+
+						continue
+					}
+
+					child = decoded
+				} else if lb.IsDecoded() == true {
+					child = lb.decoded
+				}
+			}
+
+			children = append(children, child.(encodableBox))
+		}
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Start() < children[j].Start()
+	})
+
+	return children
+}
+
+// encodeChildren writes the children of a box that occupy the source range
+// [start, end). Any bytes in that range that do not belong to a child (such
+// as boxes that were skipped in lenient mode) are copied through unchanged.
+func encodeChildren(w io.Writer, eb encodableBox, start, end int64) (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	cursor := start
+
+	var children []encodableBox
+	if bci, ok := eb.(BoxChildIndexer); ok == true {
+		children = sortedChildBoxes(bci, false)
+	}
+
+	for i, child := range children {
+		childStart := child.Start()
+
+		if childStart > cursor {
+			err := eb.CopyBytesAt(cursor, childStart-cursor, w)
+			log.PanicIf(err)
+		}
+
+		err := encodeBox(w, child, i == len(children)-1)
+		log.PanicIf(err)
+
+		cursor = childStart + child.Size()
+	}
+
+	if cursor < end {
+		err := eb.CopyBytesAt(cursor, end-cursor, w)
+		log.PanicIf(err)
+	}
+
+	return nil
+}
+
+// EncodeBox writes the given box, including its header and children. Boxes
+// that do not implement BoxEncoder, including lazy boxes that were never
+// decoded, are copied through from the resource that they were read from. The
+// original header form (32-bit or 64-bit) is retained. Since the box may be
+// written anywhere, a box that was stored with an implicit size is written
+// with an explicit one.
+func EncodeBox(w io.Writer, cb CommonBox) (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	err = encodeBox(w, cb, false)
+	log.PanicIf(err)
+
+	return nil
+}
+
+// encodeBox writes the given box. isLast indicates whether the box is the
+// last one in what is being written for its container, which is the only
+// place that an implicit size is valid.
+func encodeBox(w io.Writer, cb CommonBox, isLast bool) (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	eb, ok := cb.(encodableBox)
+	if ok == false {
+		log.Panicf("box [%s] can not be encoded", cb.Name())
+	}
+
+	headerSize := eb.HeaderSize()
+	payloadStart := eb.Start() + headerSize
+	end := eb.Start() + eb.Size()
+
+	be, isEncoder := cb.(BoxEncoder)
+	childBoxSeriesOffset, hasChildren := eb.getChildBoxSeriesOffset()
+
+	if isEncoder == false && hasChildren == false {
+		// Nothing will change, so stream it rather than buffering it. This
+		// is important for MDAT boxes.
+
+		err := encodeHeader(w, eb, end-payloadStart, isLast)
+		log.PanicIf(err)
+
+		err = eb.CopyBytesAt(payloadStart, end-payloadStart, w)
+		log.PanicIf(err)
+
+		return nil
+	}
+
+	b := new(bytes.Buffer)
+
+	if isEncoder == true {
+		data, err := be.EncodeData()
+		log.PanicIf(err)
+
+		_, err = b.Write(data)
+		log.PanicIf(err)
+	} else {
+		data, err := eb.ReadBytesAt(payloadStart, childBoxSeriesOffset)
+		log.PanicIf(err)
+
+		_, err = b.Write(data)
+		log.PanicIf(err)
+	}
+
+	if hasChildren == true {
+		err := encodeChildren(b, eb, payloadStart+childBoxSeriesOffset, end)
+		log.PanicIf(err)
+	}
+
+	err = encodeHeader(w, eb, int64(b.Len()), isLast)
+	log.PanicIf(err)
+
+	_, err = w.Write(b.Bytes())
+	log.PanicIf(err)
+
+	return nil
+}
+
+// Encode writes every box in the resource to the given writer. Unchanged
+// boxes are reproduced byte-for-byte and anything between or after the known
+// root boxes is copied through.
+func (f *Resource) Encode(w io.Writer) (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	cursor := int64(0)
+
+	children := sortedChildBoxes(f, false)
+
+	for i, child := range children {
+		childStart := child.Start()
+
+		if childStart > cursor {
+			err := f.copyBytesAt(cursor, childStart-cursor, w)
+			log.PanicIf(err)
+		}
+
+		err := encodeBox(w, child, i == len(children)-1)
+		log.PanicIf(err)
+
+		cursor = childStart + child.Size()
+	}
+
+	if cursor < f.size {
+		err := f.copyBytesAt(cursor, f.size-cursor, w)
+		log.PanicIf(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package bmfcommon
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+)
+
+// testEncoderBox has one string field, which it re-encodes.
+type testEncoderBox struct {
+	// Box is the base box.
+	Box
+
+	value string
+}
+
+func (*testEncoderBox) InlineString() string {
+	return "TestEncoderBox"
+}
+
+func (teb *testEncoderBox) EncodeData() (data []byte, err error) {
+	return []byte(teb.value), nil
+}
+
+type testEncoderBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (testEncoderBoxFactory) Name() string {
+	return "tenc"
+}
+
+// New returns a new value instance.
+func (testEncoderBoxFactory) New(box Box) (cb CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := box.Data()
+	log.PanicIf(err)
+
+	teb := &testEncoderBox{
+		Box:   box,
+		value: string(data),
+	}
+
+	return teb, -1, nil
+}
+
+func getTestEncodeResource() (original []byte, resource *Resource) {
+	ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+	RegisterBoxType(testBox2Factory{})
+	RegisterBoxType(testBox3Factory{})
+	RegisterBoxType(testEncoderBoxFactory{})
+
+	var children []byte
+	pushTestBox1(&children)
+	pushUnknownBox(&children, []byte{1, 2, 3})
+	PushBox(&children, "tenc", []byte("abc"))
+
+	var b []byte
+	pushTestBox2(&b, []byte("abcdefgh"))
+	pushTestBox3(&b, children)
+	pushUnknownBox(&b, []byte{4, 5})
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	return b, resource
+}
+
+func TestEncodeBoxHeader_32(t *testing.T) {
+	b := new(bytes.Buffer)
+
+	err := EncodeBoxHeader(b, "abcd", 10, false)
+	log.PanicIf(err)
+
+	expected := []byte{0, 0, 0, 18, 'a', 'b', 'c', 'd'}
+
+	if bytes.Equal(b.Bytes(), expected) != true {
+		t.Fatalf("Header not correct: %v", b.Bytes())
+	}
+}
+
+func TestEncodeBoxHeader_64(t *testing.T) {
+	b := new(bytes.Buffer)
+
+	err := EncodeBoxHeader(b, "abcd", 10, true)
+	log.PanicIf(err)
+
+	expected := []byte{
+		0, 0, 0, 1, 'a', 'b', 'c', 'd',
+		0, 0, 0, 0, 0, 0, 0, 26,
+	}
+
+	if bytes.Equal(b.Bytes(), expected) != true {
+		t.Fatalf("Header not correct: %v", b.Bytes())
+	}
+}
+
+func TestEncodeBoxHeader_InvalidName(t *testing.T) {
+	b := new(bytes.Buffer)
+
+	err := EncodeBoxHeader(b, "abc", 10, false)
+	if err == nil {
+		t.Fatalf("Expected error for invalid name.")
+	}
+}
+
+func TestResource_Encode_Unchanged(t *testing.T) {
+	defer ClearRegistrations()
+
+	original, resource := getTestEncodeResource()
+
+	b := new(bytes.Buffer)
+
+	err := resource.Encode(b)
+	log.PanicIf(err)
+
+	if bytes.Equal(b.Bytes(), original) != true {
+		t.Fatalf("Encoded resource not identical to original:\nACTUAL: %v\nEXPECTED: %v", b.Bytes(), original)
+	}
+}
+
+func TestResource_Encode_Changed(t *testing.T) {
+	defer ClearRegistrations()
+
+	_, resource := getTestEncodeResource()
+
+	ibe := IndexedBoxEntry{
+		NamePhrase:     "tb3 .tenc",
+		SequenceNumber: 0,
+	}
+
+	resource.Index()[ibe].(*testEncoderBox).value = "abcdef"
+
+	b := new(bytes.Buffer)
+
+	err := resource.Encode(b)
+	log.PanicIf(err)
+
+	var children []byte
+	pushTestBox1(&children)
+	pushUnknownBox(&children, []byte{1, 2, 3})
+	PushBox(&children, "tenc", []byte("abcdef"))
+
+	var expected []byte
+	pushTestBox2(&expected, []byte("abcdefgh"))
+	pushTestBox3(&expected, children)
+	pushUnknownBox(&expected, []byte{4, 5})
+
+	if bytes.Equal(b.Bytes(), expected) != true {
+		t.Fatalf("Encoded resource not correct:\nACTUAL: %v\nEXPECTED: %v", b.Bytes(), expected)
+	}
+}
+
+func TestEncodeBox(t *testing.T) {
+	defer ClearRegistrations()
+
+	original, resource := getTestEncodeResource()
+
+	ibe := IndexedBoxEntry{
+		NamePhrase:     "tb2 ",
+		SequenceNumber: 0,
+	}
+
+	b := new(bytes.Buffer)
+
+	err := EncodeBox(b, resource.Index()[ibe])
+	log.PanicIf(err)
+
+	if bytes.Equal(b.Bytes(), original[:16]) != true {
+		t.Fatalf("Encoded box not correct: %v", b.Bytes())
+	}
+}
+
+func TestEncodeBox_ImplicitSize(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+	RegisterBoxType(testEncoderBoxFactory{})
+
+	var b []byte
+	pushTestBox1(&b)
+	pushImplicitSizeBox(&b, "tenc", []byte("abc"))
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes("tenc")
+	log.PanicIf(err)
+
+	boxes[0].(*testEncoderBox).value = "defg"
+
+	output := new(bytes.Buffer)
+
+	err = resource.Encode(output)
+	log.PanicIf(err)
+
+	var expected []byte
+	pushTestBox1(&expected)
+	pushImplicitSizeBox(&expected, "tenc", []byte("defg"))
+
+	if bytes.Equal(output.Bytes(), expected) != true {
+		t.Fatalf("Encoded resource not correct:\nACTUAL: %v\nEXPECTED: %v", output.Bytes(), expected)
+	}
+}
+
+func TestEncodeBox_ImplicitSize_Standalone(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+	RegisterBoxType(testEncoderBoxFactory{})
+
+	var b []byte
+	pushTestBox1(&b)
+	pushImplicitSizeBox(&b, "tenc", []byte("abc"))
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes("tenc")
+	log.PanicIf(err)
+
+	// The box may be written somewhere other than at the end, so the size
+	// must be explicit.
+
+	output := new(bytes.Buffer)
+
+	err = EncodeBox(output, boxes[0])
+	log.PanicIf(err)
+
+	var expected []byte
+	PushBox(&expected, "tenc", []byte("abc"))
+
+	if bytes.Equal(output.Bytes(), expected) != true {
+		t.Fatalf("Encoded box not correct:\nACTUAL: %v\nEXPECTED: %v", output.Bytes(), expected)
+	}
+}
+
+func TestEncodeBox_Uuid(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterUuidBoxType(testUuidBoxFactory{})
+
+	var b []byte
+	PushUuidBox(&b, testUuidBoxExtendedType, []byte("abc"))
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes(UuidBoxName)
+	log.PanicIf(err)
+
+	boxes[0].(*testUuidBox).value = "defg"
+
+	output := new(bytes.Buffer)
+
+	err = resource.Encode(output)
+	log.PanicIf(err)
+
+	var expected []byte
+	PushUuidBox(&expected, testUuidBoxExtendedType, []byte("defg"))
+
+	if bytes.Equal(output.Bytes(), expected) != true {
+		t.Fatalf("Encoded resource not correct:\nACTUAL: %v\nEXPECTED: %v", output.Bytes(), expected)
+	}
+}
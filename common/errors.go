@@ -0,0 +1,146 @@
+package bmfcommon
+
+import (
+	"errors"
+	"fmt"
+
+	goerrors "github.com/go-errors/errors"
+)
+
+// The errors returned by log.Wrap() have to unwrap for errors.Is() and
+// errors.As() to find the errors below.
+var _ interface{ Unwrap() error } = &goerrors.Error{}
+
+// ErrTruncated indicates that data ends before its declared size.
+type ErrTruncated struct {
+	// Box is the path of the box being read. It is empty if the read was not
+	// for a particular box.
+	Box string
+
+	// Offset is where the data starts in the resource.
+	Offset int64
+
+	// Size is how many bytes were required.
+	Size int64
+
+	// Available is how many bytes there actually were.
+	Available int64
+}
+
+// NewErrTruncated returns an ErrTruncated for the given box, which may be nil.
+func NewErrTruncated(cb CommonBox, offset, size, available int64) *ErrTruncated {
+	et := &ErrTruncated{
+		Offset:    offset,
+		Size:      size,
+		Available: available,
+	}
+
+	if cb != nil {
+		et.Box = boxPath(cb)
+	}
+
+	return et
+}
+
+// Error returns the message.
+func (et *ErrTruncated) Error() string {
+	message := fmt.Sprintf("read of (%d) bytes at offset (0x%016x) is truncated: (%d)", et.Size, et.Offset, et.Available)
+
+	if et.Box != "" {
+		return fmt.Sprintf("box [%s]: %s", et.Box, message)
+	}
+
+	return message
+}
+
+// setTruncatedBox attributes a truncated read to the given box if it was not
+// already attributed to one.
+func setTruncatedBox(err error, cb CommonBox) {
+	var et *ErrTruncated
+	if errors.As(err, &et) == true && et.Box == "" {
+		et.Box = boxPath(cb)
+	}
+}
+
+// ErrUnsupportedVersion indicates that a full box has a version that can not
+// be parsed.
+type ErrUnsupportedVersion struct {
+	// Box is the path of the box.
+	Box string
+
+	// Offset is the offset of the box in the resource.
+	Offset int64
+
+	// Version is the version that was stored.
+	Version byte
+
+	// Supported has the versions that can be parsed, if known.
+	Supported []byte
+}
+
+// NewErrUnsupportedVersion returns an ErrUnsupportedVersion for the given
+// box.
+func NewErrUnsupportedVersion(cb CommonBox, version byte, supported []byte) *ErrUnsupportedVersion {
+	euv := &ErrUnsupportedVersion{
+		Box:       boxPath(cb),
+		Version:   version,
+		Supported: supported,
+	}
+
+	if bs, ok := cb.(boxStarter); ok == true {
+		euv.Offset = bs.Start()
+	}
+
+	return euv
+}
+
+// Error returns the message.
+func (euv *ErrUnsupportedVersion) Error() string {
+	if euv.Supported == nil {
+		return fmt.Sprintf("box [%s] version (%d) not supported", euv.Box, euv.Version)
+	}
+
+	return fmt.Sprintf("box [%s] version (%d) not supported: %s", euv.Box, euv.Version, fmt.Sprint(euv.Supported))
+}
+
+// ErrInvalidBoxName indicates that a box header does not have a valid
+// box-type, which usually means that the data is not a box at all.
+type ErrInvalidBoxName struct {
+	// Parent is the path of the box that contains it. It is empty for root
+	// boxes.
+	Parent string
+
+	// Offset is the offset of the header in the resource.
+	Offset int64
+
+	// Name is the box-type as read.
+	Name string
+}
+
+// Error returns the message.
+func (eibn *ErrInvalidBoxName) Error() string {
+	return fmt.Sprintf("box starting at offset (0x%016x) looks like garbage", eibn.Offset)
+}
+
+// ErrChildNotFound indicates that a box does not have a child with the
+// requested type.
+type ErrChildNotFound struct {
+	// Parent is the path of the box whose children were searched. It is empty
+	// for the resource itself or if it could not be determined.
+	Parent string
+
+	// Offset is the offset of the parent in the resource.
+	Offset int64
+
+	// Name is the box-type that was requested.
+	Name string
+}
+
+// Error returns the message.
+func (ecnf *ErrChildNotFound) Error() string {
+	if ecnf.Parent != "" {
+		return fmt.Sprintf("child box not found: [%s] under [%s]", ecnf.Name, ecnf.Parent)
+	}
+
+	return fmt.Sprintf("child box not found: [%s]", ecnf.Name)
+}
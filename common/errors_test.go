@@ -0,0 +1,166 @@
+package bmfcommon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+)
+
+func TestErrTruncated_Error(t *testing.T) {
+	et := NewErrTruncated(nil, 0x10, 8, 3)
+
+	if et.Box != "" {
+		t.Fatalf("Box not correct.")
+	} else if et.Error() != "read of (8) bytes at offset (0x0000000000000010) is truncated: (3)" {
+		t.Fatalf("Error not correct: [%s]", et.Error())
+	}
+
+	box := NewBox("abcd", 0, 8, 8, nil)
+	et = NewErrTruncated(box, 0x10, 8, 3)
+
+	if et.Box != "abcd" {
+		t.Fatalf("Box not correct.")
+	} else if et.Error() != "box [abcd]: read of (8) bytes at offset (0x0000000000000010) is truncated: (3)" {
+		t.Fatalf("Error not correct: [%s]", et.Error())
+	}
+}
+
+func TestSetTruncatedBox(t *testing.T) {
+	box := NewBox("abcd", 0, 8, 8, nil)
+
+	err := log.Wrap(NewErrTruncated(nil, 0x10, 8, 3))
+	setTruncatedBox(err, box)
+
+	var et *ErrTruncated
+	if errors.As(err, &et) != true {
+		t.Fatalf("Expected ErrTruncated.")
+	} else if et.Box != "abcd" {
+		t.Fatalf("Box not set.")
+	}
+
+	// A box that is already set is kept.
+
+	other := NewBox("efgh", 0, 8, 8, nil)
+	setTruncatedBox(err, other)
+
+	if et.Box != "abcd" {
+		t.Fatalf("Box should not have been replaced.")
+	}
+}
+
+func TestErrUnsupportedVersion_Error(t *testing.T) {
+	box := NewBox("abcd", 0x20, 8, 8, nil)
+
+	euv := NewErrUnsupportedVersion(box, 3, nil)
+	if euv.Offset != 0x20 {
+		t.Fatalf("Offset not correct.")
+	} else if euv.Error() != "box [abcd] version (3) not supported" {
+		t.Fatalf("Error not correct: [%s]", euv.Error())
+	}
+
+	euv = NewErrUnsupportedVersion(box, 3, []byte{0, 1})
+	if euv.Error() != "box [abcd] version (3) not supported: [0 1]" {
+		t.Fatalf("Error not correct: [%s]", euv.Error())
+	}
+}
+
+func TestNewResource_ErrInvalidBoxName(t *testing.T) {
+	var child []byte
+	PushBox(&child, "a\x00bc", nil)
+
+	var b []byte
+	pushTestBox3(&b, child)
+
+	_, err := parseTestLimits(b, true, ParseLimits{})
+
+	var eibn *ErrInvalidBoxName
+	if errors.As(err, &eibn) != true {
+		t.Fatalf("Expected ErrInvalidBoxName: %v", err)
+	} else if eibn.Parent != "tb3 " {
+		t.Fatalf("Parent not correct: [%s]", eibn.Parent)
+	} else if eibn.Offset != 8 {
+		t.Fatalf("Offset not correct: (%d)", eibn.Offset)
+	} else if eibn.Name != "a\x00bc" {
+		t.Fatalf("Name not correct: [%s]", eibn.Name)
+	}
+}
+
+func TestNewResource_ErrTruncated_Child(t *testing.T) {
+	var child []byte
+	pushTestBox2(&child, []byte("abcdefgh"))
+
+	var b []byte
+	pushTestBox3(&b, child[:len(child)-4])
+
+	_, err := parseTestLimits(b, true, ParseLimits{})
+
+	var et *ErrTruncated
+	if errors.As(err, &et) != true {
+		t.Fatalf("Expected ErrTruncated: %v", err)
+	} else if et.Box != "tb3 .tb2 " {
+		t.Fatalf("Box not correct: [%s]", et.Box)
+	} else if et.Offset != 8 {
+		t.Fatalf("Offset not correct: (%d)", et.Offset)
+	} else if et.Size != 16 || et.Available != 12 {
+		t.Fatalf("Sizes not correct: (%d) (%d)", et.Size, et.Available)
+	}
+}
+
+func TestLoadedBoxIndex_GetChildBoxes_ErrChildNotFound(t *testing.T) {
+	var child []byte
+	pushTestBox1(&child)
+
+	var b []byte
+	pushTestBox3(&b, child)
+
+	resource, err := parseTestLimits(b, true, ParseLimits{})
+	log.PanicIf(err)
+
+	children, err := resource.GetChildBoxes("tb3 ")
+	log.PanicIf(err)
+
+	tb3 := children[0].(*testBox3)
+
+	_, err = tb3.GetChildBoxes("tb2 ")
+
+	var ecnf *ErrChildNotFound
+	if errors.As(err, &ecnf) != true {
+		t.Fatalf("Expected ErrChildNotFound: %v", err)
+	} else if ecnf.Parent != "tb3 " {
+		t.Fatalf("Parent not correct: [%s]", ecnf.Parent)
+	} else if ecnf.Offset != 0 {
+		t.Fatalf("Offset not correct: (%d)", ecnf.Offset)
+	} else if ecnf.Name != "tb2 " {
+		t.Fatalf("Name not correct: [%s]", ecnf.Name)
+	} else if err.Error() != "child box not found: [tb2 ] under [tb3 ]" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	// A box without any children still describes itself.
+
+	b = nil
+	pushTestBox3(&b, nil)
+
+	resource, err = parseTestLimits(b, true, ParseLimits{})
+	log.PanicIf(err)
+
+	children, err = resource.GetChildBoxes("tb3 ")
+	log.PanicIf(err)
+
+	_, err = children[0].(*testBox3).GetChildBoxes("tb2 ")
+	if errors.As(err, &ecnf) != true {
+		t.Fatalf("Expected ErrChildNotFound: %v", err)
+	} else if ecnf.Parent != "tb3 " {
+		t.Fatalf("Parent not correct for empty box: [%s]", ecnf.Parent)
+	}
+
+	// At the root, there is no parent.
+
+	_, err = resource.GetChildBoxes("tb2 ")
+	if errors.As(err, &ecnf) != true {
+		t.Fatalf("Expected ErrChildNotFound: %v", err)
+	} else if ecnf.Parent != "" {
+		t.Fatalf("Parent not correct: [%s]", ecnf.Parent)
+	}
+}
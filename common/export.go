@@ -0,0 +1,185 @@
+package bmfcommon
+
+import (
+	"encoding/json"
+
+	"github.com/dsoprea/go-logging"
+)
+
+const (
+	// ExportSchemaVersion is the version of the structure produced by
+	// ExportResource. It is incremented whenever fields are renamed or removed
+	// or their meaning changes. Adding fields does not change it.
+	ExportSchemaVersion = 1
+)
+
+// FieldExporter is implemented by boxes that can describe their parsed fields
+// for structured exports.
+type FieldExporter interface {
+	// ExportFields returns the parsed fields keyed by name. Names are in
+	// snake-case and the values must be serializable as JSON. The version and
+	// flags of full boxes and the children are exported separately and should
+	// not be included.
+	ExportFields() (fields map[string]interface{}, err error)
+}
+
+// ExportedBox is the structured representation of one box. Its JSON encoding
+// is the documented export schema:
+//
+//	type           the box-type (always four characters)
+//	extended_type  the extended type of "uuid" boxes, as a UUID string
+//	offset         the offset of the box in the resource
+//	size           the size of the box including the header
+//	header_size    the size of the header (8, 16, or more for "uuid" boxes)
+//	implicit_size  true if the box was stored with a size of zero
+//	raw            true if the box-type does not have a registered factory
+//	version        the version of full boxes
+//	flags          the flags of full boxes
+//	fields         the type-specific fields (see FieldExporter)
+//	children       the child boxes in the order that they were stored
+//
+// Fields that do not apply are omitted.
+type ExportedBox struct {
+	Type         string                 `json:"type"`
+	ExtendedType string                 `json:"extended_type,omitempty"`
+	Offset       int64                  `json:"offset"`
+	Size         int64                  `json:"size"`
+	HeaderSize   int64                  `json:"header_size"`
+	ImplicitSize bool                   `json:"implicit_size,omitempty"`
+	Raw          bool                   `json:"raw,omitempty"`
+	Version      *byte                  `json:"version,omitempty"`
+	Flags        *uint32                `json:"flags,omitempty"`
+	Fields       map[string]interface{} `json:"fields,omitempty"`
+	Children     []ExportedBox          `json:"children,omitempty"`
+}
+
+// ExportedResource is the structured representation of a whole resource. Its
+// JSON encoding is the root of the documented export schema:
+//
+//	schema_version  ExportSchemaVersion
+//	size            the size of the resource
+//	boxes           the root boxes in the order that they were stored
+//	diagnostics     anything skipped while parsing in lenient mode
+type ExportedResource struct {
+	SchemaVersion int           `json:"schema_version"`
+	Size          int64         `json:"size"`
+	Boxes         []ExportedBox `json:"boxes"`
+	Diagnostics   []Diagnostic  `json:"diagnostics,omitempty"`
+}
+
+// fullBoxer is satisfied by every type that embeds FullBox.
+type fullBoxer interface {
+	Version() byte
+	Flags() uint32
+}
+
+// exportChildren exports the children of the given box in stored order.
+func exportChildren(bci BoxChildIndexer) (exported []ExportedBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	children, err := bci.Children()
+	log.PanicIf(err)
+
+	exported = make([]ExportedBox, len(children))
+	for i, cb := range children {
+		exported[i], err = ExportBox(cb)
+		log.PanicIf(err)
+	}
+
+	return exported, nil
+}
+
+// ExportBox returns the structured representation of the box and everything
+// below it. Lazy children are decoded.
+func ExportBox(cb CommonBox) (eb ExportedBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	box, ok := cb.(encodableBox)
+	if ok == false {
+		log.Panicf("box [%s] can not be exported", cb.Name())
+	}
+
+	eb = ExportedBox{
+		Type:         box.Name(),
+		Offset:       box.Start(),
+		Size:         box.Size(),
+		HeaderSize:   box.HeaderSize(),
+		ImplicitSize: box.IsSizeImplicit(),
+	}
+
+	if box.Name() == UuidBoxName {
+		eb.ExtendedType = box.ExtendedType().String()
+	}
+
+	if _, ok := cb.(*RawBox); ok == true {
+		eb.Raw = true
+	}
+
+	if fb, ok := cb.(fullBoxer); ok == true {
+		version := fb.Version()
+		flags := fb.Flags()
+
+		eb.Version = &version
+		eb.Flags = &flags
+	}
+
+	if fe, ok := cb.(FieldExporter); ok == true {
+		eb.Fields, err = fe.ExportFields()
+		log.PanicIf(err)
+	}
+
+	if bci, ok := cb.(BoxChildIndexer); ok == true {
+		eb.Children, err = exportChildren(bci)
+		log.PanicIf(err)
+	}
+
+	return eb, nil
+}
+
+// ExportResource returns the structured representation of every box in the
+// resource. Lazy boxes are decoded.
+func ExportResource(f *Resource) (er ExportedResource, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	boxes, err := exportChildren(f)
+	log.PanicIf(err)
+
+	er = ExportedResource{
+		SchemaVersion: ExportSchemaVersion,
+		Size:          f.Size(),
+		Boxes:         boxes,
+		Diagnostics:   f.Diagnostics(),
+	}
+
+	return er, nil
+}
+
+// MarshalJSON encodes the resource using the schema described by
+// ExportedResource.
+func (f *Resource) MarshalJSON() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	er, err := ExportResource(f)
+	log.PanicIf(err)
+
+	data, err = json.Marshal(er)
+	log.PanicIf(err)
+
+	return data, nil
+}
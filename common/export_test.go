@@ -0,0 +1,178 @@
+package bmfcommon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+)
+
+// ExportFields returns the parsed fields for structured exports.
+func (tb *testBox2) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"string1": tb.string1,
+		"string2": tb.string2,
+	}
+
+	return fields, nil
+}
+
+func getTestExportResource() *Resource {
+	var children []byte
+	PushBox(&children, "tfb ", []byte{0x01, 0x00, 0x00, 0x05})
+	pushUnknownBox(&children, []byte{1, 2, 3})
+
+	var b []byte
+	pushTestBox2(&b, []byte("abcdefgh"))
+	pushTestBox3(&b, children)
+
+	r := NewRegistry()
+	r.RegisterBoxType(testBox2Factory{})
+	r.RegisterBoxType(testBox3Factory{})
+	r.RegisterBoxType(testFullBoxFactory{})
+
+	options := ParseOptions{
+		Strict:   true,
+		Registry: r,
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResourceWithOptions(sb, int64(len(b)), options)
+	log.PanicIf(err)
+
+	return resource
+}
+
+func TestExportBox(t *testing.T) {
+	resource := getTestExportResource()
+
+	cb, err := resource.Find("tb3 ")
+	log.PanicIf(err)
+
+	eb, err := ExportBox(cb)
+	log.PanicIf(err)
+
+	if eb.Type != "tb3 " || eb.Offset != 16 || eb.Size != 31 || eb.HeaderSize != 8 {
+		t.Fatalf("Box not correct: %v", eb)
+	} else if eb.Version != nil || eb.Flags != nil || eb.Fields != nil {
+		t.Fatalf("Unexpected full-box or fields.")
+	} else if len(eb.Children) != 2 {
+		t.Fatalf("Child count not correct: (%d)", len(eb.Children))
+	}
+
+	tfb := eb.Children[0]
+
+	if tfb.Type != "tfb " || tfb.Offset != 24 {
+		t.Fatalf("Full box not correct: %v", tfb)
+	} else if tfb.Version == nil || *tfb.Version != 1 {
+		t.Fatalf("Version not correct.")
+	} else if tfb.Flags == nil || *tfb.Flags != 5 {
+		t.Fatalf("Flags not correct.")
+	}
+
+	raw := eb.Children[1]
+
+	if raw.Type != "wxyz" || raw.Raw != true || raw.Offset != 36 || raw.Size != 11 {
+		t.Fatalf("Raw box not correct: %v", raw)
+	}
+}
+
+func TestExportResource(t *testing.T) {
+	resource := getTestExportResource()
+
+	er, err := ExportResource(resource)
+	log.PanicIf(err)
+
+	if er.SchemaVersion != ExportSchemaVersion {
+		t.Fatalf("Schema version not correct.")
+	} else if er.Size != 47 {
+		t.Fatalf("Size not correct: (%d)", er.Size)
+	} else if len(er.Boxes) != 2 || er.Boxes[0].Type != "tb2 " || er.Boxes[1].Type != "tb3 " {
+		t.Fatalf("Root boxes not correct.")
+	} else if er.Boxes[0].Fields["string1"] != "abcd" || er.Boxes[0].Fields["string2"] != "efgh" {
+		t.Fatalf("Fields not correct: %v", er.Boxes[0].Fields)
+	}
+}
+
+func TestResource_MarshalJSON(t *testing.T) {
+	resource := getTestExportResource()
+
+	data, err := json.Marshal(resource)
+	log.PanicIf(err)
+
+	var decoded struct {
+		SchemaVersion int `json:"schema_version"`
+		Boxes         []struct {
+			Type     string                 `json:"type"`
+			Fields   map[string]interface{} `json:"fields"`
+			Children []struct {
+				Type    string `json:"type"`
+				Raw     bool   `json:"raw"`
+				Version *int   `json:"version"`
+			} `json:"children"`
+		} `json:"boxes"`
+	}
+
+	err = json.Unmarshal(data, &decoded)
+	log.PanicIf(err)
+
+	if decoded.SchemaVersion != 1 {
+		t.Fatalf("Schema version not correct.")
+	} else if decoded.Boxes[0].Fields["string1"] != "abcd" {
+		t.Fatalf("Fields not correct.")
+	}
+
+	children := decoded.Boxes[1].Children
+
+	if len(children) != 2 {
+		t.Fatalf("Children not correct.")
+	} else if children[0].Version == nil || *children[0].Version != 1 || children[0].Raw != false {
+		t.Fatalf("Full box not correct.")
+	} else if children[1].Type != "wxyz" || children[1].Raw != true || children[1].Version != nil {
+		t.Fatalf("Raw box not correct.")
+	}
+}
+
+func TestExportResource_Diagnostics(t *testing.T) {
+	var b []byte
+	pushTestBox2(&b, []byte("abcd"))
+	pushTestBox1(&b)
+
+	r := NewRegistry()
+	r.RegisterBoxType(testBox1Factory{})
+	r.RegisterBoxType(testBox2Factory{})
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResourceWithOptions(sb, int64(len(b)), ParseOptions{Registry: r})
+	log.PanicIf(err)
+
+	data, err := json.Marshal(resource)
+	log.PanicIf(err)
+
+	var decoded struct {
+		Boxes       []ExportedBox `json:"boxes"`
+		Diagnostics []struct {
+			Offset   int64  `json:"offset"`
+			Path     string `json:"path"`
+			Severity string `json:"severity"`
+		} `json:"diagnostics"`
+	}
+
+	err = json.Unmarshal(data, &decoded)
+	log.PanicIf(err)
+
+	if len(decoded.Boxes) != 1 || decoded.Boxes[0].Type != "tb1 " {
+		t.Fatalf("Boxes not correct: %v", decoded.Boxes)
+	} else if len(decoded.Diagnostics) != 1 {
+		t.Fatalf("Diagnostics not correct: %v", decoded.Diagnostics)
+	}
+
+	d := decoded.Diagnostics[0]
+
+	if d.Offset != 0 || d.Path != "tb2 " || d.Severity != "WARNING" {
+		t.Fatalf("Diagnostic not correct: %v", d)
+	}
+}
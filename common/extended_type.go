@@ -0,0 +1,50 @@
+package bmfcommon
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/dsoprea/go-logging"
+)
+
+const (
+	// UuidBoxName is the box-type of boxes that are identified by an extended
+	// type.
+	UuidBoxName = "uuid"
+)
+
+// ExtendedType is the 16-byte UUID that follows the header of a "uuid" box
+// and identifies its actual type.
+type ExtendedType [16]byte
+
+// ParseExtendedType parses a UUID in its canonical, hyphenated form (e.g.
+// "6d1d9b05-42d5-44e6-80e2-141daff757b2").
+func ParseExtendedType(phrase string) (et ExtendedType, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	raw, err := hex.DecodeString(strings.Replace(phrase, "-", "", -1))
+	log.PanicIf(err)
+
+	if len(raw) != len(et) {
+		log.Panicf("extended-type must be (%d) bytes: [%s]", len(et), phrase)
+	}
+
+	copy(et[:], raw)
+
+	return et, nil
+}
+
+// IsZero returns true if no extended type is set.
+func (et ExtendedType) IsZero() bool {
+	return et == ExtendedType{}
+}
+
+// String returns the UUID in its canonical, hyphenated form.
+func (et ExtendedType) String() string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", et[0:4], et[4:6], et[6:8], et[8:10], et[10:16])
+}
@@ -0,0 +1,56 @@
+package bmfcommon
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+)
+
+func TestParseExtendedType(t *testing.T) {
+	et, err := ParseExtendedType("6d1d9b05-42d5-44e6-80e2-141daff757b2")
+	log.PanicIf(err)
+
+	expected := ExtendedType{
+		0x6d, 0x1d, 0x9b, 0x05, 0x42, 0xd5, 0x44, 0xe6,
+		0x80, 0xe2, 0x14, 0x1d, 0xaf, 0xf7, 0x57, 0xb2,
+	}
+
+	if et != expected {
+		t.Fatalf("Extended-type not correct: %v", et)
+	}
+}
+
+func TestParseExtendedType_WrongSize(t *testing.T) {
+	_, err := ParseExtendedType("6d1d9b05-42d5")
+	if err == nil {
+		t.Fatalf("Expected error.")
+	} else if err.Error() != "extended-type must be (16) bytes: [6d1d9b05-42d5]" {
+		log.Panic(err)
+	}
+}
+
+func TestParseExtendedType_NotHex(t *testing.T) {
+	_, err := ParseExtendedType("zz1d9b05-42d5-44e6-80e2-141daff757b2")
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+}
+
+func TestExtendedType_IsZero(t *testing.T) {
+	if (ExtendedType{}).IsZero() != true {
+		t.Fatalf("Expected zero.")
+	} else if (ExtendedType{1}).IsZero() != false {
+		t.Fatalf("Expected non-zero.")
+	}
+}
+
+func TestExtendedType_String(t *testing.T) {
+	et := ExtendedType{
+		0x6d, 0x1d, 0x9b, 0x05, 0x42, 0xd5, 0x44, 0xe6,
+		0x80, 0xe2, 0x14, 0x1d, 0xaf, 0xf7, 0x57, 0xb2,
+	}
+
+	if et.String() != "6d1d9b05-42d5-44e6-80e2-141daff757b2" {
+		t.Fatalf("String() not correct: [%s]", et.String())
+	}
+}
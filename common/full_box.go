@@ -0,0 +1,81 @@
+package bmfcommon
+
+import (
+	"github.com/dsoprea/go-logging"
+)
+
+const (
+	// FullBoxHeaderSize is the size of the version and flags that begin the
+	// payload of a full box.
+	FullBoxHeaderSize = 4
+)
+
+// FullBox is embedded by boxes whose payload begins with a one-byte version
+// and 24-bit flags (a "FullBox" in ISO 14496-12).
+type FullBox struct {
+	version byte
+	flags   uint32
+}
+
+// NewFullBox returns a new FullBox struct. Only the lower 24 bits of the flags
+// are kept.
+func NewFullBox(version byte, flags uint32) FullBox {
+	return FullBox{
+		version: version,
+		flags:   flags & 0x00ffffff,
+	}
+}
+
+// Version returns the version of the box.
+func (fb FullBox) Version() byte {
+	return fb.version
+}
+
+// Flags returns the 24-bit flags of the box.
+func (fb FullBox) Flags() uint32 {
+	return fb.flags
+}
+
+// HasFlag returns true if all of the given flag bits are set.
+func (fb FullBox) HasFlag(flag uint32) bool {
+	return fb.flags&flag == flag
+}
+
+// FullBoxFactory is implemented by the factories of full boxes.
+type FullBoxFactory interface {
+	BoxFactory
+
+	// SupportedVersions returns the versions that the factory can parse.
+	SupportedVersions() []byte
+}
+
+// ReadFullBox reads the version and flags that begin the payload of the given
+// box and checks the version against those supported by the factory.
+func ReadFullBox(box Box, fbf FullBoxFactory) (fb FullBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if box.Size()-box.HeaderSize() < FullBoxHeaderSize {
+		log.Panic(NewErrTruncated(box, box.Start()+box.HeaderSize(), FullBoxHeaderSize, box.Size()-box.HeaderSize()))
+	}
+
+	raw, err := box.ReadBytesAt(box.Start()+box.HeaderSize(), FullBoxHeaderSize)
+	log.PanicIf(err)
+
+	fb = NewFullBox(raw[0], DefaultEndianness.Uint32(raw))
+
+	supportedVersions := fbf.SupportedVersions()
+	for _, version := range supportedVersions {
+		if version == fb.version {
+			return fb, nil
+		}
+	}
+
+	log.Panic(NewErrUnsupportedVersion(box, fb.version, supportedVersions))
+
+	// Never reached.
+	return fb, nil
+}
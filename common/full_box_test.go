@@ -0,0 +1,153 @@
+package bmfcommon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+)
+
+// testFullBox is a full box without any other fields.
+type testFullBox struct {
+	Box
+	FullBox
+}
+
+type testFullBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (testFullBoxFactory) Name() string {
+	return "tfb "
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (testFullBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
+// New returns a new value instance.
+func (bf testFullBoxFactory) New(box Box) (cb CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	tfb := &testFullBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	return tfb, -1, nil
+}
+
+func TestNewFullBox(t *testing.T) {
+	fb := NewFullBox(0x11, 0x22334455)
+
+	if fb.Version() != 0x11 {
+		t.Fatalf("Version() not correct.")
+	} else if fb.Flags() != 0x334455 {
+		t.Fatalf("Flags() not correct: (0x%08x)", fb.Flags())
+	}
+}
+
+func TestFullBox_HasFlag(t *testing.T) {
+	fb := NewFullBox(0, 0x000005)
+
+	if fb.HasFlag(0x000001) != true {
+		t.Fatalf("Expected flag (1).")
+	} else if fb.HasFlag(0x000005) != true {
+		t.Fatalf("Expected flags (5).")
+	} else if fb.HasFlag(0x000002) != false {
+		t.Fatalf("Expected no flag (2).")
+	} else if fb.HasFlag(0x000003) != false {
+		t.Fatalf("Expected not all of flags (3).")
+	}
+}
+
+func TestReadFullBox(t *testing.T) {
+	var b []byte
+	PushBox(&b, "tfb ", []byte{0x01, 0x22, 0x33, 0x44})
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResource(sb, 0)
+	log.PanicIf(err)
+
+	box, err := resource.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	fb, err := ReadFullBox(box, testFullBoxFactory{})
+	log.PanicIf(err)
+
+	if fb.Version() != 1 {
+		t.Fatalf("Version() not correct.")
+	} else if fb.Flags() != 0x223344 {
+		t.Fatalf("Flags() not correct: (0x%08x)", fb.Flags())
+	}
+}
+
+func TestReadFullBox_UnsupportedVersion(t *testing.T) {
+	var child []byte
+	PushBox(&child, "tfb ", []byte{0x02, 0x00, 0x00, 0x00})
+
+	var b []byte
+	PushBox(&b, "tb3 ", child)
+
+	r := NewRegistry()
+	r.RegisterBoxType(testBox3Factory{})
+	r.RegisterBoxType(testFullBoxFactory{})
+
+	options := ParseOptions{
+		Strict:   true,
+		Registry: r,
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	_, err := NewResourceWithOptions(sb, int64(len(b)), options)
+	if err == nil {
+		t.Fatalf("Expected error for unsupported version.")
+	} else if err.Error() != "box [tb3 .tfb ] version (2) not supported: [0 1]" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	var euv *ErrUnsupportedVersion
+	if errors.As(err, &euv) != true {
+		t.Fatalf("Expected ErrUnsupportedVersion.")
+	} else if euv.Box != "tb3 .tfb " || euv.Offset != 8 || euv.Version != 2 {
+		t.Fatalf("ErrUnsupportedVersion not correct: %v", euv)
+	}
+}
+
+func TestReadFullBox_TooSmall(t *testing.T) {
+	var b []byte
+	PushBox(&b, "tfb ", []byte{0x00, 0x00})
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResource(sb, 0)
+	log.PanicIf(err)
+
+	box, err := resource.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	_, err = ReadFullBox(box, testFullBoxFactory{})
+	if err == nil {
+		t.Fatalf("Expected error for truncated box.")
+	} else if err.Error() != "box [tfb ]: read of (4) bytes at offset (0x0000000000000008) is truncated: (2)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	var et *ErrTruncated
+	if errors.As(err, &et) != true {
+		t.Fatalf("Expected ErrTruncated.")
+	} else if et.Box != "tfb " {
+		t.Fatalf("Box not correct: [%s]", et.Box)
+	}
+}
@@ -47,6 +47,12 @@ func (fbi FullBoxIndex) getBoxName(cb CommonBox) (fqbn FullyQualifiedBoxName) {
 	return fqbn
 }
 
+// boxPath returns the name of the box fully-qualified with its parents in
+// dotted notation.
+func boxPath(cb CommonBox) string {
+	return FullBoxIndex(nil).getBoxName(cb).String()
+}
+
 // Add adds one CommonBox to the index.
 func (fbi FullBoxIndex) Add(cb CommonBox) {
 	name := fbi.getBoxName(cb)
@@ -90,24 +96,94 @@ func (fbi FullBoxIndex) Dump() {
 type LoadedBoxIndex map[string][]CommonBox
 
 // GetChildBoxes returns the given child box or panics. If box does not support
-// children this should return ErrNoChildren.
+// children this should return ErrNoChildren. Lazy children are decoded. In
+// lenient mode, children that fail to decode are left out, as they would have
+// been if they were parsed eagerly. The index does not know its owner, so
+// boxes with children override this to call GetChildBoxesOf() with themselves.
 func (lbi LoadedBoxIndex) GetChildBoxes(name string) (boxes []CommonBox, err error) {
+	return lbi.GetChildBoxesOf(nil, name)
+}
+
+// GetChildBoxesOf is GetChildBoxes() for the index of the given box. The box
+// is described by the ErrChildNotFound if there are no such children. It is
+// nil for the root.
+func (lbi LoadedBoxIndex) GetChildBoxesOf(owner CommonBox, name string) (boxes []CommonBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	boxes, err = lbi.decodedChildBoxes(name)
+	log.PanicIf(err)
+
+	if len(boxes) == 0 {
+		log.Panic(childNotFound(owner, name))
+	}
+
+	return boxes, nil
+}
+
+// decodedChildBoxes returns the children of the given type, if any, with lazy
+// boxes decoded and swapped for their decoded counterparts. In lenient mode,
+// children that fail to decode are skipped. The failure is recorded as a
+// diagnostic by the decode.
+func (lbi LoadedBoxIndex) decodedChildBoxes(name string) (boxes []CommonBox, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
-	boxes, found := lbi[name]
-	if found == false {
-		log.Panicf("child box not found: [%s]", name)
+	stored := lbi[name]
+	boxes = make([]CommonBox, 0, len(stored))
+
+	for i, cb := range stored {
+		if lb, ok := cb.(*LazyBox); ok == true {
+			decoded, err := lb.Decode()
+			if err != nil {
+				if lb.resource.options.Strict == true {
+					log.Panic(err)
+				}
+
+				continue
+			}
+
+			stored[i] = decoded
+			cb = decoded
+		}
+
+		boxes = append(boxes, cb)
 	}
 
 	return boxes, nil
 }
 
-// ChildrenTypes returns a slice with the names of all children with registered
-// types.
+// childNotFound returns an ErrChildNotFound for the given name under the
+// given box, which is nil for the root.
+func childNotFound(owner CommonBox, name string) *ErrChildNotFound {
+	ecnf := &ErrChildNotFound{
+		Name: name,
+	}
+
+	if owner != nil {
+		ecnf.Parent = boxPath(owner)
+
+		if bs, ok := owner.(boxStarter); ok == true {
+			ecnf.Offset = bs.Start()
+		}
+	}
+
+	return ecnf
+}
+
+// loadedBoxIndex returns the index without decoding anything.
+func (lbi LoadedBoxIndex) loadedBoxIndex() LoadedBoxIndex {
+	return lbi
+}
+
+// ChildrenTypes returns a slice with the names of the types of all children,
+// sorted by name.
 func (lbi LoadedBoxIndex) ChildrenTypes() (names []string) {
 	names = make([]string, len(lbi))
 	i := 0
@@ -129,3 +205,39 @@ type ChildBoxIndexSetter interface {
 	// registered before the child boxes can look for them.
 	SetLoadedBoxIndex(boxes Boxes)
 }
+
+// Children returns all children in the order that they were stored, including
+// RawBox boxes for types without a registered factory. Lazy children are
+// decoded and, in lenient mode, those that fail are left out.
+func (lbi LoadedBoxIndex) Children() (children []CommonBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	children = make([]CommonBox, 0)
+
+	for _, name := range lbi.ChildrenTypes() {
+		boxes, err := lbi.decodedChildBoxes(name)
+		log.PanicIf(err)
+
+		children = append(children, boxes...)
+	}
+
+	sortBoxesByStart(children)
+
+	return children, nil
+}
+
+// boxStarter is satisfied by every type that embeds Box.
+type boxStarter interface {
+	Start() int64
+}
+
+// sortBoxesByStart sorts the boxes in the order that they were stored.
+func sortBoxesByStart(boxes []CommonBox) {
+	sort.SliceStable(boxes, func(i, j int) bool {
+		return boxes[i].(boxStarter).Start() < boxes[j].(boxStarter).Start()
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
 )
 
 func TestFullyQualifiedBoxName_String(t *testing.T) {
@@ -161,3 +162,38 @@ func TestLoadedBoxIndex_ChildrenTypes(t *testing.T) {
 		t.Fatalf("Box types are not correct: [%v]", names)
 	}
 }
+
+func TestLoadedBoxIndex_Children(t *testing.T) {
+	defer ClearRegistrations()
+
+	ClearRegistrations()
+	RegisterBoxType(testBox1Factory{})
+	RegisterBoxType(testBox2Factory{})
+
+	var b []byte
+	pushTestBox2(&b, []byte("abcdefgh"))
+	pushUnknownBox(&b, []byte{1, 2, 3})
+	pushTestBox1(&b)
+	pushTestBox2(&b, []byte("ijklmnop"))
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	children, err := resource.Children()
+	log.PanicIf(err)
+
+	names := make([]string, len(children))
+	for i, cb := range children {
+		names[i] = cb.Name()
+	}
+
+	expected := []string{"tb2 ", "wxyz", "tb1 ", "tb2 "}
+
+	if reflect.DeepEqual(names, expected) != true {
+		t.Fatalf("Children not correct or not ordered: %v", names)
+	} else if children[3].(*testBox2).String1() != "ijkl" {
+		t.Fatalf("Last child not correct.")
+	}
+}
@@ -0,0 +1,89 @@
+package bmfcommon
+
+import (
+	"github.com/dsoprea/go-logging"
+)
+
+// LazyBox stands in for a box whose factory has not run yet. It is only
+// produced when parsing with ParseOptions.Lazy. LoadedBoxIndex.GetChildBoxes()
+// decodes and replaces these transparently.
+type LazyBox struct {
+	// Box is the base box.
+	Box
+
+	factory BoxFactory
+
+	// siblings and position locate the box among its siblings so that the
+	// preceding siblings can be decoded first. Factories rely on earlier
+	// boxes having been loaded.
+	siblings Boxes
+	position int
+
+	decoded   CommonBox
+	decodeErr error
+}
+
+// IsDecoded returns true if the box has been decoded.
+func (lb *LazyBox) IsDecoded() bool {
+	return lb.decoded != nil
+}
+
+// Decode runs the factory for the box, if it has not already been run, and
+// returns the type-specific box. The children of the box will also be lazy.
+// Failures are returned even in lenient mode, where they are also recorded as
+// diagnostics.
+func (lb *LazyBox) Decode() (cb CommonBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if lb.decoded != nil {
+		return lb.decoded, nil
+	} else if lb.decodeErr != nil {
+		return nil, lb.decodeErr
+	}
+
+	f := lb.resource
+
+	f.decodingDepth++
+
+	defer func() {
+		f.decodingDepth--
+	}()
+
+	for _, sibling := range lb.siblings[:lb.position] {
+		if lazySibling, ok := sibling.(*LazyBox); ok == true {
+			_, err := lazySibling.Decode()
+
+			// In lenient mode, a sibling that fails is treated as absent, as
+			// it would be when parsing eagerly. It was already recorded.
+			if err != nil && f.options.Strict == true {
+				log.Panic(err)
+			}
+		}
+	}
+
+	cb, err = decodeBox(f, lb.Box, lb.factory)
+	if err != nil {
+		lb.decodeErr = err
+
+		// The error is returned either way but is also noted in lenient
+		// mode so that it shows with the rest.
+		if f.options.Strict == false {
+			f.addDiagnostic(lb.Start(), lb.Parent(), lb.Name(), DiagnosticSeverityWarning, err)
+		}
+
+		log.Panic(err)
+	}
+
+	lb.decoded = cb
+	f.fullBoxIndexOrdered = false
+
+	return cb, nil
+}
+
+var (
+	_ CommonBox = &LazyBox{}
+)
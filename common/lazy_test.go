@@ -0,0 +1,318 @@
+package bmfcommon
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+)
+
+// testCountingBoxFactory counts how many boxes it has manufactured.
+type testCountingBoxFactory struct {
+	testEncoderBoxFactory
+
+	count *int
+}
+
+// New returns a new value instance.
+func (tcbf testCountingBoxFactory) New(box Box) (cb CommonBox, childBoxSeriesOffset int, err error) {
+	*tcbf.count++
+	return tcbf.testEncoderBoxFactory.New(box)
+}
+
+func getTestLazyResource() (original []byte, resource *Resource, count *int) {
+	ClearRegistrations()
+
+	count = new(int)
+
+	RegisterBoxType(testBox3Factory{})
+	RegisterBoxType(testCountingBoxFactory{count: count})
+
+	var children []byte
+	PushBox(&children, "tenc", []byte("ghi"))
+	pushUnknownBox(&children, []byte{1, 2, 3})
+
+	var b []byte
+	PushBox(&b, "tenc", []byte("abc"))
+	PushBox(&b, "tenc", []byte("def"))
+	pushTestBox3(&b, children)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResourceWithOptions(sb, int64(len(b)), ParseOptions{Lazy: true})
+	log.PanicIf(err)
+
+	return b, resource, count
+}
+
+func TestLazyBox_Decode(t *testing.T) {
+	defer ClearRegistrations()
+
+	_, resource, count := getTestLazyResource()
+
+	if *count != 0 {
+		t.Fatalf("No boxes should have been decoded: (%d)", *count)
+	}
+
+	lb := resource.LoadedBoxIndex["tenc"][1].(*LazyBox)
+
+	if lb.IsDecoded() != false {
+		t.Fatalf("IsDecoded() not correct.")
+	}
+
+	cb, err := lb.Decode()
+	log.PanicIf(err)
+
+	if cb.(*testEncoderBox).value != "def" {
+		t.Fatalf("Decoded box not correct.")
+	} else if lb.IsDecoded() != true {
+		t.Fatalf("IsDecoded() not correct.")
+	}
+
+	// The preceding sibling is decoded first.
+	if *count != 2 {
+		t.Fatalf("Decode count not correct: (%d)", *count)
+	}
+
+	// The result is cached.
+
+	cb2, err := lb.Decode()
+	log.PanicIf(err)
+
+	if cb2 != cb {
+		t.Fatalf("Decoded box was not cached.")
+	} else if *count != 2 {
+		t.Fatalf("Decode count not correct after second decode: (%d)", *count)
+	}
+}
+
+func TestLoadedBoxIndex_GetChildBoxes_Lazy(t *testing.T) {
+	defer ClearRegistrations()
+
+	_, resource, count := getTestLazyResource()
+
+	boxes, err := resource.GetChildBoxes("tb3 ")
+	log.PanicIf(err)
+
+	tb3 := boxes[0].(*testBox3)
+
+	if *count != 2 {
+		t.Fatalf("Decode count not correct: (%d)", *count)
+	}
+
+	children, err := tb3.GetChildBoxes("tenc")
+	log.PanicIf(err)
+
+	if children[0].(*testEncoderBox).value != "ghi" {
+		t.Fatalf("Child not correct.")
+	} else if *count != 3 {
+		t.Fatalf("Decode count not correct: (%d)", *count)
+	}
+
+	// The lazy boxes were swapped in the index.
+
+	if _, ok := tb3.LoadedBoxIndex["tenc"][0].(*testEncoderBox); ok != true {
+		t.Fatalf("Lazy box was not replaced.")
+	}
+}
+
+func TestResource_Index_Lazy(t *testing.T) {
+	defer ClearRegistrations()
+
+	original, resource, count := getTestLazyResource()
+
+	// Decode out of order.
+
+	boxes, err := resource.GetChildBoxes("tb3 ")
+	log.PanicIf(err)
+
+	_, err = boxes[0].(*testBox3).GetChildBoxes("tenc")
+	log.PanicIf(err)
+
+	lazyIndex := resource.Index()
+
+	if *count != 3 {
+		t.Fatalf("Decode count not correct: (%d)", *count)
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(original)
+
+	eagerResource, err := NewResource(sb, int64(len(original)))
+	log.PanicIf(err)
+
+	eagerIndex := eagerResource.Index()
+
+	if len(lazyIndex) != len(eagerIndex) {
+		t.Fatalf("Index size not correct: (%d) != (%d)", len(lazyIndex), len(eagerIndex))
+	}
+
+	for ibe, eagerCb := range eagerIndex {
+		lazyCb, found := lazyIndex[ibe]
+		if found != true {
+			t.Fatalf("Box not found in lazy index: %s", ibe)
+		} else if lazyCb.Name() != eagerCb.Name() {
+			t.Fatalf("Box not correct: [%s] != [%s]", lazyCb.Name(), eagerCb.Name())
+		} else if reflect.TypeOf(lazyCb) != reflect.TypeOf(eagerCb) {
+			t.Fatalf("Box type not correct: %v != %v", reflect.TypeOf(lazyCb), reflect.TypeOf(eagerCb))
+		}
+
+		lazyData, err := lazyCb.Data()
+		log.PanicIf(err)
+
+		eagerData, err := eagerCb.Data()
+		log.PanicIf(err)
+
+		if bytes.Equal(lazyData, eagerData) != true {
+			t.Fatalf("Box data not correct: %s", ibe)
+		}
+	}
+}
+
+func TestResource_Encode_Lazy(t *testing.T) {
+	defer ClearRegistrations()
+
+	original, resource, count := getTestLazyResource()
+
+	boxes, err := resource.GetChildBoxes("tenc")
+	log.PanicIf(err)
+
+	boxes[0].(*testEncoderBox).value = "xyz"
+
+	b := new(bytes.Buffer)
+
+	err = resource.Encode(b)
+	log.PanicIf(err)
+
+	expected := make([]byte, len(original))
+	copy(expected, original)
+	copy(expected[8:11], "xyz")
+
+	if bytes.Equal(b.Bytes(), expected) != true {
+		t.Fatalf("Encoded resource not correct:\nACTUAL: %v\nEXPECTED: %v", b.Bytes(), expected)
+	}
+
+	// The undecoded boxes were copied through.
+	if *count != 2 {
+		t.Fatalf("Decode count not correct: (%d)", *count)
+	}
+}
+
+func TestResource_Index_Lazy_Undecoded(t *testing.T) {
+	defer ClearRegistrations()
+
+	_, resource, count := getTestLazyResource()
+
+	index := resource.Index()
+
+	// The unknown box is included as a RawBox.
+	if len(index) != 5 {
+		t.Fatalf("Index size not correct: (%d)", len(index))
+	} else if *count != 3 {
+		t.Fatalf("Decode count not correct: (%d)", *count)
+	}
+}
+
+func TestLazyBox_Decode_Lenient(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox2Factory{})
+
+	// TB2 boxes need eight bytes.
+
+	var b []byte
+	pushTestBox2(&b, []byte{1, 2})
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResourceWithOptions(sb, int64(len(b)), ParseOptions{Lazy: true})
+	log.PanicIf(err)
+
+	if len(resource.Diagnostics()) != 0 {
+		t.Fatalf("Nothing should have been decoded yet.")
+	}
+
+	lb := resource.LoadedBoxIndex["tb2 "][0].(*LazyBox)
+
+	for i := 0; i < 2; i++ {
+		_, err = lb.Decode()
+		if err == nil {
+			t.Fatalf("Expected error.")
+		}
+	}
+
+	diagnostics := resource.Diagnostics()
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected one diagnostic: %v", diagnostics)
+	} else if diagnostics[0].Path != "tb2 " {
+		t.Fatalf("Diagnostic not correct: %s", diagnostics[0])
+	}
+}
+
+func TestResource_Index_Lazy_Lenient(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox2Factory{})
+	RegisterBoxType(testEncoderBoxFactory{})
+
+	// TB2 boxes need eight bytes, so the first one fails.
+
+	var b []byte
+	pushTestBox2(&b, []byte{1, 2})
+	PushBox(&b, "tenc", []byte("abc"))
+	pushTestBox2(&b, []byte("abcdefgh"))
+
+	eagerResource, err := NewResourceWithOptions(rifs.NewSeekableBufferWithBytes(b), int64(len(b)), ParseOptions{})
+	log.PanicIf(err)
+
+	resource, err := NewResourceWithOptions(rifs.NewSeekableBufferWithBytes(b), int64(len(b)), ParseOptions{Lazy: true})
+	log.PanicIf(err)
+
+	index := resource.Index()
+
+	if len(index) != len(eagerResource.Index()) {
+		t.Fatalf("Index does not match eager parsing: (%d) != (%d)", len(index), len(eagerResource.Index()))
+	} else if len(index) != 2 {
+		t.Fatalf("Index size not correct: (%d)", len(index))
+	}
+
+	tb2 := index[IndexedBoxEntry{NamePhrase: "tb2 ", SequenceNumber: 0}]
+	if tb2.(*testBox2).Start() != 21 {
+		t.Fatalf("Wrong box indexed.")
+	}
+
+	diagnostics := resource.Diagnostics()
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected one diagnostic: %v", diagnostics)
+	} else if diagnostics[0].Severity != eagerResource.Diagnostics()[0].Severity {
+		t.Fatalf("Diagnostic does not match eager parsing: %s", diagnostics[0])
+	}
+
+	err = resource.DecodeAll()
+	log.PanicIf(err)
+}
+
+func TestResource_DecodeAll_Strict(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox2Factory{})
+
+	var b []byte
+	pushTestBox2(&b, []byte{1, 2})
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResourceWithOptions(sb, int64(len(b)), ParseOptions{Lazy: true, Strict: true})
+	log.PanicIf(err)
+
+	err = resource.DecodeAll()
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+}
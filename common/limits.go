@@ -0,0 +1,142 @@
+package bmfcommon
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+)
+
+var (
+	// ErrLimitExceeded is matched by every LimitError.
+	ErrLimitExceeded = errors.New("parse limit exceeded")
+)
+
+// ParseLimits bounds the resources that parsing may consume so that untrusted
+// input can not cause huge allocations or unbounded recursion. A field of zero
+// uses the value from DefaultParseLimits and a negative field disables that
+// limit.
+type ParseLimits struct {
+	// MaxPayloadSize is the largest number of bytes that will be read into
+	// memory at once (e.g. by Data()). Memory-mapped resources do not copy and
+	// are not subject to it.
+	MaxPayloadSize int64
+
+	// MaxDepth is the deepest that boxes may be nested. Root boxes have a depth
+	// of one.
+	MaxDepth int
+
+	// MaxBoxes is the largest number of boxes that will be read from one
+	// resource. Exceeding it fails the parse even in lenient mode.
+	MaxBoxes int
+
+	// MaxTableEntries is the largest number of entries that a box may declare
+	// for one of its tables (e.g. the sample-sizes in "stsz").
+	MaxTableEntries int
+}
+
+var (
+	// DefaultParseLimits are the limits used for any that are not set.
+	DefaultParseLimits = ParseLimits{
+		MaxPayloadSize:  256 * 1024 * 1024,
+		MaxDepth:        32,
+		MaxBoxes:        1000000,
+		MaxTableEntries: 16 * 1024 * 1024,
+	}
+)
+
+// withDefaults returns a copy with the unset limits filled from
+// DefaultParseLimits.
+func (pl ParseLimits) withDefaults() ParseLimits {
+	if pl.MaxPayloadSize == 0 {
+		pl.MaxPayloadSize = DefaultParseLimits.MaxPayloadSize
+	}
+
+	if pl.MaxDepth == 0 {
+		pl.MaxDepth = DefaultParseLimits.MaxDepth
+	}
+
+	if pl.MaxBoxes == 0 {
+		pl.MaxBoxes = DefaultParseLimits.MaxBoxes
+	}
+
+	if pl.MaxTableEntries == 0 {
+		pl.MaxTableEntries = DefaultParseLimits.MaxTableEntries
+	}
+
+	return pl
+}
+
+// LimitError describes a ParseLimits value that was exceeded.
+type LimitError struct {
+	// Limit is the name of the ParseLimits field.
+	Limit string
+
+	// Value is what the input required.
+	Value int64
+
+	// Max is the configured limit.
+	Max int64
+}
+
+// Error returns the message.
+func (le *LimitError) Error() string {
+	return fmt.Sprintf("%s exceeded: (%d) > (%d)", le.Limit, le.Value, le.Max)
+}
+
+// Is returns true for ErrLimitExceeded.
+func (le *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// checkLimit returns a LimitError if the value is over a limit that is
+// enabled.
+func checkLimit(limit string, value int64, max int64) error {
+	if max >= 0 && value > max {
+		return &LimitError{
+			Limit: limit,
+			Value: value,
+			Max:   max,
+		}
+	}
+
+	return nil
+}
+
+// limits returns the effective limits for the resource.
+func (f *Resource) limits() ParseLimits {
+	return f.options.Limits.withDefaults()
+}
+
+// CheckEntryCount returns an error if a table in the box declares more
+// entries than allowed by MaxTableEntries or, as an ErrTruncated, than can fit
+// in the box given the smallest size of one entry. `tableOffset` is where the
+// entries start in the payload, after any fixed fields. Factories must call
+// this before allocating for a count read from the box. `minEntrySize` may be
+// zero if entries can be empty.
+func (box Box) CheckEntryCount(table string, count int64, tableOffset int64, minEntrySize int64) (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	limits := DefaultParseLimits
+	if box.resource != nil {
+		limits = box.resource.limits()
+	}
+
+	err = checkLimit("MaxTableEntries", count, int64(limits.MaxTableEntries))
+	log.PanicIf(err)
+
+	tableSize := box.Size() - box.HeaderSize() - tableOffset
+	if tableSize < 0 {
+		tableSize = 0
+	}
+
+	if minEntrySize > 0 && count > tableSize/minEntrySize {
+		log.Panic(NewErrTruncated(box, box.Start()+box.HeaderSize()+tableOffset, count*minEntrySize, tableSize))
+	}
+
+	return nil
+}
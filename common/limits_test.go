@@ -0,0 +1,287 @@
+package bmfcommon
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+)
+
+// parseTestLimits parses the bytes with the test boxes and the given limits.
+func parseTestLimits(b []byte, strict bool, limits ParseLimits) (resource *Resource, err error) {
+	r := NewRegistry()
+	r.RegisterBoxType(testBox1Factory{})
+	r.RegisterBoxType(testBox2Factory{})
+	r.RegisterBoxType(testBox3Factory{})
+
+	options := ParseOptions{
+		Strict:   strict,
+		Registry: r,
+		Limits:   limits,
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	return NewResourceWithOptions(sb, int64(len(b)), options)
+}
+
+func TestParseLimits_withDefaults(t *testing.T) {
+	pl := ParseLimits{
+		MaxDepth: -1,
+		MaxBoxes: 5,
+	}
+
+	pl = pl.withDefaults()
+
+	if pl.MaxPayloadSize != DefaultParseLimits.MaxPayloadSize {
+		t.Fatalf("MaxPayloadSize not defaulted.")
+	} else if pl.MaxDepth != -1 {
+		t.Fatalf("MaxDepth not kept.")
+	} else if pl.MaxBoxes != 5 {
+		t.Fatalf("MaxBoxes not kept.")
+	} else if pl.MaxTableEntries != DefaultParseLimits.MaxTableEntries {
+		t.Fatalf("MaxTableEntries not defaulted.")
+	}
+}
+
+func TestLimitError(t *testing.T) {
+	err := checkLimit("MaxDepth", 3, 2)
+	if err == nil {
+		t.Fatalf("Expected error.")
+	} else if err.Error() != "MaxDepth exceeded: (3) > (2)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	} else if errors.Is(err, ErrLimitExceeded) != true {
+		t.Fatalf("Expected error to match ErrLimitExceeded.")
+	}
+
+	if checkLimit("MaxDepth", 2, 2) != nil {
+		t.Fatalf("Expected no error at the limit.")
+	} else if checkLimit("MaxDepth", 100, -1) != nil {
+		t.Fatalf("Expected no error for a disabled limit.")
+	}
+}
+
+func TestBox_CheckEntryCount(t *testing.T) {
+	limits := ParseLimits{
+		MaxTableEntries: 10,
+	}
+
+	resource, err := parseTestLimits(nil, true, limits)
+	log.PanicIf(err)
+
+	box := NewBox("abcd", 0, 48, 8, resource)
+
+	err = box.CheckEntryCount("entries", 10, 0, 4)
+	log.PanicIf(err)
+
+	err = box.CheckEntryCount("entries", 11, 0, 0)
+	if err == nil {
+		t.Fatalf("Expected limit error.")
+	} else if err.Error() != "MaxTableEntries exceeded: (11) > (10)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	err = box.CheckEntryCount("entries", 6, 0, 8)
+	if err == nil {
+		t.Fatalf("Expected error for entries that can not fit.")
+	} else if err.Error() != "box [abcd]: read of (48) bytes at offset (0x0000000000000008) is truncated: (40)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	var et *ErrTruncated
+	if errors.As(err, &et) != true {
+		t.Fatalf("Expected ErrTruncated.")
+	}
+
+	// Ten entries of four bytes fit in the payload but not after an
+	// eight-byte prefix.
+
+	err = box.CheckEntryCount("entries", 8, 8, 4)
+	log.PanicIf(err)
+
+	err = box.CheckEntryCount("entries", 9, 8, 4)
+	if err == nil {
+		t.Fatalf("Expected error for entries that can not fit after the prefix.")
+	} else if errors.As(err, &et) != true {
+		t.Fatalf("Expected ErrTruncated for the prefix.")
+	} else if et.Offset != 16 || et.Available != 32 {
+		t.Fatalf("Truncation not correct: %v", et)
+	}
+
+	// A prefix that is larger than the payload leaves no room.
+
+	err = box.CheckEntryCount("entries", 1, 48, 4)
+	if err == nil {
+		t.Fatalf("Expected error for a prefix beyond the payload.")
+	}
+
+	// Boxes without a resource use the default limits.
+
+	box = NewBox("abcd", 0, 8, 8, nil)
+
+	err = box.CheckEntryCount("entries", int64(DefaultParseLimits.MaxTableEntries)+1, 0, 0)
+	if err == nil {
+		t.Fatalf("Expected default limit to apply.")
+	}
+}
+
+func TestNewResourceWithOptions_MaxDepth(t *testing.T) {
+	var inner []byte
+	pushTestBox1(&inner)
+
+	var middle []byte
+	pushTestBox3(&middle, inner)
+
+	var b []byte
+	pushTestBox3(&b, middle)
+	pushTestBox1(&b)
+
+	limits := ParseLimits{
+		MaxDepth: 2,
+	}
+
+	_, err := parseTestLimits(b, true, limits)
+	if err == nil {
+		t.Fatalf("Expected depth error.")
+	} else if err.Error() != "MaxDepth exceeded: (3) > (2)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	// In lenient mode, only the box that is too deep is skipped.
+
+	resource, err := parseTestLimits(b, false, limits)
+	log.PanicIf(err)
+
+	diagnostics := resource.Diagnostics()
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected one diagnostic: %v", diagnostics)
+	} else if diagnostics[0].Path != "tb3 .tb3 .tb1 " {
+		t.Fatalf("Diagnostic not correct: %v", diagnostics[0])
+	}
+
+	children, err := resource.Children()
+	log.PanicIf(err)
+
+	if len(children) != 2 {
+		t.Fatalf("Expected root boxes to be kept.")
+	}
+}
+
+func TestNewResourceWithOptions_MaxBoxes(t *testing.T) {
+	var b []byte
+	pushTestBox1(&b)
+	pushTestBox1(&b)
+	pushTestBox1(&b)
+
+	limits := ParseLimits{
+		MaxBoxes: 2,
+	}
+
+	// This fails even in lenient mode.
+
+	_, err := parseTestLimits(b, false, limits)
+	if err == nil {
+		t.Fatalf("Expected box-count error.")
+	} else if err.Error() != "MaxBoxes exceeded: (3) > (2)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	limits.MaxBoxes = 3
+
+	_, err = parseTestLimits(b, false, limits)
+	log.PanicIf(err)
+}
+
+func TestBox_Data_MaxPayloadSize(t *testing.T) {
+	var b []byte
+	pushTestBox2(&b, []byte("abcdefgh"))
+
+	limits := ParseLimits{
+		MaxPayloadSize: 4,
+	}
+
+	// The factory reads the whole payload.
+
+	_, err := parseTestLimits(b, true, limits)
+	if err == nil {
+		t.Fatalf("Expected payload error.")
+	} else if err.Error() != "MaxPayloadSize exceeded: (8) > (4)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	limits.MaxPayloadSize = 8
+
+	_, err = parseTestLimits(b, true, limits)
+	log.PanicIf(err)
+}
+
+func TestResource_readBytesAt_PastSize(t *testing.T) {
+	b := []byte{1, 2, 3, 4}
+
+	resource, err := parseTestLimits(nil, true, ParseLimits{})
+	log.PanicIf(err)
+
+	resource.ra = bytes.NewReader(b)
+	resource.size = int64(len(b))
+
+	// This must fail before allocating anything.
+
+	_, err = resource.readBytesAt(2, 1<<40)
+	if err == nil {
+		t.Fatalf("Expected error.")
+	} else if err.Error() != "read of (1099511627776) bytes at offset (0x0000000000000002) is truncated: (2)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+}
+
+func TestStreamParser_Parse_MaxPayloadSize(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	b := getTestStreamBytes()
+	sp := NewStreamParser(getTestStreamReader(b))
+
+	sp.Limits = ParseLimits{
+		MaxPayloadSize: 4,
+	}
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		return StreamActionParse, nil
+	}
+
+	err := sp.Parse(handler)
+	if err == nil {
+		t.Fatalf("Expected payload error.")
+	} else if err.Error() != "MaxPayloadSize exceeded: (8) > (4)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+}
+
+func TestStreamParser_Parse_MaxPayloadSize_Implicit(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox2Factory{})
+
+	var b []byte
+	pushImplicitSizeBox(&b, "tb2 ", []byte("abcdefgh"))
+
+	sp := NewStreamParser(bytes.NewReader(b))
+
+	sp.Limits = ParseLimits{
+		MaxPayloadSize: 4,
+	}
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		return StreamActionParse, nil
+	}
+
+	err := sp.Parse(handler)
+	if err == nil {
+		t.Fatalf("Expected payload error.")
+	} else if err.Error() != "MaxPayloadSize exceeded: (5) > (4)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+}
@@ -0,0 +1,177 @@
+package bmfcommon
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/dsoprea/go-logging"
+)
+
+var (
+	// errMappingNotSupported indicates that files can not be memory-mapped on
+	// this platform.
+	errMappingNotSupported = errors.New("memory-mapping not supported")
+)
+
+// mappedFile is a read-only memory-mapping of a whole file.
+type mappedFile struct {
+	data []byte
+}
+
+// ReadAt copies len(p) bytes from the given offset.
+func (mf *mappedFile) ReadAt(p []byte, offset int64) (n int, err error) {
+	if offset < 0 {
+		return 0, errors.New("negative offset")
+	} else if offset >= int64(len(mf.data)) {
+		return 0, io.EOF
+	}
+
+	n = copy(p, mf.data[offset:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// slice returns up to N bytes from the given offset without copying them. The
+// capacity is limited to the length so that appending to the slice can not
+// write into the mapping.
+func (mf *mappedFile) slice(offset int64, n int64) []byte {
+	size := int64(len(mf.data))
+
+	if offset < 0 || offset >= size {
+		return nil
+	}
+
+	end := offset + n
+	if end > size || end < offset {
+		end = size
+	}
+
+	return mf.data[offset:end:end]
+}
+
+// Close releases the mapping. Slices that were returned from it must not be
+// used afterward.
+func (mf *mappedFile) Close() (err error) {
+	if mf.data == nil {
+		return nil
+	}
+
+	err = unmapFile(mf.data)
+	mf.data = nil
+
+	return err
+}
+
+// OpenFileMapped opens and strictly parses the file at the given path using a
+// read-only memory-mapping. See OpenFileMappedWithOptions.
+func OpenFileMapped(filepath string) (resource *Resource, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	options := ParseOptions{
+		Strict: true,
+	}
+
+	resource, err = OpenFileMappedWithOptions(filepath, options)
+	log.PanicIf(err)
+
+	return resource, nil
+}
+
+// OpenFileMappedWithOptions opens and parses the file at the given path using a
+// read-only memory-mapping. Box data (e.g. from Data() and ReadBytesAt()) is
+// then returned as slices of the mapping rather than being copied, which
+// avoids an allocation per read on large files. These slices must not be
+// modified. If the file can not be mapped (e.g. it is empty or mapping is not
+// supported on this platform), regular positional reads are used instead.
+//
+// Close() must be called when the resource is no longer needed. Slices of the
+// mapping can not be used after that.
+func OpenFileMappedWithOptions(filepath string, options ParseOptions) (resource *Resource, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	f, err := os.Open(filepath)
+	log.PanicIf(err)
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		log.Panic(err)
+	}
+
+	size := fi.Size()
+
+	var ra io.ReaderAt
+	var closer io.Closer
+
+	data, err := mapFile(f, size)
+	if err == nil {
+		// The mapping does not depend on the descriptor.
+		f.Close()
+
+		mf := &mappedFile{
+			data: data,
+		}
+
+		ra = mf
+		closer = mf
+	} else {
+		resourceLogger.Warningf(nil, "Could not map file (%s); using buffered reads: %s", filepath, err)
+
+		ra = f
+		closer = f
+	}
+
+	resource, err = NewResourceFromReaderAt(ra, size, options)
+	if err != nil {
+		closer.Close()
+		log.Panic(err)
+	}
+
+	resource.closer = closer
+
+	return resource, nil
+}
+
+// IsMapped returns true if the resource reads from a memory-mapping.
+func (f *Resource) IsMapped() bool {
+	_, ok := f.ra.(*mappedFile)
+	return ok
+}
+
+// Close releases the file or mapping that was opened for the resource, if any.
+// Box data that was returned from a mapping must not be used afterward.
+func (f *Resource) Close() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if f.closer == nil {
+		return nil
+	}
+
+	err = f.closer.Close()
+	f.closer = nil
+
+	log.PanicIf(err)
+
+	return nil
+}
+
+var (
+	_ io.ReaderAt = &mappedFile{}
+	_ io.Closer   = &mappedFile{}
+)
@@ -0,0 +1,18 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package bmfcommon
+
+import (
+	"os"
+)
+
+// mapFile always fails on this platform so that buffered reads are used.
+func mapFile(f *os.File, size int64) (data []byte, err error) {
+	return nil, errMappingNotSupported
+}
+
+// unmapFile is never called on this platform.
+func unmapFile(data []byte) error {
+	return errMappingNotSupported
+}
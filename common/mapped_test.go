@@ -0,0 +1,305 @@
+package bmfcommon
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"runtime"
+	"testing"
+
+	"hash/crc32"
+	"io/ioutil"
+
+	"github.com/dsoprea/go-logging"
+)
+
+// writeTestMappedFile writes the given bytes to a temporary file and returns
+// its path.
+func writeTestMappedFile(b []byte) string {
+	f, err := ioutil.TempFile("", "bmf-mapped")
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	_, err = f.Write(b)
+	log.PanicIf(err)
+
+	return f.Name()
+}
+
+// getTestMappedOptions returns strict options that recognize the test boxes.
+func getTestMappedOptions() ParseOptions {
+	r := NewRegistry()
+	r.RegisterBoxType(testBox1Factory{})
+	r.RegisterBoxType(testBox2Factory{})
+
+	options := ParseOptions{
+		Strict:   true,
+		Registry: r,
+	}
+
+	return options
+}
+
+func TestMappedFile_ReadAt(t *testing.T) {
+	mf := &mappedFile{
+		data: []byte{1, 2, 3, 4, 5},
+	}
+
+	p := make([]byte, 3)
+
+	n, err := mf.ReadAt(p, 1)
+	log.PanicIf(err)
+
+	if n != 3 || bytes.Equal(p, []byte{2, 3, 4}) != true {
+		t.Fatalf("Read not correct: (%d) %v", n, p)
+	}
+
+	n, err = mf.ReadAt(p, 3)
+	if err != io.EOF {
+		t.Fatalf("Expected EOF for short read: %v", err)
+	} else if n != 2 || bytes.Equal(p[:n], []byte{4, 5}) != true {
+		t.Fatalf("Short read not correct: (%d) %v", n, p[:n])
+	}
+
+	n, err = mf.ReadAt(p, 5)
+	if err != io.EOF || n != 0 {
+		t.Fatalf("Expected EOF at end: (%d) %v", n, err)
+	}
+}
+
+func TestMappedFile_slice(t *testing.T) {
+	mf := &mappedFile{
+		data: []byte{1, 2, 3, 4, 5},
+	}
+
+	b := mf.slice(1, 3)
+	if bytes.Equal(b, []byte{2, 3, 4}) != true {
+		t.Fatalf("Slice not correct: %v", b)
+	} else if cap(b) != len(b) {
+		t.Fatalf("Capacity not limited: (%d)", cap(b))
+	}
+
+	// Appending must not overwrite the mapping.
+
+	b = append(b, 99)
+	if mf.data[4] != 5 {
+		t.Fatalf("Mapping was modified.")
+	}
+
+	b = mf.slice(3, 10)
+	if bytes.Equal(b, []byte{4, 5}) != true {
+		t.Fatalf("Truncated slice not correct: %v", b)
+	}
+
+	b = mf.slice(5, 1)
+	if b != nil {
+		t.Fatalf("Expected no data at end.")
+	}
+}
+
+func TestOpenFileMappedWithOptions(t *testing.T) {
+	var b []byte
+	pushTestBox1(&b)
+	pushTestBox2(&b, []byte("abcdefgh"))
+
+	filepath := writeTestMappedFile(b)
+	defer os.Remove(filepath)
+
+	resource, err := OpenFileMappedWithOptions(filepath, getTestMappedOptions())
+	log.PanicIf(err)
+
+	defer resource.Close()
+
+	if runtime.GOOS == "linux" && resource.IsMapped() != true {
+		t.Fatalf("Expected resource to be mapped.")
+	} else if resource.Size() != int64(len(b)) {
+		t.Fatalf("Size() not correct: (%d)", resource.Size())
+	}
+
+	children, err := resource.Children()
+	log.PanicIf(err)
+
+	if len(children) != 2 || children[1].(*testBox2).String1() != "abcd" {
+		t.Fatalf("Boxes not correct.")
+	}
+
+	tb2 := children[1].(*testBox2)
+
+	data1, err := tb2.Data()
+	log.PanicIf(err)
+
+	if bytes.Equal(data1, []byte("abcdefgh")) != true {
+		t.Fatalf("Data() not correct: %v", data1)
+	}
+
+	if resource.IsMapped() == true {
+		data2, err := tb2.Data()
+		log.PanicIf(err)
+
+		if &data1[0] != &data2[0] {
+			t.Fatalf("Expected Data() to share the mapping.")
+		}
+	}
+
+	buffer := new(bytes.Buffer)
+
+	err = resource.CopyBytesAt(tb2.Start(), tb2.Size(), buffer)
+	log.PanicIf(err)
+
+	if bytes.Equal(buffer.Bytes(), b[8:]) != true {
+		t.Fatalf("Copied bytes not correct.")
+	}
+
+	buffer = new(bytes.Buffer)
+
+	err = resource.CopyBytesAt(tb2.Start(), tb2.Size()+1, buffer)
+	if err == nil {
+		t.Fatalf("Expected error for truncated copy.")
+	} else if buffer.Len() != 0 {
+		t.Fatalf("Expected nothing to be written for truncated copy.")
+	}
+
+	_, err = tb2.ReadBytesAt(tb2.Start(), tb2.Size()+1)
+	if err == nil {
+		t.Fatalf("Expected error for truncated read.")
+	}
+
+	err = resource.Close()
+	log.PanicIf(err)
+
+	// Closing again is allowed.
+
+	err = resource.Close()
+	log.PanicIf(err)
+}
+
+func TestOpenFileMapped_Empty(t *testing.T) {
+	filepath := writeTestMappedFile(nil)
+	defer os.Remove(filepath)
+
+	resource, err := OpenFileMapped(filepath)
+	log.PanicIf(err)
+
+	if resource.IsMapped() != false {
+		t.Fatalf("Expected fallback to buffered reads.")
+	} else if len(resource.LoadedBoxIndex) != 0 {
+		t.Fatalf("Expected no boxes.")
+	}
+
+	err = resource.Close()
+	log.PanicIf(err)
+}
+
+func TestOpenFileMapped_NotFound(t *testing.T) {
+	_, err := OpenFileMapped(path.Join(os.TempDir(), "bmf-mapped-does-not-exist"))
+	if err == nil {
+		t.Fatalf("Expected error for missing file.")
+	}
+}
+
+func TestOpenFileMapped_ParseError(t *testing.T) {
+	var b []byte
+	PushBox(&b, "tb2 ", []byte("abc"))
+
+	filepath := writeTestMappedFile(b)
+	defer os.Remove(filepath)
+
+	_, err := OpenFileMappedWithOptions(filepath, getTestMappedOptions())
+	if err == nil {
+		t.Fatalf("Expected parse error.")
+	}
+}
+
+func TestResource_Close_NotOpened(t *testing.T) {
+	resource := getTestQueryResource()
+
+	err := resource.Close()
+	log.PanicIf(err)
+}
+
+// benchmarkResourceData reads the payload of every root box of the test asset
+// on each iteration. If `checksum` is true, every byte is also visited so that
+// the cost of faulting-in mapped pages is included.
+func benchmarkResourceData(b *testing.B, resource *Resource, checksum bool) {
+	children, err := resource.Children()
+	log.PanicIf(err)
+
+	var total int64
+	for _, cb := range children {
+		total += cb.Size() - cb.(*RawBox).HeaderSize()
+	}
+
+	b.SetBytes(total)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, cb := range children {
+			data, err := cb.(*RawBox).Data()
+			log.PanicIf(err)
+
+			if checksum == true {
+				crc32.ChecksumIEEE(data)
+			}
+		}
+	}
+}
+
+// getBenchmarkAssetFilepath returns the path of the asset used by the
+// benchmarks. Its root boxes are not registered in this package, so they are
+// all read as raw boxes.
+func getBenchmarkAssetFilepath() string {
+	return path.Join("..", "assets", "tears-of-steel.mp4")
+}
+
+// openBenchmarkBuffered parses the test asset using regular positional reads.
+func openBenchmarkBuffered() (resource *Resource, f *os.File) {
+	f, err := os.Open(getBenchmarkAssetFilepath())
+	log.PanicIf(err)
+
+	fi, err := f.Stat()
+	log.PanicIf(err)
+
+	resource, err = NewResourceWithOptions(f, fi.Size(), ParseOptions{Strict: true, Registry: NewRegistry()})
+	log.PanicIf(err)
+
+	return resource, f
+}
+
+// openBenchmarkMapped parses the test asset using a memory-mapping.
+func openBenchmarkMapped() *Resource {
+	resource, err := OpenFileMappedWithOptions(getBenchmarkAssetFilepath(), ParseOptions{Strict: true, Registry: NewRegistry()})
+	log.PanicIf(err)
+
+	return resource
+}
+
+func BenchmarkResource_Data_Buffered(b *testing.B) {
+	resource, f := openBenchmarkBuffered()
+	defer f.Close()
+
+	benchmarkResourceData(b, resource, false)
+}
+
+func BenchmarkResource_Data_Mapped(b *testing.B) {
+	resource := openBenchmarkMapped()
+	defer resource.Close()
+
+	benchmarkResourceData(b, resource, false)
+}
+
+func BenchmarkResource_Data_Checksum_Buffered(b *testing.B) {
+	resource, f := openBenchmarkBuffered()
+	defer f.Close()
+
+	benchmarkResourceData(b, resource, true)
+}
+
+func BenchmarkResource_Data_Checksum_Mapped(b *testing.B) {
+	resource := openBenchmarkMapped()
+	defer resource.Close()
+
+	benchmarkResourceData(b, resource, true)
+}
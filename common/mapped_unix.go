@@ -0,0 +1,31 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package bmfcommon
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// mapFile maps the whole file read-only.
+func mapFile(f *os.File, size int64) (data []byte, err error) {
+	if size <= 0 {
+		return nil, errors.New("can not map an empty file")
+	} else if int64(int(size)) != size {
+		return nil, errors.New("file too large to map")
+	}
+
+	data, err = syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// unmapFile releases a mapping returned by mapFile.
+func unmapFile(data []byte) error {
+	return syscall.Munmap(data)
+}
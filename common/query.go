@@ -0,0 +1,417 @@
+package bmfcommon
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dsoprea/go-logging"
+)
+
+var (
+	// ErrNoBoxesFound indicates that a query did not match any boxes.
+	ErrNoBoxesFound = errors.New("no boxes matched query")
+)
+
+// QueryAttributer is implemented by boxes that expose attributes that can be
+// matched by query predicates (e.g. "trak[hdlr=vide]").
+type QueryAttributer interface {
+	// QueryAttribute returns the value of the given attribute. `found` is
+	// false if the box does not have the attribute.
+	QueryAttribute(key string) (value string, found bool, err error)
+}
+
+// queryPredicate is one bracketed selector in a query step. It is either an
+// index or an attribute comparison.
+type queryPredicate struct {
+	isIndex bool
+	index   int
+
+	key   string
+	value string
+}
+
+// queryStep matches the children (or descendants) of the current boxes.
+type queryStep struct {
+	// descendant is true if the step matches boxes at any depth rather than
+	// only immediate children.
+	descendant bool
+
+	// name is the box-type or "*" for any.
+	name string
+
+	predicates []queryPredicate
+}
+
+// Query is a parsed path expression. Expressions are box-types separated by
+// slashes, where "//" matches at any depth and "*" matches any box-type. Each
+// step may be followed by any number of predicates:
+//
+//	[N]           the Nth (from zero) of the boxes matched under each parent
+//	[key=value]   boxes whose QueryAttribute(key) equals the value
+//
+// Values may be quoted with single or double quotes. For example:
+//
+//	moov/trak[hdlr=vide]/mdia/minf/stbl/stsd/*
+//	//infe[item_type=Exif]
+//	moov/trak[1]/tkhd
+type Query struct {
+	expression string
+	steps      []queryStep
+}
+
+// String returns the original expression.
+func (q *Query) String() string {
+	return q.expression
+}
+
+// queryParseError returns an error describing a problem with the expression.
+func queryParseError(expression string, position int, message string) error {
+	return fmt.Errorf("query [%s] not valid at position (%d): %s", expression, position, message)
+}
+
+// parseQueryPredicate parses the content between the brackets.
+func parseQueryPredicate(expression string, position int, raw string) (qp queryPredicate, err error) {
+	if raw == "" {
+		return qp, queryParseError(expression, position, "empty predicate")
+	}
+
+	if index, err := strconv.Atoi(raw); err == nil {
+		if index < 0 {
+			return qp, queryParseError(expression, position, "index can not be negative")
+		}
+
+		qp = queryPredicate{
+			isIndex: true,
+			index:   index,
+		}
+
+		return qp, nil
+	}
+
+	i := strings.IndexByte(raw, '=')
+	if i < 1 {
+		return qp, queryParseError(expression, position, "predicate must be an index or key=value")
+	}
+
+	key := raw[:i]
+	value := raw[i+1:]
+
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+
+	qp = queryPredicate{
+		key:   key,
+		value: value,
+	}
+
+	return qp, nil
+}
+
+// ParseQuery parses a path expression. See Query for the syntax.
+func ParseQuery(expression string) (q *Query, err error) {
+	steps := make([]queryStep, 0)
+
+	i := 0
+	for i < len(expression) {
+		qs := queryStep{}
+
+		if strings.HasPrefix(expression[i:], "//") == true {
+			qs.descendant = true
+			i += 2
+		} else if expression[i] == '/' {
+			i++
+		}
+
+		start := i
+		for i < len(expression) && expression[i] != '/' && expression[i] != '[' {
+			i++
+		}
+
+		qs.name = expression[start:i]
+
+		if qs.name == "" {
+			return nil, queryParseError(expression, start, "empty step")
+		} else if qs.name != "*" && len(qs.name) != 4 {
+			return nil, queryParseError(expression, start, fmt.Sprintf("box-type must be four characters or '*': [%s]", qs.name))
+		}
+
+		for i < len(expression) && expression[i] == '[' {
+			start := i
+
+			// Find the closing bracket while ignoring any in quotes.
+
+			var quote byte
+			for i++; i < len(expression); i++ {
+				c := expression[i]
+
+				if quote != 0 {
+					if c == quote {
+						quote = 0
+					}
+				} else if c == '"' || c == '\'' {
+					quote = c
+				} else if c == ']' {
+					break
+				}
+			}
+
+			if i >= len(expression) {
+				return nil, queryParseError(expression, start, "predicate not terminated")
+			}
+
+			qp, err := parseQueryPredicate(expression, start, expression[start+1:i])
+			if err != nil {
+				return nil, err
+			}
+
+			qs.predicates = append(qs.predicates, qp)
+			i++
+		}
+
+		if i < len(expression) && expression[i] != '/' {
+			return nil, queryParseError(expression, i, "expected '/' or '['")
+		}
+
+		steps = append(steps, qs)
+	}
+
+	if len(steps) == 0 {
+		return nil, queryParseError(expression, 0, "no steps")
+	}
+
+	q = &Query{
+		expression: expression,
+		steps:      steps,
+	}
+
+	return q, nil
+}
+
+// queryChildren returns the children of the given box with the given type
+// (or any type for "*") in the order that they were stored.
+func queryChildren(bci BoxChildIndexer, name string) (children []CommonBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if name == "*" {
+		children, err = bci.Children()
+		log.PanicIf(err)
+
+		return children, nil
+	}
+
+	children = make([]CommonBox, 0)
+
+	for _, childName := range bci.ChildrenTypes() {
+		if childName != name {
+			continue
+		}
+
+		children, err = bci.GetChildBoxes(childName)
+		log.PanicIf(err)
+
+		// Don't let the callers modify the index.
+		children = append([]CommonBox{}, children...)
+	}
+
+	return children, nil
+}
+
+// queryDescendants returns the given box followed by all boxes below it that
+// have children.
+func queryDescendants(bci BoxChildIndexer) (parents []BoxChildIndexer, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	parents = []BoxChildIndexer{bci}
+
+	children, err := queryChildren(bci, "*")
+	log.PanicIf(err)
+
+	for _, cb := range children {
+		if childBci, ok := cb.(BoxChildIndexer); ok == true {
+			descendants, err := queryDescendants(childBci)
+			log.PanicIf(err)
+
+			parents = append(parents, descendants...)
+		}
+	}
+
+	return parents, nil
+}
+
+// apply filters the boxes matched under one parent.
+func (qp queryPredicate) apply(boxes []CommonBox) (filtered []CommonBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if qp.isIndex == true {
+		if qp.index >= len(boxes) {
+			return []CommonBox{}, nil
+		}
+
+		return []CommonBox{boxes[qp.index]}, nil
+	}
+
+	filtered = make([]CommonBox, 0)
+
+	for _, cb := range boxes {
+		qa, ok := cb.(QueryAttributer)
+		if ok == false {
+			continue
+		}
+
+		value, found, err := qa.QueryAttribute(qp.key)
+		log.PanicIf(err)
+
+		if found == true && value == qp.value {
+			filtered = append(filtered, cb)
+		}
+	}
+
+	return filtered, nil
+}
+
+// FindAll returns every box below the given box (or resource) that matches the
+// query, in the order that they were stored. Lazy boxes are only decoded as
+// far as is necessary to evaluate the query.
+func (q *Query) FindAll(bci BoxChildIndexer) (boxes []CommonBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	current := []BoxChildIndexer{bci}
+
+	for i, qs := range q.steps {
+		matched := make([]CommonBox, 0)
+		seen := make(map[CommonBox]struct{})
+
+		for _, parent := range current {
+			parents := []BoxChildIndexer{parent}
+
+			if qs.descendant == true {
+				parents, err = queryDescendants(parent)
+				log.PanicIf(err)
+			}
+
+			for _, parent := range parents {
+				children, err := queryChildren(parent, qs.name)
+				log.PanicIf(err)
+
+				for _, qp := range qs.predicates {
+					children, err = qp.apply(children)
+					log.PanicIf(err)
+				}
+
+				for _, cb := range children {
+					if _, found := seen[cb]; found == true {
+						continue
+					}
+
+					seen[cb] = struct{}{}
+					matched = append(matched, cb)
+				}
+			}
+		}
+
+		if i == len(q.steps)-1 {
+			boxes = matched
+			break
+		}
+
+		current = make([]BoxChildIndexer, 0, len(matched))
+		for _, cb := range matched {
+			if childBci, ok := cb.(BoxChildIndexer); ok == true {
+				current = append(current, childBci)
+			}
+		}
+	}
+
+	sortBoxesByStart(boxes)
+
+	return boxes, nil
+}
+
+// Find returns the first box that matches the query or ErrNoBoxesFound.
+func (q *Query) Find(bci BoxChildIndexer) (cb CommonBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	boxes, err := q.FindAll(bci)
+	log.PanicIf(err)
+
+	if len(boxes) == 0 {
+		return nil, ErrNoBoxesFound
+	}
+
+	return boxes[0], nil
+}
+
+// FindAll parses the expression and returns every matching box below the given
+// box (or resource). See Query for the syntax.
+func FindAll(bci BoxChildIndexer, expression string) (boxes []CommonBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	q, err := ParseQuery(expression)
+	log.PanicIf(err)
+
+	boxes, err = q.FindAll(bci)
+	log.PanicIf(err)
+
+	return boxes, nil
+}
+
+// Find parses the expression and returns the first matching box below the
+// given box (or resource) or ErrNoBoxesFound. See Query for the syntax.
+func Find(bci BoxChildIndexer, expression string) (cb CommonBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	q, err := ParseQuery(expression)
+	log.PanicIf(err)
+
+	cb, err = q.Find(bci)
+	if err == ErrNoBoxesFound {
+		return nil, err
+	}
+
+	log.PanicIf(err)
+
+	return cb, nil
+}
+
+// FindAll returns every box in the resource that matches the expression. See
+// Query for the syntax.
+func (f *Resource) FindAll(expression string) (boxes []CommonBox, err error) {
+	return FindAll(f, expression)
+}
+
+// Find returns the first box in the resource that matches the expression or
+// ErrNoBoxesFound. See Query for the syntax.
+func (f *Resource) Find(expression string) (cb CommonBox, err error) {
+	return Find(f, expression)
+}
@@ -0,0 +1,301 @@
+package bmfcommon
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+)
+
+// testQueryBox2 exposes the first string of a testBox2 as a query attribute.
+type testQueryBox2 struct {
+	*testBox2
+}
+
+// QueryAttribute returns the value of the given attribute.
+func (tqb testQueryBox2) QueryAttribute(key string) (value string, found bool, err error) {
+	if key == "string1" {
+		return tqb.string1, true, nil
+	}
+
+	return "", false, nil
+}
+
+type testQueryBox2Factory struct {
+	testBox2Factory
+}
+
+// New returns a new value instance.
+func (tqbf testQueryBox2Factory) New(box Box) (cb CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	cb, childBoxSeriesOffset, err = tqbf.testBox2Factory.New(box)
+	log.PanicIf(err)
+
+	return testQueryBox2{cb.(*testBox2)}, childBoxSeriesOffset, nil
+}
+
+// getTestQueryResource returns a resource with the following structure:
+//
+//	tb1
+//	tb3
+//	  tb2 (abcdefgh)
+//	  tb3
+//	    tb2 (ijklmnop)
+//	    tb1
+//	tb2 (qrstuvwx)
+func getTestQueryResource() *Resource {
+	var inner []byte
+	pushTestBox2(&inner, []byte("ijklmnop"))
+	pushTestBox1(&inner)
+
+	var outer []byte
+	pushTestBox2(&outer, []byte("abcdefgh"))
+	pushTestBox3(&outer, inner)
+
+	var b []byte
+	pushTestBox1(&b)
+	pushTestBox3(&b, outer)
+	pushTestBox2(&b, []byte("qrstuvwx"))
+
+	r := NewRegistry()
+	r.RegisterBoxType(testBox1Factory{})
+	r.RegisterBoxType(testQueryBox2Factory{})
+	r.RegisterBoxType(testBox3Factory{})
+
+	options := ParseOptions{
+		Strict:   true,
+		Registry: r,
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResourceWithOptions(sb, int64(len(b)), options)
+	log.PanicIf(err)
+
+	return resource
+}
+
+// getTestQueryStrings returns the first string of each box, which must all be
+// testQueryBox2 boxes.
+func getTestQueryStrings(boxes []CommonBox) (strings []string) {
+	strings = make([]string, len(boxes))
+	for i, cb := range boxes {
+		strings[i] = cb.(testQueryBox2).String1()
+	}
+
+	return strings
+}
+
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery("/moov/trak[hdlr=vide][1]//stsd/*")
+	log.PanicIf(err)
+
+	if q.String() != "/moov/trak[hdlr=vide][1]//stsd/*" {
+		t.Fatalf("String() not correct: [%s]", q.String())
+	} else if len(q.steps) != 4 {
+		t.Fatalf("Step count not correct: (%d)", len(q.steps))
+	}
+
+	trak := q.steps[1]
+
+	if trak.name != "trak" || trak.descendant != false {
+		t.Fatalf("Second step not correct.")
+	} else if len(trak.predicates) != 2 {
+		t.Fatalf("Predicate count not correct.")
+	} else if trak.predicates[0].isIndex != false || trak.predicates[0].key != "hdlr" || trak.predicates[0].value != "vide" {
+		t.Fatalf("First predicate not correct.")
+	} else if trak.predicates[1].isIndex != true || trak.predicates[1].index != 1 {
+		t.Fatalf("Second predicate not correct.")
+	}
+
+	if q.steps[2].name != "stsd" || q.steps[2].descendant != true {
+		t.Fatalf("Third step not correct.")
+	} else if q.steps[3].name != "*" || q.steps[3].descendant != false {
+		t.Fatalf("Fourth step not correct.")
+	}
+}
+
+func TestParseQuery_QuotedValue(t *testing.T) {
+	q, err := ParseQuery("meta/iinf/infe[item_name='a]/b']/abcd")
+	log.PanicIf(err)
+
+	if len(q.steps) != 4 {
+		t.Fatalf("Step count not correct: (%d)", len(q.steps))
+	} else if q.steps[2].predicates[0].value != "a]/b" {
+		t.Fatalf("Value not correct: [%s]", q.steps[2].predicates[0].value)
+	}
+}
+
+func TestParseQuery_Invalid(t *testing.T) {
+	expressions := []string{
+		"",
+		"/",
+		"moov/",
+		"moov//",
+		"moo",
+		"moov[",
+		"moov[]",
+		"moov[-1]",
+		"moov[=vide]",
+		"moov[0]x",
+		"moov[abc='def]",
+	}
+
+	for _, expression := range expressions {
+		_, err := ParseQuery(expression)
+		if err == nil {
+			t.Fatalf("Expected error for expression: [%s]", expression)
+		}
+	}
+}
+
+func TestResource_FindAll(t *testing.T) {
+	resource := getTestQueryResource()
+
+	boxes, err := resource.FindAll("tb2 ")
+	log.PanicIf(err)
+
+	if len(boxes) != 1 || boxes[0].(testQueryBox2).String1() != "qrst" {
+		t.Fatalf("Root boxes not correct.")
+	}
+
+	boxes, err = resource.FindAll("tb3 /*")
+	log.PanicIf(err)
+
+	if len(boxes) != 2 {
+		t.Fatalf("Wildcard match count not correct: (%d)", len(boxes))
+	} else if boxes[0].Name() != "tb2 " || boxes[1].Name() != "tb3 " {
+		t.Fatalf("Wildcard matches not correct or not ordered.")
+	}
+
+	boxes, err = resource.FindAll("tb3 /tb3 /tb1 ")
+	log.PanicIf(err)
+
+	if len(boxes) != 1 || boxes[0].Parent().Parent().Name() != "tb3 " {
+		t.Fatalf("Nested box not correct.")
+	}
+}
+
+func TestResource_FindAll_Descendant(t *testing.T) {
+	resource := getTestQueryResource()
+
+	boxes, err := resource.FindAll("//tb2 ")
+	log.PanicIf(err)
+
+	strings := getTestQueryStrings(boxes)
+
+	if len(strings) != 3 || strings[0] != "abcd" || strings[1] != "ijkl" || strings[2] != "qrst" {
+		t.Fatalf("Descendants not correct: %v", strings)
+	}
+
+	boxes, err = resource.FindAll("tb3 //tb1 ")
+	log.PanicIf(err)
+
+	if len(boxes) != 1 || boxes[0].Parent().Name() != "tb3 " {
+		t.Fatalf("Nested descendant not correct.")
+	}
+
+	// The inner TB3 is reached from both TB3 boxes but must only be returned
+	// once.
+
+	boxes, err = resource.FindAll("//tb3 //tb3 ")
+	log.PanicIf(err)
+
+	if len(boxes) != 1 {
+		t.Fatalf("Duplicate matches not removed: (%d)", len(boxes))
+	}
+}
+
+func TestResource_FindAll_Predicates(t *testing.T) {
+	resource := getTestQueryResource()
+
+	boxes, err := resource.FindAll("//tb2 [string1=ijkl]")
+	log.PanicIf(err)
+
+	strings := getTestQueryStrings(boxes)
+
+	if len(strings) != 1 || strings[0] != "ijkl" {
+		t.Fatalf("Attribute match not correct: %v", strings)
+	}
+
+	boxes, err = resource.FindAll("//tb2 [string1=\"ijkl\"]")
+	log.PanicIf(err)
+
+	if len(boxes) != 1 {
+		t.Fatalf("Quoted attribute match not correct.")
+	}
+
+	// Boxes without the attribute never match.
+
+	boxes, err = resource.FindAll("//tb1 [string1=ijkl]")
+	log.PanicIf(err)
+
+	if len(boxes) != 0 {
+		t.Fatalf("Expected no matches for missing attribute.")
+	}
+
+	// The index applies to the matches under each parent.
+
+	boxes, err = resource.FindAll("//*[0]")
+	log.PanicIf(err)
+
+	if len(boxes) != 3 || boxes[0].Name() != "tb1 " || boxes[1].Name() != "tb2 " || boxes[2].Name() != "tb2 " {
+		t.Fatalf("Index matches not correct.")
+	}
+
+	boxes, err = resource.FindAll("tb3 /*[1]")
+	log.PanicIf(err)
+
+	if len(boxes) != 1 || boxes[0].Name() != "tb3 " {
+		t.Fatalf("Index match not correct.")
+	}
+
+	boxes, err = resource.FindAll("tb3 /*[2]")
+	log.PanicIf(err)
+
+	if len(boxes) != 0 {
+		t.Fatalf("Expected no matches for out-of-range index.")
+	}
+}
+
+func TestResource_Find(t *testing.T) {
+	resource := getTestQueryResource()
+
+	cb, err := resource.Find("//tb2 ")
+	log.PanicIf(err)
+
+	if cb.(testQueryBox2).String1() != "abcd" {
+		t.Fatalf("First match not correct.")
+	}
+
+	_, err = resource.Find("tb1 /tb2 ")
+	if err != ErrNoBoxesFound {
+		t.Fatalf("Expected ErrNoBoxesFound: %v", err)
+	}
+
+	_, err = resource.Find("tb1/")
+	if err == nil || err == ErrNoBoxesFound {
+		t.Fatalf("Expected parse error: %v", err)
+	}
+}
+
+func TestFindAll_Box(t *testing.T) {
+	resource := getTestQueryResource()
+
+	cb, err := resource.Find("tb3 ")
+	log.PanicIf(err)
+
+	boxes, err := FindAll(cb.(*testBox3), "tb2 ")
+	log.PanicIf(err)
+
+	strings := getTestQueryStrings(boxes)
+
+	if len(strings) != 1 || strings[0] != "abcd" {
+		t.Fatalf("Relative match not correct: %v", strings)
+	}
+}
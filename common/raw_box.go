@@ -0,0 +1,35 @@
+package bmfcommon
+
+import (
+	"io"
+)
+
+// RawBox is a box whose type does not have a registered factory. The payload
+// is not interpreted, but the box keeps its place among its siblings so that
+// dumps, diffs, and rewrites are faithful to the original.
+type RawBox struct {
+	// Box is the base box.
+	Box
+}
+
+// newRawBox returns a RawBox for the given base box.
+func newRawBox(box Box) *RawBox {
+	return &RawBox{
+		Box: box,
+	}
+}
+
+// PayloadSize returns the size of the payload (everything after the header).
+func (rb *RawBox) PayloadSize() int64 {
+	return rb.Size() - rb.HeaderSize()
+}
+
+// PayloadReader returns a reader over the payload. Nothing is read until the
+// reader is used. The reader does not affect other reads of the resource.
+func (rb *RawBox) PayloadReader() *io.SectionReader {
+	return io.NewSectionReader(rb.resource.ra, rb.Start()+rb.HeaderSize(), rb.PayloadSize())
+}
+
+var (
+	_ CommonBox = &RawBox{}
+)
@@ -0,0 +1,103 @@
+package bmfcommon
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+)
+
+func getTestRawBoxResource() *Resource {
+	ClearRegistrations()
+	RegisterBoxType(testBox3Factory{})
+
+	var children []byte
+	pushUnknownBox(&children, []byte{1, 2, 3})
+
+	var b []byte
+	pushTestBox3(&b, children)
+	pushUnknownBox(&b, []byte{4, 5})
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	return resource
+}
+
+func TestRawBox_PayloadSize(t *testing.T) {
+	defer ClearRegistrations()
+
+	resource := getTestRawBoxResource()
+
+	boxes, err := resource.GetChildBoxes("wxyz")
+	log.PanicIf(err)
+
+	rb := boxes[0].(*RawBox)
+
+	if rb.Start() != 19 {
+		t.Fatalf("Start() not correct: (%d)", rb.Start())
+	} else if rb.Size() != 10 {
+		t.Fatalf("Size() not correct: (%d)", rb.Size())
+	} else if rb.PayloadSize() != 2 {
+		t.Fatalf("PayloadSize() not correct: (%d)", rb.PayloadSize())
+	}
+}
+
+func TestRawBox_PayloadReader(t *testing.T) {
+	defer ClearRegistrations()
+
+	resource := getTestRawBoxResource()
+
+	cb, err := resource.Find("tb3 /wxyz")
+	log.PanicIf(err)
+
+	rb := cb.(*RawBox)
+
+	if rb.Parent().Name() != "tb3 " {
+		t.Fatalf("Parent not correct.")
+	}
+
+	data, err := ioutil.ReadAll(rb.PayloadReader())
+	log.PanicIf(err)
+
+	if bytes.Equal(data, []byte{1, 2, 3}) != true {
+		t.Fatalf("Payload not correct: %v", data)
+	}
+
+	// Reads elsewhere in the resource must not disturb the reader.
+
+	pr := rb.PayloadReader()
+
+	first := make([]byte, 1)
+
+	_, err = pr.Read(first)
+	log.PanicIf(err)
+
+	_, err = resource.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	rest, err := ioutil.ReadAll(pr)
+	log.PanicIf(err)
+
+	if first[0] != 1 || bytes.Equal(rest, []byte{2, 3}) != true {
+		t.Fatalf("Interleaved payload not correct: %v %v", first, rest)
+	}
+}
+
+func TestRawBox_Index(t *testing.T) {
+	defer ClearRegistrations()
+
+	resource := getTestRawBoxResource()
+
+	ibe := IndexedBoxEntry{
+		NamePhrase: "tb3 .wxyz",
+	}
+
+	if _, ok := resource.Index()[ibe].(*RawBox); ok != true {
+		t.Fatalf("Unknown child not indexed.")
+	}
+}
@@ -13,30 +13,54 @@ var (
 	ErrNoChildren = errors.New("box does not support children")
 )
 
-var (
-	boxMapping = make(map[string]BoxFactory)
-)
+// UuidBoxFactory is a factory for "uuid" boxes with a particular extended type.
+type UuidBoxFactory interface {
+	BoxFactory
+
+	// ExtendedType returns the extended type that the factory handles.
+	ExtendedType() ExtendedType
+}
 
-// ClearRegistrations drops all registrations. This supports testing.
+// ClearRegistrations drops all registrations from the default registry. This
+// supports testing.
 func ClearRegistrations() {
-	boxMapping = make(map[string]BoxFactory)
+	defaultRegistry.Clear()
 }
 
-// RegisterBoxType registers the factory for a box-type.
+// RegisterBoxType registers the factory for a box-type with the default
+// registry.
 func RegisterBoxType(bf BoxFactory) {
-	name := bf.Name()
+	defaultRegistry.RegisterBoxType(bf)
+}
 
-	if _, found := boxMapping[name]; found == true {
-		log.Panicf("box-factory already registered: [%s]", name)
-	}
+// GetFactory returns the factory for the given box-type from the default
+// registry. Will return `nil` if not known.
+func GetFactory(name string) BoxFactory {
+	return defaultRegistry.GetFactory(name)
+}
 
-	boxMapping[name] = bf
+// RegisterBoxTypeWithParentPath registers the factory for a box-type under the
+// given parent path with the default registry.
+func RegisterBoxTypeWithParentPath(parentPath string, bf BoxFactory) {
+	defaultRegistry.RegisterBoxTypeWithParentPath(parentPath, bf)
 }
 
-// GetFactory returns the factory for the given box-type. Will return `nil` if
-// not known.
-func GetFactory(name string) BoxFactory {
-	return boxMapping[name]
+// RegisterBoxTypeWithParentPredicate registers the factory for a box-type under
+// parents accepted by the predicate with the default registry.
+func RegisterBoxTypeWithParentPredicate(predicate ParentPredicate, bf BoxFactory) {
+	defaultRegistry.RegisterBoxTypeWithParentPredicate(predicate, bf)
+}
+
+// RegisterUuidBoxType registers the factory for "uuid" boxes with the given
+// extended type with the default registry.
+func RegisterUuidBoxType(ubf UuidBoxFactory) {
+	defaultRegistry.RegisterUuidBoxType(ubf)
+}
+
+// GetUuidFactory returns the factory for "uuid" boxes with the given extended
+// type from the default registry. Will return `nil` if not known.
+func GetUuidFactory(et ExtendedType) BoxFactory {
+	return defaultRegistry.GetUuidFactory(et)
 }
 
 // BoxNameIsValid returns true if no invalid characters are in the box-name.
@@ -53,10 +77,11 @@ func BoxNameIsValid(name string) bool {
 		return false
 	}
 
-	// Name needs to have only letters. Note that this will also fail if there
-	// were spaces *in the middle* of the name.
+	// Name needs to have only letters, digits, and hyphens (e.g. "ac-3"). Note
+	// that this will also fail if there were spaces *in the middle* of the
+	// name.
 	for _, r := range name {
-		if unicode.IsLetter(r) == false && unicode.IsDigit(r) == false {
+		if unicode.IsLetter(r) == false && unicode.IsDigit(r) == false && r != '-' {
 			return false
 		}
 	}
@@ -107,8 +132,11 @@ type BoxChildIndexer interface {
 	GetChildBoxes(name string) (boxes []CommonBox, err error)
 
 	// ChildrenTypes returns the names of the types of the children that were
-	// found. Only registered types are recognized.
+	// found.
 	ChildrenTypes() (names []string)
+
+	// Children returns all children in the order that they were stored.
+	Children() (children []CommonBox, err error)
 }
 
 // BoxFactory knows how to construct a box struct.
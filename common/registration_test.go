@@ -10,20 +10,20 @@ import (
 func TestClearRegistrations(t *testing.T) {
 	ClearRegistrations()
 
-	if len(boxMapping) != 0 {
+	if len(defaultRegistry.boxMapping) != 0 {
 		t.Fatalf("Expected no registrations at top of test.")
 	}
 
 	bf := testBox1Factory{}
 	RegisterBoxType(bf)
 
-	if len(boxMapping) != 1 {
+	if len(defaultRegistry.boxMapping) != 1 {
 		t.Fatalf("Expected exactly one registration.")
 	}
 
 	ClearRegistrations()
 
-	if len(boxMapping) != 0 {
+	if len(defaultRegistry.boxMapping) != 0 {
 		t.Fatalf("Expected no registrations at bottom of test.")
 	}
 }
@@ -35,7 +35,7 @@ func TestRegisterBoxType_Ok(t *testing.T) {
 	bf := testBox1Factory{}
 	RegisterBoxType(bf)
 
-	if len(boxMapping) != 1 {
+	if len(defaultRegistry.boxMapping) != 1 {
 		t.Fatalf("Expected exactly one registration.")
 	}
 }
@@ -86,6 +86,12 @@ func TestBoxNameIsValid_Hit(t *testing.T) {
 	}
 }
 
+func TestBoxNameIsValid_Hit_Hyphen(t *testing.T) {
+	if BoxNameIsValid("ac-3") != true {
+		t.Fatalf("Expected valid box name.")
+	}
+}
+
 func TestBoxNameIsValid_Miss_Empty(t *testing.T) {
 	if BoxNameIsValid("") != false {
 		t.Fatalf("Expected invalid box name.")
@@ -147,3 +153,69 @@ func TestChildBoxes(t *testing.T) {
 		t.Fatalf("Child boxes not correct (2).")
 	}
 }
+
+func TestRegisterUuidBoxType(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterUuidBoxType(testUuidBoxFactory{})
+
+	if len(defaultRegistry.uuidBoxMapping) != 1 {
+		t.Fatalf("Expected exactly one registration.")
+	} else if len(defaultRegistry.boxMapping) != 0 {
+		t.Fatalf("Expected no regular registrations.")
+	}
+
+	_ = GetUuidFactory(testUuidBoxExtendedType).(testUuidBoxFactory)
+
+	if GetUuidFactory(ExtendedType{}) != nil {
+		t.Fatalf("Expected no factory for unregistered extended-type.")
+	} else if GetFactory(UuidBoxName) != nil {
+		t.Fatalf("Expected no regular factory for uuid boxes.")
+	}
+}
+
+func TestRegisterUuidBoxType_AlreadyRegistered(t *testing.T) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err := errRaw.(error)
+			if err.Error() != "uuid box-factory already registered: [01020304-0506-0708-090a-0b0c0d0e0f10]" {
+				log.Panic(err)
+			}
+		} else {
+			t.Fatalf("Expected panic.")
+		}
+	}()
+
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterUuidBoxType(testUuidBoxFactory{})
+	RegisterUuidBoxType(testUuidBoxFactory{})
+}
+
+func TestRegisterBoxTypeWithParentPath(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxTypeWithParentPath("tb3 ", testBox1Factory{})
+
+	if len(defaultRegistry.contextualBoxMapping["tb1 "]) != 1 {
+		t.Fatalf("Expected exactly one registration.")
+	} else if GetFactory("tb1 ") != nil {
+		t.Fatalf("Expected no general registration.")
+	}
+}
+
+func TestRegisterBoxTypeWithParentPredicate(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxTypeWithParentPredicate(func(parent CommonBox) bool {
+		return true
+	}, testBox1Factory{})
+
+	if len(defaultRegistry.contextualBoxMapping["tb1 "]) != 1 {
+		t.Fatalf("Expected exactly one registration.")
+	}
+}
@@ -0,0 +1,274 @@
+package bmfcommon
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dsoprea/go-logging"
+)
+
+// ParentPredicate decides whether a factory applies to a box with the given
+// parent. The parent is nil for root boxes. Note that the children of the
+// parent will not have been loaded yet but its earlier siblings will have.
+type ParentPredicate func(parent CommonBox) bool
+
+// contextualBoxFactory is a factory that only applies under certain parents.
+type contextualBoxFactory struct {
+	// parentPath is the ancestry that the parent must end with, the immediate
+	// parent being last. A "*" component matches any box. This is nil if
+	// predicate is set.
+	parentPath []string
+
+	predicate ParentPredicate
+
+	factory BoxFactory
+}
+
+// matches returns true if the factory applies under the given parent.
+func (cbf contextualBoxFactory) matches(parent CommonBox) bool {
+	if cbf.predicate != nil {
+		return cbf.predicate(parent)
+	}
+
+	current := parent
+	for i := len(cbf.parentPath) - 1; i >= 0; i-- {
+		if current == nil {
+			return false
+		}
+
+		component := cbf.parentPath[i]
+		if component != "*" && component != current.Name() {
+			return false
+		}
+
+		current = current.Parent()
+	}
+
+	return true
+}
+
+// isMoreSpecificThan returns true if this factory should be preferred over the
+// other when both match. Predicates beat paths, longer paths beat shorter ones,
+// and then paths with fewer wildcards win. Otherwise, the earlier registration
+// wins.
+func (cbf contextualBoxFactory) isMoreSpecificThan(other contextualBoxFactory) bool {
+	if cbf.predicate != nil || other.predicate != nil {
+		return cbf.predicate != nil && other.predicate == nil
+	}
+
+	if len(cbf.parentPath) != len(other.parentPath) {
+		return len(cbf.parentPath) > len(other.parentPath)
+	}
+
+	wildcards := func(parentPath []string) (count int) {
+		for _, component := range parentPath {
+			if component == "*" {
+				count++
+			}
+		}
+
+		return count
+	}
+
+	return wildcards(cbf.parentPath) < wildcards(other.parentPath)
+}
+
+// Registry maps box-types to the factories that construct them. The package-
+// level registration functions operate on the default registry, which is what
+// the factories in this project register themselves with. A registry can be
+// cloned and then extended or overridden, and passed via ParseOptions, so that
+// the same box-type may be handled differently in different places.
+//
+// Factories may also be registered for a box-type under particular parents.
+// The most specific one that matches is used and the plain registration is the
+// fallback.
+type Registry struct {
+	boxMapping           map[string]BoxFactory
+	uuidBoxMapping       map[ExtendedType]BoxFactory
+	contextualBoxMapping map[string][]contextualBoxFactory
+}
+
+var (
+	defaultRegistry = NewRegistry()
+)
+
+// NewRegistry returns a new, empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		boxMapping:           make(map[string]BoxFactory),
+		uuidBoxMapping:       make(map[ExtendedType]BoxFactory),
+		contextualBoxMapping: make(map[string][]contextualBoxFactory),
+	}
+}
+
+// DefaultRegistry returns the registry that the package-level registration
+// functions operate on and that is used when no other registry is given.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Clone returns a copy of the registry. Changes to either will not affect the
+// other.
+func (r *Registry) Clone() *Registry {
+	clone := NewRegistry()
+
+	for name, bf := range r.boxMapping {
+		clone.boxMapping[name] = bf
+	}
+
+	for et, bf := range r.uuidBoxMapping {
+		clone.uuidBoxMapping[et] = bf
+	}
+
+	for name, factories := range r.contextualBoxMapping {
+		clone.contextualBoxMapping[name] = append([]contextualBoxFactory{}, factories...)
+	}
+
+	return clone
+}
+
+// Clear drops all registrations.
+func (r *Registry) Clear() {
+	r.boxMapping = make(map[string]BoxFactory)
+	r.uuidBoxMapping = make(map[ExtendedType]BoxFactory)
+	r.contextualBoxMapping = make(map[string][]contextualBoxFactory)
+}
+
+// RegisterBoxType registers the factory for a box-type. It panics if the
+// box-type is already registered.
+func (r *Registry) RegisterBoxType(bf BoxFactory) {
+	name := bf.Name()
+
+	if _, found := r.boxMapping[name]; found == true {
+		log.Panicf("box-factory already registered: [%s]", name)
+	}
+
+	r.boxMapping[name] = bf
+}
+
+// OverrideBoxType registers the factory for a box-type, replacing any factory
+// that is already registered.
+func (r *Registry) OverrideBoxType(bf BoxFactory) {
+	r.boxMapping[bf.Name()] = bf
+}
+
+// GetFactory returns the factory for the given box-type. Will return `nil` if
+// not known.
+func (r *Registry) GetFactory(name string) BoxFactory {
+	return r.boxMapping[name]
+}
+
+// BoxTypes returns the box-types that have a factory registered in general, in
+// sorted order. Registrations that only apply under certain parents and those
+// for "uuid" boxes are not included.
+func (r *Registry) BoxTypes() (names []string) {
+	names = make([]string, 0, len(r.boxMapping))
+	for name := range r.boxMapping {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// RegisterBoxTypeWithParentPath registers the factory for a box-type that only
+// applies when the ancestry of the box ends with the given path. The path is
+// slash-separated with the immediate parent last, and a "*" component matches
+// any box. For example, "ilst/*" matches any box whose grandparent is an ILST
+// box. It panics if the box-type is already registered with the same path.
+func (r *Registry) RegisterBoxTypeWithParentPath(parentPath string, bf BoxFactory) {
+	name := bf.Name()
+
+	components := strings.Split(parentPath, "/")
+	for _, component := range components {
+		if component != "*" && len(component) != 4 {
+			log.Panicf("parent path not valid: [%s]", parentPath)
+		}
+	}
+
+	for _, cbf := range r.contextualBoxMapping[name] {
+		if cbf.predicate == nil && strings.Join(cbf.parentPath, "/") == parentPath {
+			log.Panicf("box-factory already registered: [%s] under [%s]", name, parentPath)
+		}
+	}
+
+	cbf := contextualBoxFactory{
+		parentPath: components,
+		factory:    bf,
+	}
+
+	r.contextualBoxMapping[name] = append(r.contextualBoxMapping[name], cbf)
+}
+
+// RegisterBoxTypeWithParentPredicate registers the factory for a box-type that
+// only applies when the predicate accepts the parent of the box. Predicates
+// take precedence over parent paths and are tried in the order registered.
+func (r *Registry) RegisterBoxTypeWithParentPredicate(predicate ParentPredicate, bf BoxFactory) {
+	cbf := contextualBoxFactory{
+		predicate: predicate,
+		factory:   bf,
+	}
+
+	name := bf.Name()
+	r.contextualBoxMapping[name] = append(r.contextualBoxMapping[name], cbf)
+}
+
+// GetFactoryForParent returns the factory for the given box-type with the
+// given parent, which is nil for root boxes. The most specific factory
+// registered for the parent is returned, and then the factory registered for
+// the box-type in general. Will return `nil` if not known.
+func (r *Registry) GetFactoryForParent(name string, parent CommonBox) BoxFactory {
+	var best *contextualBoxFactory
+
+	factories := r.contextualBoxMapping[name]
+	for i, cbf := range factories {
+		if cbf.matches(parent) == false {
+			continue
+		}
+
+		if best == nil || cbf.isMoreSpecificThan(*best) == true {
+			best = &factories[i]
+		}
+	}
+
+	if best != nil {
+		return best.factory
+	}
+
+	return r.GetFactory(name)
+}
+
+// RegisterUuidBoxType registers the factory for "uuid" boxes with the given
+// extended type. It panics if the extended type is already registered.
+func (r *Registry) RegisterUuidBoxType(ubf UuidBoxFactory) {
+	et := ubf.ExtendedType()
+
+	if _, found := r.uuidBoxMapping[et]; found == true {
+		log.Panicf("uuid box-factory already registered: [%s]", et)
+	}
+
+	r.uuidBoxMapping[et] = ubf
+}
+
+// OverrideUuidBoxType registers the factory for "uuid" boxes with the given
+// extended type, replacing any factory that is already registered.
+func (r *Registry) OverrideUuidBoxType(ubf UuidBoxFactory) {
+	r.uuidBoxMapping[ubf.ExtendedType()] = ubf
+}
+
+// GetUuidFactory returns the factory for "uuid" boxes with the given extended
+// type. Will return `nil` if not known.
+func (r *Registry) GetUuidFactory(et ExtendedType) BoxFactory {
+	return r.uuidBoxMapping[et]
+}
+
+// getFactoryForType returns the factory for the given box-type and parent,
+// using the extended type for "uuid" boxes. Will return `nil` if not known.
+func (r *Registry) getFactoryForType(name string, et ExtendedType, parent CommonBox) BoxFactory {
+	if name == UuidBoxName {
+		return r.GetUuidFactory(et)
+	}
+
+	return r.GetFactoryForParent(name, parent)
+}
@@ -0,0 +1,350 @@
+package bmfcommon
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+)
+
+// testOverrideBox1Factory handles "tb1 " boxes differently.
+type testOverrideBox1Factory struct {
+	testEncoderBoxFactory
+}
+
+// Name returns the name of the type.
+func (testOverrideBox1Factory) Name() string {
+	return "tb1 "
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+
+	if DefaultRegistry() != defaultRegistry {
+		t.Fatalf("Default registry not correct.")
+	}
+
+	_ = DefaultRegistry().GetFactory("tb1 ").(testBox1Factory)
+}
+
+func TestRegistry_RegisterBoxType(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterBoxType(testBox1Factory{})
+
+	_ = r.GetFactory("tb1 ").(testBox1Factory)
+
+	if r.GetFactory("tb2 ") != nil {
+		t.Fatalf("Expected no factory for unregistered type.")
+	}
+}
+
+func TestRegistry_RegisterBoxType_AlreadyRegistered(t *testing.T) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err := errRaw.(error)
+			if err.Error() != "box-factory already registered: [tb1 ]" {
+				log.Panic(err)
+			}
+		} else {
+			t.Fatalf("Expected panic.")
+		}
+	}()
+
+	r := NewRegistry()
+	r.RegisterBoxType(testBox1Factory{})
+	r.RegisterBoxType(testBox1Factory{})
+}
+
+func TestRegistry_OverrideBoxType(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterBoxType(testBox1Factory{})
+	r.OverrideBoxType(testOverrideBox1Factory{})
+
+	_ = r.GetFactory("tb1 ").(testOverrideBox1Factory)
+}
+
+func TestRegistry_UuidBoxType(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterUuidBoxType(testUuidBoxFactory{})
+
+	_ = r.GetUuidFactory(testUuidBoxExtendedType).(testUuidBoxFactory)
+	_ = r.getFactoryForType(UuidBoxName, testUuidBoxExtendedType, nil).(testUuidBoxFactory)
+
+	if r.getFactoryForType(UuidBoxName, ExtendedType{}, nil) != nil {
+		t.Fatalf("Expected no factory for unregistered extended-type.")
+	}
+
+	r.OverrideUuidBoxType(testUuidBoxFactory{})
+
+	if len(r.uuidBoxMapping) != 1 {
+		t.Fatalf("Expected exactly one registration.")
+	}
+}
+
+func TestRegistry_Clone(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterBoxType(testBox1Factory{})
+	r.RegisterUuidBoxType(testUuidBoxFactory{})
+
+	clone := r.Clone()
+	clone.OverrideBoxType(testOverrideBox1Factory{})
+	clone.RegisterBoxType(testBox2Factory{})
+
+	_ = r.GetFactory("tb1 ").(testBox1Factory)
+	_ = clone.GetFactory("tb1 ").(testOverrideBox1Factory)
+	_ = clone.GetUuidFactory(testUuidBoxExtendedType).(testUuidBoxFactory)
+
+	if r.GetFactory("tb2 ") != nil {
+		t.Fatalf("Original registry was modified.")
+	}
+}
+
+func TestRegistry_BoxTypes(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterBoxType(testBox2Factory{})
+	r.RegisterBoxType(testBox1Factory{})
+	r.RegisterBoxTypeWithParentPath("tb1 ", testBox3Factory{})
+	r.RegisterUuidBoxType(testUuidBoxFactory{})
+
+	names := r.BoxTypes()
+
+	if reflect.DeepEqual(names, []string{"tb1 ", "tb2 "}) != true {
+		t.Fatalf("BoxTypes() not correct: %v", names)
+	}
+}
+
+func TestRegistry_Clear(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterBoxType(testBox1Factory{})
+	r.RegisterUuidBoxType(testUuidBoxFactory{})
+
+	r.Clear()
+
+	if len(r.boxMapping) != 0 || len(r.uuidBoxMapping) != 0 {
+		t.Fatalf("Registry not cleared.")
+	}
+}
+
+func TestNewResourceWithOptions_Registry(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+
+	r := DefaultRegistry().Clone()
+	r.OverrideBoxType(testOverrideBox1Factory{})
+
+	var b []byte
+	pushTestBox1(&b)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	options := ParseOptions{
+		Strict:   true,
+		Registry: r,
+	}
+
+	resource, err := NewResourceWithOptions(sb, int64(len(b)), options)
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes("tb1 ")
+	log.PanicIf(err)
+
+	_ = boxes[0].(*testEncoderBox)
+
+	// The default registry is unaffected.
+
+	sb = rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err = NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	boxes, err = resource.GetChildBoxes("tb1 ")
+	log.PanicIf(err)
+
+	_ = boxes[0].(*testBox1)
+}
+
+// testNamedBox is a stand-in parent with the given name and parent.
+type testNamedBox struct {
+	Box
+}
+
+func newTestNamedBox(name string, parent CommonBox) *testNamedBox {
+	box := NewBox(name, 0, 0, 8, nil)
+	box.parent = parent
+
+	return &testNamedBox{
+		Box: box,
+	}
+}
+
+// testPathBox1Factory handles "tb1 " boxes under a particular path.
+type testPathBox1Factory struct {
+	testOverrideBox1Factory
+
+	id string
+}
+
+func TestRegistry_GetFactoryForParent_Path(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterBoxType(testBox1Factory{})
+	r.RegisterBoxTypeWithParentPath("ilst/*", testPathBox1Factory{id: "ilst/*"})
+	r.RegisterBoxTypeWithParentPath("moov/ilst/*", testPathBox1Factory{id: "moov/ilst/*"})
+	r.RegisterBoxTypeWithParentPath("ilst/abcd", testPathBox1Factory{id: "ilst/abcd"})
+
+	ilst := newTestNamedBox("ilst", nil)
+	moov := newTestNamedBox("moov", nil)
+	moovIlst := newTestNamedBox("ilst", moov)
+
+	cases := []struct {
+		parent   CommonBox
+		expected string
+	}{
+		{newTestNamedBox("wxyz", ilst), "ilst/*"},
+		{newTestNamedBox("abcd", ilst), "ilst/abcd"},
+		{newTestNamedBox("wxyz", moovIlst), "moov/ilst/*"},
+		{newTestNamedBox("abcd", moovIlst), "moov/ilst/*"},
+	}
+
+	for _, c := range cases {
+		bf := r.GetFactoryForParent("tb1 ", c.parent)
+
+		if bf.(testPathBox1Factory).id != c.expected {
+			t.Fatalf("Factory not correct for parent [%s]: [%s] != [%s]", c.parent.Name(), bf.(testPathBox1Factory).id, c.expected)
+		}
+	}
+
+	// Fall back to the general registration.
+
+	_ = r.GetFactoryForParent("tb1 ", ilst).(testBox1Factory)
+	_ = r.GetFactoryForParent("tb1 ", nil).(testBox1Factory)
+}
+
+func TestRegistry_GetFactoryForParent_Predicate(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterBoxTypeWithParentPath("ilst", testPathBox1Factory{id: "path"})
+
+	r.RegisterBoxTypeWithParentPredicate(func(parent CommonBox) bool {
+		return parent == nil
+	}, testPathBox1Factory{id: "root"})
+
+	r.RegisterBoxTypeWithParentPredicate(func(parent CommonBox) bool {
+		return parent != nil && parent.Parent() == nil
+	}, testPathBox1Factory{id: "top"})
+
+	if r.GetFactoryForParent("tb1 ", nil).(testPathBox1Factory).id != "root" {
+		t.Fatalf("Expected root factory.")
+	}
+
+	// Predicates are more specific than paths.
+
+	ilst := newTestNamedBox("ilst", nil)
+
+	if r.GetFactoryForParent("tb1 ", ilst).(testPathBox1Factory).id != "top" {
+		t.Fatalf("Expected predicate factory.")
+	}
+
+	moov := newTestNamedBox("moov", nil)
+	moovIlst := newTestNamedBox("ilst", moov)
+
+	if r.GetFactoryForParent("tb1 ", moovIlst).(testPathBox1Factory).id != "path" {
+		t.Fatalf("Expected path factory.")
+	}
+
+	if r.GetFactoryForParent("tb1 ", newTestNamedBox("abcd", moov)) != nil {
+		t.Fatalf("Expected no factory.")
+	}
+}
+
+func TestRegistry_RegisterBoxTypeWithParentPath_AlreadyRegistered(t *testing.T) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err := errRaw.(error)
+			if err.Error() != "box-factory already registered: [tb1 ] under [ilst/*]" {
+				log.Panic(err)
+			}
+		} else {
+			t.Fatalf("Expected panic.")
+		}
+	}()
+
+	r := NewRegistry()
+	r.RegisterBoxTypeWithParentPath("ilst/*", testBox1Factory{})
+	r.RegisterBoxTypeWithParentPath("ilst/*", testBox1Factory{})
+}
+
+func TestRegistry_RegisterBoxTypeWithParentPath_Invalid(t *testing.T) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err := errRaw.(error)
+			if err.Error() != "parent path not valid: [ilst//abcd]" {
+				log.Panic(err)
+			}
+		} else {
+			t.Fatalf("Expected panic.")
+		}
+	}()
+
+	r := NewRegistry()
+	r.RegisterBoxTypeWithParentPath("ilst//abcd", testBox1Factory{})
+}
+
+func TestRegistry_Clone_Contextual(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterBoxTypeWithParentPath("ilst", testPathBox1Factory{id: "original"})
+
+	clone := r.Clone()
+	clone.RegisterBoxTypeWithParentPath("moov/ilst", testPathBox1Factory{id: "clone"})
+
+	moov := newTestNamedBox("moov", nil)
+	moovIlst := newTestNamedBox("ilst", moov)
+
+	if r.GetFactoryForParent("tb1 ", moovIlst).(testPathBox1Factory).id != "original" {
+		t.Fatalf("Original registry was modified.")
+	} else if clone.GetFactoryForParent("tb1 ", moovIlst).(testPathBox1Factory).id != "clone" {
+		t.Fatalf("Clone not correct.")
+	}
+}
+
+func TestNewResource_ParentPath(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+	RegisterBoxType(testBox3Factory{})
+	RegisterBoxTypeWithParentPath("tb3 /tb3 ", testOverrideBox1Factory{})
+
+	var inner []byte
+	pushTestBox1(&inner)
+
+	var outer []byte
+	pushTestBox1(&outer)
+	pushTestBox3(&outer, inner)
+
+	var b []byte
+	pushTestBox3(&b, outer)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	index := resource.Index()
+
+	ibe := IndexedBoxEntry{
+		NamePhrase: "tb3 .tb1 ",
+	}
+
+	_ = index[ibe].(*testBox1)
+
+	ibe = IndexedBoxEntry{
+		NamePhrase: "tb3 .tb3 .tb1 ",
+	}
+
+	_ = index[ibe].(*testEncoderBox)
+}
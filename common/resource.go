@@ -1,7 +1,10 @@
 package bmfcommon
 
 import (
+	"errors"
 	"io"
+	"math"
+	"sync"
 
 	"encoding/binary"
 
@@ -12,14 +15,93 @@ var (
 	resourceLogger = log.NewLogger("bmfcommon.resource")
 )
 
-// Resource defines a file structure.
+// ParseOptions adjusts how a resource is parsed. Note that the zero value is
+// lenient whereas NewResource() parses strictly.
+type ParseOptions struct {
+	// Lazy defers running the box factories until a box is first accessed.
+	// Only the headers of the root boxes are read up front.
+	Lazy bool
+
+	// Strict fails the whole parse on the first anomaly. Otherwise, a box that
+	// can not be parsed is recorded as a Diagnostic and skipped, along with its
+	// children, and parsing continues.
+	Strict bool
+
+	// Registry provides the box factories. The default registry is used if
+	// this is nil.
+	Registry *Registry
+
+	// Limits bounds what parsing may consume. Unset limits use
+	// DefaultParseLimits.
+	Limits ParseLimits
+}
+
+// registry returns the registry to use.
+func (po ParseOptions) registry() *Registry {
+	if po.Registry == nil {
+		return defaultRegistry
+	}
+
+	return po.Registry
+}
+
+// readSeekerReaderAt adapts an io.ReadSeeker to io.ReaderAt. Every read needs
+// a seek, so reads are serialized.
+type readSeekerReaderAt struct {
+	rs    io.ReadSeeker
+	mutex sync.Mutex
+}
+
+// ReadAt reads len(p) bytes from the given offset.
+func (rsra *readSeekerReaderAt) ReadAt(p []byte, offset int64) (n int, err error) {
+	rsra.mutex.Lock()
+	defer rsra.mutex.Unlock()
+
+	_, err = rsra.rs.Seek(offset, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err = io.ReadFull(rsra.rs, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+
+	return n, err
+}
+
+// Resource defines a file structure. All reads are positional, so the boxes of
+// a resource that was not parsed lazily can be read from concurrently.
 type Resource struct {
-	rs           io.ReadSeeker
+	ra           io.ReaderAt
+	size         int64
 	isFragmented bool
 
-	// fullBoxIndex has all [known] boxes encountered in the stream.
+	// closer releases the file or mapping if the resource opened it.
+	closer io.Closer
+
+	options ParseOptions
+
+	// decodingDepth is greater than zero while a lazy box is being decoded.
+	decodingDepth int
+
+	// fullBoxIndexOrdered is false if lazy boxes have been decoded since the
+	// full index was last put into stream order.
+	fullBoxIndexOrdered bool
+
+	// fullBoxIndex has all boxes encountered in the stream.
 	fullBoxIndex FullBoxIndex
 
+	// diagnostics has the anomalies that were skipped in lenient mode.
+	diagnostics []Diagnostic
+
+	// boxCount is the number of boxes read so far.
+	boxCount int
+
+	// limitErr is set once MaxBoxes is exceeded. Parsing then fails even in
+	// lenient mode, since skipping each remaining box would not be bounded.
+	limitErr error
+
 	// LoadedBoxIndex contains this box's children.
 	LoadedBoxIndex
 }
@@ -32,12 +114,63 @@ func NewResource(rs io.ReadSeeker, size int64) (resource *Resource, err error) {
 		}
 	}()
 
-	// This has all [known] boxes encountered in the stream.
+	options := ParseOptions{
+		Strict: true,
+	}
+
+	resource, err = NewResourceWithOptions(rs, size, options)
+	log.PanicIf(err)
+
+	return resource, nil
+}
+
+// NewResourceWithOptions returns a new Resource struct parsed with the given
+// options. If the ReadSeeker also implements io.ReaderAt (as *os.File does),
+// that is used instead. Otherwise, reads are serialized so that they do not
+// interfere with each other.
+func NewResourceWithOptions(rs io.ReadSeeker, size int64, options ParseOptions) (resource *Resource, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	ra, ok := rs.(io.ReaderAt)
+	if ok == false {
+		ra = &readSeekerReaderAt{
+			rs: rs,
+		}
+	}
+
+	resource, err = NewResourceFromReaderAt(ra, size, options)
+	log.PanicIf(err)
+
+	return resource, nil
+}
+
+// NewResourceFromReaderAt returns a new Resource struct parsed with the given
+// options. Only positional reads are used, so the resource may be shared by
+// goroutines that read boxes and extract data. Lazy boxes are decoded when
+// first accessed, which is not safe to do concurrently; call Index() first to
+// decode everything before sharing a lazily-parsed resource.
+func NewResourceFromReaderAt(ra io.ReaderAt, size int64, options ParseOptions) (resource *Resource, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	// This has all boxes encountered in the stream.
 	fullBoxIndex := make(FullBoxIndex)
 
 	resource = &Resource{
-		rs:           rs,
+		ra:           ra,
+		size:         size,
+		options:      options,
 		fullBoxIndex: fullBoxIndex,
+
+		// Nothing has been decoded yet in lazy mode.
+		fullBoxIndexOrdered: options.Lazy == false,
 	}
 
 	resourceLogger.Debugf(nil, "Parsing stream with (%d) bytes.", size)
@@ -53,31 +186,165 @@ func NewResource(rs io.ReadSeeker, size int64) (resource *Resource, err error) {
 	return resource, nil
 }
 
-// Index returns the complete index of the boxes found in the parsed file.
+// Index returns the complete index of the boxes found in the parsed file. In
+// lazy mode, this decodes every box that has not been decoded yet since the
+// index can only describe decoded boxes. Use GetChildBoxes() to stay lazy. In
+// lenient mode, boxes that fail to decode are recorded as diagnostics and left
+// out of the index. In strict mode, such a failure panics; call DecodeAll()
+// first to get it as an error.
 func (f *Resource) Index() FullBoxIndex {
+	if f.options.Lazy == true && f.decodingDepth == 0 && f.fullBoxIndexOrdered == false {
+		err := f.decodeAll()
+		log.PanicIf(err)
+	}
+
 	return f.fullBoxIndex
 }
 
-// readBytesAt reads a box at n and offset.
-func (f *Resource) readBytesAt(offset int64, n int64) (b []byte, err error) {
+// Diagnostics returns the anomalies that were skipped while parsing in lenient
+// mode, in the order encountered.
+func (f *Resource) Diagnostics() []Diagnostic {
+	return f.diagnostics
+}
+
+// addDiagnostic records an anomaly at the given offset. The name is empty if
+// the box header could not be read.
+func (f *Resource) addDiagnostic(offset int64, parent CommonBox, name string, severity DiagnosticSeverity, err error) {
+	fqbn := f.fullBoxIndex.getBoxName(parent)
+	if name != "" {
+		fqbn = append(fqbn, name)
+	}
+
+	d := Diagnostic{
+		Offset:   offset,
+		Path:     fqbn.String(),
+		Severity: severity,
+		Message:  err.Error(),
+	}
+
+	resourceLogger.Warningf(nil, "Skipping box: %s", d)
+
+	f.diagnostics = append(f.diagnostics, d)
+}
+
+// DecodeAll decodes every lazy box that has not been decoded yet. In strict
+// mode, the first failure is returned. In lenient mode, failures are recorded
+// as diagnostics instead. This does nothing if the resource was not parsed
+// lazily.
+func (f *Resource) DecodeAll() (err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
-	b = make([]byte, n)
+	if f.options.Lazy == true && f.fullBoxIndexOrdered == false {
+		err := f.decodeAll()
+		log.PanicIf(err)
+	}
+
+	return nil
+}
 
-	_, err = f.rs.Seek(offset, io.SeekStart)
+// decodeAll decodes every lazy box and then rebuilds the full index in stream
+// order, since boxes may have been decoded out of order.
+func (f *Resource) decodeAll() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	ordered := make([]CommonBox, 0)
+
+	var walk func(bci BoxChildIndexer)
+	walk = func(bci BoxChildIndexer) {
+		for _, child := range sortedChildBoxes(bci, true) {
+			ordered = append(ordered, child)
+
+			if childBci, ok := child.(BoxChildIndexer); ok == true {
+				walk(childBci)
+			}
+		}
+	}
+
+	walk(f)
+
+	for ibe := range f.fullBoxIndex {
+		delete(f.fullBoxIndex, ibe)
+	}
+
+	for _, cb := range ordered {
+		f.fullBoxIndex.Add(cb)
+	}
+
+	f.fullBoxIndexOrdered = true
+
+	return nil
+}
+
+// Size returns the size of the resource.
+func (f *Resource) Size() int64 {
+	return f.size
+}
+
+// CopyBytesAt copies N bytes from the given offset in the resource to the
+// given writer.
+func (f *Resource) CopyBytesAt(offset int64, n int64, w io.Writer) (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	err = f.copyBytesAt(offset, n, w)
 	log.PanicIf(err)
 
-	_, err = f.rs.Read(b)
+	return nil
+}
+
+// readBytesAt reads N bytes from the given offset. A short read is an error.
+// If the resource is memory-mapped, the bytes are not copied and must not be
+// modified.
+func (f *Resource) readBytesAt(offset int64, n int64) (b []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if mf, ok := f.ra.(*mappedFile); ok == true {
+		b = mf.slice(offset, n)
+		if int64(len(b)) != n {
+			log.Panic(NewErrTruncated(nil, offset, n, int64(len(b))))
+		}
+
+		return b, nil
+	}
+
+	if f.size > 0 && offset+n > f.size {
+		log.Panic(NewErrTruncated(nil, offset, n, f.size-offset))
+	}
+
+	err = checkLimit("MaxPayloadSize", n, f.limits().MaxPayloadSize)
 	log.PanicIf(err)
 
+	b = make([]byte, n)
+
+	read, err := f.ra.ReadAt(b, offset)
+	if int64(read) != n {
+		if err == io.EOF {
+			log.Panic(NewErrTruncated(nil, offset, n, int64(read)))
+		}
+
+		log.PanicIf(err)
+	}
+
 	return b, nil
 }
 
-// copyBytesAt seeksand then copies N bytes from the resource to the writer.
+// copyBytesAt copies N bytes from the given offset in the resource to the
+// writer. A short read is an error.
 func (f *Resource) copyBytesAt(offset int64, n int64, w io.Writer) (err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
@@ -85,55 +352,70 @@ func (f *Resource) copyBytesAt(offset int64, n int64, w io.Writer) (err error) {
 		}
 	}()
 
-	_, err = f.rs.Seek(offset, io.SeekStart)
-	log.PanicIf(err)
+	if mf, ok := f.ra.(*mappedFile); ok == true {
+		b := mf.slice(offset, n)
+
+		if int64(len(b)) != n {
+			log.Panic(NewErrTruncated(nil, offset, n, int64(len(b))))
+		}
 
-	_, err = io.CopyN(w, f.rs, n)
+		_, err := w.Write(b)
+		log.PanicIf(err)
+
+		return nil
+	}
+
+	sr := io.NewSectionReader(f.ra, offset, n)
+
+	copied, err := io.Copy(w, sr)
 	log.PanicIf(err)
 
+	if copied != n {
+		log.Panic(NewErrTruncated(nil, offset, n, copied))
+	}
+
 	return nil
 }
 
-// readBaseBox reads a box from an offset.
-func (f *Resource) readBaseBox(offset int64) (box Box, err error) {
+// readBoxHeader reads a box header from the current position of the reader.
+// The offset is only used for messages. A size of (0) is returned as-is for
+// boxes that extend to the end of their container. The extended type is only
+// read for "uuid" boxes.
+func readBoxHeader(r io.Reader, offset int64) (boxType string, extendedType ExtendedType, boxSize, headerSize int64, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
-	_, err = f.rs.Seek(offset, io.SeekStart)
-	log.PanicIf(err)
-
 	// Read 32-bit box-size.
 
 	var rawBoxSize uint32
 
-	err = binary.Read(f.rs, DefaultEndianness, &rawBoxSize)
+	err = binary.Read(r, DefaultEndianness, &rawBoxSize)
 	log.PanicIf(err)
 
-	boxSize := int64(rawBoxSize)
+	boxSize = int64(rawBoxSize)
 
 	// Read box-type.
 
 	boxTypeRaw := make([]byte, 4)
 
-	_, err = io.ReadFull(f.rs, boxTypeRaw)
+	_, err = io.ReadFull(r, boxTypeRaw)
 	log.PanicIf(err)
 
-	boxType := string(boxTypeRaw)
+	boxType = string(boxTypeRaw)
 
 	// We'll interpret everything as data. So, if there is good data
 	// followed by garbage, we may interpret the garbage as well. So, if we
 	// see a box with an invalid name, panic as soon as possible.
 	if BoxNameIsValid(boxType) == false {
-		log.Panicf(
-			"box starting at offset (0x%016x) looks like garbage",
-			offset)
+		log.Panic(&ErrInvalidBoxName{
+			Offset: offset,
+			Name:   boxType,
+		})
 	}
 
-	var headerSize int64
-
 	if boxSize > 1 {
 		// We have an alternative 32-bit box-size.
 
@@ -150,7 +432,7 @@ func (f *Resource) readBaseBox(offset int64) (box Box, err error) {
 
 		var rawBoxSize uint64
 
-		err = binary.Read(f.rs, DefaultEndianness, &rawBoxSize)
+		err = binary.Read(r, DefaultEndianness, &rawBoxSize)
 		log.PanicIf(err)
 
 		if rawBoxSize > 0x7FFFFFFFFFFFFFFF {
@@ -158,17 +440,73 @@ func (f *Resource) readBaseBox(offset int64) (box Box, err error) {
 		}
 
 		boxSize = int64(rawBoxSize)
-	} else if boxSize == 0 {
-		// TODO(dustin): Come back to this when we have a supporting example.
-		log.Panicf("box [%s] size is (0) and unhandled", boxType)
+	} else {
+		// The box extends to the end of its container. The caller will have
+		// to determine the size.
+
+		headerSize = 8
+	}
+
+	if boxType == UuidBoxName {
+		_, err = io.ReadFull(r, extendedType[:])
+		log.PanicIf(err)
+
+		headerSize += int64(len(extendedType))
+	}
+
+	return boxType, extendedType, boxSize, headerSize, nil
+}
+
+// readBaseBox reads a box from an offset. A box stored with a size of (0) is
+// given the space remaining up to the end offset.
+func (f *Resource) readBaseBox(offset int64, end int64) (box Box, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	// The header is not bounded by the size of the resource here. The extent
+	// of the box is checked by the callers.
+	cr := &countingReader{
+		r: io.NewSectionReader(f.ra, offset, math.MaxInt64-offset),
+	}
+
+	boxType, extendedType, boxSize, headerSize, err := readBoxHeader(cr, offset)
+	if errors.Is(err, io.EOF) == true || errors.Is(err, io.ErrUnexpectedEOF) == true {
+		// The partial results tell us how much of the header was needed.
+
+		required := headerSize
+		if boxType == "" {
+			required = 8
+		} else if boxType == UuidBoxName {
+			required += int64(len(extendedType))
+		}
+
+		log.Panic(NewErrTruncated(nil, offset, required, cr.n))
+	}
+
+	log.PanicIf(err)
+
+	implicitSize := false
+	if boxSize == 0 {
+		boxSize = end - offset
+		implicitSize = true
+
+		resourceLogger.Debugf(nil,
+			"Box [%s] at offset (0x%016x) extends to the end of its container with size (%d).",
+			boxType, offset, boxSize)
 	}
 
 	box = NewBox(boxType, offset, boxSize, headerSize, f)
+	box.implicitSize = implicitSize
+	box.extendedType = extendedType
 
 	return box, nil
 }
 
-// ReadBaseBox reads the base box at the given offset. Supports testing.
+// ReadBaseBox reads the base box at the given offset. A box stored with a size
+// of (0) is assumed to extend to the end of the resource. Supports testing.
 func (f *Resource) ReadBaseBox(offset int64) (box Box, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
@@ -176,34 +514,99 @@ func (f *Resource) ReadBaseBox(offset int64) (box Box, err error) {
 		}
 	}()
 
-	box, err = f.readBaseBox(offset)
+	box, err = f.readBaseBox(offset, f.size)
 	log.PanicIf(err)
 
 	return box, nil
 }
 
-func readBox(f *Resource, parent CommonBox, offset int64) (cb CommonBox, known bool, err error) {
+// readBox reads the box at the given offset. The box may not extend past the
+// given end offset. Boxes without a registered factory are returned as a
+// RawBox with `known` set to false.
+func readBox(f *Resource, parent CommonBox, offset int64, end int64) (cb CommonBox, known bool, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
-	box, err := f.ReadBaseBox(offset)
+	limits := f.limits()
+
+	f.boxCount++
+
+	err = checkLimit("MaxBoxes", int64(f.boxCount), int64(limits.MaxBoxes))
+	if err != nil {
+		f.limitErr = err
+		log.Panic(err)
+	}
+
+	depth := 1
+	for p := parent; p != nil; p = p.Parent() {
+		depth++
+	}
+
+	err = checkLimit("MaxDepth", int64(depth), int64(limits.MaxDepth))
 	log.PanicIf(err)
 
+	box, err := f.readBaseBox(offset, end)
+	if err != nil {
+		var eibn *ErrInvalidBoxName
+		if errors.As(err, &eibn) == true {
+			eibn.Parent = boxPath(parent)
+		}
+
+		setTruncatedBox(err, parent)
+
+		log.Panic(err)
+	}
+
 	box.parent = parent
 
+	if box.Size() < box.HeaderSize() {
+		log.Panicf("box [%s] has a size (%d) smaller than its header (%d)", box.Name(), box.Size(), box.HeaderSize())
+	} else if offset+box.Size() > end {
+		log.Panic(NewErrTruncated(box, offset, box.Size(), end-offset))
+	}
+
 	name := box.Name()
 
-	bf := GetFactory(name)
+	bf := f.options.registry().getFactoryForType(name, box.ExtendedType(), parent)
 
 	if bf == nil {
-		resourceLogger.Warningf(nil, "No factory registered for box-type [%s].", name)
-		return box, false, nil
+		if name == UuidBoxName {
+			resourceLogger.Warningf(nil, "No factory registered for uuid box with extended-type [%s].", box.ExtendedType())
+		} else {
+			resourceLogger.Warningf(nil, "No factory registered for box-type [%s].", name)
+		}
+
+		rb := newRawBox(box)
+		f.fullBoxIndex.Add(rb)
+
+		return rb, false, nil
 	}
 
-	// Construct the type-specific box.
+	if f.options.Lazy == true {
+		lb := &LazyBox{
+			Box:     box,
+			factory: bf,
+		}
+
+		return lb, true, nil
+	}
+
+	cb, err = decodeBox(f, box, bf)
+	log.PanicIf(err)
+
+	return cb, true, nil
+}
+
+// decodeBox constructs the type-specific box and reads its children.
+func decodeBox(f *Resource, box Box, bf BoxFactory) (cb CommonBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
 
 	cb, childBoxSeriesOffset, err := bf.New(box)
 	log.PanicIf(err)
@@ -211,6 +614,10 @@ func readBox(f *Resource, parent CommonBox, offset int64) (cb CommonBox, known b
 	f.fullBoxIndex.Add(cb)
 
 	if childBoxSeriesOffset >= 0 {
+		if cbso, ok := cb.(childBoxSeriesOffsetSetter); ok == true {
+			cbso.setChildBoxSeriesOffset(int64(childBoxSeriesOffset))
+		}
+
 		boxes, err := box.ReadBoxes(childBoxSeriesOffset, cb)
 		log.PanicIf(err)
 
@@ -218,7 +625,7 @@ func readBox(f *Resource, parent CommonBox, offset int64) (cb CommonBox, known b
 		cbis.SetLoadedBoxIndex(boxes)
 	}
 
-	return cb, true, nil
+	return cb, nil
 }
 
 func readBoxes(f *Resource, parent CommonBox, start int64, totalSize int64) (boxes Boxes, err error) {
@@ -229,22 +636,44 @@ func readBoxes(f *Resource, parent CommonBox, start int64, totalSize int64) (box
 	}()
 
 	parentName := GetParentBoxName(parent)
+	end := start + totalSize
 
 	i := 0
-	for offset := start; offset < start+totalSize; {
+	for offset := start; offset < end; {
 		resourceLogger.Debugf(nil, "[%s] Reading child (%d) box at offset (0x%016x).", parentName, i, offset)
 
-		cb, known, err := readBox(f, parent, offset)
-		log.PanicIf(err)
+		cb, _, err := readBox(f, parent, offset, end)
+		if err != nil {
+			if f.options.Strict == true || f.limitErr != nil {
+				log.Panic(err)
+			}
+
+			// If the header is sound, skip just this box. Otherwise, we can
+			// not find the next sibling and have to skip the rest.
+
+			box, headerErr := f.readBaseBox(offset, end)
+			if headerErr != nil {
+				f.addDiagnostic(offset, parent, "", DiagnosticSeverityError, err)
+				break
+			} else if box.Size() < box.HeaderSize() || offset+box.Size() > end {
+				f.addDiagnostic(offset, parent, box.Name(), DiagnosticSeverityError, err)
+				break
+			}
+
+			f.addDiagnostic(offset, parent, box.Name(), DiagnosticSeverityWarning, err)
 
-		if known == true {
-			boxes = append(boxes, cb)
-		} else {
 			// We insert nil entries to maintain the integrity of the child
-			// list.
+			// list. The bytes are still copied through when encoding.
 			boxes = append(boxes, nil)
+
+			offset += box.Size()
+			i++
+
+			continue
 		}
 
+		boxes = append(boxes, cb)
+
 		name := cb.Name()
 		size := cb.Size()
 
@@ -254,5 +683,13 @@ func readBoxes(f *Resource, parent CommonBox, start int64, totalSize int64) (box
 		i++
 	}
 
+	// Lazy boxes need their preceding siblings in order to decode.
+	for i, cb := range boxes {
+		if lb, ok := cb.(*LazyBox); ok == true {
+			lb.siblings = boxes
+			lb.position = i
+		}
+	}
+
 	return boxes, nil
 }
@@ -2,7 +2,11 @@ package bmfcommon
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/dsoprea/go-logging"
@@ -138,6 +142,79 @@ func TestResource_copyBytesAt(t *testing.T) {
 	}
 }
 
+func TestResource_readBytesAt_Truncated(t *testing.T) {
+	sb := rifs.NewSeekableBufferWithBytes([]byte{1, 2, 3, 4})
+
+	resource, err := NewResource(sb, 0)
+	log.PanicIf(err)
+
+	_, err = resource.readBytesAt(2, 4)
+	if err == nil {
+		t.Fatalf("Expected error for short read.")
+	} else if err.Error() != "read of (4) bytes at offset (0x0000000000000002) is truncated: (2)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+}
+
+func TestResource_copyBytesAt_Truncated(t *testing.T) {
+	sb := rifs.NewSeekableBufferWithBytes([]byte{1, 2, 3, 4})
+
+	resource, err := NewResource(sb, 0)
+	log.PanicIf(err)
+
+	b := new(bytes.Buffer)
+
+	err = resource.copyBytesAt(2, 4, b)
+	if err == nil {
+		t.Fatalf("Expected error for short read.")
+	} else if err.Error() != "read of (4) bytes at offset (0x0000000000000002) is truncated: (2)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	var et *ErrTruncated
+	if errors.As(err, &et) != true {
+		t.Fatalf("Expected ErrTruncated.")
+	} else if et.Offset != 2 || et.Size != 4 || et.Available != 2 {
+		t.Fatalf("ErrTruncated not correct: %v", et)
+	}
+}
+
+func TestResource_CopyBytesAt(t *testing.T) {
+	data := []byte{
+		0, 0, 0, 0,
+		1, 2, 3, 4, 5,
+		0, 0, 0, 0, 0,
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(data)
+
+	resource, err := NewResource(sb, 0)
+	log.PanicIf(err)
+
+	b := new(bytes.Buffer)
+
+	err = resource.CopyBytesAt(4, 5, b)
+	log.PanicIf(err)
+
+	if bytes.Equal(b.Bytes(), data[4:9]) != true {
+		t.Fatalf("Copied bytes not correct.")
+	}
+}
+
+func TestResource_Size(t *testing.T) {
+	var buffer []byte
+	PushBox(&buffer, "abcd", []byte{6, 7, 8, 9})
+
+	sb := rifs.NewSeekableBufferWithBytes(buffer)
+
+	resource, err := NewResource(sb, int64(len(buffer)))
+	log.PanicIf(err)
+
+	if resource.Size() != int64(len(buffer)) {
+		t.Fatalf("Size() not correct.")
+	}
+}
+
 func TestResource_readBaseBox_32(t *testing.T) {
 	var buffer []byte
 	PushBox(&buffer, "abcd", []byte{6, 7, 8, 9})
@@ -147,7 +224,7 @@ func TestResource_readBaseBox_32(t *testing.T) {
 	resource, err := NewResource(sb, int64(len(buffer)))
 	log.PanicIf(err)
 
-	box, err := resource.readBaseBox(0)
+	box, err := resource.readBaseBox(0, resource.Size())
 	log.PanicIf(err)
 
 	if box.Size() != int64(12) {
@@ -166,7 +243,7 @@ func TestResource_readBaseBox_64(t *testing.T) {
 	resource, err := NewResource(sb, int64(len(buffer)))
 	log.PanicIf(err)
 
-	box, err := resource.readBaseBox(0)
+	box, err := resource.readBaseBox(0, resource.Size())
 	log.PanicIf(err)
 
 	if box.Size() != int64(20) {
@@ -188,7 +265,7 @@ func TestResource_readBaseBox_Front(t *testing.T) {
 	resource, err := NewResource(sb, int64(len(data)))
 	log.PanicIf(err)
 
-	box, err := resource.readBaseBox(0)
+	box, err := resource.readBaseBox(0, resource.Size())
 	log.PanicIf(err)
 
 	if box.Size() != int64(12) {
@@ -214,7 +291,7 @@ func TestResource_readBaseBox_Middle(t *testing.T) {
 	resource, err := NewResource(sb, int64(len(data)))
 	log.PanicIf(err)
 
-	box, err := resource.readBaseBox(12)
+	box, err := resource.readBaseBox(12, resource.Size())
 	log.PanicIf(err)
 
 	if box.Size() != int64(16) {
@@ -337,7 +414,7 @@ func TestReadBox(t *testing.T) {
 	resource, err := NewResource(sb, size)
 	log.PanicIf(err)
 
-	cb1, known1, err := readBox(resource, nil, 0)
+	cb1, known1, err := readBox(resource, nil, 0, resource.Size())
 	log.PanicIf(err)
 
 	if known1 != true {
@@ -346,7 +423,7 @@ func TestReadBox(t *testing.T) {
 		t.Fatalf("First box name not correct.")
 	}
 
-	cb2, known2, err := readBox(resource, nil, 16)
+	cb2, known2, err := readBox(resource, nil, 16, resource.Size())
 	log.PanicIf(err)
 
 	if known2 != true {
@@ -355,7 +432,7 @@ func TestReadBox(t *testing.T) {
 		t.Fatalf("Second box name not correct.")
 	}
 
-	cb3, known3, err := readBox(resource, nil, 24)
+	cb3, known3, err := readBox(resource, nil, 24, resource.Size())
 	log.PanicIf(err)
 
 	if known3 != false {
@@ -378,7 +455,7 @@ func TestReadBox_InvalidBoxName(t *testing.T) {
 	resource, err := NewResource(sb, 0)
 	log.PanicIf(err)
 
-	_, _, err = readBox(resource, nil, 0)
+	_, _, err = readBox(resource, nil, 0, resource.Size())
 	if err == nil {
 		t.Fatalf("Expected error.")
 	} else if err.Error() != "box starting at offset (0x0000000000000000) looks like garbage" {
@@ -410,7 +487,7 @@ func TestReadBox_WithChildBoxes(t *testing.T) {
 	resource, err := NewResource(sb, size)
 	log.PanicIf(err)
 
-	cb, _, err := readBox(resource, nil, 0)
+	cb, _, err := readBox(resource, nil, 0, resource.Size())
 	log.PanicIf(err)
 
 	if cb.Name() != "tb3 " {
@@ -486,3 +563,376 @@ func TestReadBoxes(t *testing.T) {
 		t.Fatalf("The second string is not correct.")
 	}
 }
+
+func TestNewResource_Strict(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox2Factory{})
+
+	// TB2 boxes need eight bytes.
+
+	var b []byte
+	pushTestBox2(&b, []byte{1, 2})
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	_, err := NewResource(sb, int64(len(b)))
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+}
+
+func TestNewResourceWithOptions_Lenient_SkipBox(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+	RegisterBoxType(testBox2Factory{})
+
+	// TB2 boxes need eight bytes.
+
+	var b []byte
+	pushTestBox2(&b, []byte{1, 2})
+	pushTestBox1(&b)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResourceWithOptions(sb, int64(len(b)), ParseOptions{})
+	log.PanicIf(err)
+
+	diagnostics := resource.Diagnostics()
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected one diagnostic: %v", diagnostics)
+	}
+
+	d := diagnostics[0]
+
+	if d.Offset != 0 || d.Path != "tb2 " || d.Severity != DiagnosticSeverityWarning {
+		t.Fatalf("Diagnostic not correct: %s", d)
+	}
+
+	if _, found := resource.LoadedBoxIndex["tb2 "]; found != false {
+		t.Fatalf("Bad box should not be loaded.")
+	} else if _, found := resource.LoadedBoxIndex["tb1 "]; found != true {
+		t.Fatalf("Sibling after bad box not loaded.")
+	}
+}
+
+func TestNewResourceWithOptions_Lenient_Truncated(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+	RegisterBoxType(testBox3Factory{})
+
+	// The second child claims to be larger than its parent.
+
+	var children []byte
+	pushTestBox1(&children)
+	PushBytes(&children, uint32(100))
+	PushBytes(&children, []byte("tb1 "))
+
+	var b []byte
+	pushTestBox3(&b, children)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResourceWithOptions(sb, int64(len(b)), ParseOptions{})
+	log.PanicIf(err)
+
+	diagnostics := resource.Diagnostics()
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected one diagnostic: %v", diagnostics)
+	}
+
+	d := diagnostics[0]
+
+	if d.Offset != 16 || d.Path != "tb3 .tb1 " || d.Severity != DiagnosticSeverityError {
+		t.Fatalf("Diagnostic not correct: %s", d)
+	}
+
+	ibe := IndexedBoxEntry{
+		NamePhrase:     "tb3 .tb1 ",
+		SequenceNumber: 0,
+	}
+
+	if _, found := resource.Index()[ibe]; found != true {
+		t.Fatalf("Child before truncated box not loaded.")
+	}
+}
+
+func TestNewResourceWithOptions_Lenient_Garbage(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+
+	var b []byte
+	pushTestBox1(&b)
+	PushBytes(&b, []byte{0, 0, 0, 8, 1, 2, 3, 4})
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResourceWithOptions(sb, int64(len(b)), ParseOptions{})
+	log.PanicIf(err)
+
+	diagnostics := resource.Diagnostics()
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected one diagnostic: %v", diagnostics)
+	}
+
+	d := diagnostics[0]
+
+	if d.Offset != 8 || d.Path != "" || d.Severity != DiagnosticSeverityError {
+		t.Fatalf("Diagnostic not correct: %s", d)
+	} else if d.Message != "box starting at offset (0x0000000000000008) looks like garbage" {
+		t.Fatalf("Diagnostic message not correct: [%s]", d.Message)
+	}
+
+	if _, found := resource.LoadedBoxIndex["tb1 "]; found != true {
+		t.Fatalf("Box before garbage not loaded.")
+	}
+}
+
+func TestResource_readBaseBox_ImplicitSize(t *testing.T) {
+	var b []byte
+	pushTestBox1(&b)
+	pushImplicitSizeBox(&b, "tb2 ", []byte("abcdefgh"))
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource := &Resource{
+		ra:   &readSeekerReaderAt{rs: sb},
+		size: int64(len(b)),
+	}
+
+	box, err := resource.readBaseBox(8, resource.Size())
+	log.PanicIf(err)
+
+	if box.Name() != "tb2 " {
+		t.Fatalf("Name not correct: [%s]", box.Name())
+	} else if box.Size() != 16 {
+		t.Fatalf("Size not correct: (%d)", box.Size())
+	} else if box.HeaderSize() != 8 {
+		t.Fatalf("Header size not correct: (%d)", box.HeaderSize())
+	} else if box.IsSizeImplicit() != true {
+		t.Fatalf("Size should be implicit.")
+	}
+
+	box, err = resource.readBaseBox(0, resource.Size())
+	log.PanicIf(err)
+
+	if box.IsSizeImplicit() != false {
+		t.Fatalf("Size should not be implicit.")
+	}
+}
+
+func TestNewResource_ImplicitSize_Nested(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+	RegisterBoxType(testBox2Factory{})
+	RegisterBoxType(testBox3Factory{})
+
+	// The implicit size only extends to the end of the parent.
+
+	var children []byte
+	pushTestBox1(&children)
+	pushImplicitSizeBox(&children, "tb2 ", []byte("abcdefgh"))
+
+	var b []byte
+	pushTestBox3(&b, children)
+	pushTestBox1(&b)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	ibe := IndexedBoxEntry{
+		NamePhrase:     "tb3 .tb2 ",
+		SequenceNumber: 0,
+	}
+
+	tb2 := resource.Index()[ibe].(*testBox2)
+
+	if tb2.Size() != 16 {
+		t.Fatalf("Size not correct: (%d)", tb2.Size())
+	} else if tb2.IsSizeImplicit() != true {
+		t.Fatalf("Size should be implicit.")
+	} else if tb2.String2() != "efgh" {
+		t.Fatalf("Data not correct.")
+	}
+
+	if _, found := resource.LoadedBoxIndex["tb1 "]; found != true {
+		t.Fatalf("Sibling after parent not loaded.")
+	}
+}
+
+func TestNewResource_Uuid(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterUuidBoxType(testUuidBoxFactory{})
+
+	unknownEt := ExtendedType{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+
+	var b []byte
+	PushUuidBox(&b, unknownEt, []byte("xyz"))
+	PushUuidBox(&b, testUuidBoxExtendedType, []byte("abc"))
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes(UuidBoxName)
+	log.PanicIf(err)
+
+	if len(boxes) != 2 {
+		t.Fatalf("Expected both uuid boxes: (%d)", len(boxes))
+	}
+
+	rb := boxes[0].(*RawBox)
+
+	if rb.ExtendedType() != unknownEt {
+		t.Fatalf("Unregistered extended-type not correct: [%s]", rb.ExtendedType())
+	}
+
+	tub := boxes[1].(*testUuidBox)
+
+	if tub.ExtendedType() != testUuidBoxExtendedType {
+		t.Fatalf("Extended-type not correct: [%s]", tub.ExtendedType())
+	} else if tub.HeaderSize() != 24 {
+		t.Fatalf("Header size not correct: (%d)", tub.HeaderSize())
+	} else if tub.Start() != 27 || tub.Size() != 27 {
+		t.Fatalf("Extent not correct.")
+	} else if tub.value != "abc" {
+		t.Fatalf("Payload not correct: [%s]", tub.value)
+	}
+}
+
+func TestReadSeekerReaderAt_ReadAt(t *testing.T) {
+	sb := rifs.NewSeekableBufferWithBytes([]byte{1, 2, 3, 4})
+
+	rsra := &readSeekerReaderAt{
+		rs: sb,
+	}
+
+	b := make([]byte, 2)
+
+	n, err := rsra.ReadAt(b, 1)
+	log.PanicIf(err)
+
+	if n != 2 || bytes.Equal(b, []byte{2, 3}) != true {
+		t.Fatalf("ReadAt() not correct: %v", b)
+	}
+
+	n, err = rsra.ReadAt(b, 3)
+	if err != io.EOF {
+		t.Fatalf("Expected EOF: %v", err)
+	} else if n != 1 || b[0] != 4 {
+		t.Fatalf("Partial read not correct.")
+	}
+}
+
+func TestNewResourceFromReaderAt(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterBoxType(testBox1Factory{})
+	r.RegisterBoxType(testBox2Factory{})
+
+	var b []byte
+	pushTestBox1(&b)
+	pushTestBox2(&b, []byte("abcdefgh"))
+
+	options := ParseOptions{
+		Strict:   true,
+		Registry: r,
+	}
+
+	resource, err := NewResourceFromReaderAt(bytes.NewReader(b), int64(len(b)), options)
+	log.PanicIf(err)
+
+	children, err := resource.Children()
+	log.PanicIf(err)
+
+	if len(children) != 2 || children[0].Name() != "tb1 " {
+		t.Fatalf("Children not correct.")
+	} else if children[1].(*testBox2).String1() != "abcd" {
+		t.Fatalf("Data not correct.")
+	}
+}
+
+// testConcurrentReads reads the payloads of many boxes from many goroutines
+// at once and makes sure that none of them were mixed up.
+func testConcurrentReads(t *testing.T, parse func(b []byte, options ParseOptions) (*Resource, error)) {
+	r := NewRegistry()
+	r.RegisterBoxType(testBox2Factory{})
+
+	var b []byte
+	for i := 0; i < 100; i++ {
+		pushTestBox2(&b, []byte(fmt.Sprintf("%08d", i)))
+	}
+
+	options := ParseOptions{
+		Strict:   true,
+		Registry: r,
+	}
+
+	resource, err := parse(b, options)
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes("tb2 ")
+	log.PanicIf(err)
+
+	var wg sync.WaitGroup
+	errors := make(chan error, len(boxes))
+
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for round := 0; round < 20; round++ {
+				for i, cb := range boxes {
+					data, err := cb.Data()
+					if err != nil {
+						errors <- err
+						return
+					}
+
+					if string(data) != fmt.Sprintf("%08d", i) {
+						errors <- fmt.Errorf("box (%d) read [%s]", i, data)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errors)
+
+	for err := range errors {
+		t.Fatalf("Concurrent read not correct: %v", err)
+	}
+}
+
+func TestResource_ConcurrentReads_ReaderAt(t *testing.T) {
+	testConcurrentReads(t, func(b []byte, options ParseOptions) (*Resource, error) {
+		return NewResourceFromReaderAt(bytes.NewReader(b), int64(len(b)), options)
+	})
+}
+
+func TestResource_ConcurrentReads_ReadSeeker(t *testing.T) {
+	testConcurrentReads(t, func(b []byte, options ParseOptions) (*Resource, error) {
+		sb := rifs.NewSeekableBufferWithBytes(b)
+		return NewResourceWithOptions(sb, int64(len(b)), options)
+	})
+}
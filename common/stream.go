@@ -0,0 +1,467 @@
+package bmfcommon
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/dsoprea/go-logging"
+)
+
+var (
+	streamLogger = log.NewLogger("bmfcommon.stream")
+)
+
+// StreamEventType describes a step in walking a stream.
+type StreamEventType int
+
+const (
+	// StreamEventBoxStart is emitted after a box header has been read and
+	// before its payload has been read.
+	StreamEventBoxStart StreamEventType = iota
+
+	// StreamEventBoxParsed is emitted after a box has been parsed by its
+	// factory.
+	StreamEventBoxParsed
+
+	// StreamEventBoxEnd is emitted after the payload of a box has been dealt
+	// with.
+	StreamEventBoxEnd
+)
+
+// String returns a descriptive name for the event type.
+func (set StreamEventType) String() string {
+	switch set {
+	case StreamEventBoxStart:
+		return "BoxStart"
+	case StreamEventBoxParsed:
+		return "BoxParsed"
+	case StreamEventBoxEnd:
+		return "BoxEnd"
+	}
+
+	return fmt.Sprintf("StreamEventType(%d)", int(set))
+}
+
+// StreamAction tells the StreamParser what to do with the payload of a box.
+// It is only consulted for StreamEventBoxStart events.
+type StreamAction int
+
+const (
+	// StreamActionParse buffers the box and parses it (and its children) with
+	// the registered factory. Boxes without a registered factory are skipped
+	// without being buffered.
+	StreamActionParse StreamAction = iota
+
+	// StreamActionSkip discards whatever part of the payload the handler did
+	// not read from StreamEvent.Payload.
+	StreamActionSkip
+
+	// StreamActionDescend walks the payload as a series of child boxes, in the
+	// same walk and without buffering the box. The children produce their own
+	// events before the StreamEventBoxEnd event of the box. This is only valid
+	// for boxes whose payload is nothing but child boxes (e.g. "moov", "trak",
+	// "moof", or "traf").
+	StreamActionDescend
+)
+
+// StreamBoxHeader describes a box encountered in a stream.
+type StreamBoxHeader struct {
+	// Name is the box-type.
+	Name string
+
+	// ExtendedType is only set for "uuid" boxes.
+	ExtendedType ExtendedType
+
+	// Start is the offset of the box in the stream.
+	Start int64
+
+	// Size is the size of the box including the header. This is zero in the
+	// StreamEventBoxStart event for a box that extends to the end of the
+	// stream and the actual size in later events.
+	Size int64
+
+	// ImplicitSize is true if the box was stored with a size of zero, meaning
+	// that it extends to the end of its container or of the stream.
+	ImplicitSize bool
+
+	// HeaderSize is the size of the header.
+	HeaderSize int64
+
+	// Depth is how deeply the box is nested. Root boxes have a depth of one.
+	Depth int
+
+	// Parent describes the container that the box was found in. It is nil for
+	// root boxes.
+	Parent *StreamBoxHeader
+}
+
+// String returns a descriptive string.
+func (sbh StreamBoxHeader) String() string {
+	return fmt.Sprintf("StreamBoxHeader<NAME=[%s] START=(0x%016x) SIZE=(%d)>", sbh.Name, sbh.Start, sbh.Size)
+}
+
+// StreamEvent is one event emitted by a StreamParser.
+type StreamEvent struct {
+	// Type is the event type.
+	Type StreamEventType
+
+	// Header describes the box.
+	Header StreamBoxHeader
+
+	// Payload is set for StreamEventBoxStart events. The handler may read the
+	// payload from it but must then return StreamActionSkip.
+	Payload io.Reader
+
+	// Box is set for StreamEventBoxParsed events.
+	Box CommonBox
+}
+
+// StreamHandler receives the events emitted by a StreamParser. The action is
+// ignored for everything but StreamEventBoxStart events.
+type StreamHandler func(se StreamEvent) (action StreamAction, err error)
+
+// windowReaderAt exposes a buffered part of a stream at its original offsets
+// so that the regular factories can be used on it.
+type windowReaderAt struct {
+	start int64
+	r     *bytes.Reader
+}
+
+// ReadAt reads from the buffered data using the original offsets.
+func (wra *windowReaderAt) ReadAt(p []byte, offset int64) (n int, err error) {
+	return wra.r.ReadAt(p, offset-wra.start)
+}
+
+// countingReader tracks how much has been read.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read reads from the underlying reader.
+func (cr *countingReader) Read(p []byte) (n int, err error) {
+	n, err = cr.r.Read(p)
+	cr.n += int64(n)
+
+	return n, err
+}
+
+// StreamParser walks the boxes of a forward-only stream (such as a pipe or a
+// request body). It never seeks and only buffers boxes that the handler asks
+// to have parsed.
+//
+// The children of a parsed box are available from the box in the
+// StreamEventBoxParsed event. The handler can instead return
+// StreamActionDescend for a container to get events for its children. All
+// offsets are relative to the start of the stream.
+type StreamParser struct {
+	r *countingReader
+
+	// fullBoxIndex has all boxes that were parsed.
+	fullBoxIndex FullBoxIndex
+
+	// Limits bounds what parsing a buffered box may consume, including the
+	// size of the buffer. Unset limits use DefaultParseLimits.
+	Limits ParseLimits
+
+	// Registry provides the box factories. The default registry is used if
+	// this is nil.
+	Registry *Registry
+
+	// LoadedBoxIndex contains the root boxes that were parsed.
+	LoadedBoxIndex
+}
+
+// NewStreamParser returns a new StreamParser.
+func NewStreamParser(r io.Reader) *StreamParser {
+	return &StreamParser{
+		r: &countingReader{
+			r: r,
+		},
+		fullBoxIndex:   make(FullBoxIndex),
+		LoadedBoxIndex: make(LoadedBoxIndex),
+	}
+}
+
+// Index returns the index of all boxes that were parsed.
+func (sp *StreamParser) Index() FullBoxIndex {
+	return sp.fullBoxIndex
+}
+
+// options returns the options that buffered boxes are parsed with.
+func (sp *StreamParser) options() ParseOptions {
+	return ParseOptions{
+		Strict:   true,
+		Limits:   sp.Limits,
+		Registry: sp.Registry,
+	}
+}
+
+// parseBox buffers the rest of the box and parses it using the registered
+// factory. The size is updated if the box extends to the end of the stream.
+// The parent is the stand-in for the container that the box was found in, if
+// any.
+func (sp *StreamParser) parseBox(sbh *StreamBoxHeader, header []byte, parent CommonBox) (cb CommonBox, known bool, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	maxPayloadSize := sp.Limits.withDefaults().MaxPayloadSize
+
+	var data []byte
+
+	if sbh.Size == 0 {
+		r := io.Reader(sp.r)
+		if maxPayloadSize >= 0 {
+			r = io.LimitReader(sp.r, maxPayloadSize+1)
+		}
+
+		rest, err := ioutil.ReadAll(r)
+		log.PanicIf(err)
+
+		err = checkLimit("MaxPayloadSize", int64(len(rest)), maxPayloadSize)
+		log.PanicIf(err)
+
+		data = append(header, rest...)
+		sbh.Size = int64(len(data))
+	} else {
+		err = checkLimit("MaxPayloadSize", sbh.Size-sbh.HeaderSize, maxPayloadSize)
+		log.PanicIf(err)
+
+		data = make([]byte, sbh.Size)
+		copy(data, header)
+
+		_, err = io.ReadFull(sp.r, data[sbh.HeaderSize:])
+		log.PanicIf(err)
+	}
+
+	wra := &windowReaderAt{
+		start: sbh.Start,
+		r:     bytes.NewReader(data),
+	}
+
+	resource := &Resource{
+		ra:           wra,
+		size:         sbh.Start + sbh.Size,
+		fullBoxIndex: sp.fullBoxIndex,
+		options:      sp.options(),
+	}
+
+	cb, known, err = readBox(resource, parent, sbh.Start, resource.size)
+	log.PanicIf(err)
+
+	return cb, known, nil
+}
+
+// containerBox returns a stand-in for a container that is being descended
+// into. It is the parent of the boxes that are parsed within it so that they
+// are indexed and resolved under their full names. The container itself is
+// not buffered so the stand-in has no data.
+func (sp *StreamParser) containerBox(sbh *StreamBoxHeader, parent CommonBox) CommonBox {
+	resource := &Resource{
+		ra: &windowReaderAt{
+			start: sbh.Start,
+			r:     bytes.NewReader(nil),
+		},
+		size:         sbh.Start,
+		fullBoxIndex: sp.fullBoxIndex,
+		options:      sp.options(),
+	}
+
+	box := NewBox(sbh.Name, sbh.Start, sbh.Size, sbh.HeaderSize, resource)
+	box.extendedType = sbh.ExtendedType
+	box.implicitSize = sbh.ImplicitSize
+	box.parent = parent
+
+	return box
+}
+
+// Parse reads boxes until the end of the stream, passing events to the
+// handler. Parsed root boxes are available from the StreamParser afterwards.
+func (sp *StreamParser) Parse(handler StreamHandler) (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	err = sp.parseBoxes(handler, nil, nil)
+	log.PanicIf(err)
+
+	return nil
+}
+
+// parseBoxes reads boxes until the end of the given container, or of the
+// stream for root boxes, passing events to the handler. The container box is
+// the stand-in that boxes parsed within the container are attached to.
+func (sp *StreamParser) parseBoxes(handler StreamHandler, container *StreamBoxHeader, containerBox CommonBox) (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	depth := 1
+
+	// The end is unknown (negative) for the root and for containers that
+	// extend to the end of the stream.
+	end := int64(-1)
+
+	if container != nil {
+		depth = container.Depth + 1
+
+		if container.Size != 0 {
+			end = container.Start + container.Size
+		}
+	}
+
+	err = checkLimit("MaxDepth", int64(depth), int64(sp.Limits.withDefaults().MaxDepth))
+	log.PanicIf(err)
+
+	for {
+		start := sp.r.n
+
+		if end >= 0 && start >= end {
+			break
+		}
+
+		// Don't read past the end of the container.
+
+		r := io.Reader(sp.r)
+		if end >= 0 {
+			r = io.LimitReader(sp.r, end-start)
+		}
+
+		// Capture the header so that it can be included if the box is
+		// buffered.
+
+		header := new(bytes.Buffer)
+		tr := io.TeeReader(r, header)
+
+		name, extendedType, size, headerSize, err := readBoxHeader(tr, start)
+		if err != nil {
+			// A clean end of the stream is only possible at a box boundary
+			// and where the end was not known.
+			if log.Is(err, io.EOF) == true && header.Len() == 0 && end < 0 {
+				break
+			}
+
+			log.Panic(err)
+		}
+
+		// A size of zero means that the box extends to the end of its
+		// container or of the stream.
+		implicitSize := size == 0
+
+		if implicitSize == false && size < headerSize {
+			log.Panicf("box [%s] at offset (0x%016x) has a size (%d) smaller than its header", name, start, size)
+		} else if end >= 0 {
+			if implicitSize == true {
+				size = end - start
+			} else if start+size > end {
+				log.Panicf("box [%s] at offset (0x%016x) with size (%d) extends past the end of its container", name, start, size)
+			}
+		}
+
+		sbh := StreamBoxHeader{
+			Name:         name,
+			ExtendedType: extendedType,
+			Start:        start,
+			Size:         size,
+			HeaderSize:   headerSize,
+			ImplicitSize: implicitSize,
+			Depth:        depth,
+			Parent:       container,
+		}
+
+		streamLogger.Debugf(nil, "Read box header: %s", sbh)
+
+		payloadSize := size - headerSize
+
+		var payload io.Reader
+		if size == 0 {
+			payload = sp.r
+		} else {
+			payload = io.LimitReader(sp.r, payloadSize)
+		}
+
+		se := StreamEvent{
+			Type:    StreamEventBoxStart,
+			Header:  sbh,
+			Payload: payload,
+		}
+
+		action, err := handler(se)
+		log.PanicIf(err)
+
+		consumed := sp.r.n - start - headerSize
+
+		if action == StreamActionParse && sp.options().registry().getFactoryForType(name, extendedType, containerBox) == nil {
+			// Don't buffer what we can not parse.
+
+			streamLogger.Warningf(nil, "No factory registered for box-type [%s].", name)
+			action = StreamActionSkip
+		}
+
+		if action == StreamActionParse {
+			if consumed != 0 {
+				log.Panicf("payload of box [%s] was read by the handler and can not be parsed", name)
+			}
+
+			cb, known, err := sp.parseBox(&sbh, header.Bytes(), containerBox)
+			log.PanicIf(err)
+
+			if known == true {
+				if container == nil {
+					sp.LoadedBoxIndex[name] = append(sp.LoadedBoxIndex[name], cb)
+				}
+
+				se := StreamEvent{
+					Type:   StreamEventBoxParsed,
+					Header: sbh,
+					Box:    cb,
+				}
+
+				_, err := handler(se)
+				log.PanicIf(err)
+			}
+		} else if action == StreamActionDescend {
+			if consumed != 0 {
+				log.Panicf("payload of box [%s] was read by the handler and can not be descended into", name)
+			}
+
+			err := sp.parseBoxes(handler, &sbh, sp.containerBox(&sbh, containerBox))
+			log.PanicIf(err)
+
+			if size == 0 {
+				sbh.Size = sp.r.n - start
+			}
+		} else if action == StreamActionSkip {
+			if size == 0 {
+				_, err := io.Copy(ioutil.Discard, sp.r)
+				log.PanicIf(err)
+
+				sbh.Size = sp.r.n - start
+			} else {
+				_, err := io.CopyN(ioutil.Discard, sp.r, payloadSize-consumed)
+				log.PanicIf(err)
+			}
+		} else {
+			log.Panicf("stream action (%d) not valid", action)
+		}
+
+		se = StreamEvent{
+			Type:   StreamEventBoxEnd,
+			Header: sbh,
+		}
+
+		_, err = handler(se)
+		log.PanicIf(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,474 @@
+package bmfcommon
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+)
+
+func getTestStreamBytes() []byte {
+	RegisterBoxType(testBox1Factory{})
+	RegisterBoxType(testBox2Factory{})
+	RegisterBoxType(testBox3Factory{})
+
+	var children []byte
+	pushTestBox1(&children)
+	pushTestBox2(&children, []byte("ijklmnop"))
+
+	var b []byte
+	pushTestBox2(&b, []byte("abcdefgh"))
+	pushUnknownBox(&b, []byte{1, 2, 3})
+	pushTestBox3(&b, children)
+
+	return b
+}
+
+// getTestStreamReader returns a reader that can not seek.
+func getTestStreamReader(b []byte) io.Reader {
+	return io.MultiReader(bytes.NewReader(b))
+}
+
+func TestStreamEventType_String(t *testing.T) {
+	if StreamEventBoxParsed.String() != "BoxParsed" {
+		t.Fatalf("String() not correct.")
+	}
+}
+
+func TestStreamParser_Parse(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	b := getTestStreamBytes()
+	sp := NewStreamParser(getTestStreamReader(b))
+
+	events := make([]string, 0)
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		events = append(events, fmt.Sprintf("%s:%s:%d", se.Type, se.Header.Name, se.Header.Start))
+		return StreamActionParse, nil
+	}
+
+	err := sp.Parse(handler)
+	log.PanicIf(err)
+
+	expectedEvents := []string{
+		"BoxStart:tb2 :0",
+		"BoxParsed:tb2 :0",
+		"BoxEnd:tb2 :0",
+		"BoxStart:wxyz:16",
+		"BoxEnd:wxyz:16",
+		"BoxStart:tb3 :27",
+		"BoxParsed:tb3 :27",
+		"BoxEnd:tb3 :27",
+	}
+
+	if reflect.DeepEqual(events, expectedEvents) != true {
+		t.Fatalf("Events not correct: %v", events)
+	}
+
+	boxes, err := sp.GetChildBoxes("tb2 ")
+	log.PanicIf(err)
+
+	if boxes[0].(*testBox2).String1() != "abcd" {
+		t.Fatalf("Parsed box not correct.")
+	}
+
+	ibe := IndexedBoxEntry{
+		NamePhrase:     "tb3 .tb2 ",
+		SequenceNumber: 0,
+	}
+
+	cb, found := sp.Index()[ibe]
+	if found != true {
+		t.Fatalf("Child box not indexed.")
+	}
+
+	tb2 := cb.(*testBox2)
+
+	if tb2.Start() != 27+8+8 {
+		t.Fatalf("Child box start not correct: (%d)", tb2.Start())
+	} else if tb2.String2() != "mnop" {
+		t.Fatalf("Child box not correct.")
+	}
+}
+
+func TestStreamParser_Parse_RootOnly(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	b := getTestStreamBytes()
+	sp := NewStreamParser(getTestStreamReader(b))
+
+	names := make([]string, 0)
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		if se.Type == StreamEventBoxStart {
+			names = append(names, se.Header.Name)
+		}
+
+		return StreamActionParse, nil
+	}
+
+	err := sp.Parse(handler)
+	log.PanicIf(err)
+
+	// The children of TB3 do not produce events.
+	if reflect.DeepEqual(names, []string{"tb2 ", "wxyz", "tb3 "}) != true {
+		t.Fatalf("Events not correct: %v", names)
+	}
+}
+
+func TestStreamParser_Parse_Descend(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	b := getTestStreamBytes()
+	sp := NewStreamParser(getTestStreamReader(b))
+
+	events := make([]string, 0)
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		parentName := ""
+		if se.Header.Parent != nil {
+			parentName = se.Header.Parent.Name
+		}
+
+		events = append(events, fmt.Sprintf("%s:%s:%d:%d:[%s]", se.Type, se.Header.Name, se.Header.Start, se.Header.Depth, parentName))
+
+		if se.Type == StreamEventBoxStart && se.Header.Name == "tb3 " {
+			// Walk the children without buffering the container.
+			return StreamActionDescend, nil
+		}
+
+		return StreamActionParse, nil
+	}
+
+	err := sp.Parse(handler)
+	log.PanicIf(err)
+
+	expectedEvents := []string{
+		"BoxStart:tb2 :0:1:[]",
+		"BoxParsed:tb2 :0:1:[]",
+		"BoxEnd:tb2 :0:1:[]",
+		"BoxStart:wxyz:16:1:[]",
+		"BoxEnd:wxyz:16:1:[]",
+		"BoxStart:tb3 :27:1:[]",
+		"BoxStart:tb1 :35:2:[tb3 ]",
+		"BoxParsed:tb1 :35:2:[tb3 ]",
+		"BoxEnd:tb1 :35:2:[tb3 ]",
+		"BoxStart:tb2 :43:2:[tb3 ]",
+		"BoxParsed:tb2 :43:2:[tb3 ]",
+		"BoxEnd:tb2 :43:2:[tb3 ]",
+		"BoxEnd:tb3 :27:1:[]",
+	}
+
+	if reflect.DeepEqual(events, expectedEvents) != true {
+		t.Fatalf("Events not correct: %v", events)
+	}
+
+	// Only root boxes are loaded but the children are indexed under their
+	// containers.
+
+	if reflect.DeepEqual(sp.ChildrenTypes(), []string{"tb2 "}) != true {
+		t.Fatalf("Loaded boxes not correct: %v", sp.ChildrenTypes())
+	}
+
+	ibe := IndexedBoxEntry{
+		NamePhrase:     "tb3 .tb2 ",
+		SequenceNumber: 0,
+	}
+
+	cb, found := sp.Index()[ibe]
+	if found != true {
+		t.Fatalf("Child box not indexed.")
+	} else if cb.(*testBox2).String2() != "mnop" {
+		t.Fatalf("Child box not correct.")
+	}
+}
+
+func TestStreamParser_Parse_Descend_ImplicitSize(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+
+	// A container that extends to the end of the stream, holding a box that
+	// extends to the end of the container.
+
+	var children []byte
+	pushTestBox1(&children)
+	PushBytes(&children, uint32(0))
+	PushBytes(&children, []byte("wxyz"))
+	PushBytes(&children, []byte{1, 2, 3})
+
+	var b []byte
+	PushBytes(&b, uint32(0))
+	PushBytes(&b, []byte("tb3 "))
+	PushBytes(&b, children)
+
+	sp := NewStreamParser(getTestStreamReader(b))
+
+	ends := make([]string, 0)
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		if se.Type == StreamEventBoxEnd {
+			ends = append(ends, fmt.Sprintf("%s:%d:%d", se.Header.Name, se.Header.Start, se.Header.Size))
+		}
+
+		if se.Type == StreamEventBoxStart && se.Header.Name == "tb3 " {
+			return StreamActionDescend, nil
+		}
+
+		return StreamActionParse, nil
+	}
+
+	err := sp.Parse(handler)
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(ends, []string{"tb1 :8:8", "wxyz:16:11", "tb3 :0:27"}) != true {
+		t.Fatalf("Events not correct: %v", ends)
+	}
+}
+
+func TestStreamParser_Parse_Descend_Overrun(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	// A child that claims to be larger than its container.
+
+	var children []byte
+	PushBytes(&children, uint32(16))
+	PushBytes(&children, []byte("wxyz"))
+
+	var b []byte
+	PushBox(&b, "tb3 ", children)
+	PushBytes(&b, make([]byte, 8))
+
+	sp := NewStreamParser(getTestStreamReader(b))
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		return StreamActionDescend, nil
+	}
+
+	err := sp.Parse(handler)
+	if err == nil {
+		t.Fatalf("Expected error.")
+	} else if strings.Contains(err.Error(), "extends past the end of its container") != true {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+}
+
+func TestStreamParser_Parse_Registry(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	b := getTestStreamBytes()
+
+	// Only TB2 is known to the parser.
+
+	ClearRegistrations()
+
+	r := NewRegistry()
+	r.RegisterBoxType(testBox2Factory{})
+
+	sp := NewStreamParser(getTestStreamReader(b))
+	sp.Registry = r
+
+	parsed := make([]string, 0)
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		if se.Type == StreamEventBoxParsed {
+			parsed = append(parsed, se.Header.Name)
+		}
+
+		return StreamActionParse, nil
+	}
+
+	err := sp.Parse(handler)
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(parsed, []string{"tb2 "}) != true {
+		t.Fatalf("Parsed boxes not correct: %v", parsed)
+	}
+}
+
+func TestStreamParser_Parse_SkipAndConsume(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	b := getTestStreamBytes()
+	sp := NewStreamParser(getTestStreamReader(b))
+
+	var consumed []byte
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		if se.Type != StreamEventBoxStart {
+			return StreamActionSkip, nil
+		}
+
+		if se.Header.Name == "tb2 " {
+			// Only read part of it.
+
+			consumed = make([]byte, 3)
+
+			_, err := io.ReadFull(se.Payload, consumed)
+			log.PanicIf(err)
+
+			return StreamActionSkip, nil
+		}
+
+		return StreamActionParse, nil
+	}
+
+	err := sp.Parse(handler)
+	log.PanicIf(err)
+
+	if bytes.Equal(consumed, []byte("abc")) != true {
+		t.Fatalf("Consumed data not correct: %v", consumed)
+	} else if _, found := sp.LoadedBoxIndex["tb2 "]; found != false {
+		t.Fatalf("Skipped box should not have been parsed.")
+	} else if _, found := sp.LoadedBoxIndex["tb3 "]; found != true {
+		t.Fatalf("Box after the skipped box was not parsed.")
+	}
+}
+
+func TestStreamParser_Parse_ConsumeThenParse(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	b := getTestStreamBytes()
+	sp := NewStreamParser(getTestStreamReader(b))
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		if se.Type == StreamEventBoxStart {
+			_, err := ioutil.ReadAll(se.Payload)
+			log.PanicIf(err)
+		}
+
+		return StreamActionParse, nil
+	}
+
+	err := sp.Parse(handler)
+	if err == nil {
+		t.Fatalf("Expected error for parsing a consumed payload.")
+	}
+}
+
+func TestStreamParser_Parse_Truncated(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	b := getTestStreamBytes()
+	sp := NewStreamParser(getTestStreamReader(b[:len(b)-2]))
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		return StreamActionParse, nil
+	}
+
+	err := sp.Parse(handler)
+	if err == nil {
+		t.Fatalf("Expected error for truncated stream.")
+	}
+}
+
+func TestStreamParser_Parse_HandlerError(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	b := getTestStreamBytes()
+	sp := NewStreamParser(getTestStreamReader(b))
+
+	handler := func(se StreamEvent) (action StreamAction, err error) {
+		return StreamActionParse, fmt.Errorf("handler failed")
+	}
+
+	err := sp.Parse(handler)
+	if err == nil || err.Error() != "handler failed" {
+		t.Fatalf("Expected handler error: %v", err)
+	}
+}
+
+func TestWindowReaderAt(t *testing.T) {
+	wra := &windowReaderAt{
+		start: 100,
+		r:     bytes.NewReader([]byte{1, 2, 3, 4}),
+	}
+
+	b := make([]byte, 2)
+
+	_, err := wra.ReadAt(b, 102)
+	log.PanicIf(err)
+
+	if bytes.Equal(b, []byte{3, 4}) != true {
+		t.Fatalf("ReadAt() not correct: %v", b)
+	}
+
+	_, err = wra.ReadAt(b, 103)
+	if err != io.EOF {
+		t.Fatalf("Expected EOF for read past the window: %v", err)
+	}
+}
+
+func TestStreamParser_Parse_ImplicitSize(t *testing.T) {
+	ClearRegistrations()
+	defer ClearRegistrations()
+
+	RegisterBoxType(testBox1Factory{})
+	RegisterBoxType(testBox2Factory{})
+
+	var b []byte
+	pushTestBox1(&b)
+	pushImplicitSizeBox(&b, "tb2 ", []byte("abcdefgh"))
+
+	for _, action := range []StreamAction{StreamActionParse, StreamActionSkip} {
+		sp := NewStreamParser(getTestStreamReader(b))
+
+		events := make([]string, 0)
+
+		handler := func(se StreamEvent) (StreamAction, error) {
+			events = append(events, fmt.Sprintf("%s:%s:%d:%v", se.Type, se.Header.Name, se.Header.Size, se.Header.ImplicitSize))
+			return action, nil
+		}
+
+		err := sp.Parse(handler)
+		log.PanicIf(err)
+
+		var expectedEvents []string
+		if action == StreamActionParse {
+			expectedEvents = []string{
+				"BoxStart:tb1 :8:false",
+				"BoxParsed:tb1 :8:false",
+				"BoxEnd:tb1 :8:false",
+				"BoxStart:tb2 :0:true",
+				"BoxParsed:tb2 :16:true",
+				"BoxEnd:tb2 :16:true",
+			}
+		} else {
+			expectedEvents = []string{
+				"BoxStart:tb1 :8:false",
+				"BoxEnd:tb1 :8:false",
+				"BoxStart:tb2 :0:true",
+				"BoxEnd:tb2 :16:true",
+			}
+		}
+
+		if reflect.DeepEqual(events, expectedEvents) != true {
+			t.Fatalf("Events not correct for action (%d): %v", action, events)
+		}
+
+		if action == StreamActionParse {
+			boxes, err := sp.GetChildBoxes("tb2 ")
+			log.PanicIf(err)
+
+			if boxes[0].(*testBox2).String2() != "efgh" {
+				t.Fatalf("Parsed box not correct.")
+			}
+		}
+	}
+}
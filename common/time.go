@@ -39,6 +39,16 @@ func NewStandard32TimeSupport(creationEpoch, modificationEpoch, scaledDuration,
 	}
 }
 
+// CreationEpoch returns the creation time expressed as an MP4 epoch.
+func (sts Standard32TimeSupport) CreationEpoch() uint64 {
+	return sts.creationEpoch
+}
+
+// ModificationEpoch returns the modification time expressed as an MP4 epoch.
+func (sts Standard32TimeSupport) ModificationEpoch() uint64 {
+	return sts.modificationEpoch
+}
+
 // CreationTime returns the creation time.
 func (sts Standard32TimeSupport) CreationTime() time.Time {
 	t := EpochToTime(sts.creationEpoch)
@@ -109,6 +119,18 @@ func (sts Standard32TimeSupport) InlineString() string {
 		float64(sts.Duration())/float64(time.Second), optional)
 }
 
+// ExportFields returns the time fields for structured exports.
+func (sts Standard32TimeSupport) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"creation_epoch":     sts.creationEpoch,
+		"modification_epoch": sts.modificationEpoch,
+		"time_scale":         sts.timeScale,
+		"scaled_duration":    sts.scaledDuration,
+	}
+
+	return fields, nil
+}
+
 // TimeToEpoch returns the number of seconds since the MP4 epoch.
 func TimeToEpoch(t time.Time) uint64 {
 	d := t.Sub(epochTime)
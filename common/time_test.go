@@ -206,3 +206,23 @@ func TestGetDurationString(t *testing.T) {
 		t.Fatalf("Duration string not correct.")
 	}
 }
+
+func TestStandard32TimeSupport_CreationEpoch(t *testing.T) {
+	sts := Standard32TimeSupport{
+		creationEpoch: 123,
+	}
+
+	if sts.CreationEpoch() != 123 {
+		t.Fatalf("CreationEpoch() not correct.")
+	}
+}
+
+func TestStandard32TimeSupport_ModificationEpoch(t *testing.T) {
+	sts := Standard32TimeSupport{
+		modificationEpoch: 456,
+	}
+
+	if sts.ModificationEpoch() != 456 {
+		t.Fatalf("ModificationEpoch() not correct.")
+	}
+}
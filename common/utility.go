@@ -131,6 +131,16 @@ func PushBox(buffer *[]byte, name string, data interface{}) {
 	}
 }
 
+// PushUuidBox pushes a "uuid" box with the given extended type to the given
+// byte-slice pointer.
+func PushUuidBox(buffer *[]byte, et ExtendedType, data []byte) {
+	payload := make([]byte, 0, len(et)+len(data))
+	payload = append(payload, et[:]...)
+	payload = append(payload, data...)
+
+	PushBox(buffer, UuidBoxName, payload)
+}
+
 // PushBytes encodes the given integer and pushes to the byte-slice pointer.
 func PushBytes(buffer *[]byte, x interface{}) {
 	var encoded []byte
@@ -293,3 +293,21 @@ func TestDumpBytes(t *testing.T) {
 
 	DumpBytes(b)
 }
+
+func TestPushUuidBox(t *testing.T) {
+	b := make([]byte, 0)
+
+	et := ExtendedType{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	PushUuidBox(&b, et, []byte{17, 18})
+
+	expected := []byte{
+		0, 0, 0, 26,
+		'u', 'u', 'i', 'd',
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+		17, 18,
+	}
+
+	if bytes.Equal(b, expected) != true {
+		t.Fatalf("Bytes not correct: %x\n", b)
+	}
+}
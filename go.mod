@@ -6,5 +6,6 @@ go 1.13
 require (
 	github.com/dsoprea/go-logging v0.0.0-20200710184922-b02d349568dd
 	github.com/dsoprea/go-utility v0.0.0-20200711062821-1fa87d5bb02f
+	github.com/go-errors/errors v1.4.2
 	github.com/jessevdk/go-flags v1.4.0
 )
@@ -1,6 +1,9 @@
 package bmftype
 
 import (
+	"errors"
+	"path"
+	"testing"
 	"time"
 
 	"github.com/dsoprea/go-iso-bmf/common"
@@ -18,3 +21,85 @@ func getTestStandard32Time() (now time.Time, sts bmfcommon.Standard32TimeSupport
 
 	return now, sts
 }
+
+func getTestAssetFilepath(filename string) string {
+	return path.Join("..", "assets", filename)
+}
+
+// truncatedBoxTests are payloads that stop short of the fixed fields of their
+// boxes along with the sizes that the factories should report.
+var truncatedBoxTests = []struct {
+	factory   bmfcommon.BoxFactory
+	data      []byte
+	size      int64
+	available int64
+}{
+	// Version and flags, and the sample-size but not the count.
+	{factory: stszBoxFactory{}, data: make([]byte, 8), size: 12, available: 8},
+
+	// Version and flags, and the field-size but not the count.
+	{factory: stz2BoxFactory{}, data: make([]byte, 8), size: 12, available: 8},
+
+	// Version and flags but no entry count.
+	{factory: stscBoxFactory{}, data: make([]byte, 4), size: 8, available: 4},
+	{factory: stcoBoxFactory{}, data: make([]byte, 4), size: 8, available: 4},
+	{factory: co64BoxFactory{}, data: make([]byte, 4), size: 8, available: 4},
+	{factory: stssBoxFactory{}, data: make([]byte, 4), size: 8, available: 4},
+	{factory: cttsBoxFactory{}, data: make([]byte, 4), size: 8, available: 4},
+
+	// Version and flags and all but the composition end-time.
+	{factory: cslgBoxFactory{}, data: make([]byte, 20), size: 24, available: 20},
+
+	// The major brand but not the minor version.
+	{factory: ftypBoxFactory{}, data: make([]byte, 4), size: 8, available: 4},
+
+	// Version and flags and the handler but not the reserved fields.
+	{factory: hdlrBoxFactory{}, data: make([]byte, 12), size: 24, available: 12},
+
+	// Version and flags but no entry count or item ID.
+	{factory: iinfBoxFactory{}, data: make([]byte, 4), size: 6, available: 4},
+	{factory: pitmBoxFactory{}, data: make([]byte, 4), size: 6, available: 4},
+	{factory: elstBoxFactory{}, data: make([]byte, 4), size: 8, available: 4},
+	{factory: sttsBoxFactory{}, data: make([]byte, 4), size: 8, available: 4},
+
+	// Version and flags and the times but not the rate and volume or the
+	// language.
+	{factory: mvhdBoxFactory{}, data: make([]byte, 20), size: 26, available: 20},
+	{factory: mdhdBoxFactory{}, data: make([]byte, 20), size: 22, available: 20},
+
+	// Version and flags and the graphics-mode only.
+	{factory: vmhdBoxFactory{}, data: make([]byte, 6), size: 8, available: 6},
+
+	// Version and flags and all but the average bitrate.
+	{factory: hmhdBoxFactory{}, data: make([]byte, 12), size: 16, available: 12},
+
+	// Version and flags but no size or sequence number.
+	{factory: mfroBoxFactory{}, data: make([]byte, 4), size: 8, available: 4},
+	{factory: mfhdBoxFactory{}, data: make([]byte, 4), size: 8, available: 4},
+
+	// Version and flags, the track ID, and the lengths but not the count.
+	{factory: tfraBoxFactory{}, data: make([]byte, 12), size: 16, available: 12},
+
+	// Version and flags and half of the time or duration.
+	{factory: tfdtBoxFactory{}, data: make([]byte, 6), size: 8, available: 6},
+	{factory: mehdBoxFactory{}, data: make([]byte, 6), size: 8, available: 6},
+
+	// Version and flags and all but the default sample-flags.
+	{factory: trexBoxFactory{}, data: make([]byte, 20), size: 24, available: 20},
+}
+
+func TestBoxFactory_New_Truncated(t *testing.T) {
+	for _, test := range truncatedBoxTests {
+		_, err := getTestBoxFactoryNew(test.factory, test.data)
+		if err == nil {
+			t.Fatalf("Expected error for [%s].", test.factory.Name())
+		}
+
+		var et *bmfcommon.ErrTruncated
+		if errors.As(err, &et) != true {
+			t.Fatalf("Expected ErrTruncated for [%s]: [%s]", test.factory.Name(), err.Error())
+		} else if et.Size != test.size || et.Available != test.available {
+			t.Fatalf("Truncation not correct for [%s]: %v", test.factory.Name(), et)
+		}
+	}
+}
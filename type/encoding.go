@@ -0,0 +1,25 @@
+package bmftype
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// pushVersionAndFlags pushes the four-byte version-and-flags prefix of a full
+// box.
+func pushVersionAndFlags(data *[]byte, version byte, flags uint32) {
+	bmfcommon.PushBytes(data, uint32(version)<<24|flags&0x00ffffff)
+}
+
+// pushSizedUint pushes the value as a big-endian unsigned integer of the given
+// number of bytes (zero through eight).
+func pushSizedUint(data *[]byte, value uint64, size int) {
+	if size < 0 || size > 8 {
+		log.Panicf("integer size not valid: (%d)", size)
+	}
+
+	for i := size - 1; i >= 0; i-- {
+		*data = append(*data, byte(value>>(uint(i)*8)))
+	}
+}
@@ -0,0 +1,152 @@
+package bmftype
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// getTestParsedBox wraps the given payload in a box and parses it with the
+// given factory.
+func getTestParsedBox(factory bmfcommon.BoxFactory, data []byte) bmfcommon.CommonBox {
+	cb, err := getTestBoxFactoryNew(factory, data)
+	log.PanicIf(err)
+
+	return cb
+}
+
+// getTestBoxFactoryNew runs the factory for a box with the given payload and
+// returns its error, if any.
+func getTestBoxFactoryNew(factory bmfcommon.BoxFactory, data []byte) (cb bmfcommon.CommonBox, err error) {
+	var b []byte
+	bmfcommon.PushBox(&b, factory.Name(), data)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, 0)
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err = factory.New(box)
+	return cb, err
+}
+
+// assertEncodeData checks that the box re-encodes to the given payload.
+func assertEncodeData(t *testing.T, cb bmfcommon.CommonBox, expected []byte) {
+	data, err := cb.(bmfcommon.BoxEncoder).EncodeData()
+	log.PanicIf(err)
+
+	if bytes.Equal(data, expected) != true {
+		t.Fatalf("EncodeData() not correct for [%s]:\nACTUAL: %v\nEXPECTED: %v", cb.Name(), data, expected)
+	}
+}
+
+func TestPushVersionAndFlags(t *testing.T) {
+	var data []byte
+	pushVersionAndFlags(&data, 1, 0x01020304)
+
+	if bytes.Equal(data, []byte{1, 2, 3, 4}) != true {
+		t.Fatalf("Encoding not correct: %v", data)
+	}
+}
+
+func TestPushSizedUint(t *testing.T) {
+	var data []byte
+	pushSizedUint(&data, 0x010203, 3)
+	pushSizedUint(&data, 0x05, 0)
+	pushSizedUint(&data, 0x0607, 2)
+
+	if bytes.Equal(data, []byte{1, 2, 3, 6, 7}) != true {
+		t.Fatalf("Encoding not correct: %v", data)
+	}
+}
+
+func TestResource_Encode_Assets(t *testing.T) {
+	filenames := []string{
+		"tears-of-steel.mp4",
+		"image.heic",
+	}
+
+	for _, filename := range filenames {
+		filepath := getTestAssetFilepath(filename)
+
+		original, err := ioutil.ReadFile(filepath)
+		log.PanicIf(err)
+
+		f, err := os.Open(filepath)
+		log.PanicIf(err)
+
+		defer f.Close()
+
+		resource, err := bmfcommon.NewResource(f, int64(len(original)))
+		log.PanicIf(err)
+
+		b := new(bytes.Buffer)
+
+		err = resource.Encode(b)
+		log.PanicIf(err)
+
+		if bytes.Equal(b.Bytes(), original) != true {
+			t.Fatalf("Encoded resource not identical to original: [%s]", filename)
+		}
+	}
+}
+
+func TestResource_Encode_Modified(t *testing.T) {
+	filepath := getTestAssetFilepath("tears-of-steel.mp4")
+
+	original, err := ioutil.ReadFile(filepath)
+	log.PanicIf(err)
+
+	sb := rifs.NewSeekableBufferWithBytes(original)
+
+	resource, err := bmfcommon.NewResource(sb, int64(len(original)))
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes("ftyp")
+	log.PanicIf(err)
+
+	ftyp := boxes[0].(*FtypBox)
+	ftyp.compatibleBrands = append(ftyp.compatibleBrands, "abcd")
+
+	b := new(bytes.Buffer)
+
+	err = resource.Encode(b)
+	log.PanicIf(err)
+
+	if b.Len() != len(original)+4 {
+		t.Fatalf("Encoded size not correct: (%d)", b.Len())
+	}
+
+	// Re-parse.
+
+	encoded := b.Bytes()
+	sb = rifs.NewSeekableBufferWithBytes(encoded)
+
+	resource, err = bmfcommon.NewResource(sb, int64(len(encoded)))
+	log.PanicIf(err)
+
+	boxes, err = resource.GetChildBoxes("ftyp")
+	log.PanicIf(err)
+
+	brands := boxes[0].(*FtypBox).CompatibleBrands()
+
+	if brands[len(brands)-1] != "abcd" {
+		t.Fatalf("Modified field not retained: %v", brands)
+	}
+
+	boxes, err = resource.GetChildBoxes("moov")
+	log.PanicIf(err)
+
+	if _, err := boxes[0].(*MoovBox).GetChildBoxes("mvhd"); err != nil {
+		t.Fatalf("Boxes after the modified box not readable.")
+	}
+}
@@ -0,0 +1,269 @@
+package bmftype
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+var (
+	// ErrAlreadyFastStart indicates that the MOOV box already precedes the
+	// media data.
+	ErrAlreadyFastStart = errors.New("moov already precedes mdat")
+)
+
+// promotedStcoBox is an STCO box that is temporarily substituted with a CO64
+// box because its offsets no longer fit in 32 bits.
+type promotedStcoBox struct {
+	*Co64Box
+}
+
+// Name returns the name of the box that will be written.
+func (promotedStcoBox) Name() string {
+	return "co64"
+}
+
+// chunkOffsetTable is one STCO or CO64 box along with the offsets that it was
+// parsed with.
+type chunkOffsetTable struct {
+	stco *StcoBox
+	co64 *Co64Box
+
+	originalOffsets []uint64
+
+	// promoted is non-nil if an STCO box has been swapped with a CO64 box.
+	promoted *promotedStcoBox
+}
+
+// getChunkOffsetTables returns every STCO and CO64 box under the MOOV box.
+func getChunkOffsetTables(resource *bmfcommon.Resource) (tables []*chunkOffsetTable) {
+	tables = make([]*chunkOffsetTable, 0)
+
+	for ibe, cb := range resource.Index() {
+		if strings.HasPrefix(ibe.NamePhrase, "moov.") == false {
+			continue
+		}
+
+		if stco, ok := cb.(*StcoBox); ok == true {
+			offsets := make([]uint64, len(stco.chunkOffsets))
+			for i, offset := range stco.chunkOffsets {
+				offsets[i] = uint64(offset)
+			}
+
+			cot := &chunkOffsetTable{
+				stco:            stco,
+				originalOffsets: offsets,
+			}
+
+			tables = append(tables, cot)
+		} else if co64, ok := cb.(*Co64Box); ok == true {
+			offsets := make([]uint64, len(co64.chunkOffsets))
+			copy(offsets, co64.chunkOffsets)
+
+			cot := &chunkOffsetTable{
+				co64:            co64,
+				originalOffsets: offsets,
+			}
+
+			tables = append(tables, cot)
+		}
+	}
+
+	return tables
+}
+
+// replaceChild swaps one child of the STBL box for another.
+func (cot *chunkOffsetTable) replaceChild(from, to bmfcommon.CommonBox) {
+	stbl := cot.stco.Parent().(*StblBox)
+
+	boxes := stbl.LoadedBoxIndex["stco"]
+	for i, cb := range boxes {
+		if cb == from {
+			boxes[i] = to
+		}
+	}
+}
+
+// promote substitutes a CO64 box for the STCO box.
+func (cot *chunkOffsetTable) promote() {
+	if cot.stco == nil || cot.promoted != nil {
+		return
+	}
+
+	co64 := &Co64Box{
+		Box:          cot.stco.Box,
+		FullBox:      cot.stco.FullBox,
+		chunkOffsets: make([]uint64, len(cot.originalOffsets)),
+	}
+
+	cot.promoted = &promotedStcoBox{
+		Co64Box: co64,
+	}
+
+	cot.replaceChild(cot.stco, cot.promoted)
+}
+
+// apply sets the offsets to the shifted originals.
+func (cot *chunkOffsetTable) apply(shift func(offset uint64) uint64) {
+	if cot.promoted != nil {
+		for i, offset := range cot.originalOffsets {
+			cot.promoted.chunkOffsets[i] = shift(offset)
+		}
+	} else if cot.stco != nil {
+		for i, offset := range cot.originalOffsets {
+			cot.stco.chunkOffsets[i] = uint32(shift(offset))
+		}
+	} else {
+		for i, offset := range cot.originalOffsets {
+			cot.co64.chunkOffsets[i] = shift(offset)
+		}
+	}
+}
+
+// restore undoes any changes.
+func (cot *chunkOffsetTable) restore() {
+	if cot.promoted != nil {
+		cot.replaceChild(cot.promoted, cot.stco)
+		cot.promoted = nil
+	}
+
+	cot.apply(func(offset uint64) uint64 {
+		return offset
+	})
+}
+
+// overflows returns true if this is an STCO box and any of the shifted
+// offsets would not fit in 32 bits.
+func (cot *chunkOffsetTable) overflows(shift func(offset uint64) uint64) bool {
+	if cot.stco == nil || cot.promoted != nil {
+		return false
+	}
+
+	for _, offset := range cot.originalOffsets {
+		if shift(offset) > math.MaxUint32 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FastStart writes the resource to the given writer with the MOOV box moved
+// ahead of the first MDAT box, as qt-faststart does, so that playback can
+// begin before the whole file has been downloaded. Every STCO and CO64 chunk
+// offset is adjusted by the distance that the data moved and STCO boxes are
+// promoted to CO64 boxes if their offsets would no longer fit in 32 bits. The
+// resource is left unchanged. ErrAlreadyFastStart is returned, and nothing is
+// written, if the MOOV box already precedes the MDAT box.
+func FastStart(resource *bmfcommon.Resource, w io.Writer) (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	moovBoxes, err := resource.GetChildBoxes("moov")
+	log.PanicIf(err)
+
+	moov := moovBoxes[0].(*MoovBox)
+
+	mdatBoxes, err := resource.GetChildBoxes("mdat")
+	log.PanicIf(err)
+
+	mdatStart := mdatBoxes[0].(*MdatBox).Start()
+	for _, cb := range mdatBoxes[1:] {
+		if start := cb.(*MdatBox).Start(); start < mdatStart {
+			mdatStart = start
+		}
+	}
+
+	moovStart := moov.Start()
+	moovEnd := moovStart + moov.Size()
+
+	if moovStart < mdatStart {
+		return ErrAlreadyFastStart
+	}
+
+	tables := getChunkOffsetTables(resource)
+
+	defer func() {
+		for _, cot := range tables {
+			cot.restore()
+		}
+	}()
+
+	// Data between the MDAT box and the MOOV box is pushed forward by the
+	// size of the new MOOV box. Data after the MOOV box only moves if the
+	// MOOV box grew.
+
+	var moovData []byte
+	var shift func(offset uint64) uint64
+
+	for {
+		b := new(bytes.Buffer)
+
+		err := bmfcommon.EncodeBox(b, moov)
+		log.PanicIf(err)
+
+		moovData = b.Bytes()
+		newMoovSize := int64(len(moovData))
+
+		shift = func(offset uint64) uint64 {
+			if offset >= uint64(moovEnd) {
+				return offset + uint64(newMoovSize-moov.Size())
+			} else if offset >= uint64(mdatStart) {
+				return offset + uint64(newMoovSize)
+			}
+
+			return offset
+		}
+
+		// Promoting a table grows the MOOV box, so keep going until the size
+		// settles.
+
+		promoted := false
+		for _, cot := range tables {
+			if cot.overflows(shift) == true {
+				cot.promote()
+				promoted = true
+			}
+		}
+
+		if promoted == false {
+			break
+		}
+	}
+
+	for _, cot := range tables {
+		cot.apply(shift)
+	}
+
+	b := new(bytes.Buffer)
+
+	err = bmfcommon.EncodeBox(b, moov)
+	log.PanicIf(err)
+
+	if b.Len() != len(moovData) {
+		log.Panicf("moov size changed while shifting offsets: (%d) != (%d)", b.Len(), len(moovData))
+	}
+
+	err = resource.CopyBytesAt(0, mdatStart, w)
+	log.PanicIf(err)
+
+	_, err = w.Write(b.Bytes())
+	log.PanicIf(err)
+
+	err = resource.CopyBytesAt(mdatStart, moovStart-mdatStart, w)
+	log.PanicIf(err)
+
+	err = resource.CopyBytesAt(moovEnd, resource.Size()-moovEnd, w)
+	log.PanicIf(err)
+
+	return nil
+}
@@ -0,0 +1,303 @@
+package bmftype
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// getTestSampleData returns the data for every sample in every track.
+func getTestSampleData(resource *bmfcommon.Resource, raw []byte) (samples [][]byte) {
+	samples = make([][]byte, 0)
+
+	for ibe, cb := range resource.Index() {
+		if ibe.NamePhrase != "moov.trak.mdia.minf.stbl" {
+			continue
+		}
+
+		st, err := cb.(*StblBox).SampleTable()
+		log.PanicIf(err)
+
+		for _, si := range st.Samples() {
+			samples = append(samples, raw[si.Offset():si.Offset()+uint64(si.Size())])
+		}
+	}
+
+	return samples
+}
+
+func TestFastStart_Asset(t *testing.T) {
+	original, err := ioutil.ReadFile(getTestAssetFilepath("tears-of-steel.mp4"))
+	log.PanicIf(err)
+
+	sb := rifs.NewSeekableBufferWithBytes(original)
+
+	resource, err := bmfcommon.NewResource(sb, int64(len(original)))
+	log.PanicIf(err)
+
+	b := new(bytes.Buffer)
+
+	err = FastStart(resource, b)
+	log.PanicIf(err)
+
+	updated := b.Bytes()
+
+	if len(updated) != len(original) {
+		t.Fatalf("Output size not correct: (%d) != (%d)", len(updated), len(original))
+	}
+
+	sb = rifs.NewSeekableBufferWithBytes(updated)
+
+	updatedResource, err := bmfcommon.NewResource(sb, int64(len(updated)))
+	log.PanicIf(err)
+
+	moovBoxes, err := updatedResource.GetChildBoxes("moov")
+	log.PanicIf(err)
+
+	mdatBoxes, err := updatedResource.GetChildBoxes("mdat")
+	log.PanicIf(err)
+
+	if moovBoxes[0].(*MoovBox).Start() > mdatBoxes[0].(*MdatBox).Start() {
+		t.Fatalf("MOOV box was not moved.")
+	}
+
+	// The samples should be identical at their new offsets. Note that the
+	// index is a map so we compare the tracks independently of order.
+
+	originalSamples := getTestSampleData(resource, original)
+	updatedSamples := getTestSampleData(updatedResource, updated)
+
+	if len(updatedSamples) != len(originalSamples) {
+		t.Fatalf("Sample count not correct.")
+	}
+
+	originalData := make(map[string]int)
+	for _, data := range originalSamples {
+		originalData[string(data)]++
+	}
+
+	for _, data := range updatedSamples {
+		originalData[string(data)]--
+	}
+
+	for _, count := range originalData {
+		if count != 0 {
+			t.Fatalf("Sample data not correct after move.")
+		}
+	}
+
+	// The original resource should not have been modified.
+
+	b = new(bytes.Buffer)
+
+	err = resource.Encode(b)
+	log.PanicIf(err)
+
+	if bytes.Equal(b.Bytes(), original) != true {
+		t.Fatalf("Original resource was modified.")
+	}
+
+	// Running it again should have no effect.
+
+	err = FastStart(updatedResource, new(bytes.Buffer))
+	if err != ErrAlreadyFastStart {
+		t.Fatalf("Expected ErrAlreadyFastStart: %v", err)
+	}
+}
+
+// getTestSlowStartResource returns a resource with a MOOV box that follows the
+// MDAT box and has one STCO box with the given offsets. The MOOV box is
+// optionally stored with an implicit size.
+func getTestSlowStartResource(chunkOffsets []uint32, implicitMoovSize bool) (moovSize int, resource *bmfcommon.Resource) {
+	var ftypData []byte
+	ftypData = append(ftypData, []byte("isom")...)
+	bmfcommon.PushBytes(&ftypData, uint32(0))
+
+	var stcoData []byte
+	bmfcommon.PushBytes(&stcoData, uint32(0))
+	bmfcommon.PushBytes(&stcoData, uint32(len(chunkOffsets)))
+
+	for _, chunkOffset := range chunkOffsets {
+		bmfcommon.PushBytes(&stcoData, chunkOffset)
+	}
+
+	var stblData []byte
+	bmfcommon.PushBox(&stblData, "stco", stcoData)
+
+	var minfData []byte
+	bmfcommon.PushBox(&minfData, "stbl", stblData)
+
+	var mdiaData []byte
+	bmfcommon.PushBox(&mdiaData, "minf", minfData)
+
+	var trakData []byte
+	bmfcommon.PushBox(&trakData, "mdia", mdiaData)
+
+	var moovData []byte
+	bmfcommon.PushBox(&moovData, "trak", trakData)
+
+	var b []byte
+	bmfcommon.PushBox(&b, "ftyp", ftypData)
+	bmfcommon.PushBox(&b, "mdat", []byte{1, 2, 3, 4})
+
+	if implicitMoovSize == true {
+		bmfcommon.PushBytes(&b, uint32(0))
+		bmfcommon.PushBytes(&b, []byte("moov"))
+		bmfcommon.PushBytes(&b, moovData)
+	} else {
+		bmfcommon.PushBox(&b, "moov", moovData)
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	return len(moovData) + 8, resource
+}
+
+func TestFastStart_Synthetic(t *testing.T) {
+	// The MDAT payload starts at (24).
+	moovSize, resource := getTestSlowStartResource([]uint32{24, 26}, false)
+
+	b := new(bytes.Buffer)
+
+	err := FastStart(resource, b)
+	log.PanicIf(err)
+
+	updated := b.Bytes()
+
+	sb := rifs.NewSeekableBufferWithBytes(updated)
+
+	updatedResource, err := bmfcommon.NewResource(sb, int64(len(updated)))
+	log.PanicIf(err)
+
+	ibe := bmfcommon.IndexedBoxEntry{
+		NamePhrase:     "moov.trak.mdia.minf.stbl.stco",
+		SequenceNumber: 0,
+	}
+
+	chunkOffsets := updatedResource.Index()[ibe].(*StcoBox).ChunkOffsets()
+
+	if chunkOffsets[0] != uint32(24+moovSize) || chunkOffsets[1] != uint32(26+moovSize) {
+		t.Fatalf("Chunk-offsets not correct: %v", chunkOffsets)
+	} else if updated[chunkOffsets[0]] != 1 || updated[chunkOffsets[1]] != 3 {
+		t.Fatalf("Chunk-offsets do not point to the data.")
+	}
+}
+
+func TestFastStart_ImplicitMoovSize(t *testing.T) {
+	moovSize, resource := getTestSlowStartResource([]uint32{24, 26}, true)
+
+	b := new(bytes.Buffer)
+
+	err := FastStart(resource, b)
+	log.PanicIf(err)
+
+	updated := b.Bytes()
+
+	// The MOOV box is no longer last, so it needs an explicit size.
+	if bmfcommon.DefaultEndianness.Uint32(updated[16:20]) != uint32(moovSize) {
+		t.Fatalf("MOOV size not correct: (%d)", bmfcommon.DefaultEndianness.Uint32(updated[16:20]))
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(updated)
+
+	updatedResource, err := bmfcommon.NewResource(sb, int64(len(updated)))
+	log.PanicIf(err)
+
+	children, err := updatedResource.Children()
+	log.PanicIf(err)
+
+	names := make([]string, 0)
+	for _, cb := range children {
+		names = append(names, cb.Name())
+	}
+
+	if reflect.DeepEqual(names, []string{"ftyp", "moov", "mdat"}) != true {
+		t.Fatalf("Root boxes not correct: %v", names)
+	}
+
+	ibe := bmfcommon.IndexedBoxEntry{
+		NamePhrase:     "moov.trak.mdia.minf.stbl.stco",
+		SequenceNumber: 0,
+	}
+
+	chunkOffsets := updatedResource.Index()[ibe].(*StcoBox).ChunkOffsets()
+
+	if updated[chunkOffsets[0]] != 1 || updated[chunkOffsets[1]] != 3 {
+		t.Fatalf("Chunk-offsets do not point to the data: %v", chunkOffsets)
+	}
+}
+
+func TestChunkOffsetTable_Promote(t *testing.T) {
+	_, resource := getTestSlowStartResource([]uint32{24, 26}, false)
+
+	tables := getChunkOffsetTables(resource)
+	if len(tables) != 1 {
+		t.Fatalf("Expected one chunk-offset table: (%d)", len(tables))
+	}
+
+	cot := tables[0]
+
+	shift := func(offset uint64) uint64 {
+		return offset + 0x100000000
+	}
+
+	if cot.overflows(shift) != true {
+		t.Fatalf("Expected overflow.")
+	}
+
+	cot.promote()
+	cot.apply(shift)
+
+	moovBoxes, err := resource.GetChildBoxes("moov")
+	log.PanicIf(err)
+
+	b := new(bytes.Buffer)
+
+	err = bmfcommon.EncodeBox(b, moovBoxes[0])
+	log.PanicIf(err)
+
+	encoded := b.Bytes()
+
+	sb := rifs.NewSeekableBufferWithBytes(encoded)
+
+	updatedResource, err := bmfcommon.NewResource(sb, int64(len(encoded)))
+	log.PanicIf(err)
+
+	ibe := bmfcommon.IndexedBoxEntry{
+		NamePhrase:     "moov.trak.mdia.minf.stbl.co64",
+		SequenceNumber: 0,
+	}
+
+	cb, found := updatedResource.Index()[ibe]
+	if found != true {
+		t.Fatalf("STCO box was not promoted.")
+	}
+
+	chunkOffsets := cb.(*Co64Box).ChunkOffsets()
+
+	if chunkOffsets[0] != 0x100000000+24 || chunkOffsets[1] != 0x100000000+26 {
+		t.Fatalf("Chunk-offsets not correct: %v", chunkOffsets)
+	}
+
+	// Restore.
+
+	cot.restore()
+
+	stblBoxes, err := cot.stco.Parent().(*StblBox).GetChildBoxes("stco")
+	log.PanicIf(err)
+
+	if stblBoxes[0] != cot.stco {
+		t.Fatalf("STCO box was not restored.")
+	} else if cot.stco.ChunkOffsets()[0] != 24 || cot.stco.ChunkOffsets()[1] != 26 {
+		t.Fatalf("STCO offsets were not restored.")
+	}
+}
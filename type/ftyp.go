@@ -62,10 +62,19 @@ func (fb *FtypBox) parse() (err error) {
 	data, err := fb.Data()
 	log.PanicIf(err)
 
+	if len(data) < 8 {
+		log.Panic(bmfcommon.NewErrTruncated(fb, fb.Start()+fb.HeaderSize(), 8, int64(len(data))))
+	}
+
 	fb.majorBrand = string(data[0:4])
 	fb.minorVersion = bmfcommon.DefaultEndianness.Uint32(data[4:8])
 
 	if len(data) > 8 {
+		// The last brand must be whole.
+		if remainder := (len(data) - 8) % 4; remainder != 0 {
+			log.Panic(bmfcommon.NewErrTruncated(fb, fb.Start()+fb.HeaderSize()+int64(len(data)-remainder), 4, int64(remainder)))
+		}
+
 		for i := 8; i < len(data); i += 4 {
 			fb.compatibleBrands = append(fb.compatibleBrands, string(data[i:i+4]))
 		}
@@ -74,6 +83,43 @@ func (fb *FtypBox) parse() (err error) {
 	return nil
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (fb *FtypBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"major_brand":       fb.majorBrand,
+		"minor_version":     fb.minorVersion,
+		"compatible_brands": fb.compatibleBrands,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (fb *FtypBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if len(fb.majorBrand) != 4 {
+		log.Panicf("ftyp: major brand must be four bytes: [%s]", fb.majorBrand)
+	}
+
+	data = append(data, []byte(fb.majorBrand)...)
+	bmfcommon.PushBytes(&data, fb.minorVersion)
+
+	for _, brand := range fb.compatibleBrands {
+		if len(brand) != 4 {
+			log.Panicf("ftyp: compatible brand must be four bytes: [%s]", brand)
+		}
+
+		data = append(data, []byte(brand)...)
+	}
+
+	return data, nil
+}
+
 type ftypBoxFactory struct {
 }
 
@@ -101,8 +147,9 @@ func (ftypBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = ftypBoxFactory{}
-	_ bmfcommon.CommonBox  = &FtypBox{}
+	_ bmfcommon.BoxFactory    = ftypBoxFactory{}
+	_ bmfcommon.CommonBox     = &FtypBox{}
+	_ bmfcommon.FieldExporter = &FtypBox{}
 )
 
 func init() {
@@ -72,6 +72,27 @@ func TestFtypBox_InlineString(t *testing.T) {
 	}
 }
 
+func TestFtypBox_ExportFields(t *testing.T) {
+	fb := FtypBox{
+		majorBrand:       "abcd",
+		minorVersion:     11,
+		compatibleBrands: []string{"efgh", "ijkl"},
+	}
+
+	fields, err := fb.ExportFields()
+	log.PanicIf(err)
+
+	expected := map[string]interface{}{
+		"major_brand":       "abcd",
+		"minor_version":     uint32(11),
+		"compatible_brands": []string{"efgh", "ijkl"},
+	}
+
+	if reflect.DeepEqual(fields, expected) != true {
+		t.Fatalf("ExportFields() not correct: %v", fields)
+	}
+}
+
 func TestFtypBoxFactory_Name(t *testing.T) {
 	name := ftypBoxFactory{}.Name()
 
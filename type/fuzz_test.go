@@ -0,0 +1,427 @@
+//go:build go1.18
+// +build go1.18
+
+package bmftype
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+var (
+	// fuzzAssets are the files that the fuzz corpora are seeded from.
+	fuzzAssets = []string{
+		"tears-of-steel.mp4",
+		"image.heic",
+	}
+
+	// fuzzLimits keeps each fuzz iteration small.
+	fuzzLimits = bmfcommon.ParseLimits{
+		MaxPayloadSize:  1024 * 1024,
+		MaxBoxes:        10000,
+		MaxTableEntries: 100000,
+	}
+
+	// fuzzContextualBoxTypes are the box-types whose factories need boxes
+	// elsewhere in the resource. Their synthetic seeds only parse within
+	// the synthetic resource seed.
+	fuzzContextualBoxTypes = map[string]bool{
+		"tkhd": true,
+		"infe": true,
+		"cdsc": true,
+	}
+
+	// fuzzMaxSeedPayloadSize is the largest box payload used as a seed for
+	// the factories.
+	fuzzMaxSeedPayloadSize int64 = 64 * 1024
+)
+
+// getFuzzAssetRootBoxes returns the root boxes of the asset, which are all read
+// as raw boxes.
+func getFuzzAssetRootBoxes(filename string) (data []byte, boxes []bmfcommon.CommonBox) {
+	data, err := os.ReadFile(getTestAssetFilepath(filename))
+	log.PanicIf(err)
+
+	options := bmfcommon.ParseOptions{
+		Strict:   true,
+		Registry: bmfcommon.NewRegistry(),
+	}
+
+	resource, err := bmfcommon.NewResourceFromReaderAt(bytes.NewReader(data), int64(len(data)), options)
+	log.PanicIf(err)
+
+	boxes, err = resource.Children()
+	log.PanicIf(err)
+
+	return data, boxes
+}
+
+// getFuzzResourceSeed returns the asset with the payload of the "mdat" boxes
+// removed, which keeps the structure but not the bulk of the size.
+func getFuzzResourceSeed(filename string) []byte {
+	data, boxes := getFuzzAssetRootBoxes(filename)
+
+	var seed []byte
+	for _, cb := range boxes {
+		rb := cb.(*bmfcommon.RawBox)
+
+		start := rb.Start()
+		end := start + rb.Size()
+
+		if rb.Name() == "mdat" {
+			bmfcommon.PushBox(&seed, "mdat", nil)
+			continue
+		}
+
+		seed = append(seed, data[start:end]...)
+	}
+
+	return seed
+}
+
+// getFuzzFactorySeeds returns the name and payload of every small box in the
+// asset that has a registered factory. They are in the order that they appear
+// in the asset so that the corpus is the same on every run.
+func getFuzzFactorySeeds(filename string) (names []string, payloads [][]byte) {
+	f, err := os.Open(getTestAssetFilepath(filename))
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+	log.PanicIf(err)
+
+	resource, err := bmfcommon.NewResource(f, fi.Size())
+	log.PanicIf(err)
+
+	type seedBox interface {
+		bmfcommon.CommonBox
+		Start() int64
+		HeaderSize() int64
+	}
+
+	boxes := make([]seedBox, 0)
+	for _, cb := range resource.Index() {
+		box, ok := cb.(seedBox)
+
+		if ok == false || box.Size()-box.HeaderSize() > fuzzMaxSeedPayloadSize {
+			continue
+		} else if bmfcommon.GetFactory(box.Name()) == nil {
+			continue
+		}
+
+		boxes = append(boxes, box)
+	}
+
+	sort.Slice(boxes, func(i, j int) bool {
+		return boxes[i].Start() < boxes[j].Start()
+	})
+
+	for _, box := range boxes {
+		data, err := box.Data()
+		log.PanicIf(err)
+
+		names = append(names, box.Name())
+		payloads = append(payloads, data)
+	}
+
+	return names, payloads
+}
+
+// getFuzzPayload returns the given fields in sequence.
+func getFuzzPayload(fields ...interface{}) (data []byte) {
+	for _, field := range fields {
+		bmfcommon.PushBytes(&data, field)
+	}
+
+	return data
+}
+
+// getFuzzSyntheticSeeds returns a small, valid payload for every box-type
+// that has a factory. These cover the factories that do not appear in the
+// assets.
+func getFuzzSyntheticSeeds() map[string][]byte {
+	matrix := getFuzzPayload(
+		uint32(0x00010000), uint32(0), uint32(0),
+		uint32(0), uint32(0x00010000), uint32(0),
+		uint32(0), uint32(0), uint32(0x40000000))
+
+	audioSampleEntry := getFuzzPayload(make([]byte, 6), uint16(1), make([]byte, 8), uint16(2), uint16(16), uint32(0), uint32(48000<<16))
+
+	visualSampleEntry := getFuzzPayload(make([]byte, 6), uint16(1), make([]byte, 16), uint16(640), uint16(480), uint32(0x00480000), uint32(0x00480000), uint32(0), uint16(1), make([]byte, 32), uint16(0x18), uint16(0xffff))
+
+	return map[string][]byte{
+		// Containers with no children.
+		"moov": nil,
+		"trak": nil,
+		"edts": nil,
+		"mdia": nil,
+		"minf": nil,
+		"stbl": nil,
+		"mvex": nil,
+		"moof": nil,
+		"traf": nil,
+		"mfra": nil,
+		"meta": getFuzzPayload(uint32(0)),
+		"iinf": getFuzzPayload(uint32(0), uint16(0)),
+		"iref": getFuzzPayload(uint32(0)),
+		"stsd": getFuzzPayload(uint32(0), uint32(0)),
+
+		// Opaque data.
+		"mdat": getFuzzPayload([]byte{1, 2, 3, 4}),
+		"idat": getFuzzPayload([]byte{1, 2, 3, 4}),
+
+		"ftyp": getFuzzPayload([]byte("isom"), uint32(0x200), []byte("isom"), []byte("mp41")),
+		"hdlr": getFuzzPayload(uint32(0), uint32(0), []byte("vide"), make([]byte, 12), []byte("Video\x00")),
+		"iods": testIodsData,
+		"mvhd": getFuzzPayload(uint32(0), uint32(0), uint32(0), uint32(1000), uint32(5000), uint32(0x00010000), uint16(0x0100), make([]byte, 10), matrix, make([]byte, 24), uint32(2)),
+		"tkhd": getFuzzPayload(uint32(3), uint32(0), uint32(0), uint32(1), uint32(0), uint32(5000), make([]byte, 8), uint16(0), uint16(0), uint16(0), uint16(0), matrix, uint32(640<<16), uint32(480<<16)),
+		"elst": getFuzzPayload(uint32(0), uint32(1), uint32(5000), uint32(0), uint16(1), uint16(0)),
+		"mdhd": getFuzzPayload(uint32(0), uint32(0), uint32(0), uint32(90000), uint32(450000), uint16(0x55c4), uint16(0)),
+		"vmhd": getFuzzPayload(uint32(1), uint16(0), uint16(0), uint16(0), uint16(0)),
+		"hmhd": getFuzzPayload(uint32(0), uint16(1400), uint16(1000), uint32(8000), uint32(6000), uint32(0)),
+
+		// Sample tables for two samples in one chunk.
+		"stts": getFuzzPayload(uint32(0), uint32(1), uint32(2), uint32(3000)),
+		"ctts": getFuzzPayload(uint32(0), uint32(1), uint32(2), uint32(1500)),
+		"cslg": getFuzzPayload(uint32(0), uint32(1500), uint32(0), uint32(1500), uint32(0), uint32(6000)),
+		"stss": getFuzzPayload(uint32(0), uint32(1), uint32(1)),
+		"sdtp": getFuzzPayload(uint32(0), uint8(0x20), uint8(0x10)),
+		"stsc": getFuzzPayload(uint32(0), uint32(1), uint32(1), uint32(2), uint32(1)),
+		"stsz": getFuzzPayload(uint32(0), uint32(0), uint32(2), uint32(100), uint32(200)),
+		"stz2": getFuzzPayload(uint32(0), uint32(8), uint32(2), uint8(100), uint8(200)),
+		"stco": getFuzzPayload(uint32(0), uint32(1), uint32(48)),
+		"co64": getFuzzPayload(uint32(0), uint32(1), uint64(48)),
+
+		// Sample entries and their configurations.
+		"avc1": visualSampleEntry,
+		"avc3": visualSampleEntry,
+		"hvc1": visualSampleEntry,
+		"hev1": visualSampleEntry,
+		"av01": visualSampleEntry,
+		"vp09": visualSampleEntry,
+		"mp4a": audioSampleEntry,
+		"Opus": audioSampleEntry,
+		"fLaC": audioSampleEntry,
+		"ac-3": audioSampleEntry,
+		"ec-3": audioSampleEntry,
+		"alac": audioSampleEntry,
+		"avcC": testAvcCData,
+		"hvcC": getTestHvcCData(0x01, 0x60000000, []byte{0xb0, 0, 0, 0, 0, 0}, 93),
+		"av1C": []byte{0x81, 0x04, 0x0c, 0x12, 0x0a},
+		"vpcC": getTestVpcCData(0, 10, 8, 1, false, 1, 1, 1),
+		"esds": testEsdsData,
+		"dOps": getTestDOpsData(2, 0),
+		"dfLa": getTestDfLaData(),
+		"dac3": []byte{0x10, 0x3d, 0xc0},
+		"dec3": []byte{0x14, 0x00, 0x20, 0x0f, 0x02, 0x80},
+
+		// Fragments.
+		"mehd": getFuzzPayload(uint32(0), uint32(5000)),
+		"trex": getFuzzPayload(uint32(0), uint32(1), uint32(1), uint32(0), uint32(0), uint32(0)),
+		"mfhd": getFuzzPayload(uint32(0), uint32(1)),
+		"tfhd": getFuzzPayload(uint32(0x020000), uint32(1)),
+		"tfdt": getFuzzPayload(uint32(0), uint32(90000)),
+		"trun": getFuzzPayload(uint32(0x000301), uint32(2), uint32(100), uint32(3000), uint32(100), uint32(3000), uint32(200)),
+		"tfra": getFuzzPayload(uint32(0), uint32(1), uint32(0), uint32(1), uint32(0), uint32(0), uint8(1), uint8(1), uint8(1)),
+		"mfro": getFuzzPayload(uint32(0), uint32(16)),
+
+		// Items.
+		"pitm": getFuzzPayload(uint32(0), uint16(1)),
+		"iloc": getFuzzPayload(uint32(0x01000000), uint8(0x44), uint8(0x00), uint16(1), uint16(1), uint16(1), uint16(0), uint16(1), uint32(0), uint32(4)),
+		"infe": getFuzzPayload(uint32(0x02000000), uint16(1), uint16(0), []byte("hvc1"), []byte("\x00")),
+		"cdsc": getFuzzPayload(uint16(2), uint16(1), uint16(1)),
+	}
+}
+
+// getFuzzSyntheticResourceSeed returns a small resource with the boxes that
+// can only be parsed in context: a track, items with references, and an ALAC
+// configuration in its sample-entry.
+func getFuzzSyntheticResourceSeed() []byte {
+	seeds := getFuzzSyntheticSeeds()
+
+	var meta []byte
+	bmfcommon.PushBytes(&meta, uint32(0))
+	bmfcommon.PushBox(&meta, "hdlr", getFuzzPayload(uint32(0), uint32(0), []byte("pict"), make([]byte, 12), []byte("\x00")))
+	bmfcommon.PushBox(&meta, "pitm", seeds["pitm"])
+
+	var iinf []byte
+	bmfcommon.PushBytes(&iinf, uint32(0))
+	bmfcommon.PushBytes(&iinf, uint16(2))
+	bmfcommon.PushBox(&iinf, "infe", seeds["infe"])
+	bmfcommon.PushBox(&iinf, "infe", getFuzzPayload(uint32(0x02000000), uint16(2), uint16(0), []byte("Exif"), []byte("\x00")))
+	bmfcommon.PushBox(&meta, "iinf", iinf)
+
+	var iref []byte
+	bmfcommon.PushBytes(&iref, uint32(0))
+	bmfcommon.PushBox(&iref, "cdsc", seeds["cdsc"])
+	bmfcommon.PushBox(&meta, "iref", iref)
+
+	bmfcommon.PushBox(&meta, "iloc", seeds["iloc"])
+
+	alac := getFuzzPayload(seeds["alac"])
+	bmfcommon.PushBox(&alac, "alac", getTestAlacData())
+
+	var stsd []byte
+	bmfcommon.PushBytes(&stsd, uint32(0))
+	bmfcommon.PushBytes(&stsd, uint32(1))
+	bmfcommon.PushBox(&stsd, "alac", alac)
+
+	var stbl []byte
+	bmfcommon.PushBox(&stbl, "stsd", stsd)
+
+	var minf []byte
+	bmfcommon.PushBox(&minf, "stbl", stbl)
+
+	var mdia []byte
+	bmfcommon.PushBox(&mdia, "mdhd", seeds["mdhd"])
+	bmfcommon.PushBox(&mdia, "minf", minf)
+
+	var trak []byte
+	bmfcommon.PushBox(&trak, "tkhd", seeds["tkhd"])
+	bmfcommon.PushBox(&trak, "mdia", mdia)
+
+	var moov []byte
+	bmfcommon.PushBox(&moov, "mvhd", seeds["mvhd"])
+	bmfcommon.PushBox(&moov, "trak", trak)
+
+	var seed []byte
+	bmfcommon.PushBox(&seed, "ftyp", seeds["ftyp"])
+	bmfcommon.PushBox(&seed, "moov", moov)
+	bmfcommon.PushBox(&seed, "meta", meta)
+	bmfcommon.PushBox(&seed, "mdat", seeds["mdat"])
+
+	return seed
+}
+
+// FuzzNewResource parses arbitrary resources. Errors are expected, but
+// parsing must not crash, hang, or allocate without bound, and whatever parses
+// must also export and encode.
+func FuzzNewResource(f *testing.F) {
+	for _, filename := range fuzzAssets {
+		f.Add(getFuzzResourceSeed(filename))
+	}
+
+	f.Add(getFuzzSyntheticResourceSeed())
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, strict := range []bool{true, false} {
+			options := bmfcommon.ParseOptions{
+				Strict: strict,
+				Limits: fuzzLimits,
+			}
+
+			resource, err := bmfcommon.NewResourceFromReaderAt(bytes.NewReader(data), int64(len(data)), options)
+			if err != nil {
+				continue
+			}
+
+			bmfcommon.ExportResource(resource)
+			resource.Encode(new(bytes.Buffer))
+		}
+	})
+}
+
+// FuzzBoxFactories runs the registered factory for the given box-type on
+// arbitrary payloads. The corpus is seeded with every box in the assets that
+// has a factory and with a synthetic payload for every factory.
+func FuzzBoxFactories(f *testing.F) {
+	for _, filename := range fuzzAssets {
+		names, payloads := getFuzzFactorySeeds(filename)
+		for i, name := range names {
+			f.Add(name, payloads[i])
+		}
+	}
+
+	seeds := getFuzzSyntheticSeeds()
+	for _, name := range bmfcommon.DefaultRegistry().BoxTypes() {
+		f.Add(name, seeds[name])
+	}
+
+	f.Fuzz(func(t *testing.T, name string, payload []byte) {
+		if bmfcommon.BoxNameIsValid(name) == false {
+			t.Skip()
+		}
+
+		bf := bmfcommon.GetFactory(name)
+		if bf == nil {
+			t.Skip()
+		}
+
+		var b []byte
+		bmfcommon.PushBox(&b, name, payload)
+
+		// The factory is run directly below, so nothing is registered for
+		// the resource itself.
+
+		options := bmfcommon.ParseOptions{
+			Strict:   true,
+			Registry: bmfcommon.NewRegistry(),
+			Limits:   fuzzLimits,
+		}
+
+		resource, err := bmfcommon.NewResourceFromReaderAt(bytes.NewReader(b), int64(len(b)), options)
+		log.PanicIf(err)
+
+		box, err := resource.ReadBaseBox(0)
+		log.PanicIf(err)
+
+		cb, _, err := bf.New(box)
+		if err != nil {
+			return
+		}
+
+		if fe, ok := cb.(bmfcommon.FieldExporter); ok == true {
+			fe.ExportFields()
+		}
+	})
+}
+
+func TestGetFuzzSyntheticSeeds(t *testing.T) {
+	seeds := getFuzzSyntheticSeeds()
+
+	for _, name := range bmfcommon.DefaultRegistry().BoxTypes() {
+		data, found := seeds[name]
+		if found == false {
+			t.Fatalf("No synthetic seed for registered box-type: [%s]", name)
+		}
+
+		if fuzzContextualBoxTypes[name] == true {
+			continue
+		}
+
+		_, err := getTestBoxFactoryNew(bmfcommon.GetFactory(name), data)
+		if err != nil {
+			t.Fatalf("Synthetic seed for [%s] does not parse: [%s]", name, err.Error())
+		}
+	}
+
+	if len(seeds) != len(bmfcommon.DefaultRegistry().BoxTypes()) {
+		t.Fatalf("Synthetic seed for a box-type that is not registered.")
+	}
+}
+
+func TestGetFuzzSyntheticResourceSeed(t *testing.T) {
+	seed := getFuzzSyntheticResourceSeed()
+
+	resource, err := bmfcommon.NewResourceFromReaderAt(bytes.NewReader(seed), int64(len(seed)), bmfcommon.ParseOptions{Strict: true})
+	log.PanicIf(err)
+
+	index := resource.Index()
+
+	if _, ok := index[bmfcommon.IndexedBoxEntry{NamePhrase: "moov.trak.tkhd", SequenceNumber: 0}].(*TkhdBox); ok == false {
+		t.Fatalf("TKHD box not parsed.")
+	} else if _, ok := index[bmfcommon.IndexedBoxEntry{NamePhrase: "meta.iinf.infe", SequenceNumber: 1}].(*InfeBox); ok == false {
+		t.Fatalf("INFE box not parsed.")
+	} else if _, ok := index[bmfcommon.IndexedBoxEntry{NamePhrase: "meta.iref.cdsc", SequenceNumber: 0}].(*CdscBox); ok == false {
+		t.Fatalf("CDSC box not parsed.")
+	} else if _, ok := index[bmfcommon.IndexedBoxEntry{NamePhrase: "moov.trak.mdia.minf.stbl.stsd.alac.alac", SequenceNumber: 0}].(*AlacBox); ok == false {
+		t.Fatalf("ALAC configuration not parsed.")
+	}
+}
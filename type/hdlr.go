@@ -11,24 +11,13 @@ import (
 // HdlrBox is the "Handler Reference" box.
 type HdlrBox struct {
 	bmfcommon.Box
+	bmfcommon.FullBox
 
-	version byte
-	flags   uint32
 	handler string
 
 	hdlrName string
 }
 
-// Version is the box version.
-func (hb *HdlrBox) Version() byte {
-	return hb.version
-}
-
-// Flags are flags.
-func (hb *HdlrBox) Flags() uint32 {
-	return hb.flags
-}
-
 // Handler is the type of media.
 func (hb *HdlrBox) Handler() string {
 	return hb.handler
@@ -39,6 +28,19 @@ func (hb *HdlrBox) HdlrName() string {
 	return hb.hdlrName
 }
 
+// QueryAttribute returns the value of the given attribute for path queries.
+// "handler" is the handler-type and "name" is the handler name.
+func (hb *HdlrBox) QueryAttribute(key string) (value string, found bool, err error) {
+	switch key {
+	case "handler":
+		return hb.handler, true, nil
+	case "name":
+		return hb.hdlrName, true, nil
+	}
+
+	return "", false, nil
+}
+
 // String returns a descriptive string.
 func (hb *HdlrBox) String() string {
 	return fmt.Sprintf("hdlr<%s>", hb.InlineString())
@@ -48,7 +50,7 @@ func (hb *HdlrBox) String() string {
 func (hb *HdlrBox) InlineString() string {
 	return fmt.Sprintf(
 		"%s VER=(0x%02x) FLAGS=(0x%08x) HANDLER=[%s] HDLR-NAME=(%d)[%s]",
-		hb.Box.InlineString(), hb.version, hb.flags, hb.handler, len(hb.hdlrName), hb.hdlrName)
+		hb.Box.InlineString(), hb.Version(), hb.Flags(), hb.handler, len(hb.hdlrName), hb.hdlrName)
 }
 
 func (b *HdlrBox) parse() (err error) {
@@ -61,8 +63,9 @@ func (b *HdlrBox) parse() (err error) {
 	data, err := b.Data()
 	log.PanicIf(err)
 
-	b.version = data[0]
-	b.flags = bmfcommon.DefaultEndianness.Uint32(data[0:4])
+	if len(data) < 24 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 24, int64(len(data))))
+	}
 
 	// Bytes 4:8 are for "pre_defined", which is not further described in the
 	// specification and is assumed to be analogous to reserved bytes.
@@ -88,6 +91,49 @@ func (b *HdlrBox) parse() (err error) {
 	return nil
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (hb *HdlrBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"handler": hb.handler,
+		"name":    hb.hdlrName,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. The pre-defined and reserved
+// fields, as well as anything that follows the name, are carried over.
+func (hb *HdlrBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := hb.Data()
+	log.PanicIf(err)
+
+	if len(hb.handler) != 4 {
+		log.Panicf("hdlr: handler must be four bytes: [%s]", hb.handler)
+	}
+
+	pushVersionAndFlags(&data, hb.Version(), hb.Flags())
+	data = append(data, original[4:8]...)
+	data = append(data, []byte(hb.handler)...)
+	data = append(data, original[12:24]...)
+	data = append(data, []byte(hb.hdlrName)...)
+
+	// Find the end of the original name.
+
+	i := 24
+	for ; i < len(original) && original[i] != 0; i++ {
+	}
+
+	data = append(data, original[i:]...)
+
+	return data, nil
+}
+
 type hdlrBoxFactory struct {
 }
 
@@ -96,16 +142,25 @@ func (hdlrBoxFactory) Name() string {
 	return "hdlr"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (hdlrBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
 // New returns a new value instance.
-func (hdlrBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf hdlrBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	hdlrBox := &HdlrBox{
-		Box: box,
+		Box:     box,
+		FullBox: fb,
 	}
 
 	err = hdlrBox.parse()
@@ -115,8 +170,10 @@ func (hdlrBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = hdlrBoxFactory{}
-	_ bmfcommon.CommonBox  = &HdlrBox{}
+	_ bmfcommon.FullBoxFactory  = hdlrBoxFactory{}
+	_ bmfcommon.CommonBox       = &HdlrBox{}
+	_ bmfcommon.FieldExporter   = &HdlrBox{}
+	_ bmfcommon.QueryAttributer = &HdlrBox{}
 )
 
 func init() {
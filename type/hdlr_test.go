@@ -11,7 +11,7 @@ import (
 
 func TestHdlrBox_Version(t *testing.T) {
 	hb := HdlrBox{
-		version: 11,
+		FullBox: bmfcommon.NewFullBox(11, 0),
 	}
 
 	if hb.Version() != 11 {
@@ -21,7 +21,7 @@ func TestHdlrBox_Version(t *testing.T) {
 
 func TestHdlrBox_Flags(t *testing.T) {
 	hb := HdlrBox{
-		flags: 11,
+		FullBox: bmfcommon.NewFullBox(0, 11),
 	}
 
 	if hb.Flags() != 11 {
@@ -49,13 +49,40 @@ func TestHdlrBox_HdlrName(t *testing.T) {
 	}
 }
 
+func TestHdlrBox_QueryAttribute(t *testing.T) {
+	hb := HdlrBox{
+		handler:  "vide",
+		hdlrName: "name_test",
+	}
+
+	value, found, err := hb.QueryAttribute("handler")
+	log.PanicIf(err)
+
+	if found != true || value != "vide" {
+		t.Fatalf("Handler attribute not correct: [%s]", value)
+	}
+
+	value, found, err = hb.QueryAttribute("name")
+	log.PanicIf(err)
+
+	if found != true || value != "name_test" {
+		t.Fatalf("Name attribute not correct: [%s]", value)
+	}
+
+	_, found, err = hb.QueryAttribute("invalid")
+	log.PanicIf(err)
+
+	if found != false {
+		t.Fatalf("Expected unknown attribute to not be found.")
+	}
+}
+
 func TestHdlrBox_String(t *testing.T) {
 	box := bmfcommon.NewBox("abcd", 1234, 5678, 8, nil)
 
 	hb := HdlrBox{
 		Box:      box,
-		version:  11,
-		flags:    11,
+		FullBox:  bmfcommon.NewFullBox(11, 11),
 		handler:  "handler_test",
 		hdlrName: "name_test",
 	}
@@ -70,8 +97,7 @@ func TestHdlrBox_InlineString(t *testing.T) {
 
 	hb := HdlrBox{
 		Box:      box,
-		version:  11,
-		flags:    11,
+		FullBox:  bmfcommon.NewFullBox(11, 11),
 		handler:  "handler_test",
 		hdlrName: "name_test",
 	}
@@ -95,7 +121,7 @@ func TestHdlrBoxFactory_New(t *testing.T) {
 	var hdlrBoxData []byte
 
 	// Version and flags.
-	bmfcommon.PushBytes(&hdlrBoxData, uint32(0x11223344))
+	bmfcommon.PushBytes(&hdlrBoxData, uint32(0x00223344))
 
 	// Reserved spacing.
 	bmfcommon.PushBytes(&hdlrBoxData, uint32(0))
@@ -131,11 +157,11 @@ func TestHdlrBoxFactory_New(t *testing.T) {
 
 	hb := cb.(*HdlrBox)
 
-	if hb.Version() != 0x11 {
+	if hb.Version() != 0 {
 		t.Fatalf("Version() not correct.")
 	}
 
-	if hb.Flags() != 0x11223344 {
+	if hb.Flags() != 0x223344 {
 		t.Fatalf("Flags() not correct: (0x%x)", hb.Flags())
 	}
 
@@ -147,11 +173,11 @@ func TestHdlrBoxFactory_New(t *testing.T) {
 		t.Fatalf("HdlrName() not correct.")
 	}
 
-	if hb.String() != "hdlr<NAME=[hdlr] PARENT=[ROOT] START=(0x0000000000000000) SIZE=(41) VER=(0x11) FLAGS=(0x11223344) HANDLER=[abcd] HDLR-NAME=(8)[testname]>" {
+	if hb.String() != "hdlr<NAME=[hdlr] PARENT=[ROOT] START=(0x0000000000000000) SIZE=(41) VER=(0x00) FLAGS=(0x00223344) HANDLER=[abcd] HDLR-NAME=(8)[testname]>" {
 		t.Fatalf("String() not correct: [%s]", hb.String())
 	}
 
-	if hb.InlineString() != "NAME=[hdlr] PARENT=[ROOT] START=(0x0000000000000000) SIZE=(41) VER=(0x11) FLAGS=(0x11223344) HANDLER=[abcd] HDLR-NAME=(8)[testname]" {
+	if hb.InlineString() != "NAME=[hdlr] PARENT=[ROOT] START=(0x0000000000000000) SIZE=(41) VER=(0x00) FLAGS=(0x00223344) HANDLER=[abcd] HDLR-NAME=(8)[testname]" {
 		t.Fatalf("InlineString() not correct: [%s]", hb.InlineString())
 	}
 }
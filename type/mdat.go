@@ -11,6 +11,9 @@ import (
 // A container box which can hold all of the actual media data. This is just a
 // big space where the EXIF and image data offsets refer and has little value in
 // being directly referenced.
+//
+// This type intentionally does not implement BoxEncoder so that its content is
+// streamed rather than buffered when a resource is encoded.
 type MdatBox struct {
 	bmfcommon.Box
 }
@@ -11,6 +11,9 @@ type MetaBox struct {
 	// Box is the base inner box.
 	bmfcommon.Box
 
+	// FullBox is the version and flags.
+	bmfcommon.FullBox
+
 	// LoadedBoxIndex contains this box's children.
 	bmfcommon.LoadedBoxIndex
 }
@@ -23,6 +26,18 @@ func (meta *MetaBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
 	meta.LoadedBoxIndex = fbi
 }
 
+// GetChildBoxes returns the child boxes of the given type.
+func (meta *MetaBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return meta.GetChildBoxesOf(meta, name)
+}
+
+// EncodeData returns the payload of the box preceding its children.
+func (meta *MetaBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, meta.Version(), meta.Flags())
+
+	return data, nil
+}
+
 type metaBoxFactory struct {
 }
 
@@ -31,24 +46,33 @@ func (metaBoxFactory) Name() string {
 	return "meta"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (metaBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
 // New returns a new value instance.
-func (metaBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf metaBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	metaBox := &MetaBox{
-		Box: box,
+		Box:     box,
+		FullBox: fb,
 	}
 
 	return metaBox, 4, nil
 }
 
 var (
-	_ bmfcommon.BoxFactory = metaBoxFactory{}
-	_ bmfcommon.CommonBox  = &MetaBox{}
+	_ bmfcommon.FullBoxFactory = metaBoxFactory{}
+	_ bmfcommon.CommonBox      = &MetaBox{}
 )
 
 func init() {
@@ -22,6 +22,23 @@ func (idat *IdatBox) InlineString() string {
 		idat.Box.InlineString(), len(idat.data))
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (idat *IdatBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"data_size": len(idat.data),
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (idat *IdatBox) EncodeData() (data []byte, err error) {
+	data = make([]byte, len(idat.data))
+	copy(data, idat.data)
+
+	return data, nil
+}
+
 type idatBoxFactory struct {
 }
 
@@ -50,8 +67,9 @@ func (idatBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = idatBoxFactory{}
-	_ bmfcommon.CommonBox  = &IdatBox{}
+	_ bmfcommon.BoxFactory    = idatBoxFactory{}
+	_ bmfcommon.CommonBox     = &IdatBox{}
+	_ bmfcommon.FieldExporter = &IdatBox{}
 )
 
 func init() {
@@ -20,6 +20,9 @@ type IinfBox struct {
 	// Box is the base inner box.
 	bmfcommon.Box
 
+	// FullBox is the version and flags.
+	bmfcommon.FullBox
+
 	// LoadedBoxIndex contains this box's children.
 	bmfcommon.LoadedBoxIndex
 
@@ -71,8 +74,24 @@ func (iinf *IinfBox) loadItem(infe *InfeBox) {
 	}
 }
 
+// loadItems makes sure that every INFE child has been decoded, and therefore
+// registered, when the resource is parsed lazily.
+func (iinf *IinfBox) loadItems() (err error) {
+	if _, found := iinf.LoadedBoxIndex["infe"]; found == false {
+		return nil
+	}
+
+	_, err = iinf.GetChildBoxes("infe")
+	return err
+}
+
 // GetItemWithId returns the item with the given ID.
 func (iinf *IinfBox) GetItemWithId(itemId uint32) (infe *InfeBox, err error) {
+	err = iinf.loadItems()
+	if err != nil {
+		return nil, err
+	}
+
 	infe, found := iinf.itemsById[itemId]
 	if found == false {
 		return nil, ErrNoItemsFound
@@ -84,6 +103,11 @@ func (iinf *IinfBox) GetItemWithId(itemId uint32) (infe *InfeBox, err error) {
 // GetItemWithName returns the item with the given name or
 // ErrNoItemsFound if none.
 func (iinf *IinfBox) GetItemWithName(typeName string) (infe *InfeBox, err error) {
+	err = iinf.loadItems()
+	if err != nil {
+		return nil, err
+	}
+
 	infe, found := iinf.itemsByName[typeName]
 	if found == false {
 		return nil, ErrNoItemsFound
@@ -107,6 +131,33 @@ func (iinf *IinfBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
 	iinf.LoadedBoxIndex = fbi
 }
 
+// GetChildBoxes returns the child boxes of the given type.
+func (iinf *IinfBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return iinf.GetChildBoxesOf(iinf, name)
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (iinf *IinfBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"entry_count": iinf.entryCount,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box preceding its children.
+func (iinf *IinfBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, iinf.Version(), iinf.Flags())
+
+	if iinf.Version() == 0 {
+		bmfcommon.PushBytes(&data, uint16(iinf.entryCount))
+	} else {
+		bmfcommon.PushBytes(&data, iinf.entryCount)
+	}
+
+	return data, nil
+}
+
 type iinfBoxFactory struct {
 }
 
@@ -115,34 +166,47 @@ func (iinfBoxFactory) Name() string {
 	return "iinf"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (iinfBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
 // New returns a new value instance.
-func (iinfBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, skipBytes int, err error) {
+func (bf iinfBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, skipBytes int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
-	data, err := box.Data()
+	fb, err := bmfcommon.ReadFullBox(box, bf)
 	log.PanicIf(err)
 
-	version := data[0]
+	data, err := box.Data()
+	log.PanicIf(err)
 
 	iinf := newIinfBox(box)
+	iinf.FullBox = fb
 
-	skipBytes = 4
+	skipBytes = bmfcommon.FullBoxHeaderSize
 
-	if version == 0 {
-		size := 2
+	// The count is 32-bit in version 1.
+	size := 2
+	if fb.Version() != 0 {
+		size = 4
+	}
 
+	if len(data) < skipBytes+size {
+		log.Panic(bmfcommon.NewErrTruncated(box, box.Start()+box.HeaderSize(), int64(skipBytes+size), int64(len(data))))
+	}
+
+	if fb.Version() == 0 {
 		entryCount16 := bmfcommon.DefaultEndianness.Uint16(data[skipBytes : skipBytes+size])
 
 		iinf.entryCount = uint32(entryCount16)
 
 		skipBytes += size
 	} else {
-		size := 4
-
 		iinf.entryCount = bmfcommon.DefaultEndianness.Uint32(data[skipBytes : skipBytes+size])
 
 		skipBytes += size
@@ -152,8 +216,9 @@ func (iinfBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, skipBytes
 }
 
 var (
-	_ bmfcommon.BoxFactory = iinfBoxFactory{}
-	_ bmfcommon.CommonBox  = &IinfBox{}
+	_ bmfcommon.FullBoxFactory = iinfBoxFactory{}
+	_ bmfcommon.CommonBox      = &IinfBox{}
+	_ bmfcommon.FieldExporter  = &IinfBox{}
 )
 
 func init() {
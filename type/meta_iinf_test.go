@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"io/ioutil"
+
 	"github.com/dsoprea/go-logging"
 	"github.com/dsoprea/go-utility/filesystem"
 
@@ -404,3 +406,47 @@ func TestIinfBoxFactory_New_Version1(t *testing.T) {
 		t.Fatalf("entryCount not correct.")
 	}
 }
+
+func TestIinfBox_GetItemWithId_Lazy(t *testing.T) {
+	data, err := ioutil.ReadFile(getTestAssetFilepath("image.heic"))
+	log.PanicIf(err)
+
+	sb := rifs.NewSeekableBufferWithBytes(data)
+
+	eagerResource, err := bmfcommon.NewResource(sb, int64(len(data)))
+	log.PanicIf(err)
+
+	sb = rifs.NewSeekableBufferWithBytes(data)
+
+	lazyResource, err := bmfcommon.NewResourceWithOptions(sb, int64(len(data)), bmfcommon.ParseOptions{Lazy: true})
+	log.PanicIf(err)
+
+	getIinf := func(resource *bmfcommon.Resource) *IinfBox {
+		metaBoxes, err := resource.GetChildBoxes("meta")
+		log.PanicIf(err)
+
+		iinfBoxes, err := metaBoxes[0].(*MetaBox).GetChildBoxes("iinf")
+		log.PanicIf(err)
+
+		return iinfBoxes[0].(*IinfBox)
+	}
+
+	eagerIinf := getIinf(eagerResource)
+	lazyIinf := getIinf(lazyResource)
+
+	infeBoxes, err := eagerIinf.GetChildBoxes("infe")
+	log.PanicIf(err)
+
+	for _, cb := range infeBoxes {
+		eagerInfe := cb.(*InfeBox)
+
+		lazyInfe, err := lazyIinf.GetItemWithId(eagerInfe.ItemId())
+		log.PanicIf(err)
+
+		if lazyInfe.ItemId() != eagerInfe.ItemId() {
+			t.Fatalf("Item ID not correct: (%d) != (%d)", lazyInfe.ItemId(), eagerInfe.ItemId())
+		} else if lazyInfe.ItemType() != eagerInfe.ItemType() {
+			t.Fatalf("Item type not correct for item (%d).", eagerInfe.ItemId())
+		}
+	}
+}
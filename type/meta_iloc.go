@@ -38,8 +38,7 @@ func (iiw IlocIntegerWidth) IsValid() bool {
 // IlocBox is the "Item Location" box.
 type IlocBox struct {
 	bmfcommon.Box
-
-	version byte
+	bmfcommon.FullBox
 
 	offsetSize     IlocIntegerWidth
 	lengthSize     IlocIntegerWidth
@@ -121,6 +120,21 @@ func (iloc IlocBox) Dump() (err error) {
 	return nil
 }
 
+// ReadExtent returns the data for the given extent. If the resource is
+// memory-mapped, the data is not copied and must not be modified.
+func (iloc IlocBox) ReadExtent(ie IlocExtent) (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err = iloc.ReadBytesAt(int64(ie.Offset()), int64(ie.Length()))
+	log.PanicIf(err)
+
+	return data, nil
+}
+
 func (iloc IlocBox) writeItemExtent(itemId int, infe *InfeBox, ie IlocExtent, extentNumber int, w io.Writer) (err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
@@ -223,6 +237,101 @@ func (iloc *IlocBox) InlineString() string {
 		iloc.Box.InlineString(), iloc.offsetSize, iloc.lengthSize, iloc.baseOffsetSize, iloc.indexSize, len(iloc.items))
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (iloc *IlocBox) ExportFields() (fields map[string]interface{}, err error) {
+	items := make([]map[string]interface{}, len(iloc.items))
+	for i, ii := range iloc.items {
+		extents := make([]map[string]interface{}, len(ii.extents))
+		for j, ie := range ii.extents {
+			extents[j] = map[string]interface{}{
+				"index":  ie.extentIndex,
+				"offset": ie.extentOffset,
+				"length": ie.extentLength,
+			}
+		}
+
+		var baseOffset uint64
+		for _, b := range ii.baseOffset {
+			baseOffset = baseOffset<<8 | uint64(b)
+		}
+
+		items[i] = map[string]interface{}{
+			"item_id":              ii.itemId,
+			"construction_method":  ii.constructionMethod,
+			"data_reference_index": ii.dataReferenceIndex,
+			"base_offset":          baseOffset,
+			"extents":              extents,
+		}
+	}
+
+	fields = map[string]interface{}{
+		"offset_size":      int(iloc.offsetSize),
+		"length_size":      int(iloc.lengthSize),
+		"base_offset_size": int(iloc.baseOffsetSize),
+		"index_size":       int(iloc.indexSize),
+		"items":            items,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. The reserved nibble of version
+// (0) is not modeled and is carried over.
+func (iloc *IlocBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := iloc.Data()
+	log.PanicIf(err)
+
+	pushVersionAndFlags(&data, iloc.Version(), iloc.Flags())
+
+	data = append(data, byte(iloc.offsetSize<<4|iloc.lengthSize))
+
+	if iloc.Version() == 1 || iloc.Version() == 2 {
+		data = append(data, byte(iloc.baseOffsetSize<<4|iloc.indexSize))
+	} else {
+		data = append(data, byte(iloc.baseOffsetSize<<4)|original[5]&0x0f)
+	}
+
+	if iloc.Version() < 2 {
+		bmfcommon.PushBytes(&data, uint16(len(iloc.items)))
+	} else {
+		bmfcommon.PushBytes(&data, uint32(len(iloc.items)))
+	}
+
+	for _, ii := range iloc.items {
+		if iloc.Version() < 2 {
+			bmfcommon.PushBytes(&data, uint16(ii.itemId))
+		} else {
+			bmfcommon.PushBytes(&data, ii.itemId)
+		}
+
+		if iloc.Version() == 1 || iloc.Version() == 2 {
+			bmfcommon.PushBytes(&data, ii.constructionMethod)
+		}
+
+		bmfcommon.PushBytes(&data, ii.dataReferenceIndex)
+		data = append(data, ii.baseOffset...)
+
+		bmfcommon.PushBytes(&data, uint16(len(ii.extents)))
+
+		for _, ie := range ii.extents {
+			if iloc.Version() == 1 || iloc.Version() == 2 {
+				pushSizedUint(&data, ie.extentIndex, int(iloc.indexSize))
+			}
+
+			pushSizedUint(&data, ie.extentOffset, int(iloc.offsetSize))
+			pushSizedUint(&data, ie.extentLength, int(iloc.lengthSize))
+		}
+	}
+
+	return data, nil
+}
+
 type ilocBoxFactory struct {
 }
 
@@ -412,6 +521,11 @@ func (factory ilocBoxFactory) readItem(r io.Reader, version byte, baseOffsetSize
 	return ii, nil
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (ilocBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1, 2}
+}
+
 // New returns a new value instance.
 func (factory ilocBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
@@ -420,14 +534,13 @@ func (factory ilocBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, ch
 		}
 	}()
 
-	data, err := box.Data()
+	fb, err := bmfcommon.ReadFullBox(box, factory)
 	log.PanicIf(err)
 
-	version := data[0]
+	data, err := box.Data()
+	log.PanicIf(err)
 
-	if version > 2 {
-		log.Panicf("iloc: version (%d) not supported (1)", version)
-	}
+	version := fb.Version()
 
 	b := bytes.NewBuffer(data[4:])
 	br := bufio.NewReader(b)
@@ -480,20 +593,42 @@ func (factory ilocBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, ch
 		log.PanicIf(err)
 	}
 
+	// The items follow the version and flags, the field-sizes, and the count.
+	tableOffset := int64(8)
+	if version >= 2 {
+		tableOffset += 2
+	}
+
+	// Every item has at least an ID, a data-reference index, and an extent
+	// count.
+
+	err = box.CheckEntryCount("items", int64(itemCount), tableOffset, 6)
+	log.PanicIf(err)
+
 	itemsIndex := make(map[uint32]IlocItem)
 
+	// Extents may not take any space if all of their fields have a width of
+	// zero, so their total is checked as we go.
+
+	extentCount := 0
+
 	items := make([]IlocItem, int(itemCount))
 	for i := 0; i < int(itemCount); i++ {
 		ii, err := factory.readItem(br, version, baseOffsetSize, offsetSize, lengthSize, indexSize)
 		log.PanicIf(err)
 
+		extentCount += len(ii.extents)
+
+		err = box.CheckEntryCount("extents", int64(extentCount), tableOffset, 0)
+		log.PanicIf(err)
+
 		items[i] = ii
 		itemsIndex[ii.itemId] = ii
 	}
 
 	iloc := &IlocBox{
 		Box:     box,
-		version: version,
+		FullBox: fb,
 
 		offsetSize:     offsetSize,
 		lengthSize:     lengthSize,
@@ -509,8 +644,9 @@ func (factory ilocBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, ch
 }
 
 var (
-	_ bmfcommon.BoxFactory = ilocBoxFactory{}
-	_ bmfcommon.CommonBox  = &IlocBox{}
+	_ bmfcommon.FullBoxFactory = ilocBoxFactory{}
+	_ bmfcommon.CommonBox      = &IlocBox{}
+	_ bmfcommon.FieldExporter  = &IlocBox{}
 )
 
 func init() {
@@ -178,6 +178,42 @@ func TestIlocBox_writeItemExtent(t *testing.T) {
 	}
 }
 
+func TestIlocBox_ReadExtent(t *testing.T) {
+	b := []byte{
+		0, 0, 0, 0, 0, 0, 0, 0,
+		1, 2, 3, 4,
+		5, 6,
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := bmfcommon.NewResource(sb, 0)
+	log.PanicIf(err)
+
+	iloc := &IlocBox{
+		Box: bmfcommon.NewBox("", 0, 0, 0, resource),
+	}
+
+	ie := IlocExtent{
+		extentOffset: 8,
+		extentLength: 4,
+	}
+
+	data, err := iloc.ReadExtent(ie)
+	log.PanicIf(err)
+
+	if bytes.Equal(data, []byte{1, 2, 3, 4}) != true {
+		t.Fatalf("Data not correct: %v", data)
+	}
+
+	ie.extentLength = 10
+
+	_, err = iloc.ReadExtent(ie)
+	if err == nil {
+		t.Fatalf("Expected error for truncated extent.")
+	}
+}
+
 func TestIlocBox_writeItemExtents(t *testing.T) {
 	// Establish base box.
 
@@ -1472,3 +1508,52 @@ func TestIlocBoxFactory_New_Version2_WithItems(t *testing.T) {
 		t.Fatalf("Two extents in second item are not correct.")
 	}
 }
+
+func TestIlocBoxFactory_New_MaxTableEntries_Extents(t *testing.T) {
+	var data []byte
+
+	// Version 0 with every width set to zero, so that extents do not take any
+	// space.
+	bmfcommon.PushBytes(&data, []byte{0, 0, 0, 0})
+	bmfcommon.PushBytes(&data, uint8(0))
+	bmfcommon.PushBytes(&data, uint8(0))
+
+	itemCount := uint16(2)
+	bmfcommon.PushBytes(&data, itemCount)
+
+	for itemId := uint16(1); itemId <= 2; itemId++ {
+		bmfcommon.PushBytes(&data, itemId)
+
+		// dataReferenceIndex
+		bmfcommon.PushBytes(&data, uint16(0))
+
+		// extentCount
+		bmfcommon.PushBytes(&data, uint16(6))
+	}
+
+	var b []byte
+	bmfcommon.PushBox(&b, "iloc", data)
+
+	options := bmfcommon.ParseOptions{
+		Strict:   true,
+		Registry: bmfcommon.NewRegistry(),
+		Limits: bmfcommon.ParseLimits{
+			MaxTableEntries: 10,
+		},
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResourceWithOptions(sb, int64(len(b)), options)
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	_, _, err = ilocBoxFactory{}.New(box)
+	if err == nil {
+		t.Fatalf("Expected limit error.")
+	} else if err.Error() != "MaxTableEntries exceeded: (12) > (10)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+}
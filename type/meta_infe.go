@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"strconv"
 	"unicode"
 
 	"encoding/binary"
@@ -66,7 +67,9 @@ type InfeBox struct {
 	// Box is the base inner box.
 	bmfcommon.Box
 
-	version             byte
+	// FullBox is the version and flags.
+	bmfcommon.FullBox
+
 	itemId              uint32
 	itemProtectionIndex uint16
 	itemName            string
@@ -116,6 +119,23 @@ func (infe *InfeBox) ItemType() InfeItemType {
 	return infe.itemType
 }
 
+// QueryAttribute returns the value of the given attribute for path queries:
+// "item_id", "item_name", "item_type", or "content_type".
+func (infe *InfeBox) QueryAttribute(key string) (value string, found bool, err error) {
+	switch key {
+	case "item_id":
+		return strconv.FormatUint(uint64(infe.itemId), 10), true, nil
+	case "item_name":
+		return infe.itemName, true, nil
+	case "item_type":
+		return infe.itemType.String(), true, nil
+	case "content_type":
+		return infe.contentType, true, nil
+	}
+
+	return "", false, nil
+}
+
 // ItemUriType returns the URI type (if a URI).
 func (infe *InfeBox) ItemUriType() string {
 	return infe.itemUriType
@@ -125,14 +145,14 @@ func (infe *InfeBox) ItemUriType() string {
 func (infe *InfeBox) InlineString() string {
 	var extTypePhrase string
 
-	if infe.version == 1 {
+	if infe.Version() == 1 {
 		extTypePhrase = fmt.Sprintf(" EXT-TYPE=(%d)", infe.extensionType)
 	}
 
 	var mimePhrase string
 	var uriPhrase string
 
-	if infe.version >= 2 {
+	if infe.Version() >= 2 {
 		if infe.itemType.IsMime() == true {
 			mimePhrase = fmt.Sprintf(
 				" CONTENT-TYPE=[%s] CONTENT-ENCODING=[%s]",
@@ -148,7 +168,74 @@ func (infe *InfeBox) InlineString() string {
 
 	return fmt.Sprintf(
 		"%s VER=(%d) ITEM-ID=(%d) PROTECTION-INDEX=(%d) NAME=[%s] ITEM-TYPE=[%s]%s%s%s",
-		infe.Box.InlineString(), infe.version, infe.itemId, infe.itemProtectionIndex, infe.itemName, infe.itemType, extTypePhrase, mimePhrase, uriPhrase)
+		infe.Box.InlineString(), infe.Version(), infe.itemId, infe.itemProtectionIndex, infe.itemName, infe.itemType, extTypePhrase, mimePhrase, uriPhrase)
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (infe *InfeBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"item_id":               infe.itemId,
+		"item_protection_index": infe.itemProtectionIndex,
+		"item_name":             infe.itemName,
+		"item_type":             infe.itemType.String(),
+		"content_type":          infe.contentType,
+		"content_encoding":      infe.contentEncoding,
+		"extension_type":        infe.extensionType,
+		"item_uri_type":         infe.itemUriType,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (infe *InfeBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	pushVersionAndFlags(&data, infe.Version(), infe.Flags())
+
+	pushString := func(s string) {
+		data = append(data, []byte(s)...)
+		data = append(data, 0)
+	}
+
+	if infe.Version() == 0 || infe.Version() == 1 {
+		bmfcommon.PushBytes(&data, uint16(infe.itemId))
+		bmfcommon.PushBytes(&data, infe.itemProtectionIndex)
+
+		pushString(infe.itemName)
+		pushString(infe.contentType)
+		pushString(infe.contentEncoding)
+
+		if infe.Version() == 1 {
+			bmfcommon.PushBytes(&data, infe.extensionType)
+		}
+	} else if infe.Version() == 2 || infe.Version() == 3 {
+		if infe.Version() == 2 {
+			bmfcommon.PushBytes(&data, uint16(infe.itemId))
+		} else {
+			bmfcommon.PushBytes(&data, infe.itemId)
+		}
+
+		bmfcommon.PushBytes(&data, infe.itemProtectionIndex)
+		bmfcommon.PushBytes(&data, uint32(infe.itemType))
+
+		pushString(infe.itemName)
+
+		if infe.itemType.IsMime() == true {
+			pushString(infe.contentType)
+			pushString(infe.contentEncoding)
+		} else if infe.itemType.IsUri() == true {
+			pushString(infe.itemUriType)
+		}
+	} else {
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(infe, infe.Version(), nil))
+	}
+
+	return data, nil
 }
 
 type infeBoxFactory struct {
@@ -159,31 +246,35 @@ func (infeBoxFactory) Name() string {
 	return "infe"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (infeBoxFactory) SupportedVersions() []byte {
+	// NOTE(dustin): The spec implies that we'll maintain a lot of the structure below in future versions, but, obviously, new versions will carry changes and we are cynical that what we'd have would still work.
+	return []byte{0, 1, 2, 3}
+}
+
 // New returns a new value instance.
-func (infeBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf infeBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	data, err := box.Data()
 	log.PanicIf(err)
 
 	infe := &InfeBox{
 		Box:     box,
-		version: data[0],
-	}
-
-	if infe.version > 3 {
-		// NOTE(dustin): The spec implies that we'll maintain a lot of the structure below in future versions, but, obviously, new versions will carry changes and we are cynical that what we'd have would still work.
-		log.Panicf("infe: version (%d) not supported", infe.version)
+		FullBox: fb,
 	}
 
 	b := bytes.NewBuffer(data[4:])
 	br := bufio.NewReader(b)
 
-	if infe.version == 0 || infe.version == 1 {
+	if infe.Version() == 0 || infe.Version() == 1 {
 		// itemId
 
 		var itemId16 uint16
@@ -223,27 +314,27 @@ func (infeBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 
 		infe.contentEncoding = contentEncodingRaw[:len(contentEncodingRaw)-1]
 
-		if infe.version == 1 {
+		if infe.Version() == 1 {
 			err := binary.Read(br, bmfcommon.DefaultEndianness, &infe.extensionType)
 			log.PanicIf(err)
 		}
 	}
 
-	if infe.version >= 2 {
+	if infe.Version() >= 2 {
 		// itemId
 
-		if infe.version == 2 {
+		if infe.Version() == 2 {
 			var itemId16 uint16
 
 			err = binary.Read(br, bmfcommon.DefaultEndianness, &itemId16)
 			log.PanicIf(err)
 
 			infe.itemId = uint32(itemId16)
-		} else if infe.version == 3 {
+		} else if infe.Version() == 3 {
 			err := binary.Read(br, bmfcommon.DefaultEndianness, &infe.itemId)
 			log.PanicIf(err)
 		} else {
-			log.Panicf("infe: version (%d) not supported", infe.version)
+			log.Panic(bmfcommon.NewErrUnsupportedVersion(infe, infe.Version(), nil))
 		}
 
 		// itemProtectionIndex
@@ -298,8 +389,10 @@ func (infeBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = infeBoxFactory{}
-	_ bmfcommon.CommonBox  = &InfeBox{}
+	_ bmfcommon.FullBoxFactory  = infeBoxFactory{}
+	_ bmfcommon.CommonBox       = &InfeBox{}
+	_ bmfcommon.FieldExporter   = &InfeBox{}
+	_ bmfcommon.QueryAttributer = &InfeBox{}
 )
 
 func init() {
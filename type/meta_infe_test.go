@@ -161,6 +161,38 @@ func TestInfeBox_ItemType(t *testing.T) {
 	}
 }
 
+func TestInfeBox_QueryAttribute(t *testing.T) {
+	infe := &InfeBox{
+		itemId:      11,
+		itemName:    "name",
+		itemType:    InfeItemTypeFromBytes([4]byte{'E', 'x', 'i', 'f'}),
+		contentType: "type",
+	}
+
+	attributes := map[string]string{
+		"item_id":      "11",
+		"item_name":    "name",
+		"item_type":    "Exif",
+		"content_type": "type",
+	}
+
+	for key, expected := range attributes {
+		value, found, err := infe.QueryAttribute(key)
+		log.PanicIf(err)
+
+		if found != true || value != expected {
+			t.Fatalf("Attribute [%s] not correct: [%s]", key, value)
+		}
+	}
+
+	_, found, err := infe.QueryAttribute("invalid")
+	log.PanicIf(err)
+
+	if found != false {
+		t.Fatalf("Expected unknown attribute to not be found.")
+	}
+}
+
 func TestInfeBox_ExtensionType(t *testing.T) {
 	infe := &InfeBox{
 		extensionType: 11,
@@ -185,7 +217,7 @@ func TestInfeBox_InlineString_Version0(t *testing.T) {
 	itemType := InfeItemTypeFromBytes([4]byte{'a', 'b', 'c', 'd'})
 
 	infe := &InfeBox{
-		version:             0,
+		FullBox:             bmfcommon.NewFullBox(0, 0),
 		itemType:            itemType,
 		itemId:              11,
 		itemProtectionIndex: 0,
@@ -201,7 +233,7 @@ func TestInfeBox_InlineString_Version1(t *testing.T) {
 	itemType := InfeItemTypeFromBytes([4]byte{'a', 'b', 'c', 'd'})
 
 	infe := &InfeBox{
-		version:             1,
+		FullBox:             bmfcommon.NewFullBox(1, 0),
 		itemType:            itemType,
 		itemId:              11,
 		itemProtectionIndex: 0,
@@ -218,7 +250,7 @@ func TestInfeBox_InlineString_Version2_NoMimeNoUri(t *testing.T) {
 	itemType := InfeItemTypeFromBytes([4]byte{'a', 'b', 'c', 'd'})
 
 	infe := &InfeBox{
-		version:             2,
+		FullBox:             bmfcommon.NewFullBox(2, 0),
 		itemType:            itemType,
 		itemId:              11,
 		itemProtectionIndex: 0,
@@ -235,7 +267,7 @@ func TestInfeBox_InlineString_Version2_Mime(t *testing.T) {
 	itemType := InfeItemTypeFromBytes([4]byte{'m', 'i', 'm', 'e'})
 
 	infe := &InfeBox{
-		version:             2,
+		FullBox:             bmfcommon.NewFullBox(2, 0),
 		itemType:            itemType,
 		itemId:              11,
 		itemProtectionIndex: 0,
@@ -252,7 +284,7 @@ func TestInfeBox_InlineString_Version2_Uri(t *testing.T) {
 	itemType := InfeItemTypeFromBytes([4]byte{'u', 'r', 'i', ' '})
 
 	infe := &InfeBox{
-		version:             3,
+		FullBox:             bmfcommon.NewFullBox(3, 0),
 		itemType:            itemType,
 		itemId:              11,
 		itemProtectionIndex: 0,
@@ -810,3 +842,62 @@ func TestInfeBox_New_Version2_Uri(t *testing.T) {
 		t.Fatalf("GetItemWithName() does not return the correct INFE record.")
 	}
 }
+
+func TestInfeBox_New_Lenient(t *testing.T) {
+	pushInfe := func(b *[]byte, itemId, itemProtectionIndex uint16) {
+		var infeData []byte
+
+		bmfcommon.PushBytes(&infeData, []byte{0, 0, 0, 0})
+		bmfcommon.PushBytes(&infeData, itemId)
+		bmfcommon.PushBytes(&infeData, itemProtectionIndex)
+		bmfcommon.PushBytes(&infeData, []byte("abc\000def\000ghi\000"))
+
+		bmfcommon.PushBox(b, "infe", infeData)
+	}
+
+	var iinfData []byte
+	bmfcommon.PushBytes(&iinfData, []byte{0, 0, 0, 0})
+	bmfcommon.PushBytes(&iinfData, uint16(2))
+
+	// Protection is not supported.
+	pushInfe(&iinfData, 11, 1)
+	pushInfe(&iinfData, 22, 0)
+
+	var b []byte
+	bmfcommon.PushBox(&b, "iinf", iinfData)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	_, err := bmfcommon.NewResource(sb, int64(len(b)))
+	if err == nil {
+		t.Fatalf("Expected error in strict mode.")
+	}
+
+	resource, err := bmfcommon.NewResourceWithOptions(sb, int64(len(b)), bmfcommon.ParseOptions{})
+	log.PanicIf(err)
+
+	diagnostics := resource.Diagnostics()
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected one diagnostic: %v", diagnostics)
+	} else if diagnostics[0].Path != "iinf.infe" || diagnostics[0].Offset != 14 {
+		t.Fatalf("Diagnostic not correct: %s", diagnostics[0])
+	}
+
+	iinfBoxes, err := resource.GetChildBoxes("iinf")
+	log.PanicIf(err)
+
+	iinf := iinfBoxes[0].(*IinfBox)
+
+	_, err = iinf.GetItemWithId(11)
+	if err != ErrNoItemsFound {
+		t.Fatalf("Expected skipped item to be missing: %v", err)
+	}
+
+	infe, err := iinf.GetItemWithId(22)
+	log.PanicIf(err)
+
+	if infe.ItemName() != "abc" {
+		t.Fatalf("Item after skipped item not correct.")
+	}
+}
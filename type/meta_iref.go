@@ -11,18 +11,12 @@ import (
 // IrefBox is a "Item Reference" box.
 type IrefBox struct {
 	bmfcommon.Box
-
-	version byte
+	bmfcommon.FullBox
 
 	// LoadedBoxIndex contains this box's children.
 	bmfcommon.LoadedBoxIndex
 }
 
-// Version retrns the structural version of the IREF box.
-func (iref IrefBox) Version() byte {
-	return iref.version
-}
-
 // InlineString returns an undecorated string of field names and values.
 func (iref *IrefBox) InlineString() string {
 	return fmt.Sprintf(
@@ -41,6 +35,18 @@ func (iref *IrefBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
 	iref.LoadedBoxIndex = fbi
 }
 
+// GetChildBoxes returns the child boxes of the given type.
+func (iref *IrefBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return iref.GetChildBoxesOf(iref, name)
+}
+
+// EncodeData returns the payload of the box preceding its children.
+func (iref *IrefBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, iref.Version(), iref.Flags())
+
+	return data, nil
+}
+
 type irefBoxFactory struct {
 }
 
@@ -49,35 +55,38 @@ func (irefBoxFactory) Name() string {
 	return "iref"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (irefBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
 // New returns a new value instance.
 //
 // This contains other boxes, but the box-types are actually the reference-
 // types (e.g. cdsc)..
-func (irefBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf irefBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
-	data, err := box.Data()
+	fb, err := bmfcommon.ReadFullBox(box, bf)
 	log.PanicIf(err)
 
-	version := data[0]
-
 	// TODO(dustin): Circle back to this when we have sample data.
 
 	iref := &IrefBox{
 		Box:     box,
-		version: version,
+		FullBox: fb,
 	}
 
 	return iref, 4, nil
 }
 
 var (
-	_ bmfcommon.BoxFactory = irefBoxFactory{}
-	_ bmfcommon.CommonBox  = &IrefBox{}
+	_ bmfcommon.FullBoxFactory = irefBoxFactory{}
+	_ bmfcommon.CommonBox      = &IrefBox{}
 )
 
 func init() {
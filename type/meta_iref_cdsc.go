@@ -41,6 +41,48 @@ func (cdsc *CdscBox) InlineString() string {
 		cdsc.Box.InlineString(), cdsc.version, cdsc.fromItemId, len(toItemIdsPhrases), toItemIdsPhrase)
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (cdsc *CdscBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"from_item_id": cdsc.fromItemId,
+		"to_item_ids":  cdsc.toItemIds,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. The widths of the item IDs are
+// determined by the version of the parent IREF box.
+func (cdsc *CdscBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	iref, ok := cdsc.Parent().(*IrefBox)
+	if ok == false {
+		log.Panicf("cdsc: parent is not an IREF box")
+	}
+
+	pushItemId := func(itemId uint32) {
+		if iref.Version() == 0 {
+			bmfcommon.PushBytes(&data, uint16(itemId))
+		} else {
+			bmfcommon.PushBytes(&data, itemId)
+		}
+	}
+
+	pushItemId(cdsc.fromItemId)
+	bmfcommon.PushBytes(&data, uint16(len(cdsc.toItemIds)))
+
+	for _, toItemId := range cdsc.toItemIds {
+		pushItemId(toItemId)
+	}
+
+	return data, nil
+}
+
 type cdscBoxFactory struct {
 }
 
@@ -73,6 +115,16 @@ func (cdscBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 	iref := irefCommonBox.(*IrefBox)
 	irefVersion := iref.Version()
 
+	// The item IDs are 32-bit in version 1.
+	itemIdSize := 2
+	if irefVersion == 1 {
+		itemIdSize = 4
+	}
+
+	if len(data) < itemIdSize+2 {
+		log.Panic(bmfcommon.NewErrTruncated(box, box.Start()+box.HeaderSize(), int64(itemIdSize+2), int64(len(data))))
+	}
+
 	var fromItemId uint32
 
 	offset := 0
@@ -93,6 +145,9 @@ func (cdscBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 
 	offset += 2
 
+	err = box.CheckEntryCount("references", int64(referenceCount), int64(offset), int64(itemIdSize))
+	log.PanicIf(err)
+
 	toItemIds := make([]uint32, referenceCount)
 
 	for i := 0; i < int(referenceCount); i++ {
@@ -123,8 +178,9 @@ func (cdscBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = cdscBoxFactory{}
-	_ bmfcommon.CommonBox  = &CdscBox{}
+	_ bmfcommon.BoxFactory    = cdscBoxFactory{}
+	_ bmfcommon.CommonBox     = &CdscBox{}
+	_ bmfcommon.FieldExporter = &CdscBox{}
 )
 
 func init() {
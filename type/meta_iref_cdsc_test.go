@@ -46,7 +46,7 @@ func TestCdscBox_New_Version0_WithReferences(t *testing.T) {
 	log.PanicIf(err)
 
 	iref := &IrefBox{
-		version: 0,
+		FullBox: bmfcommon.NewFullBox(0, 0),
 	}
 
 	ibe := bmfcommon.IndexedBoxEntry{"meta.iref", 0}
@@ -100,7 +100,7 @@ func TestCdscBox_New_Version1_WithReferences(t *testing.T) {
 	log.PanicIf(err)
 
 	iref := &IrefBox{
-		version: 1,
+		FullBox: bmfcommon.NewFullBox(1, 0),
 	}
 
 	ibe := bmfcommon.IndexedBoxEntry{"meta.iref", 0}
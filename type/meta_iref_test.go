@@ -12,7 +12,7 @@ import (
 
 func TestIrefBox_Version(t *testing.T) {
 	iref := &IrefBox{
-		version: 11,
+		FullBox: bmfcommon.NewFullBox(11, 0),
 	}
 
 	if iref.Version() != 11 {
@@ -22,7 +22,7 @@ func TestIrefBox_Version(t *testing.T) {
 
 func TestIrefBox_InlineString(t *testing.T) {
 	iref := &IrefBox{
-		version: 11,
+		FullBox: bmfcommon.NewFullBox(11, 0),
 	}
 
 	if iref.InlineString() != "NAME=[] PARENT=[ROOT] START=(0x0000000000000000) SIZE=(0)" {
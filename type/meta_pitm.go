@@ -11,6 +11,7 @@ import (
 // PitmBox is a "Handler Reference" box.
 type PitmBox struct {
 	bmfcommon.Box
+	bmfcommon.FullBox
 
 	itemId uint32
 }
@@ -27,6 +28,28 @@ func (pitm *PitmBox) InlineString() string {
 		pitm.Box.InlineString(), pitm.itemId)
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (pitm *PitmBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"item_id": pitm.itemId,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (pitm *PitmBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, pitm.Version(), pitm.Flags())
+
+	if pitm.Version() == 0 {
+		bmfcommon.PushBytes(&data, uint16(pitm.itemId))
+	} else {
+		bmfcommon.PushBytes(&data, pitm.itemId)
+	}
+
+	return data, nil
+}
+
 type pitmBoxFactory struct {
 }
 
@@ -35,41 +58,57 @@ func (pitmBoxFactory) Name() string {
 	return "pitm"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (pitmBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
 // New returns a new value instance.
-func (pitmBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf pitmBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	data, err := box.Data()
 	log.PanicIf(err)
 
-	version := data[0]
+	// The item ID is 32-bit in version 1.
+	size := 6
+	if fb.Version() != 0 {
+		size = 8
+	}
+
+	if len(data) < size {
+		log.Panic(bmfcommon.NewErrTruncated(box, box.Start()+box.HeaderSize(), int64(size), int64(len(data))))
+	}
 
 	var itemId uint32
 
-	if version == 0 {
+	if fb.Version() == 0 {
 		itemId16 := bmfcommon.DefaultEndianness.Uint16(data[4:6])
 		itemId = uint32(itemId16)
-	} else if version == 1 {
-		itemId = bmfcommon.DefaultEndianness.Uint32(data[4:8])
 	} else {
-		log.Panicf("pitm: version (%d) not supported", version)
+		itemId = bmfcommon.DefaultEndianness.Uint32(data[4:8])
 	}
 
 	pitm := &PitmBox{
-		Box:    box,
-		itemId: itemId,
+		Box:     box,
+		FullBox: fb,
+		itemId:  itemId,
 	}
 
 	return pitm, -1, nil
 }
 
 var (
-	_ bmfcommon.BoxFactory = pitmBoxFactory{}
-	_ bmfcommon.CommonBox  = &PitmBox{}
+	_ bmfcommon.FullBoxFactory = pitmBoxFactory{}
+	_ bmfcommon.CommonBox      = &PitmBox{}
+	_ bmfcommon.FieldExporter  = &PitmBox{}
 )
 
 func init() {
@@ -30,8 +30,11 @@ func TestMetaBoxFactory_Name(t *testing.T) {
 }
 
 func TestMetaBoxFactory_New(t *testing.T) {
+	// Version and flags.
+	data := []byte{0, 0, 0, 0}
+
 	b := []byte{}
-	bmfcommon.PushBox(&b, "meta", nil)
+	bmfcommon.PushBox(&b, "meta", data)
 
 	// Parse.
 
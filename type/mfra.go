@@ -0,0 +1,69 @@
+package bmftype
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// MfraBox is the "Movie Fragment Random Access" box.
+//
+// It provides a table that may assist readers in finding sync samples in a
+// file using movie fragments. It is usually placed at the end of the file.
+type MfraBox struct {
+	bmfcommon.Box
+
+	// LoadedBoxIndex contains this box's children.
+	bmfcommon.LoadedBoxIndex
+}
+
+// SetLoadedBoxIndex sets the child boxes after a box has been manufactured
+// and the children have been parsed. This allows parent boxes to be
+// registered before the child boxes can look for them.
+func (mfra *MfraBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
+	fbi := boxes.Index()
+	mfra.LoadedBoxIndex = fbi
+}
+
+// GetChildBoxes returns the child boxes of the given type.
+func (mfra *MfraBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return mfra.GetChildBoxesOf(mfra, name)
+}
+
+// EncodeData returns the payload of the box preceding its children. This box
+// only has children.
+func (mfra *MfraBox) EncodeData() (data []byte, err error) {
+	return []byte{}, nil
+}
+
+type mfraBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (mfraBoxFactory) Name() string {
+	return "mfra"
+}
+
+// New returns a new value instance.
+func (mfraBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	mfraBox := &MfraBox{
+		Box: box,
+	}
+
+	return mfraBox, 0, nil
+}
+
+var (
+	_ bmfcommon.BoxFactory = mfraBoxFactory{}
+	_ bmfcommon.CommonBox  = &MfraBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(mfraBoxFactory{})
+}
@@ -0,0 +1,112 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// MfroBox is the "Movie Fragment Random Access Offset" box. It is the last box
+// in the MFRA box and allows readers to locate the MFRA box by scanning
+// backwards from the end of the file.
+type MfroBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	parentSize uint32
+}
+
+// ParentSize returns the size of the enclosing MFRA box.
+func (mb *MfroBox) ParentSize() uint32 {
+	return mb.parentSize
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (mb *MfroBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) PARENT-SIZE=(%d)",
+		mb.Box.InlineString(), mb.Version(), mb.Flags(), mb.parentSize)
+}
+
+func (b *MfroBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < 8 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 8, int64(len(data))))
+	}
+
+	b.parentSize = bmfcommon.DefaultEndianness.Uint32(data[4:8])
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (mb *MfroBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"parent_size": mb.parentSize,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (mb *MfroBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, mb.Version(), mb.Flags())
+	bmfcommon.PushBytes(&data, mb.parentSize)
+
+	return data, nil
+}
+
+type mfroBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (mfroBoxFactory) Name() string {
+	return "mfro"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (mfroBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf mfroBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	mfroBox := &MfroBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = mfroBox.parse()
+	log.PanicIf(err)
+
+	return mfroBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = mfroBoxFactory{}
+	_ bmfcommon.CommonBox      = &MfroBox{}
+	_ bmfcommon.FieldExporter  = &MfroBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(mfroBoxFactory{})
+}
@@ -0,0 +1,58 @@
+package bmftype
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestMfroBoxFactory_Name(t *testing.T) {
+	name := mfroBoxFactory{}.Name()
+
+	if name != "mfro" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestMfroBoxFactory_New(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	bmfcommon.PushBytes(&data, uint32(1024))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "mfro", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := mfroBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	mfro := cb.(*MfroBox)
+
+	if mfro.ParentSize() != 1024 {
+		t.Fatalf("ParentSize() not correct.")
+	}
+}
+
+func TestMfroBox_EncodeData(t *testing.T) {
+	var data []byte
+	bmfcommon.PushBytes(&data, uint32(0))
+	bmfcommon.PushBytes(&data, uint32(123))
+
+	cb := getTestParsedBox(mfroBoxFactory{}, data)
+	assertEncodeData(t, cb, data)
+}
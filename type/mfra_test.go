@@ -0,0 +1,55 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestMfraBox_SetLoadedBoxIndex(t *testing.T) {
+	lbi := make(bmfcommon.Boxes, 0)
+
+	mfra := new(MfraBox)
+	mfra.SetLoadedBoxIndex(lbi)
+
+	if reflect.DeepEqual(mfra.LoadedBoxIndex, lbi.Index()) != true {
+		t.Fatalf("SetLoadedBoxIndex() did not set the LBI correctly.")
+	}
+}
+
+func TestMfraBoxFactory_Name(t *testing.T) {
+	name := mfraBoxFactory{}.Name()
+
+	if name != "mfra" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestMfraBoxFactory_New(t *testing.T) {
+	b := []byte{}
+	bmfcommon.PushBox(&b, "mfra", nil)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := mfraBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	// Nothing else we can validate.
+	_, ok := cb.(*MfraBox)
+
+	if ok != true {
+		t.Fatalf("Expected an 'mfra' box.")
+	}
+}
@@ -0,0 +1,299 @@
+package bmftype
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// TfraEntry is one random-access point.
+type TfraEntry struct {
+	time         uint64
+	moofOffset   uint64
+	trafNumber   uint32
+	trunNumber   uint32
+	sampleNumber uint32
+}
+
+// Time returns the presentation time of the sync sample, in the media
+// time-scale.
+func (te TfraEntry) Time() uint64 {
+	return te.time
+}
+
+// MoofOffset returns the file offset of the MOOF box that contains the sample.
+func (te TfraEntry) MoofOffset() uint64 {
+	return te.moofOffset
+}
+
+// TrafNumber returns the (one-based) number of the TRAF box that contains the
+// sample.
+func (te TfraEntry) TrafNumber() uint32 {
+	return te.trafNumber
+}
+
+// TrunNumber returns the (one-based) number of the TRUN box that contains the
+// sample.
+func (te TfraEntry) TrunNumber() uint32 {
+	return te.trunNumber
+}
+
+// SampleNumber returns the (one-based) number of the sample within the TRUN.
+func (te TfraEntry) SampleNumber() uint32 {
+	return te.sampleNumber
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (te TfraEntry) InlineString() string {
+	return fmt.Sprintf(
+		"TIME=(%d) MOOF-OFFSET=(0x%016x) TRAF=(%d) TRUN=(%d) SAMPLE=(%d)",
+		te.time, te.moofOffset, te.trafNumber, te.trunNumber, te.sampleNumber)
+}
+
+// TfraBox is the "Track Fragment Random Access" box.
+type TfraBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	trackId uint32
+	entries []TfraEntry
+}
+
+// TrackId returns the ID of the track that these entries describe.
+func (tb *TfraBox) TrackId() uint32 {
+	return tb.trackId
+}
+
+// Entries returns the random-access points.
+func (tb *TfraBox) Entries() []TfraEntry {
+	return tb.entries
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (tb *TfraBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%06x) TRACK-ID=(%d) ENTRIES=(%d)",
+		tb.Box.InlineString(), tb.Version(), tb.Flags(), tb.trackId, len(tb.entries))
+}
+
+// readVariableUint reads a one- to four-byte big-endian integer.
+func readVariableUint(r io.Reader, size int) (value uint32, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	raw := make([]byte, size)
+
+	_, err = io.ReadFull(r, raw)
+	log.PanicIf(err)
+
+	for _, x := range raw {
+		value = value<<8 | uint32(x)
+	}
+
+	return value, nil
+}
+
+func (b *TfraBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < 16 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 16, int64(len(data))))
+	}
+
+	b.trackId = bmfcommon.DefaultEndianness.Uint32(data[4:8])
+
+	lengths := bmfcommon.DefaultEndianness.Uint32(data[8:12])
+	trafNumberSize := int((lengths>>4)&0x3) + 1
+	trunNumberSize := int((lengths>>2)&0x3) + 1
+	sampleNumberSize := int(lengths&0x3) + 1
+
+	count := bmfcommon.DefaultEndianness.Uint32(data[12:16])
+
+	entrySize := 8 + trafNumberSize + trunNumberSize + sampleNumberSize
+	if b.Version() == 1 {
+		entrySize += 8
+	}
+
+	if uint64(len(data)-16) < uint64(count)*uint64(entrySize) {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize()+16, int64(count)*int64(entrySize), int64(len(data)-16)))
+	}
+
+	s := bytes.NewBuffer(data[16:])
+
+	err = b.CheckEntryCount("entries", int64(count), 16, 0)
+	log.PanicIf(err)
+
+	b.entries = make([]TfraEntry, count)
+
+	for i := range b.entries {
+		entry := &b.entries[i]
+
+		if b.Version() == 1 {
+			err = binary.Read(s, bmfcommon.DefaultEndianness, &entry.time)
+			log.PanicIf(err)
+
+			err = binary.Read(s, bmfcommon.DefaultEndianness, &entry.moofOffset)
+			log.PanicIf(err)
+		} else {
+			var time32 uint32
+
+			err = binary.Read(s, bmfcommon.DefaultEndianness, &time32)
+			log.PanicIf(err)
+
+			entry.time = uint64(time32)
+
+			var moofOffset32 uint32
+
+			err = binary.Read(s, bmfcommon.DefaultEndianness, &moofOffset32)
+			log.PanicIf(err)
+
+			entry.moofOffset = uint64(moofOffset32)
+		}
+
+		entry.trafNumber, err = readVariableUint(s, trafNumberSize)
+		log.PanicIf(err)
+
+		entry.trunNumber, err = readVariableUint(s, trunNumberSize)
+		log.PanicIf(err)
+
+		entry.sampleNumber, err = readVariableUint(s, sampleNumberSize)
+		log.PanicIf(err)
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (tb *TfraBox) ExportFields() (fields map[string]interface{}, err error) {
+	entries := make([]map[string]interface{}, len(tb.entries))
+	for i, te := range tb.entries {
+		entries[i] = map[string]interface{}{
+			"time":          te.time,
+			"moof_offset":   te.moofOffset,
+			"traf_number":   te.trafNumber,
+			"trun_number":   te.trunNumber,
+			"sample_number": te.sampleNumber,
+		}
+	}
+
+	fields = map[string]interface{}{
+		"track_id": tb.trackId,
+		"entries":  entries,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. The narrowest integer widths that
+// can hold the TRAF, TRUN, and sample numbers are used.
+func (tb *TfraBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if tb.Version() > 1 {
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(tb, tb.Version(), nil))
+	}
+
+	widthOf := func(get func(TfraEntry) uint32) int {
+		width := 1
+
+		for _, entry := range tb.entries {
+			value := get(entry)
+
+			for width < 4 && value>>(uint(width)*8) != 0 {
+				width++
+			}
+		}
+
+		return width
+	}
+
+	trafNumberSize := widthOf(TfraEntry.TrafNumber)
+	trunNumberSize := widthOf(TfraEntry.TrunNumber)
+	sampleNumberSize := widthOf(TfraEntry.SampleNumber)
+
+	pushVersionAndFlags(&data, tb.Version(), tb.Flags())
+	bmfcommon.PushBytes(&data, tb.trackId)
+	bmfcommon.PushBytes(&data, uint32((trafNumberSize-1)<<4|(trunNumberSize-1)<<2|(sampleNumberSize-1)))
+	bmfcommon.PushBytes(&data, uint32(len(tb.entries)))
+
+	for _, entry := range tb.entries {
+		if tb.Version() == 1 {
+			bmfcommon.PushBytes(&data, entry.time)
+			bmfcommon.PushBytes(&data, entry.moofOffset)
+		} else {
+			bmfcommon.PushBytes(&data, uint32(entry.time))
+			bmfcommon.PushBytes(&data, uint32(entry.moofOffset))
+		}
+
+		pushSizedUint(&data, uint64(entry.trafNumber), trafNumberSize)
+		pushSizedUint(&data, uint64(entry.trunNumber), trunNumberSize)
+		pushSizedUint(&data, uint64(entry.sampleNumber), sampleNumberSize)
+	}
+
+	return data, nil
+}
+
+type tfraBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (tfraBoxFactory) Name() string {
+	return "tfra"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (tfraBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
+// New returns a new value instance.
+func (bf tfraBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	tfraBox := &TfraBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = tfraBox.parse()
+	log.PanicIf(err)
+
+	return tfraBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = tfraBoxFactory{}
+	_ bmfcommon.CommonBox      = &TfraBox{}
+	_ bmfcommon.FieldExporter  = &TfraBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(tfraBoxFactory{})
+}
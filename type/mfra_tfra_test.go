@@ -0,0 +1,149 @@
+package bmftype
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestTfraBoxFactory_Name(t *testing.T) {
+	name := tfraBoxFactory{}.Name()
+
+	if name != "tfra" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestTfraBoxFactory_New_Version0(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	bmfcommon.PushBytes(&data, uint32(1))
+
+	// traf-number is one byte, trun-number is two bytes, and sample-number is
+	// three bytes.
+	bmfcommon.PushBytes(&data, uint32(0x00<<4|0x01<<2|0x02))
+
+	bmfcommon.PushBytes(&data, uint32(1))
+
+	bmfcommon.PushBytes(&data, uint32(3000))
+	bmfcommon.PushBytes(&data, uint32(0x1000))
+	bmfcommon.PushBytes(&data, uint8(1))
+	bmfcommon.PushBytes(&data, uint16(2))
+	bmfcommon.PushBytes(&data, []byte{0x00, 0x01, 0x03})
+
+	var b []byte
+	bmfcommon.PushBox(&b, "tfra", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := tfraBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	tfra := cb.(*TfraBox)
+
+	if tfra.TrackId() != 1 {
+		t.Fatalf("TrackId() not correct.")
+	}
+
+	entries := tfra.Entries()
+
+	if len(entries) != 1 {
+		t.Fatalf("Entries() not correct.")
+	}
+
+	entry := entries[0]
+
+	if entry.Time() != 3000 {
+		t.Fatalf("Time() not correct.")
+	} else if entry.MoofOffset() != 0x1000 {
+		t.Fatalf("MoofOffset() not correct.")
+	} else if entry.TrafNumber() != 1 {
+		t.Fatalf("TrafNumber() not correct.")
+	} else if entry.TrunNumber() != 2 {
+		t.Fatalf("TrunNumber() not correct.")
+	} else if entry.SampleNumber() != 0x103 {
+		t.Fatalf("SampleNumber() not correct.")
+	}
+}
+
+func TestTfraBoxFactory_New_Version1(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0x01000000))
+
+	bmfcommon.PushBytes(&data, uint32(2))
+	bmfcommon.PushBytes(&data, uint32(0))
+	bmfcommon.PushBytes(&data, uint32(1))
+
+	bmfcommon.PushBytes(&data, uint64(0x100000000))
+	bmfcommon.PushBytes(&data, uint64(0x200000000))
+	bmfcommon.PushBytes(&data, uint8(1))
+	bmfcommon.PushBytes(&data, uint8(1))
+	bmfcommon.PushBytes(&data, uint8(5))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "tfra", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := tfraBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	tfra := cb.(*TfraBox)
+	entry := tfra.Entries()[0]
+
+	if entry.Time() != 0x100000000 {
+		t.Fatalf("Time() not correct.")
+	} else if entry.MoofOffset() != 0x200000000 {
+		t.Fatalf("MoofOffset() not correct.")
+	} else if entry.SampleNumber() != 5 {
+		t.Fatalf("SampleNumber() not correct.")
+	}
+}
+
+func TestTfraBox_EncodeData(t *testing.T) {
+	var data []byte
+	bmfcommon.PushBytes(&data, uint32(0x01000000))
+
+	// track-ID
+	bmfcommon.PushBytes(&data, uint32(1))
+
+	// TRAF number is one byte, TRUN number is two bytes, sample number is
+	// three bytes.
+	bmfcommon.PushBytes(&data, uint32(0<<4|1<<2|2))
+
+	// entry-count
+	bmfcommon.PushBytes(&data, uint32(1))
+
+	bmfcommon.PushBytes(&data, uint64(1000))
+	bmfcommon.PushBytes(&data, uint64(0x2000))
+	data = append(data, 1)
+	data = append(data, 0x01, 0x02)
+	data = append(data, 0x01, 0x02, 0x03)
+
+	cb := getTestParsedBox(tfraBoxFactory{}, data)
+	assertEncodeData(t, cb, data)
+}
@@ -0,0 +1,69 @@
+package bmftype
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// MoofBox is the "Movie Fragment" box.
+//
+// Movie fragments extend the presentation in time. They provide the
+// information that would previously have been in the MOOV box.
+type MoofBox struct {
+	bmfcommon.Box
+
+	// LoadedBoxIndex contains this box's children.
+	bmfcommon.LoadedBoxIndex
+}
+
+// SetLoadedBoxIndex sets the child boxes after a box has been manufactured
+// and the children have been parsed. This allows parent boxes to be
+// registered before the child boxes can look for them.
+func (moof *MoofBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
+	fbi := boxes.Index()
+	moof.LoadedBoxIndex = fbi
+}
+
+// GetChildBoxes returns the child boxes of the given type.
+func (moof *MoofBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return moof.GetChildBoxesOf(moof, name)
+}
+
+// EncodeData returns the payload of the box preceding its children. This box
+// only has children.
+func (moof *MoofBox) EncodeData() (data []byte, err error) {
+	return []byte{}, nil
+}
+
+type moofBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (moofBoxFactory) Name() string {
+	return "moof"
+}
+
+// New returns a new value instance.
+func (moofBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	moofBox := &MoofBox{
+		Box: box,
+	}
+
+	return moofBox, 0, nil
+}
+
+var (
+	_ bmfcommon.BoxFactory = moofBoxFactory{}
+	_ bmfcommon.CommonBox  = &MoofBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(moofBoxFactory{})
+}
@@ -0,0 +1,111 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// MfhdBox is the "Movie Fragment Header" box.
+type MfhdBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	sequenceNumber uint32
+}
+
+// SequenceNumber returns the ordinal number of this fragment, in increasing
+// order.
+func (mb *MfhdBox) SequenceNumber() uint32 {
+	return mb.sequenceNumber
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (mb *MfhdBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) SEQUENCE-NUMBER=(%d)",
+		mb.Box.InlineString(), mb.Version(), mb.Flags(), mb.sequenceNumber)
+}
+
+func (b *MfhdBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < 8 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 8, int64(len(data))))
+	}
+
+	b.sequenceNumber = bmfcommon.DefaultEndianness.Uint32(data[4:8])
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (mb *MfhdBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"sequence_number": mb.sequenceNumber,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (mb *MfhdBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, mb.Version(), mb.Flags())
+	bmfcommon.PushBytes(&data, mb.sequenceNumber)
+
+	return data, nil
+}
+
+type mfhdBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (mfhdBoxFactory) Name() string {
+	return "mfhd"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (mfhdBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf mfhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	mfhdBox := &MfhdBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = mfhdBox.parse()
+	log.PanicIf(err)
+
+	return mfhdBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = mfhdBoxFactory{}
+	_ bmfcommon.CommonBox      = &MfhdBox{}
+	_ bmfcommon.FieldExporter  = &MfhdBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(mfhdBoxFactory{})
+}
@@ -0,0 +1,58 @@
+package bmftype
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestMfhdBoxFactory_Name(t *testing.T) {
+	name := mfhdBoxFactory{}.Name()
+
+	if name != "mfhd" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestMfhdBoxFactory_New(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	bmfcommon.PushBytes(&data, uint32(99))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "mfhd", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := mfhdBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	mfhd := cb.(*MfhdBox)
+
+	if mfhd.SequenceNumber() != 99 {
+		t.Fatalf("SequenceNumber() not correct.")
+	}
+}
+
+func TestMfhdBox_EncodeData(t *testing.T) {
+	var data []byte
+	bmfcommon.PushBytes(&data, uint32(0))
+	bmfcommon.PushBytes(&data, uint32(99))
+
+	cb := getTestParsedBox(mfhdBoxFactory{}, data)
+	assertEncodeData(t, cb, data)
+}
@@ -0,0 +1,201 @@
+package bmftype
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// Trafs returns the TRAF child boxes in the order that they were stored.
+func (moof *MoofBox) Trafs() (trafs []*TrafBox) {
+	if _, found := moof.LoadedBoxIndex["traf"]; found == false {
+		return []*TrafBox{}
+	}
+
+	boxes, err := moof.GetChildBoxes("traf")
+	log.PanicIf(err)
+
+	trafs = make([]*TrafBox, 0, len(boxes))
+	for _, cb := range boxes {
+		if traf, ok := cb.(*TrafBox); ok == true {
+			trafs = append(trafs, traf)
+		}
+	}
+
+	return trafs
+}
+
+// TrackIds returns the IDs of the tracks that have fragments in this MOOF, in
+// order of first appearance.
+func (moof *MoofBox) TrackIds() (trackIds []uint32, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	trackIds = make([]uint32, 0)
+	seen := make(map[uint32]struct{})
+
+	for _, traf := range moof.Trafs() {
+		tfhd, err := traf.Tfhd()
+		log.PanicIf(err)
+
+		trackId := tfhd.TrackId()
+		if _, found := seen[trackId]; found == true {
+			continue
+		}
+
+		seen[trackId] = struct{}{}
+		trackIds = append(trackIds, trackId)
+	}
+
+	return trackIds, nil
+}
+
+// findTrex returns the TREX box for the given track or nil if there is none.
+func findTrex(fbi bmfcommon.FullBoxIndex, trackId uint32) *TrexBox {
+	for i := 0; ; i++ {
+		ibe := bmfcommon.IndexedBoxEntry{
+			NamePhrase:     "moov.mvex.trex",
+			SequenceNumber: i,
+		}
+
+		cb, found := fbi[ibe]
+		if found == false {
+			return nil
+		}
+
+		if trex, ok := cb.(*TrexBox); ok == true && trex.TrackId() == trackId {
+			return trex
+		}
+	}
+}
+
+// Samples resolves every sample of the given track in this fragment. TRUN
+// values take precedence over the TFHD defaults, which take precedence over
+// the TREX defaults in the MOOV box. Chunk numbers are the (one-based) TRUN
+// numbers within their TRAF.
+func (moof *MoofBox) Samples(trackId uint32) (samples []SampleInfo, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	index := moof.Index()
+
+	samples = make([]SampleInfo, 0)
+
+	// The end of the data of the previous TRAF is the implicit base
+	// data-offset of the next one, regardless of which track it belongs to.
+	previousDataEnd := uint64(moof.Start())
+
+	dts := uint64(0)
+
+	for i, traf := range moof.Trafs() {
+		tfhd, err := traf.Tfhd()
+		log.PanicIf(err)
+
+		var baseDataOffset uint64
+
+		if tfhd.HasFlag(TfhdBaseDataOffsetPresent) == true {
+			baseDataOffset = tfhd.BaseDataOffset()
+		} else if tfhd.HasFlag(TfhdDefaultBaseIsMoof) == true || i == 0 {
+			baseDataOffset = uint64(moof.Start())
+		} else {
+			baseDataOffset = previousDataEnd
+		}
+
+		// Every TRAF is sized from the defaults of its own track since its
+		// data may precede the data of the one that we want.
+		trex := findTrex(index, tfhd.TrackId())
+
+		isWanted := tfhd.TrackId() == trackId
+
+		if isWanted == true {
+			if tfdt := traf.Tfdt(); tfdt != nil {
+				dts = tfdt.BaseMediaDecodeTime()
+			}
+		}
+
+		sampleDescriptionIndex := uint32(1)
+		if tfhd.HasFlag(TfhdSampleDescriptionIndexPresent) == true {
+			sampleDescriptionIndex = tfhd.SampleDescriptionIndex()
+		} else if trex != nil {
+			sampleDescriptionIndex = trex.DefaultSampleDescriptionIndex()
+		}
+
+		offset := baseDataOffset
+
+		for j, trun := range traf.Truns() {
+			if trun.HasFlag(TrunDataOffsetPresent) == true {
+				offset = uint64(int64(baseDataOffset) + int64(trun.DataOffset()))
+			}
+
+			for k, entry := range trun.Entries() {
+				var size uint32
+
+				if trun.HasFlag(TrunSampleSizePresent) == true {
+					size = entry.SampleSize()
+				} else if tfhd.HasFlag(TfhdDefaultSampleSizePresent) == true {
+					size = tfhd.DefaultSampleSize()
+				} else if trex != nil {
+					size = trex.DefaultSampleSize()
+				} else {
+					log.Panicf("trun: no sample size for track (%d)", tfhd.TrackId())
+				}
+
+				if isWanted == true {
+					var duration uint32
+
+					if trun.HasFlag(TrunSampleDurationPresent) == true {
+						duration = entry.SampleDuration()
+					} else if tfhd.HasFlag(TfhdDefaultSampleDurationPresent) == true {
+						duration = tfhd.DefaultSampleDuration()
+					} else if trex != nil {
+						duration = trex.DefaultSampleDuration()
+					}
+
+					var flags SampleFlags
+
+					if trun.HasFlag(TrunSampleFlagsPresent) == true {
+						flags = entry.SampleFlags()
+					} else if k == 0 && trun.HasFlag(TrunFirstSampleFlagsPresent) == true {
+						flags = trun.FirstSampleFlags()
+					} else if tfhd.HasFlag(TfhdDefaultSampleFlagsPresent) == true {
+						flags = tfhd.DefaultSampleFlags()
+					} else if trex != nil {
+						flags = trex.DefaultSampleFlags()
+					}
+
+					cts := int64(dts)
+					if trun.HasFlag(TrunSampleCompositionTimeOffsetPresent) == true {
+						cts += entry.SampleCompositionTimeOffset()
+					}
+
+					si := SampleInfo{
+						index:                  uint32(len(samples)),
+						offset:                 offset,
+						size:                   size,
+						chunk:                  uint32(j + 1),
+						sampleDescriptionIndex: sampleDescriptionIndex,
+						dts:                    dts,
+						cts:                    cts,
+						duration:               duration,
+						isSync:                 flags.IsNonSyncSample() == false,
+					}
+
+					samples = append(samples, si)
+					dts += uint64(duration)
+				}
+
+				offset += uint64(size)
+			}
+		}
+
+		previousDataEnd = offset
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,253 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func getTestMoofBox() *MoofBox {
+	var b []byte
+
+	// moov: a TREX for track (1) only.
+
+	var trexData []byte
+	bmfcommon.PushBytes(&trexData, uint32(0))
+	bmfcommon.PushBytes(&trexData, uint32(1))
+	bmfcommon.PushBytes(&trexData, uint32(3))
+	bmfcommon.PushBytes(&trexData, uint32(100))
+	bmfcommon.PushBytes(&trexData, uint32(0))
+	bmfcommon.PushBytes(&trexData, uint32(0x00010000))
+
+	var mvexData []byte
+	bmfcommon.PushBox(&mvexData, "trex", trexData)
+
+	var moovData []byte
+	bmfcommon.PushBox(&moovData, "mvex", mvexData)
+
+	bmfcommon.PushBox(&b, "moov", moovData)
+
+	// moof
+
+	var moofData []byte
+
+	var mfhdData []byte
+	bmfcommon.PushBytes(&mfhdData, uint32(0))
+	bmfcommon.PushBytes(&mfhdData, uint32(1))
+
+	bmfcommon.PushBox(&moofData, "mfhd", mfhdData)
+
+	// The first TRAF is for track (1), is based on the MOOF, starts at a
+	// decode-time of (1000), and has two samples with explicit sizes. The
+	// first sample is a sync sample and the second takes its flags from the
+	// TREX.
+
+	var traf1Data []byte
+
+	var tfhd1Data []byte
+	bmfcommon.PushBytes(&tfhd1Data, uint32(TfhdDefaultBaseIsMoof))
+	bmfcommon.PushBytes(&tfhd1Data, uint32(1))
+
+	bmfcommon.PushBox(&traf1Data, "tfhd", tfhd1Data)
+
+	var tfdtData []byte
+	bmfcommon.PushBytes(&tfdtData, uint32(0))
+	bmfcommon.PushBytes(&tfdtData, uint32(1000))
+
+	bmfcommon.PushBox(&traf1Data, "tfdt", tfdtData)
+
+	var trun1Data []byte
+	bmfcommon.PushBytes(&trun1Data, uint32(TrunDataOffsetPresent|TrunFirstSampleFlagsPresent|TrunSampleSizePresent))
+	bmfcommon.PushBytes(&trun1Data, uint32(2))
+	bmfcommon.PushBytes(&trun1Data, uint32(200))
+	bmfcommon.PushBytes(&trun1Data, uint32(0))
+	bmfcommon.PushBytes(&trun1Data, uint32(10))
+	bmfcommon.PushBytes(&trun1Data, uint32(20))
+
+	bmfcommon.PushBox(&traf1Data, "trun", trun1Data)
+
+	bmfcommon.PushBox(&moofData, "traf", traf1Data)
+
+	// The second TRAF is for track (2), has no TREX, and continues where the
+	// data of the first TRAF ends.
+
+	var traf2Data []byte
+
+	var tfhd2Data []byte
+	bmfcommon.PushBytes(&tfhd2Data, uint32(TfhdDefaultSampleSizePresent))
+	bmfcommon.PushBytes(&tfhd2Data, uint32(2))
+	bmfcommon.PushBytes(&tfhd2Data, uint32(50))
+
+	bmfcommon.PushBox(&traf2Data, "tfhd", tfhd2Data)
+
+	var trun2Data []byte
+	bmfcommon.PushBytes(&trun2Data, uint32(TrunSampleDurationPresent))
+	bmfcommon.PushBytes(&trun2Data, uint32(2))
+	bmfcommon.PushBytes(&trun2Data, uint32(5))
+	bmfcommon.PushBytes(&trun2Data, uint32(6))
+
+	bmfcommon.PushBox(&traf2Data, "trun", trun2Data)
+
+	bmfcommon.PushBox(&moofData, "traf", traf2Data)
+
+	bmfcommon.PushBox(&b, "moof", moofData)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes("moof")
+	log.PanicIf(err)
+
+	return boxes[0].(*MoofBox)
+}
+
+func TestMoofBox_Trafs(t *testing.T) {
+	moof := getTestMoofBox()
+
+	if len(moof.Trafs()) != 2 {
+		t.Fatalf("Trafs() not correct.")
+	}
+}
+
+func TestMoofBox_TrackIds(t *testing.T) {
+	moof := getTestMoofBox()
+
+	trackIds, err := moof.TrackIds()
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(trackIds, []uint32{1, 2}) != true {
+		t.Fatalf("TrackIds() not correct: %v", trackIds)
+	}
+}
+
+func TestMoofBox_Samples(t *testing.T) {
+	moof := getTestMoofBox()
+
+	// The MOOV box is (48) bytes so the MOOF starts there.
+
+	samples, err := moof.Samples(1)
+	log.PanicIf(err)
+
+	expected := []SampleInfo{
+		{index: 0, offset: 248, size: 10, chunk: 1, sampleDescriptionIndex: 3, dts: 1000, cts: 1000, duration: 100, isSync: true},
+		{index: 1, offset: 258, size: 20, chunk: 1, sampleDescriptionIndex: 3, dts: 1100, cts: 1100, duration: 100, isSync: false},
+	}
+
+	if reflect.DeepEqual(samples, expected) != true {
+		for i, si := range samples {
+			t.Logf("(%d): %s", i, si)
+		}
+
+		t.Fatalf("Samples() not correct for track (1).")
+	}
+
+	samples, err = moof.Samples(2)
+	log.PanicIf(err)
+
+	expected = []SampleInfo{
+		{index: 0, offset: 278, size: 50, chunk: 1, sampleDescriptionIndex: 1, dts: 0, cts: 0, duration: 5, isSync: true},
+		{index: 1, offset: 328, size: 50, chunk: 1, sampleDescriptionIndex: 1, dts: 5, cts: 5, duration: 6, isSync: true},
+	}
+
+	if reflect.DeepEqual(samples, expected) != true {
+		for i, si := range samples {
+			t.Logf("(%d): %s", i, si)
+		}
+
+		t.Fatalf("Samples() not correct for track (2).")
+	}
+}
+
+func TestMoofBox_Samples_UnknownTrack(t *testing.T) {
+	moof := getTestMoofBox()
+
+	samples, err := moof.Samples(99)
+	log.PanicIf(err)
+
+	if len(samples) != 0 {
+		t.Fatalf("Expected no samples.")
+	}
+}
+
+func TestMoofBox_Samples_TrexPerTrack(t *testing.T) {
+	var b []byte
+
+	// moov: TREX boxes for tracks (1) and (2) with different default sizes.
+
+	var mvexData []byte
+
+	for _, trackId := range []uint32{1, 2} {
+		var trexData []byte
+		bmfcommon.PushBytes(&trexData, uint32(0))
+		bmfcommon.PushBytes(&trexData, trackId)
+		bmfcommon.PushBytes(&trexData, uint32(1))
+		bmfcommon.PushBytes(&trexData, uint32(10))
+		bmfcommon.PushBytes(&trexData, trackId*100)
+		bmfcommon.PushBytes(&trexData, uint32(0))
+
+		bmfcommon.PushBox(&mvexData, "trex", trexData)
+	}
+
+	var moovData []byte
+	bmfcommon.PushBox(&moovData, "mvex", mvexData)
+
+	bmfcommon.PushBox(&b, "moov", moovData)
+
+	// moof: one TRAF per track. Neither has a base data-offset, so the second
+	// one continues where the data of the first ends. The sample sizes come
+	// from the TREX boxes.
+
+	var moofData []byte
+
+	for _, trackId := range []uint32{1, 2} {
+		var trafData []byte
+
+		var tfhdData []byte
+		bmfcommon.PushBytes(&tfhdData, uint32(0))
+		bmfcommon.PushBytes(&tfhdData, trackId)
+
+		bmfcommon.PushBox(&trafData, "tfhd", tfhdData)
+
+		var trunData []byte
+		bmfcommon.PushBytes(&trunData, uint32(0))
+		bmfcommon.PushBytes(&trunData, uint32(2))
+
+		bmfcommon.PushBox(&trafData, "trun", trunData)
+
+		bmfcommon.PushBox(&moofData, "traf", trafData)
+	}
+
+	bmfcommon.PushBox(&b, "moof", moofData)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes("moof")
+	log.PanicIf(err)
+
+	moof := boxes[0].(*MoofBox)
+
+	// The MOOV box is (80) bytes so the MOOF starts there. The samples of
+	// track (1) are (100) bytes each and those of track (2) are (200).
+
+	samples, err := moof.Samples(2)
+	log.PanicIf(err)
+
+	if len(samples) != 2 {
+		t.Fatalf("Expected two samples.")
+	} else if samples[0].Offset() != 280 || samples[0].Size() != 200 {
+		t.Fatalf("First sample not correct: %s", samples[0])
+	} else if samples[1].Offset() != 480 || samples[1].Size() != 200 {
+		t.Fatalf("Second sample not correct: %s", samples[1])
+	}
+}
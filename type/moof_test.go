@@ -0,0 +1,55 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestMoofBox_SetLoadedBoxIndex(t *testing.T) {
+	lbi := make(bmfcommon.Boxes, 0)
+
+	moof := new(MoofBox)
+	moof.SetLoadedBoxIndex(lbi)
+
+	if reflect.DeepEqual(moof.LoadedBoxIndex, lbi.Index()) != true {
+		t.Fatalf("SetLoadedBoxIndex() did not set the LBI correctly.")
+	}
+}
+
+func TestMoofBoxFactory_Name(t *testing.T) {
+	name := moofBoxFactory{}.Name()
+
+	if name != "moof" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestMoofBoxFactory_New(t *testing.T) {
+	b := []byte{}
+	bmfcommon.PushBox(&b, "moof", nil)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := moofBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	// Nothing else we can validate.
+	_, ok := cb.(*MoofBox)
+
+	if ok != true {
+		t.Fatalf("Expected an 'moof' box.")
+	}
+}
@@ -0,0 +1,123 @@
+package bmftype
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// TrafBox is the "Track Fragment" box.
+type TrafBox struct {
+	bmfcommon.Box
+
+	// LoadedBoxIndex contains this box's children.
+	bmfcommon.LoadedBoxIndex
+}
+
+// SetLoadedBoxIndex sets the child boxes after a box has been manufactured
+// and the children have been parsed. This allows parent boxes to be
+// registered before the child boxes can look for them.
+func (traf *TrafBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
+	fbi := boxes.Index()
+	traf.LoadedBoxIndex = fbi
+}
+
+// GetChildBoxes returns the child boxes of the given type.
+func (traf *TrafBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return traf.GetChildBoxesOf(traf, name)
+}
+
+// childBox returns the first child with the given name or nil if not present.
+func (traf *TrafBox) childBox(name string) bmfcommon.CommonBox {
+	if _, found := traf.LoadedBoxIndex[name]; found == false {
+		return nil
+	}
+
+	boxes, err := traf.GetChildBoxes(name)
+	log.PanicIf(err)
+
+	if len(boxes) == 0 {
+		return nil
+	}
+
+	return boxes[0]
+}
+
+// Tfhd returns the TFHD child box. Every TRAF box is required to have one.
+func (traf *TrafBox) Tfhd() (tfhd *TfhdBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	tfhd, ok := traf.childBox("tfhd").(*TfhdBox)
+	if ok == false {
+		log.Panicf("traf: no track-fragment header (tfhd)")
+	}
+
+	return tfhd, nil
+}
+
+// Tfdt returns the TFDT child box or nil if not present.
+func (traf *TrafBox) Tfdt() *TfdtBox {
+	tfdt, _ := traf.childBox("tfdt").(*TfdtBox)
+	return tfdt
+}
+
+// Truns returns the TRUN child boxes in the order that they were stored.
+func (traf *TrafBox) Truns() (truns []*TrunBox) {
+	if _, found := traf.LoadedBoxIndex["trun"]; found == false {
+		return []*TrunBox{}
+	}
+
+	boxes, err := traf.GetChildBoxes("trun")
+	log.PanicIf(err)
+
+	truns = make([]*TrunBox, 0, len(boxes))
+	for _, cb := range boxes {
+		if trun, ok := cb.(*TrunBox); ok == true {
+			truns = append(truns, trun)
+		}
+	}
+
+	return truns
+}
+
+// EncodeData returns the payload of the box preceding its children. This box
+// only has children.
+func (traf *TrafBox) EncodeData() (data []byte, err error) {
+	return []byte{}, nil
+}
+
+type trafBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (trafBoxFactory) Name() string {
+	return "traf"
+}
+
+// New returns a new value instance.
+func (trafBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	trafBox := &TrafBox{
+		Box: box,
+	}
+
+	return trafBox, 0, nil
+}
+
+var (
+	_ bmfcommon.BoxFactory = trafBoxFactory{}
+	_ bmfcommon.CommonBox  = &TrafBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(trafBoxFactory{})
+}
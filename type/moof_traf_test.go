@@ -0,0 +1,55 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestTrafBox_SetLoadedBoxIndex(t *testing.T) {
+	lbi := make(bmfcommon.Boxes, 0)
+
+	traf := new(TrafBox)
+	traf.SetLoadedBoxIndex(lbi)
+
+	if reflect.DeepEqual(traf.LoadedBoxIndex, lbi.Index()) != true {
+		t.Fatalf("SetLoadedBoxIndex() did not set the LBI correctly.")
+	}
+}
+
+func TestTrafBoxFactory_Name(t *testing.T) {
+	name := trafBoxFactory{}.Name()
+
+	if name != "traf" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestTrafBoxFactory_New(t *testing.T) {
+	b := []byte{}
+	bmfcommon.PushBox(&b, "traf", nil)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := trafBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	// Nothing else we can validate.
+	_, ok := cb.(*TrafBox)
+
+	if ok != true {
+		t.Fatalf("Expected an 'traf' box.")
+	}
+}
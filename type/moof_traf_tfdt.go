@@ -0,0 +1,136 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// TfdtBox is the "Track Fragment Base Media Decode Time" box.
+type TfdtBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	baseMediaDecodeTime uint64
+}
+
+// BaseMediaDecodeTime returns the decoding time of the first sample in the
+// track fragment, in the media time-scale.
+func (tb *TfdtBox) BaseMediaDecodeTime() uint64 {
+	return tb.baseMediaDecodeTime
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (tb *TfdtBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) BASE-MEDIA-DECODE-TIME=(%d)",
+		tb.Box.InlineString(), tb.Version(), tb.Flags(), tb.baseMediaDecodeTime)
+}
+
+func (b *TfdtBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	// The time is 64-bit in version 1.
+	size := 8
+	if b.Version() == 1 {
+		size = 12
+	}
+
+	if len(data) < size {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), int64(size), int64(len(data))))
+	}
+
+	if b.Version() == 0 {
+		b.baseMediaDecodeTime = uint64(bmfcommon.DefaultEndianness.Uint32(data[4:8]))
+	} else if b.Version() == 1 {
+		b.baseMediaDecodeTime = bmfcommon.DefaultEndianness.Uint64(data[4:12])
+	} else {
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(b, b.Version(), nil))
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (tb *TfdtBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"base_media_decode_time": tb.baseMediaDecodeTime,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (tb *TfdtBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	pushVersionAndFlags(&data, tb.Version(), tb.Flags())
+
+	if tb.Version() == 0 {
+		bmfcommon.PushBytes(&data, uint32(tb.baseMediaDecodeTime))
+	} else if tb.Version() == 1 {
+		bmfcommon.PushBytes(&data, tb.baseMediaDecodeTime)
+	} else {
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(tb, tb.Version(), nil))
+	}
+
+	return data, nil
+}
+
+type tfdtBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (tfdtBoxFactory) Name() string {
+	return "tfdt"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (tfdtBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
+// New returns a new value instance.
+func (bf tfdtBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	tfdtBox := &TfdtBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = tfdtBox.parse()
+	log.PanicIf(err)
+
+	return tfdtBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = tfdtBoxFactory{}
+	_ bmfcommon.CommonBox      = &TfdtBox{}
+	_ bmfcommon.FieldExporter  = &TfdtBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(tfdtBoxFactory{})
+}
@@ -0,0 +1,141 @@
+package bmftype
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestTfdtBoxFactory_Name(t *testing.T) {
+	name := tfdtBoxFactory{}.Name()
+
+	if name != "tfdt" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestTfdtBoxFactory_New_Version0(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	bmfcommon.PushBytes(&data, uint32(5000))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "tfdt", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := tfdtBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	tfdt := cb.(*TfdtBox)
+
+	if tfdt.BaseMediaDecodeTime() != 5000 {
+		t.Fatalf("BaseMediaDecodeTime() not correct.")
+	}
+}
+
+func TestTfdtBoxFactory_New_Version1(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0x01000000))
+
+	bmfcommon.PushBytes(&data, uint64(0x123456789))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "tfdt", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := tfdtBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	tfdt := cb.(*TfdtBox)
+
+	if tfdt.Version() != 1 {
+		t.Fatalf("Version() not correct.")
+	} else if tfdt.BaseMediaDecodeTime() != 0x123456789 {
+		t.Fatalf("BaseMediaDecodeTime() not correct.")
+	}
+}
+
+func TestTfdtBoxFactory_New_UnsupportedVersion(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0x02000000))
+
+	bmfcommon.PushBytes(&data, uint64(0x123456789))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "tfdt", data)
+
+	// Parse.
+
+	// Nothing is registered so that the factory only runs below.
+
+	options := bmfcommon.ParseOptions{
+		Strict:   true,
+		Registry: bmfcommon.NewRegistry(),
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResourceWithOptions(sb, int64(len(b)), options)
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	_, _, err = tfdtBoxFactory{}.New(box)
+
+	var euv *bmfcommon.ErrUnsupportedVersion
+	if errors.As(err, &euv) != true {
+		t.Fatalf("Expected ErrUnsupportedVersion: %v", err)
+	} else if euv.Box != "tfdt" {
+		t.Fatalf("Box not correct: [%s]", euv.Box)
+	} else if euv.Version != 2 {
+		t.Fatalf("Version not correct: (%d)", euv.Version)
+	} else if err.Error() != "box [tfdt] version (2) not supported: [0 1]" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+}
+
+func TestTfdtBox_EncodeData(t *testing.T) {
+	var data0 []byte
+	bmfcommon.PushBytes(&data0, uint32(0))
+	bmfcommon.PushBytes(&data0, uint32(5678))
+
+	cb := getTestParsedBox(tfdtBoxFactory{}, data0)
+	assertEncodeData(t, cb, data0)
+
+	var data1 []byte
+	bmfcommon.PushBytes(&data1, uint32(0x01000000))
+	bmfcommon.PushBytes(&data1, uint64(0x123456789))
+
+	cb = getTestParsedBox(tfdtBoxFactory{}, data1)
+	assertEncodeData(t, cb, data1)
+}
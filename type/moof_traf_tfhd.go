@@ -0,0 +1,227 @@
+package bmftype
+
+import (
+	"bytes"
+	"fmt"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// TfhdBaseDataOffsetPresent indicates that an explicit base data-offset is
+	// stored.
+	TfhdBaseDataOffsetPresent = 0x000001
+
+	// TfhdSampleDescriptionIndexPresent indicates that a sample-description
+	// index is stored that overrides the TREX default.
+	TfhdSampleDescriptionIndexPresent = 0x000002
+
+	// TfhdDefaultSampleDurationPresent indicates that a default sample
+	// duration is stored.
+	TfhdDefaultSampleDurationPresent = 0x000008
+
+	// TfhdDefaultSampleSizePresent indicates that a default sample size is
+	// stored.
+	TfhdDefaultSampleSizePresent = 0x000010
+
+	// TfhdDefaultSampleFlagsPresent indicates that default sample flags are
+	// stored.
+	TfhdDefaultSampleFlagsPresent = 0x000020
+
+	// TfhdDurationIsEmpty indicates that there are no samples for this
+	// time-interval.
+	TfhdDurationIsEmpty = 0x010000
+
+	// TfhdDefaultBaseIsMoof indicates that the base data-offset is the start
+	// of the enclosing MOOF box when no explicit offset is stored.
+	TfhdDefaultBaseIsMoof = 0x020000
+)
+
+// TfhdBox is the "Track Fragment Header" box.
+type TfhdBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	trackId                uint32
+	baseDataOffset         uint64
+	sampleDescriptionIndex uint32
+	defaultSampleDuration  uint32
+	defaultSampleSize      uint32
+	defaultSampleFlags     SampleFlags
+}
+
+// TrackId returns the ID of the track that this fragment belongs to.
+func (tb *TfhdBox) TrackId() uint32 {
+	return tb.trackId
+}
+
+// BaseDataOffset returns the explicit base data-offset. This is only
+// meaningful if TfhdBaseDataOffsetPresent is set.
+func (tb *TfhdBox) BaseDataOffset() uint64 {
+	return tb.baseDataOffset
+}
+
+// SampleDescriptionIndex returns the sample-description index. This is only
+// meaningful if TfhdSampleDescriptionIndexPresent is set.
+func (tb *TfhdBox) SampleDescriptionIndex() uint32 {
+	return tb.sampleDescriptionIndex
+}
+
+// DefaultSampleDuration returns the default sample duration. This is only
+// meaningful if TfhdDefaultSampleDurationPresent is set.
+func (tb *TfhdBox) DefaultSampleDuration() uint32 {
+	return tb.defaultSampleDuration
+}
+
+// DefaultSampleSize returns the default sample size. This is only meaningful
+// if TfhdDefaultSampleSizePresent is set.
+func (tb *TfhdBox) DefaultSampleSize() uint32 {
+	return tb.defaultSampleSize
+}
+
+// DefaultSampleFlags returns the default sample flags. This is only meaningful
+// if TfhdDefaultSampleFlagsPresent is set.
+func (tb *TfhdBox) DefaultSampleFlags() SampleFlags {
+	return tb.defaultSampleFlags
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (tb *TfhdBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%06x) TRACK-ID=(%d)",
+		tb.Box.InlineString(), tb.Version(), tb.Flags(), tb.trackId)
+}
+
+func (b *TfhdBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	s := bytes.NewBuffer(data[4:])
+
+	err = binary.Read(s, bmfcommon.DefaultEndianness, &b.trackId)
+	log.PanicIf(err)
+
+	if b.HasFlag(TfhdBaseDataOffsetPresent) == true {
+		err = binary.Read(s, bmfcommon.DefaultEndianness, &b.baseDataOffset)
+		log.PanicIf(err)
+	}
+
+	if b.HasFlag(TfhdSampleDescriptionIndexPresent) == true {
+		err = binary.Read(s, bmfcommon.DefaultEndianness, &b.sampleDescriptionIndex)
+		log.PanicIf(err)
+	}
+
+	if b.HasFlag(TfhdDefaultSampleDurationPresent) == true {
+		err = binary.Read(s, bmfcommon.DefaultEndianness, &b.defaultSampleDuration)
+		log.PanicIf(err)
+	}
+
+	if b.HasFlag(TfhdDefaultSampleSizePresent) == true {
+		err = binary.Read(s, bmfcommon.DefaultEndianness, &b.defaultSampleSize)
+		log.PanicIf(err)
+	}
+
+	if b.HasFlag(TfhdDefaultSampleFlagsPresent) == true {
+		err = binary.Read(s, bmfcommon.DefaultEndianness, &b.defaultSampleFlags)
+		log.PanicIf(err)
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (tb *TfhdBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"track_id":                 tb.trackId,
+		"base_data_offset":         tb.baseDataOffset,
+		"sample_description_index": tb.sampleDescriptionIndex,
+		"default_sample_duration":  tb.defaultSampleDuration,
+		"default_sample_size":      tb.defaultSampleSize,
+		"default_sample_flags":     tb.defaultSampleFlags.exportFields(),
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (tb *TfhdBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, tb.Version(), tb.Flags())
+	bmfcommon.PushBytes(&data, tb.trackId)
+
+	if tb.HasFlag(TfhdBaseDataOffsetPresent) == true {
+		bmfcommon.PushBytes(&data, tb.baseDataOffset)
+	}
+
+	if tb.HasFlag(TfhdSampleDescriptionIndexPresent) == true {
+		bmfcommon.PushBytes(&data, tb.sampleDescriptionIndex)
+	}
+
+	if tb.HasFlag(TfhdDefaultSampleDurationPresent) == true {
+		bmfcommon.PushBytes(&data, tb.defaultSampleDuration)
+	}
+
+	if tb.HasFlag(TfhdDefaultSampleSizePresent) == true {
+		bmfcommon.PushBytes(&data, tb.defaultSampleSize)
+	}
+
+	if tb.HasFlag(TfhdDefaultSampleFlagsPresent) == true {
+		bmfcommon.PushBytes(&data, uint32(tb.defaultSampleFlags))
+	}
+
+	return data, nil
+}
+
+type tfhdBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (tfhdBoxFactory) Name() string {
+	return "tfhd"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (tfhdBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf tfhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	tfhdBox := &TfhdBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = tfhdBox.parse()
+	log.PanicIf(err)
+
+	return tfhdBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = tfhdBoxFactory{}
+	_ bmfcommon.CommonBox      = &TfhdBox{}
+	_ bmfcommon.FieldExporter  = &TfhdBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(tfhdBoxFactory{})
+}
@@ -0,0 +1,123 @@
+package bmftype
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestTfhdBoxFactory_Name(t *testing.T) {
+	name := tfhdBoxFactory{}.Name()
+
+	if name != "tfhd" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestTfhdBoxFactory_New_AllFields(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	flags := uint32(TfhdBaseDataOffsetPresent | TfhdSampleDescriptionIndexPresent | TfhdDefaultSampleDurationPresent | TfhdDefaultSampleSizePresent | TfhdDefaultSampleFlagsPresent)
+	bmfcommon.PushBytes(&data, flags)
+
+	bmfcommon.PushBytes(&data, uint32(7))
+	bmfcommon.PushBytes(&data, uint64(0x100000000))
+	bmfcommon.PushBytes(&data, uint32(2))
+	bmfcommon.PushBytes(&data, uint32(1000))
+	bmfcommon.PushBytes(&data, uint32(500))
+	bmfcommon.PushBytes(&data, uint32(0x00010000))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "tfhd", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := tfhdBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	tfhd := cb.(*TfhdBox)
+
+	if tfhd.Flags() != flags {
+		t.Fatalf("Flags() not correct.")
+	} else if tfhd.TrackId() != 7 {
+		t.Fatalf("TrackId() not correct.")
+	} else if tfhd.BaseDataOffset() != 0x100000000 {
+		t.Fatalf("BaseDataOffset() not correct.")
+	} else if tfhd.SampleDescriptionIndex() != 2 {
+		t.Fatalf("SampleDescriptionIndex() not correct.")
+	} else if tfhd.DefaultSampleDuration() != 1000 {
+		t.Fatalf("DefaultSampleDuration() not correct.")
+	} else if tfhd.DefaultSampleSize() != 500 {
+		t.Fatalf("DefaultSampleSize() not correct.")
+	} else if tfhd.DefaultSampleFlags().IsNonSyncSample() != true {
+		t.Fatalf("DefaultSampleFlags() not correct.")
+	}
+}
+
+func TestTfhdBoxFactory_New_SomeFields(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	flags := uint32(TfhdDefaultSampleSizePresent | TfhdDefaultBaseIsMoof)
+	bmfcommon.PushBytes(&data, flags)
+
+	bmfcommon.PushBytes(&data, uint32(7))
+	bmfcommon.PushBytes(&data, uint32(500))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "tfhd", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := tfhdBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	tfhd := cb.(*TfhdBox)
+
+	if tfhd.HasFlag(TfhdDefaultBaseIsMoof) != true {
+		t.Fatalf("HasFlag() not correct for default-base-is-moof.")
+	} else if tfhd.HasFlag(TfhdBaseDataOffsetPresent) != false {
+		t.Fatalf("HasFlag() not correct for base-data-offset.")
+	} else if tfhd.DefaultSampleSize() != 500 {
+		t.Fatalf("DefaultSampleSize() not correct.")
+	} else if tfhd.DefaultSampleDuration() != 0 {
+		t.Fatalf("DefaultSampleDuration() not correct.")
+	}
+}
+
+func TestTfhdBox_EncodeData(t *testing.T) {
+	var data []byte
+
+	flags := uint32(TfhdBaseDataOffsetPresent | TfhdSampleDescriptionIndexPresent | TfhdDefaultSampleDurationPresent | TfhdDefaultSampleSizePresent | TfhdDefaultSampleFlagsPresent | TfhdDefaultBaseIsMoof)
+	bmfcommon.PushBytes(&data, flags)
+
+	bmfcommon.PushBytes(&data, uint32(1))
+	bmfcommon.PushBytes(&data, uint64(0x1000))
+	bmfcommon.PushBytes(&data, uint32(2))
+	bmfcommon.PushBytes(&data, uint32(3))
+	bmfcommon.PushBytes(&data, uint32(4))
+	bmfcommon.PushBytes(&data, uint32(0x00010000))
+
+	cb := getTestParsedBox(tfhdBoxFactory{}, data)
+	assertEncodeData(t, cb, data)
+}
@@ -0,0 +1,276 @@
+package bmftype
+
+import (
+	"bytes"
+	"fmt"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// TrunDataOffsetPresent indicates that a data-offset is stored.
+	TrunDataOffsetPresent = 0x000001
+
+	// TrunFirstSampleFlagsPresent indicates that the flags of the first
+	// sample are stored separately.
+	TrunFirstSampleFlagsPresent = 0x000004
+
+	// TrunSampleDurationPresent indicates that each sample has a duration.
+	TrunSampleDurationPresent = 0x000100
+
+	// TrunSampleSizePresent indicates that each sample has a size.
+	TrunSampleSizePresent = 0x000200
+
+	// TrunSampleFlagsPresent indicates that each sample has flags.
+	TrunSampleFlagsPresent = 0x000400
+
+	// TrunSampleCompositionTimeOffsetPresent indicates that each sample has a
+	// composition-time offset.
+	TrunSampleCompositionTimeOffsetPresent = 0x000800
+)
+
+// TrunEntry is one sample in a track run. Only the fields indicated by the
+// TRUN flags are meaningful.
+type TrunEntry struct {
+	sampleDuration              uint32
+	sampleSize                  uint32
+	sampleFlags                 SampleFlags
+	sampleCompositionTimeOffset int64
+}
+
+// SampleDuration returns the sample duration.
+func (te TrunEntry) SampleDuration() uint32 {
+	return te.sampleDuration
+}
+
+// SampleSize returns the sample size.
+func (te TrunEntry) SampleSize() uint32 {
+	return te.sampleSize
+}
+
+// SampleFlags returns the sample flags.
+func (te TrunEntry) SampleFlags() SampleFlags {
+	return te.sampleFlags
+}
+
+// SampleCompositionTimeOffset returns the composition-time offset. This is
+// unsigned in version (0) and signed in version (1).
+func (te TrunEntry) SampleCompositionTimeOffset() int64 {
+	return te.sampleCompositionTimeOffset
+}
+
+// TrunBox is the "Track Fragment Run" box.
+type TrunBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	dataOffset       int32
+	firstSampleFlags SampleFlags
+	entries          []TrunEntry
+}
+
+// DataOffset returns the offset of the run's data relative to the base
+// data-offset. This is only meaningful if TrunDataOffsetPresent is set.
+func (tb *TrunBox) DataOffset() int32 {
+	return tb.dataOffset
+}
+
+// FirstSampleFlags returns the flags of the first sample. This is only
+// meaningful if TrunFirstSampleFlagsPresent is set.
+func (tb *TrunBox) FirstSampleFlags() SampleFlags {
+	return tb.firstSampleFlags
+}
+
+// Entries returns the per-sample entries.
+func (tb *TrunBox) Entries() []TrunEntry {
+	return tb.entries
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (tb *TrunBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%06x) SAMPLE-COUNT=(%d) DATA-OFFSET=(%d)",
+		tb.Box.InlineString(), tb.Version(), tb.Flags(), len(tb.entries), tb.dataOffset)
+}
+
+func (b *TrunBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	s := bytes.NewBuffer(data[4:])
+
+	var sampleCount uint32
+
+	err = binary.Read(s, bmfcommon.DefaultEndianness, &sampleCount)
+	log.PanicIf(err)
+
+	if b.HasFlag(TrunDataOffsetPresent) == true {
+		err = binary.Read(s, bmfcommon.DefaultEndianness, &b.dataOffset)
+		log.PanicIf(err)
+	}
+
+	if b.HasFlag(TrunFirstSampleFlagsPresent) == true {
+		err = binary.Read(s, bmfcommon.DefaultEndianness, &b.firstSampleFlags)
+		log.PanicIf(err)
+	}
+
+	entrySize := 0
+	for _, flag := range []uint32{TrunSampleDurationPresent, TrunSampleSizePresent, TrunSampleFlagsPresent, TrunSampleCompositionTimeOffsetPresent} {
+		if b.HasFlag(flag) == true {
+			entrySize += 4
+		}
+	}
+
+	if uint64(s.Len()) < uint64(sampleCount)*uint64(entrySize) {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize()+int64(len(data)-s.Len()), int64(sampleCount)*int64(entrySize), int64(s.Len())))
+	}
+
+	err = b.CheckEntryCount("entries", int64(sampleCount), int64(len(data)-s.Len()), 0)
+	log.PanicIf(err)
+
+	b.entries = make([]TrunEntry, sampleCount)
+
+	for i := range b.entries {
+		entry := &b.entries[i]
+
+		if b.HasFlag(TrunSampleDurationPresent) == true {
+			err = binary.Read(s, bmfcommon.DefaultEndianness, &entry.sampleDuration)
+			log.PanicIf(err)
+		}
+
+		if b.HasFlag(TrunSampleSizePresent) == true {
+			err = binary.Read(s, bmfcommon.DefaultEndianness, &entry.sampleSize)
+			log.PanicIf(err)
+		}
+
+		if b.HasFlag(TrunSampleFlagsPresent) == true {
+			err = binary.Read(s, bmfcommon.DefaultEndianness, &entry.sampleFlags)
+			log.PanicIf(err)
+		}
+
+		if b.HasFlag(TrunSampleCompositionTimeOffsetPresent) == true {
+			var rawOffset uint32
+
+			err = binary.Read(s, bmfcommon.DefaultEndianness, &rawOffset)
+			log.PanicIf(err)
+
+			if b.Version() == 0 {
+				entry.sampleCompositionTimeOffset = int64(rawOffset)
+			} else {
+				entry.sampleCompositionTimeOffset = int64(int32(rawOffset))
+			}
+		}
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (tb *TrunBox) ExportFields() (fields map[string]interface{}, err error) {
+	entries := make([]map[string]interface{}, len(tb.entries))
+	for i, te := range tb.entries {
+		entries[i] = map[string]interface{}{
+			"sample_duration":                te.sampleDuration,
+			"sample_size":                    te.sampleSize,
+			"sample_flags":                   te.sampleFlags.exportFields(),
+			"sample_composition_time_offset": te.sampleCompositionTimeOffset,
+		}
+	}
+
+	fields = map[string]interface{}{
+		"data_offset":        tb.dataOffset,
+		"first_sample_flags": tb.firstSampleFlags.exportFields(),
+		"entries":            entries,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (tb *TrunBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, tb.Version(), tb.Flags())
+	bmfcommon.PushBytes(&data, uint32(len(tb.entries)))
+
+	if tb.HasFlag(TrunDataOffsetPresent) == true {
+		bmfcommon.PushBytes(&data, uint32(tb.dataOffset))
+	}
+
+	if tb.HasFlag(TrunFirstSampleFlagsPresent) == true {
+		bmfcommon.PushBytes(&data, uint32(tb.firstSampleFlags))
+	}
+
+	for _, entry := range tb.entries {
+		if tb.HasFlag(TrunSampleDurationPresent) == true {
+			bmfcommon.PushBytes(&data, entry.sampleDuration)
+		}
+
+		if tb.HasFlag(TrunSampleSizePresent) == true {
+			bmfcommon.PushBytes(&data, entry.sampleSize)
+		}
+
+		if tb.HasFlag(TrunSampleFlagsPresent) == true {
+			bmfcommon.PushBytes(&data, uint32(entry.sampleFlags))
+		}
+
+		if tb.HasFlag(TrunSampleCompositionTimeOffsetPresent) == true {
+			bmfcommon.PushBytes(&data, uint32(entry.sampleCompositionTimeOffset))
+		}
+	}
+
+	return data, nil
+}
+
+type trunBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (trunBoxFactory) Name() string {
+	return "trun"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (trunBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
+// New returns a new value instance.
+func (bf trunBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	trunBox := &TrunBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = trunBox.parse()
+	log.PanicIf(err)
+
+	return trunBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = trunBoxFactory{}
+	_ bmfcommon.CommonBox      = &TrunBox{}
+	_ bmfcommon.FieldExporter  = &TrunBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(trunBoxFactory{})
+}
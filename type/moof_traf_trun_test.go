@@ -0,0 +1,211 @@
+package bmftype
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestTrunBox_ExportFields(t *testing.T) {
+	tb := TrunBox{
+		dataOffset:       -8,
+		firstSampleFlags: 0x02000000,
+		entries: []TrunEntry{
+			{
+				sampleDuration:              11,
+				sampleSize:                  22,
+				sampleFlags:                 0x01010005,
+				sampleCompositionTimeOffset: -33,
+			},
+		},
+	}
+
+	fields, err := tb.ExportFields()
+	log.PanicIf(err)
+
+	if fields["data_offset"] != int32(-8) {
+		t.Fatalf("Data offset not correct.")
+	}
+
+	firstSampleFlags := fields["first_sample_flags"].(map[string]interface{})
+
+	if firstSampleFlags["sample_depends_on"] != uint8(2) || firstSampleFlags["is_non_sync_sample"] != false {
+		t.Fatalf("First sample flags not correct: %v", firstSampleFlags)
+	}
+
+	entries := fields["entries"].([]map[string]interface{})
+	if len(entries) != 1 {
+		t.Fatalf("Entry count not correct.")
+	}
+
+	entry := entries[0]
+	sampleFlags := entry["sample_flags"].(map[string]interface{})
+
+	if entry["sample_duration"] != uint32(11) || entry["sample_size"] != uint32(22) || entry["sample_composition_time_offset"] != int64(-33) {
+		t.Fatalf("Entry not correct: %v", entry)
+	} else if sampleFlags["sample_depends_on"] != uint8(1) || sampleFlags["is_non_sync_sample"] != true || sampleFlags["degradation_priority"] != uint16(5) {
+		t.Fatalf("Sample flags not correct: %v", sampleFlags)
+	}
+}
+
+func TestTrunBoxFactory_Name(t *testing.T) {
+	name := trunBoxFactory{}.Name()
+
+	if name != "trun" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestTrunBoxFactory_New_Version0(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(TrunDataOffsetPresent|TrunFirstSampleFlagsPresent|TrunSampleSizePresent|TrunSampleCompositionTimeOffsetPresent))
+
+	bmfcommon.PushBytes(&data, uint32(2))
+	bmfcommon.PushBytes(&data, uint32(0xfffffff8))
+	bmfcommon.PushBytes(&data, uint32(0x02000000))
+
+	bmfcommon.PushBytes(&data, uint32(100))
+	bmfcommon.PushBytes(&data, uint32(0xffffffff))
+
+	bmfcommon.PushBytes(&data, uint32(200))
+	bmfcommon.PushBytes(&data, uint32(30))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "trun", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := trunBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	trun := cb.(*TrunBox)
+
+	if trun.DataOffset() != -8 {
+		t.Fatalf("DataOffset() not correct.")
+	} else if trun.FirstSampleFlags() != 0x02000000 {
+		t.Fatalf("FirstSampleFlags() not correct.")
+	}
+
+	entries := trun.Entries()
+
+	if len(entries) != 2 {
+		t.Fatalf("Entries() not correct.")
+	} else if entries[0].SampleSize() != 100 || entries[1].SampleSize() != 200 {
+		t.Fatalf("SampleSize() not correct.")
+	} else if entries[0].SampleCompositionTimeOffset() != 0xffffffff {
+		t.Fatalf("SampleCompositionTimeOffset() should be unsigned for version 0.")
+	} else if entries[1].SampleCompositionTimeOffset() != 30 {
+		t.Fatalf("SampleCompositionTimeOffset() not correct.")
+	} else if entries[0].SampleDuration() != 0 {
+		t.Fatalf("SampleDuration() not correct.")
+	}
+}
+
+func TestTrunBoxFactory_New_Version1(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0x01000000|TrunSampleDurationPresent|TrunSampleFlagsPresent|TrunSampleCompositionTimeOffsetPresent))
+
+	bmfcommon.PushBytes(&data, uint32(1))
+
+	bmfcommon.PushBytes(&data, uint32(1000))
+	bmfcommon.PushBytes(&data, uint32(0x00010000))
+	bmfcommon.PushBytes(&data, uint32(0xffffffec))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "trun", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := trunBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	trun := cb.(*TrunBox)
+	entries := trun.Entries()
+
+	if trun.Version() != 1 {
+		t.Fatalf("Version() not correct.")
+	} else if entries[0].SampleDuration() != 1000 {
+		t.Fatalf("SampleDuration() not correct.")
+	} else if entries[0].SampleFlags().IsNonSyncSample() != true {
+		t.Fatalf("SampleFlags() not correct.")
+	} else if entries[0].SampleCompositionTimeOffset() != -20 {
+		t.Fatalf("SampleCompositionTimeOffset() should be signed for version 1.")
+	}
+}
+
+func TestTrunBoxFactory_New_Truncated(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(TrunSampleSizePresent))
+
+	bmfcommon.PushBytes(&data, uint32(3))
+	bmfcommon.PushBytes(&data, uint32(100))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "trun", data)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, 0)
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	_, _, err = trunBoxFactory{}.New(box)
+	if err == nil {
+		t.Fatalf("Expected error for truncated table.")
+	}
+}
+
+func TestTrunBox_EncodeData(t *testing.T) {
+	var data []byte
+
+	flags := uint32(TrunDataOffsetPresent | TrunFirstSampleFlagsPresent | TrunSampleDurationPresent | TrunSampleSizePresent | TrunSampleFlagsPresent | TrunSampleCompositionTimeOffsetPresent)
+	bmfcommon.PushBytes(&data, uint32(0x01000000)|flags)
+
+	// sample-count
+	bmfcommon.PushBytes(&data, uint32(2))
+
+	// data-offset (-8)
+	bmfcommon.PushBytes(&data, uint32(0xfffffff8))
+
+	// first-sample-flags
+	bmfcommon.PushBytes(&data, uint32(0x02000000))
+
+	for i := uint32(0); i < 2; i++ {
+		bmfcommon.PushBytes(&data, uint32(100+i))
+		bmfcommon.PushBytes(&data, uint32(200+i))
+		bmfcommon.PushBytes(&data, uint32(0x00010000))
+
+		// composition-time offset (-1)
+		bmfcommon.PushBytes(&data, uint32(0xffffffff))
+	}
+
+	cb := getTestParsedBox(trunBoxFactory{}, data)
+	assertEncodeData(t, cb, data)
+}
@@ -28,21 +28,34 @@ func (mv MoovBox) IsFragmented() bool {
 // SetLoadedBoxIndex sets the child boxes after a box has been manufactured
 // and the children have been parsed. This allows parent boxes to be
 // registered before the child boxes can look for them.
+//
+// The presence of the MVEX box can only be determined here since the children
+// are not yet loaded when the factory runs.
 func (moov *MoovBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
 	fbi := boxes.Index()
 	moov.LoadedBoxIndex = fbi
+
+	_, moov.isFragmented = fbi["mvex"]
 }
 
-func (b *MoovBox) parse() (err error) {
-	defer func() {
-		if errRaw := recover(); errRaw != nil {
-			err = log.Wrap(errRaw.(error))
-		}
-	}()
+// GetChildBoxes returns the child boxes of the given type.
+func (moov *MoovBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return moov.GetChildBoxesOf(moov, name)
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (moov *MoovBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"is_fragmented": moov.isFragmented,
+	}
 
-	_, b.isFragmented = b.LoadedBoxIndex["mvex"]
+	return fields, nil
+}
 
-	return nil
+// EncodeData returns the payload of the box preceding its children. This box
+// only has children.
+func (moov *MoovBox) EncodeData() (data []byte, err error) {
+	return []byte{}, nil
 }
 
 type moovBoxFactory struct {
@@ -65,15 +78,13 @@ func (moovBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 		Box: box,
 	}
 
-	err = moovBox.parse()
-	log.PanicIf(err)
-
 	return moovBox, 0, nil
 }
 
 var (
-	_ bmfcommon.BoxFactory = moovBoxFactory{}
-	_ bmfcommon.CommonBox  = &MoovBox{}
+	_ bmfcommon.BoxFactory    = moovBoxFactory{}
+	_ bmfcommon.CommonBox     = &MoovBox{}
+	_ bmfcommon.FieldExporter = &MoovBox{}
 )
 
 func init() {
@@ -0,0 +1,147 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// IodsBox is the "Object Descriptor" box from ISO 14496-14. It has the initial
+// object-descriptor of the presentation.
+type IodsBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	descriptor bmfcommon.Descriptor
+}
+
+// Descriptor returns the descriptor. This is normally an
+// InitialObjectDescriptor.
+func (ib *IodsBox) Descriptor() bmfcommon.Descriptor {
+	return ib.descriptor
+}
+
+// InitialObjectDescriptor returns the initial object-descriptor or nil if the
+// box has some other descriptor.
+func (ib *IodsBox) InitialObjectDescriptor() *bmfcommon.InitialObjectDescriptor {
+	iod, _ := ib.descriptor.(*bmfcommon.InitialObjectDescriptor)
+	return iod
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (ib *IodsBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s DESCRIPTOR=[%v]",
+		ib.Box.InlineString(), ib.descriptor)
+}
+
+func (b *IodsBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	// The version and flags were already checked.
+	data = data[4:]
+
+	b.descriptor, _, err = bmfcommon.ReadDescriptor(data, b.Start()+b.HeaderSize()+4)
+	log.PanicIf(err)
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (ib *IodsBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"tag": ib.descriptor.DescriptorTag(),
+	}
+
+	if iod := ib.InitialObjectDescriptor(); iod != nil {
+		fields["object_descriptor_id"] = iod.ObjectDescriptorId
+		fields["od_profile_level"] = iod.OdProfileLevelIndication
+		fields["scene_profile_level"] = iod.SceneProfileLevelIndication
+		fields["audio_profile_level"] = iod.AudioProfileLevelIndication
+		fields["visual_profile_level"] = iod.VisualProfileLevelIndication
+		fields["graphics_profile_level"] = iod.GraphicsProfileLevelIndication
+
+		trackIds := make([]uint32, 0)
+		for _, d := range iod.Descriptors {
+			if eiid, ok := d.(*bmfcommon.EsIdIncDescriptor); ok == true {
+				trackIds = append(trackIds, eiid.TrackId)
+			}
+		}
+
+		fields["track_ids"] = trackIds
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (ib *IodsBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	bmfcommon.PushBytes(&data, uint32(ib.Version())<<24|ib.Flags()&0x00ffffff)
+
+	encoded, err := bmfcommon.EncodeDescriptor(ib.descriptor)
+	log.PanicIf(err)
+
+	bmfcommon.PushBytes(&data, encoded)
+
+	return data, nil
+}
+
+type iodsBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (iodsBoxFactory) Name() string {
+	return "iods"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (iodsBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf iodsBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	iodsBox := &IodsBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = iodsBox.parse()
+	log.PanicIf(err)
+
+	return iodsBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = iodsBoxFactory{}
+	_ bmfcommon.CommonBox      = &IodsBox{}
+	_ bmfcommon.FieldExporter  = &IodsBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(iodsBoxFactory{})
+}
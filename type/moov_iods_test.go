@@ -0,0 +1,62 @@
+package bmftype
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+var (
+	// testIodsData is an "iods" payload as written by common muxers: an
+	// MP4_IOD with padded sizes and no profile requirements except AAC and
+	// AVC, and ES_ID_Inc descriptors for tracks one and two.
+	testIodsData, _ = hex.DecodeString("00000000" + "10808080" + "19" + "004f" + "ffff29" + "15ff" + "0e80808004" + "00000001" + "0e80808004" + "00000002")
+)
+
+func TestIodsBoxFactory_Name(t *testing.T) {
+	if (iodsBoxFactory{}).Name() != "iods" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestIodsBoxFactory_New(t *testing.T) {
+	cb := getTestParsedBox(iodsBoxFactory{}, testIodsData)
+	iods := cb.(*IodsBox)
+
+	iod := iods.InitialObjectDescriptor()
+	if iod == nil {
+		t.Fatalf("InitialObjectDescriptor() not correct.")
+	} else if iod.DescriptorTag() != bmfcommon.Mp4IodTag {
+		t.Fatalf("Tag not correct.")
+	} else if iod.ObjectDescriptorId != 1 {
+		t.Fatalf("ObjectDescriptorId not correct: (%d)", iod.ObjectDescriptorId)
+	} else if iod.UrlFlag != false {
+		t.Fatalf("UrlFlag not correct.")
+	} else if iod.AudioProfileLevelIndication != 0x29 {
+		t.Fatalf("AudioProfileLevelIndication not correct.")
+	} else if iod.VisualProfileLevelIndication != 0x15 {
+		t.Fatalf("VisualProfileLevelIndication not correct.")
+	} else if len(iod.Descriptors) != 2 {
+		t.Fatalf("Descriptors not correct.")
+	}
+
+	fields, err := iods.ExportFields()
+	if err != nil {
+		t.Fatalf("ExportFields() failed: [%s]", err.Error())
+	}
+
+	trackIds := fields["track_ids"].([]uint32)
+	if len(trackIds) != 2 || trackIds[0] != 1 || trackIds[1] != 2 {
+		t.Fatalf("Track IDs not correct: %v", trackIds)
+	}
+
+	assertEncodeData(t, cb, testIodsData)
+}
+
+func TestIodsBoxFactory_New_Truncated(t *testing.T) {
+	_, err := getTestBoxFactoryNew(iodsBoxFactory{}, testIodsData[:20])
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+}
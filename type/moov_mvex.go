@@ -0,0 +1,68 @@
+package bmftype
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// MvexBox is the "Movie Extends" box.
+//
+// Its presence warns readers that there might be movie fragments in this file.
+type MvexBox struct {
+	bmfcommon.Box
+
+	// LoadedBoxIndex contains this box's children.
+	bmfcommon.LoadedBoxIndex
+}
+
+// SetLoadedBoxIndex sets the child boxes after a box has been manufactured
+// and the children have been parsed. This allows parent boxes to be
+// registered before the child boxes can look for them.
+func (mvex *MvexBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
+	fbi := boxes.Index()
+	mvex.LoadedBoxIndex = fbi
+}
+
+// GetChildBoxes returns the child boxes of the given type.
+func (mvex *MvexBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return mvex.GetChildBoxesOf(mvex, name)
+}
+
+// EncodeData returns the payload of the box preceding its children. This box
+// only has children.
+func (mvex *MvexBox) EncodeData() (data []byte, err error) {
+	return []byte{}, nil
+}
+
+type mvexBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (mvexBoxFactory) Name() string {
+	return "mvex"
+}
+
+// New returns a new value instance.
+func (mvexBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	mvexBox := &MvexBox{
+		Box: box,
+	}
+
+	return mvexBox, 0, nil
+}
+
+var (
+	_ bmfcommon.BoxFactory = mvexBoxFactory{}
+	_ bmfcommon.CommonBox  = &MvexBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(mvexBoxFactory{})
+}
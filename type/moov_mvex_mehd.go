@@ -0,0 +1,137 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// MehdBox is the "Movie Extends Header" box. It provides the overall duration
+// of a fragmented movie, including fragments.
+type MehdBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	fragmentDuration uint64
+}
+
+// FragmentDuration returns the duration of the longest track, including
+// fragments, in the movie time-scale.
+func (mb *MehdBox) FragmentDuration() uint64 {
+	return mb.fragmentDuration
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (mb *MehdBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) FRAGMENT-DURATION=(%d)",
+		mb.Box.InlineString(), mb.Version(), mb.Flags(), mb.fragmentDuration)
+}
+
+func (b *MehdBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	// The duration is 64-bit in version 1.
+	size := 8
+	if b.Version() == 1 {
+		size = 12
+	}
+
+	if len(data) < size {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), int64(size), int64(len(data))))
+	}
+
+	if b.Version() == 0 {
+		b.fragmentDuration = uint64(bmfcommon.DefaultEndianness.Uint32(data[4:8]))
+	} else if b.Version() == 1 {
+		b.fragmentDuration = bmfcommon.DefaultEndianness.Uint64(data[4:12])
+	} else {
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(b, b.Version(), nil))
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (mb *MehdBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"fragment_duration": mb.fragmentDuration,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (mb *MehdBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	pushVersionAndFlags(&data, mb.Version(), mb.Flags())
+
+	if mb.Version() == 0 {
+		bmfcommon.PushBytes(&data, uint32(mb.fragmentDuration))
+	} else if mb.Version() == 1 {
+		bmfcommon.PushBytes(&data, mb.fragmentDuration)
+	} else {
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(mb, mb.Version(), nil))
+	}
+
+	return data, nil
+}
+
+type mehdBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (mehdBoxFactory) Name() string {
+	return "mehd"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (mehdBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
+// New returns a new value instance.
+func (bf mehdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	mehdBox := &MehdBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = mehdBox.parse()
+	log.PanicIf(err)
+
+	return mehdBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = mehdBoxFactory{}
+	_ bmfcommon.CommonBox      = &MehdBox{}
+	_ bmfcommon.FieldExporter  = &MehdBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(mehdBoxFactory{})
+}
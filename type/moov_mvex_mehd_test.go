@@ -0,0 +1,98 @@
+package bmftype
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestMehdBoxFactory_Name(t *testing.T) {
+	name := mehdBoxFactory{}.Name()
+
+	if name != "mehd" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestMehdBoxFactory_New_Version0(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	bmfcommon.PushBytes(&data, uint32(1234))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "mehd", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := mehdBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	mehd := cb.(*MehdBox)
+
+	if mehd.FragmentDuration() != 1234 {
+		t.Fatalf("FragmentDuration() not correct.")
+	}
+}
+
+func TestMehdBoxFactory_New_Version1(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0x01000000))
+
+	bmfcommon.PushBytes(&data, uint64(0x100000000))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "mehd", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := mehdBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	mehd := cb.(*MehdBox)
+
+	if mehd.Version() != 1 {
+		t.Fatalf("Version() not correct.")
+	} else if mehd.FragmentDuration() != 0x100000000 {
+		t.Fatalf("FragmentDuration() not correct.")
+	}
+}
+
+func TestMehdBox_EncodeData(t *testing.T) {
+	var data0 []byte
+	bmfcommon.PushBytes(&data0, uint32(0))
+	bmfcommon.PushBytes(&data0, uint32(1234))
+
+	cb := getTestParsedBox(mehdBoxFactory{}, data0)
+	assertEncodeData(t, cb, data0)
+
+	var data1 []byte
+	bmfcommon.PushBytes(&data1, uint32(0x01000000))
+	bmfcommon.PushBytes(&data1, uint64(0x123456789))
+
+	cb = getTestParsedBox(mehdBoxFactory{}, data1)
+	assertEncodeData(t, cb, data1)
+}
@@ -0,0 +1,55 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestMvexBox_SetLoadedBoxIndex(t *testing.T) {
+	lbi := make(bmfcommon.Boxes, 0)
+
+	mvex := new(MvexBox)
+	mvex.SetLoadedBoxIndex(lbi)
+
+	if reflect.DeepEqual(mvex.LoadedBoxIndex, lbi.Index()) != true {
+		t.Fatalf("SetLoadedBoxIndex() did not set the LBI correctly.")
+	}
+}
+
+func TestMvexBoxFactory_Name(t *testing.T) {
+	name := mvexBoxFactory{}.Name()
+
+	if name != "mvex" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestMvexBoxFactory_New(t *testing.T) {
+	b := []byte{}
+	bmfcommon.PushBox(&b, "mvex", nil)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := mvexBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	// Nothing else we can validate.
+	_, ok := cb.(*MvexBox)
+
+	if ok != true {
+		t.Fatalf("Expected an 'mvex' box.")
+	}
+}
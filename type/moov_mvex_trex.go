@@ -0,0 +1,148 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// TrexBox is the "Track Extends" box. It sets up the defaults used by the
+// movie fragments of one track.
+type TrexBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	trackId                       uint32
+	defaultSampleDescriptionIndex uint32
+	defaultSampleDuration         uint32
+	defaultSampleSize             uint32
+	defaultSampleFlags            SampleFlags
+}
+
+// TrackId returns the ID of the track that these defaults apply to.
+func (tb *TrexBox) TrackId() uint32 {
+	return tb.trackId
+}
+
+// DefaultSampleDescriptionIndex returns the default sample-description index.
+func (tb *TrexBox) DefaultSampleDescriptionIndex() uint32 {
+	return tb.defaultSampleDescriptionIndex
+}
+
+// DefaultSampleDuration returns the default sample duration.
+func (tb *TrexBox) DefaultSampleDuration() uint32 {
+	return tb.defaultSampleDuration
+}
+
+// DefaultSampleSize returns the default sample size.
+func (tb *TrexBox) DefaultSampleSize() uint32 {
+	return tb.defaultSampleSize
+}
+
+// DefaultSampleFlags returns the default sample flags.
+func (tb *TrexBox) DefaultSampleFlags() SampleFlags {
+	return tb.defaultSampleFlags
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (tb *TrexBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) TRACK-ID=(%d) DEFAULT-DESC-INDEX=(%d) DEFAULT-DURATION=(%d) DEFAULT-SIZE=(%d) DEFAULT-FLAGS=(0x%08x)",
+		tb.Box.InlineString(), tb.Version(), tb.Flags(), tb.trackId, tb.defaultSampleDescriptionIndex,
+		tb.defaultSampleDuration, tb.defaultSampleSize, uint32(tb.defaultSampleFlags))
+}
+
+func (b *TrexBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < 24 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 24, int64(len(data))))
+	}
+
+	b.trackId = bmfcommon.DefaultEndianness.Uint32(data[4:8])
+	b.defaultSampleDescriptionIndex = bmfcommon.DefaultEndianness.Uint32(data[8:12])
+	b.defaultSampleDuration = bmfcommon.DefaultEndianness.Uint32(data[12:16])
+	b.defaultSampleSize = bmfcommon.DefaultEndianness.Uint32(data[16:20])
+	b.defaultSampleFlags = SampleFlags(bmfcommon.DefaultEndianness.Uint32(data[20:24]))
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (tb *TrexBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"track_id":                         tb.trackId,
+		"default_sample_description_index": tb.defaultSampleDescriptionIndex,
+		"default_sample_duration":          tb.defaultSampleDuration,
+		"default_sample_size":              tb.defaultSampleSize,
+		"default_sample_flags":             tb.defaultSampleFlags.exportFields(),
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (tb *TrexBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, tb.Version(), tb.Flags())
+	bmfcommon.PushBytes(&data, tb.trackId)
+	bmfcommon.PushBytes(&data, tb.defaultSampleDescriptionIndex)
+	bmfcommon.PushBytes(&data, tb.defaultSampleDuration)
+	bmfcommon.PushBytes(&data, tb.defaultSampleSize)
+	bmfcommon.PushBytes(&data, uint32(tb.defaultSampleFlags))
+
+	return data, nil
+}
+
+type trexBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (trexBoxFactory) Name() string {
+	return "trex"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (trexBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf trexBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	trexBox := &TrexBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = trexBox.parse()
+	log.PanicIf(err)
+
+	return trexBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = trexBoxFactory{}
+	_ bmfcommon.CommonBox      = &TrexBox{}
+	_ bmfcommon.FieldExporter  = &TrexBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(trexBoxFactory{})
+}
@@ -0,0 +1,74 @@
+package bmftype
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestTrexBoxFactory_Name(t *testing.T) {
+	name := trexBoxFactory{}.Name()
+
+	if name != "trex" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestTrexBoxFactory_New(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	bmfcommon.PushBytes(&data, uint32(11))
+	bmfcommon.PushBytes(&data, uint32(22))
+	bmfcommon.PushBytes(&data, uint32(33))
+	bmfcommon.PushBytes(&data, uint32(44))
+	bmfcommon.PushBytes(&data, uint32(0x00010000))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "trex", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := trexBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	trex := cb.(*TrexBox)
+
+	if trex.TrackId() != 11 {
+		t.Fatalf("TrackId() not correct.")
+	} else if trex.DefaultSampleDescriptionIndex() != 22 {
+		t.Fatalf("DefaultSampleDescriptionIndex() not correct.")
+	} else if trex.DefaultSampleDuration() != 33 {
+		t.Fatalf("DefaultSampleDuration() not correct.")
+	} else if trex.DefaultSampleSize() != 44 {
+		t.Fatalf("DefaultSampleSize() not correct.")
+	} else if trex.DefaultSampleFlags().IsNonSyncSample() != true {
+		t.Fatalf("DefaultSampleFlags() not correct.")
+	}
+}
+
+func TestTrexBox_EncodeData(t *testing.T) {
+	var data []byte
+	bmfcommon.PushBytes(&data, uint32(0))
+	bmfcommon.PushBytes(&data, uint32(11))
+	bmfcommon.PushBytes(&data, uint32(22))
+	bmfcommon.PushBytes(&data, uint32(33))
+	bmfcommon.PushBytes(&data, uint32(44))
+	bmfcommon.PushBytes(&data, uint32(0x00010000))
+
+	cb := getTestParsedBox(trexBoxFactory{}, data)
+	assertEncodeData(t, cb, data)
+}
@@ -37,22 +37,11 @@ func (rate MvhdRate) String() string {
 // and relevant to the entire presentationconsidered as a whole.
 type MvhdBox struct {
 	bmfcommon.Box
+	bmfcommon.FullBox
 	bmfcommon.Standard32TimeSupport
 
-	flags   uint32
-	version uint8
-	rate    MvhdRate
-	volume  bmfcommon.Volume
-}
-
-// Flags returns the flags of the box. The first byte is the version.
-func (mb *MvhdBox) Flags() uint32 {
-	return mb.flags
-}
-
-// Version returns the version of the box
-func (mb *MvhdBox) Version() uint8 {
-	return mb.version
+	rate   MvhdRate
+	volume bmfcommon.Volume
 }
 
 // Rate returns the playback rate.
@@ -69,7 +58,7 @@ func (mb *MvhdBox) Volume() bmfcommon.Volume {
 func (mb *MvhdBox) InlineString() string {
 	return fmt.Sprintf(
 		"%s VER=(0x%02x) FLAGS=(0x%08x) RATE=(%d]) VOLUME=[%s] %s",
-		mb.Box.InlineString(), mb.version, mb.flags, mb.rate, mb.volume,
+		mb.Box.InlineString(), mb.Version(), mb.Flags(), mb.rate, mb.volume,
 		mb.Standard32TimeSupport.InlineString())
 }
 
@@ -83,14 +72,22 @@ func (b *MvhdBox) parse() (err error) {
 	data, err := b.Data()
 	log.PanicIf(err)
 
-	b.version = data[0]
+	// Version 1 has 64-bit times. The fields after the volume are not read.
+	size := 26
+	if b.Version() == 1 {
+		size = 38
+	}
+
+	if len(data) < size {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), int64(size), int64(len(data))))
+	}
 
 	var creationEpoch uint64
 	var modificationEpoch uint64
 	var timeScale uint64
 	var duration uint64
 
-	if b.version == 0 {
+	if b.Version() == 0 {
 		creationEpoch32 := bmfcommon.DefaultEndianness.Uint32(data[4:8])
 		creationEpoch = uint64(creationEpoch32)
 
@@ -105,16 +102,18 @@ func (b *MvhdBox) parse() (err error) {
 
 		b.rate = MvhdRate(bmfcommon.DefaultEndianness.Uint32(data[20:24]))
 		b.volume = bmfcommon.Volume(bmfcommon.DefaultEndianness.Uint16(data[24:26]))
-	} else if b.version == 1 {
+	} else if b.Version() == 1 {
+		// Version 1 has 64-bit times but the time-scale is still 32-bit.
+
 		creationEpoch = bmfcommon.DefaultEndianness.Uint64(data[4:12])
 		modificationEpoch = bmfcommon.DefaultEndianness.Uint64(data[12:20])
-		timeScale = bmfcommon.DefaultEndianness.Uint64(data[20:28])
-		duration = bmfcommon.DefaultEndianness.Uint64(data[28:36])
+		timeScale = uint64(bmfcommon.DefaultEndianness.Uint32(data[20:24]))
+		duration = bmfcommon.DefaultEndianness.Uint64(data[24:32])
 
-		b.rate = MvhdRate(bmfcommon.DefaultEndianness.Uint32(data[36:40]))
-		b.volume = bmfcommon.Volume(bmfcommon.DefaultEndianness.Uint16(data[40:42]))
+		b.rate = MvhdRate(bmfcommon.DefaultEndianness.Uint32(data[32:36]))
+		b.volume = bmfcommon.Volume(bmfcommon.DefaultEndianness.Uint16(data[36:38]))
 	} else {
-		log.Panicf("mvhd: version (%d) not supported", b.version)
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(b, b.Version(), nil))
 	}
 
 	b.Standard32TimeSupport = bmfcommon.NewStandard32TimeSupport(
@@ -126,6 +125,66 @@ func (b *MvhdBox) parse() (err error) {
 	return nil
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (mb *MvhdBox) ExportFields() (fields map[string]interface{}, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fields, err = mb.Standard32TimeSupport.ExportFields()
+	log.PanicIf(err)
+
+	fields["rate"] = float64(mb.rate) / 0x10000
+	fields["volume"] = float64(mb.volume) / 0x100
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. Fields that are not modeled (the
+// matrix, the pre-defined fields, and the next track ID) are carried over.
+func (mb *MvhdBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := mb.Data()
+	log.PanicIf(err)
+
+	data = make([]byte, len(original))
+	copy(data, original)
+
+	data[0] = mb.Version()
+
+	var duration uint64
+	if mb.HasDuration() == true {
+		duration = mb.ScaledDuration()
+	}
+
+	if mb.Version() == 0 {
+		bmfcommon.DefaultEndianness.PutUint32(data[4:8], uint32(mb.CreationEpoch()))
+		bmfcommon.DefaultEndianness.PutUint32(data[8:12], uint32(mb.ModificationEpoch()))
+		bmfcommon.DefaultEndianness.PutUint32(data[12:16], uint32(mb.TimeScale()))
+		bmfcommon.DefaultEndianness.PutUint32(data[16:20], uint32(duration))
+		bmfcommon.DefaultEndianness.PutUint32(data[20:24], uint32(mb.rate))
+		bmfcommon.DefaultEndianness.PutUint16(data[24:26], uint16(mb.volume))
+	} else if mb.Version() == 1 {
+		bmfcommon.DefaultEndianness.PutUint64(data[4:12], mb.CreationEpoch())
+		bmfcommon.DefaultEndianness.PutUint64(data[12:20], mb.ModificationEpoch())
+		bmfcommon.DefaultEndianness.PutUint32(data[20:24], uint32(mb.TimeScale()))
+		bmfcommon.DefaultEndianness.PutUint64(data[24:32], duration)
+		bmfcommon.DefaultEndianness.PutUint32(data[32:36], uint32(mb.rate))
+		bmfcommon.DefaultEndianness.PutUint16(data[36:38], uint16(mb.volume))
+	} else {
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(mb, mb.Version(), nil))
+	}
+
+	return data, nil
+}
+
 type mvhdBoxFactory struct {
 }
 
@@ -134,16 +193,25 @@ func (mvhdBoxFactory) Name() string {
 	return "mvhd"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (mvhdBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
 // New returns a new value instance.
-func (mvhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf mvhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	mvhdBox := &MvhdBox{
-		Box: box,
+		Box:     box,
+		FullBox: fb,
 	}
 
 	err = mvhdBox.parse()
@@ -153,8 +221,9 @@ func (mvhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = mvhdBoxFactory{}
-	_ bmfcommon.CommonBox  = &MvhdBox{}
+	_ bmfcommon.FullBoxFactory = mvhdBoxFactory{}
+	_ bmfcommon.CommonBox      = &MvhdBox{}
+	_ bmfcommon.FieldExporter  = &MvhdBox{}
 )
 
 func init() {
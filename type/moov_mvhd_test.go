@@ -1,6 +1,7 @@
 package bmftype
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -46,7 +47,7 @@ func TestMvhdRate_IsFullSpeed_True(t *testing.T) {
 
 func TestMvhdBox_Flags(t *testing.T) {
 	mb := MvhdBox{
-		flags: 11,
+		FullBox: bmfcommon.NewFullBox(0, 11),
 	}
 
 	if mb.Flags() != 11 {
@@ -56,7 +57,7 @@ func TestMvhdBox_Flags(t *testing.T) {
 
 func TestMvhdBox_Version(t *testing.T) {
 	mb := MvhdBox{
-		version: 22,
+		FullBox: bmfcommon.NewFullBox(22, 0),
 	}
 
 	if mb.Version() != 22 {
@@ -96,8 +97,7 @@ func TestMvhdBox_InlineString(t *testing.T) {
 
 	mb := MvhdBox{
 		Standard32TimeSupport: sts,
-		flags:                 11,
-		version:               22,
+		FullBox:               bmfcommon.NewFullBox(22, 11),
 		rate:                  33,
 		volume:                0,
 	}
@@ -107,6 +107,25 @@ func TestMvhdBox_InlineString(t *testing.T) {
 	}
 }
 
+func TestMvhdBox_ExportFields(t *testing.T) {
+	mb := MvhdBox{
+		Standard32TimeSupport: bmfcommon.NewStandard32TimeSupport(1, 2, 3, 4),
+		rate:                  0x00018000,
+		volume:                0x0100,
+	}
+
+	fields, err := mb.ExportFields()
+	log.PanicIf(err)
+
+	if fields["rate"] != 1.5 {
+		t.Fatalf("Rate not correct: %v", fields["rate"])
+	} else if fields["volume"] != 1.0 {
+		t.Fatalf("Volume not correct: %v", fields["volume"])
+	} else if fields["time_scale"] != uint64(4) {
+		t.Fatalf("Time-scale not correct: %v", fields["time_scale"])
+	}
+}
+
 func TestMvhdBoxFactory_Name(t *testing.T) {
 	mbf := mvhdBoxFactory{}
 	if mbf.Name() != "mvhd" {
@@ -211,7 +230,7 @@ func TestMvhdBoxFactory_New_Version1(t *testing.T) {
 	bmfcommon.PushBytes(&data, epoch+1)
 
 	// timeScale
-	bmfcommon.PushBytes(&data, uint64(30))
+	bmfcommon.PushBytes(&data, uint32(30))
 
 	// scaledDuration
 	bmfcommon.PushBytes(&data, uint64(300))
@@ -268,3 +287,134 @@ func TestMvhdBoxFactory_New_Version1(t *testing.T) {
 		t.Fatalf("Volume() not correct.")
 	}
 }
+
+func TestMvhdBox_EncodeData_Version1(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0x01000000))
+
+	// creation and modification epochs
+	bmfcommon.PushBytes(&data, uint64(3677725917))
+	bmfcommon.PushBytes(&data, uint64(3677725918))
+
+	// timeScale
+	bmfcommon.PushBytes(&data, uint32(90000))
+
+	// scaledDuration (more than 32 bits)
+	bmfcommon.PushBytes(&data, uint64(0x123456789))
+
+	// rate and volume
+	bmfcommon.PushBytes(&data, uint32(0x00010000))
+	bmfcommon.PushBytes(&data, uint16(0x0100))
+
+	// reserved, matrix, pre-defined, and next track ID
+	bmfcommon.PushBytes(&data, make([]byte, 10+36+24))
+	bmfcommon.PushBytes(&data, uint32(3))
+
+	cb := getTestParsedBox(mvhdBoxFactory{}, data)
+	mb := cb.(*MvhdBox)
+
+	if mb.TimeScale() != 90000 {
+		t.Fatalf("TimeScale() not correct: (%d)", mb.TimeScale())
+	} else if mb.ScaledDuration() != 0x123456789 {
+		t.Fatalf("ScaledDuration() not correct: (%d)", mb.ScaledDuration())
+	} else if mb.Rate().IsFullSpeed() != true {
+		t.Fatalf("Rate() not correct: (%d)", mb.Rate())
+	} else if mb.Volume() != 0x0100 {
+		t.Fatalf("Volume() not correct: (%d)", mb.Volume())
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestMvhdBox_UnsupportedVersion_LazyLenient(t *testing.T) {
+	var mvhdData []byte
+	bmfcommon.PushBytes(&mvhdData, uint32(0x05000000))
+	bmfcommon.PushBytes(&mvhdData, make([]byte, 96))
+
+	var moovData []byte
+	bmfcommon.PushBox(&moovData, "mvhd", mvhdData)
+
+	var b []byte
+	bmfcommon.PushBox(&b, "moov", moovData)
+
+	for _, lazy := range []bool{false, true} {
+		sb := rifs.NewSeekableBufferWithBytes(b)
+
+		resource, err := bmfcommon.NewResourceWithOptions(sb, int64(len(b)), bmfcommon.ParseOptions{Lazy: lazy})
+		log.PanicIf(err)
+
+		index := resource.Index()
+
+		if _, found := index[bmfcommon.IndexedBoxEntry{NamePhrase: "moov.mvhd", SequenceNumber: 0}]; found == true {
+			t.Fatalf("Invalid box should not be indexed (lazy=%v).", lazy)
+		} else if _, found := index[bmfcommon.IndexedBoxEntry{NamePhrase: "moov", SequenceNumber: 0}]; found != true {
+			t.Fatalf("Parent should be indexed (lazy=%v).", lazy)
+		}
+
+		diagnostics := resource.Diagnostics()
+		if len(diagnostics) != 1 || diagnostics[0].Severity != bmfcommon.DiagnosticSeverityWarning {
+			t.Fatalf("Expected one warning (lazy=%v): %v", lazy, diagnostics)
+		}
+	}
+}
+
+func TestMvhdBox_UnsupportedVersion_LazyLenient_Sibling(t *testing.T) {
+	var badMvhdData []byte
+	bmfcommon.PushBytes(&badMvhdData, uint32(0x05000000))
+	bmfcommon.PushBytes(&badMvhdData, make([]byte, 96))
+
+	var goodMvhdData []byte
+	bmfcommon.PushBytes(&goodMvhdData, uint32(0))
+	bmfcommon.PushBytes(&goodMvhdData, make([]byte, 96))
+
+	var moovData []byte
+	bmfcommon.PushBox(&moovData, "mvhd", badMvhdData)
+	bmfcommon.PushBox(&moovData, "mvhd", goodMvhdData)
+
+	var b []byte
+	bmfcommon.PushBox(&b, "moov", moovData)
+
+	for _, lazy := range []bool{false, true} {
+		sb := rifs.NewSeekableBufferWithBytes(b)
+
+		resource, err := bmfcommon.NewResourceWithOptions(sb, int64(len(b)), bmfcommon.ParseOptions{Lazy: lazy})
+		log.PanicIf(err)
+
+		boxes, err := resource.GetChildBoxes("moov")
+		log.PanicIf(err)
+
+		moov := boxes[0].(*MoovBox)
+
+		children, err := moov.Children()
+		if err != nil {
+			t.Fatalf("Children() failed (lazy=%v): [%s]", lazy, err.Error())
+		} else if len(children) != 1 {
+			t.Fatalf("Children() not correct (lazy=%v): %v", lazy, children)
+		}
+
+		mvhds, err := moov.GetChildBoxes("mvhd")
+		if err != nil {
+			t.Fatalf("GetChildBoxes() failed (lazy=%v): [%s]", lazy, err.Error())
+		} else if len(mvhds) != 1 || mvhds[0] != children[0] {
+			t.Fatalf("GetChildBoxes() not correct (lazy=%v): %v", lazy, mvhds)
+		}
+
+		mvhd, ok := mvhds[0].(*MvhdBox)
+		if ok != true {
+			t.Fatalf("Expected MVHD box (lazy=%v).", lazy)
+		} else if mvhd.Version() != 0 {
+			t.Fatalf("Wrong MVHD box (lazy=%v).", lazy)
+		}
+
+		if len(resource.Diagnostics()) != 1 {
+			t.Fatalf("Expected one diagnostic (lazy=%v): %v", lazy, resource.Diagnostics())
+		}
+
+		_, err = json.Marshal(resource)
+		if err != nil {
+			t.Fatalf("Export failed (lazy=%v): [%s]", lazy, err.Error())
+		}
+	}
+}
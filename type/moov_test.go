@@ -1,9 +1,14 @@
 package bmftype
 
 import (
+	"encoding/json"
+	"io"
+	"os"
 	"reflect"
 	"testing"
 
+	"io/ioutil"
+
 	"github.com/dsoprea/go-logging"
 	"github.com/dsoprea/go-utility/filesystem"
 
@@ -73,3 +78,240 @@ func TestMoovBoxFactory_New(t *testing.T) {
 		t.Fatalf("Expected an 'moov' box.")
 	}
 }
+
+func TestMoovBox_IsFragmented_FromChildren(t *testing.T) {
+	var moovData []byte
+	bmfcommon.PushBox(&moovData, "mvex", nil)
+
+	var b []byte
+	bmfcommon.PushBox(&b, "moov", moovData)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes("moov")
+	log.PanicIf(err)
+
+	moov := boxes[0].(*MoovBox)
+
+	if moov.IsFragmented() != true {
+		t.Fatalf("IsFragmented() not correct.")
+	}
+}
+
+func TestMoovBox_Stream(t *testing.T) {
+	f, err := os.Open(getTestAssetFilepath("tears-of-steel.mp4"))
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	// Hide the seeker.
+	r := io.MultiReader(f)
+
+	sp := bmfcommon.NewStreamParser(r)
+
+	mdatSize := int64(0)
+
+	handler := func(se bmfcommon.StreamEvent) (action bmfcommon.StreamAction, err error) {
+		if se.Type == bmfcommon.StreamEventBoxStart && se.Header.Name == "mdat" {
+			n, err := io.Copy(ioutil.Discard, se.Payload)
+			log.PanicIf(err)
+
+			mdatSize = n
+
+			return bmfcommon.StreamActionSkip, nil
+		}
+
+		return bmfcommon.StreamActionParse, nil
+	}
+
+	err = sp.Parse(handler)
+	log.PanicIf(err)
+
+	if mdatSize != 2872360-8 {
+		t.Fatalf("MDAT payload size not correct: (%d)", mdatSize)
+	}
+
+	moovBoxes, err := sp.GetChildBoxes("moov")
+	log.PanicIf(err)
+
+	trakBoxes, err := moovBoxes[0].(*MoovBox).GetChildBoxes("trak")
+	log.PanicIf(err)
+
+	if len(trakBoxes) != 2 {
+		t.Fatalf("TRAK count not correct: (%d)", len(trakBoxes))
+	}
+
+	tkhd, err := trakBoxes[1].(*TrakBox).Tkhd()
+	log.PanicIf(err)
+
+	if tkhd.TrackId() != 2 {
+		t.Fatalf("Track ID not correct: (%d)", tkhd.TrackId())
+	}
+}
+
+func TestMoovBox_Stream_Descend(t *testing.T) {
+	f, err := os.Open(getTestAssetFilepath("tears-of-steel.mp4"))
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	// Hide the seeker.
+	r := io.MultiReader(f)
+
+	sp := bmfcommon.NewStreamParser(r)
+
+	trakBoxes := make([]*TrakBox, 0)
+
+	handler := func(se bmfcommon.StreamEvent) (action bmfcommon.StreamAction, err error) {
+		if se.Type == bmfcommon.StreamEventBoxParsed && se.Header.Name == "trak" {
+			if se.Header.Depth != 2 || se.Header.Parent.Name != "moov" {
+				t.Fatalf("TRAK header not correct: %s", se.Header)
+			}
+
+			trakBoxes = append(trakBoxes, se.Box.(*TrakBox))
+		}
+
+		if se.Type != bmfcommon.StreamEventBoxStart {
+			return bmfcommon.StreamActionParse, nil
+		} else if se.Header.Name == "moov" {
+			return bmfcommon.StreamActionDescend, nil
+		} else if se.Header.Name == "mdat" {
+			return bmfcommon.StreamActionSkip, nil
+		}
+
+		return bmfcommon.StreamActionParse, nil
+	}
+
+	err = sp.Parse(handler)
+	log.PanicIf(err)
+
+	// The TKHD boxes depend on the MVHD box, which was parsed separately.
+
+	if len(trakBoxes) != 2 {
+		t.Fatalf("TRAK count not correct: (%d)", len(trakBoxes))
+	}
+
+	tkhd, err := trakBoxes[1].Tkhd()
+	log.PanicIf(err)
+
+	if tkhd.TrackId() != 2 {
+		t.Fatalf("Track ID not correct: (%d)", tkhd.TrackId())
+	}
+}
+
+// getTestTrakSampleTable returns the sample-table of the given track.
+func getTestTrakSampleTable(resource *bmfcommon.Resource, trackIndex int) (tkhd *TkhdBox, st *SampleTable) {
+	moovBoxes, err := resource.GetChildBoxes("moov")
+	log.PanicIf(err)
+
+	trakBoxes, err := moovBoxes[0].(*MoovBox).GetChildBoxes("trak")
+	log.PanicIf(err)
+
+	trak := trakBoxes[trackIndex].(*TrakBox)
+
+	tkhd, err = trak.Tkhd()
+	log.PanicIf(err)
+
+	mdia, err := trak.Mdia()
+	log.PanicIf(err)
+
+	minfBoxes, err := mdia.GetChildBoxes("minf")
+	log.PanicIf(err)
+
+	stblBoxes, err := minfBoxes[0].(*MinfBox).GetChildBoxes("stbl")
+	log.PanicIf(err)
+
+	st, err = stblBoxes[0].(*StblBox).SampleTable()
+	log.PanicIf(err)
+
+	return tkhd, st
+}
+
+func TestMoovBox_Lazy(t *testing.T) {
+	data, err := ioutil.ReadFile(getTestAssetFilepath("tears-of-steel.mp4"))
+	log.PanicIf(err)
+
+	sb := rifs.NewSeekableBufferWithBytes(data)
+
+	eagerResource, err := bmfcommon.NewResource(sb, int64(len(data)))
+	log.PanicIf(err)
+
+	sb = rifs.NewSeekableBufferWithBytes(data)
+
+	lazyResource, err := bmfcommon.NewResourceWithOptions(sb, int64(len(data)), bmfcommon.ParseOptions{Lazy: true})
+	log.PanicIf(err)
+
+	for i := 0; i < 2; i++ {
+		eagerTkhd, eagerSt := getTestTrakSampleTable(eagerResource, i)
+		lazyTkhd, lazySt := getTestTrakSampleTable(lazyResource, i)
+
+		if lazyTkhd.TrackId() != eagerTkhd.TrackId() {
+			t.Fatalf("Track ID not correct: (%d) != (%d)", lazyTkhd.TrackId(), eagerTkhd.TrackId())
+		} else if reflect.DeepEqual(lazySt.Samples(), eagerSt.Samples()) != true {
+			t.Fatalf("Samples not correct for track (%d).", i)
+		}
+	}
+
+	if len(lazyResource.Index()) != len(eagerResource.Index()) {
+		t.Fatalf("Index size not correct: (%d) != (%d)", len(lazyResource.Index()), len(eagerResource.Index()))
+	}
+}
+
+func TestMoovBox_Export_Asset(t *testing.T) {
+	f, err := os.Open(getTestAssetFilepath("tears-of-steel.mp4"))
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+	log.PanicIf(err)
+
+	resource, err := bmfcommon.NewResource(f, fi.Size())
+	log.PanicIf(err)
+
+	data, err := json.Marshal(resource)
+	log.PanicIf(err)
+
+	var er bmfcommon.ExportedResource
+
+	err = json.Unmarshal(data, &er)
+	log.PanicIf(err)
+
+	types := make([]string, len(er.Boxes))
+	for i, eb := range er.Boxes {
+		types[i] = eb.Type
+	}
+
+	if reflect.DeepEqual(types, []string{"ftyp", "free", "mdat", "moov"}) != true {
+		t.Fatalf("Root boxes not correct: %v", types)
+	}
+
+	moov := er.Boxes[3]
+
+	if moov.Fields["is_fragmented"] != false {
+		t.Fatalf("MOOV fields not correct: %v", moov.Fields)
+	} else if moov.Children[0].Type != "mvhd" {
+		t.Fatalf("First MOOV child not correct: [%s]", moov.Children[0].Type)
+	}
+
+	var tkhd *bmfcommon.ExportedBox
+	for _, child := range moov.Children {
+		if child.Type == "trak" {
+			tkhd = &child.Children[0]
+			break
+		}
+	}
+
+	if tkhd == nil || tkhd.Type != "tkhd" {
+		t.Fatalf("TKHD not found.")
+	} else if *tkhd.Version != 0 || *tkhd.Flags != 3 {
+		t.Fatalf("TKHD version or flags not correct.")
+	} else if tkhd.Fields["width"] != float64(1920) || tkhd.Fields["height"] != float64(800) {
+		t.Fatalf("TKHD fields not correct: %v", tkhd.Fields)
+	}
+}
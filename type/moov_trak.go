@@ -1,6 +1,8 @@
 package bmftype
 
 import (
+	"strconv"
+
 	"github.com/dsoprea/go-logging"
 
 	"github.com/dsoprea/go-iso-bmf/common"
@@ -29,6 +31,150 @@ func (trak *TrakBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
 	trak.LoadedBoxIndex = fbi
 }
 
+// GetChildBoxes returns the child boxes of the given type.
+func (trak *TrakBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return trak.GetChildBoxesOf(trak, name)
+}
+
+// Tkhd returns the TKHD child box.
+func (trak *TrakBox) Tkhd() (tkhd *TkhdBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	boxes, err := trak.GetChildBoxes("tkhd")
+	log.PanicIf(err)
+
+	tkhd, ok := boxes[0].(*TkhdBox)
+	if ok == false {
+		log.Panicf("trak: tkhd child is not a track header")
+	}
+
+	return tkhd, nil
+}
+
+// Mdia returns the MDIA child box.
+func (trak *TrakBox) Mdia() (mdia *MdiaBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	boxes, err := trak.GetChildBoxes("mdia")
+	log.PanicIf(err)
+
+	mdia, ok := boxes[0].(*MdiaBox)
+	if ok == false {
+		log.Panicf("trak: mdia child is not a media box")
+	}
+
+	return mdia, nil
+}
+
+// Edts returns the EDTS child box or nil if the track has no edits.
+func (trak *TrakBox) Edts() (edts *EdtsBox, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if _, found := trak.LoadedBoxIndex["edts"]; found == false {
+		return nil, nil
+	}
+
+	boxes, err := trak.GetChildBoxes("edts")
+	log.PanicIf(err)
+
+	edts, ok := boxes[0].(*EdtsBox)
+	if ok == false {
+		log.Panicf("trak: edts child is not an edit box")
+	}
+
+	return edts, nil
+}
+
+// HandlerType returns the handler-type of the media in this track (e.g.
+// "vide" or "soun").
+func (trak *TrakBox) HandlerType() (handlerType string, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	mdia, err := trak.Mdia()
+	log.PanicIf(err)
+
+	boxes, err := mdia.GetChildBoxes("hdlr")
+	log.PanicIf(err)
+
+	hdlr, ok := boxes[0].(*HdlrBox)
+	if ok == false {
+		log.Panicf("trak: hdlr child is not a handler box")
+	}
+
+	return hdlr.Handler(), nil
+}
+
+// MediaTimeScale returns the time-scale of the media in this track. This is
+// the scale of all sample timestamps and durations.
+func (trak *TrakBox) MediaTimeScale() (timeScale uint64, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	mdia, err := trak.Mdia()
+	log.PanicIf(err)
+
+	boxes, err := mdia.GetChildBoxes("mdhd")
+	log.PanicIf(err)
+
+	mdhd, ok := boxes[0].(*MdhdBox)
+	if ok == false {
+		log.Panicf("trak: mdhd child is not a media header")
+	}
+
+	return mdhd.TimeScale(), nil
+}
+
+// QueryAttribute returns the value of the given attribute for path queries.
+// "hdlr" is the handler-type of the media (e.g. "vide") and "track_id" is the
+// track ID.
+func (trak *TrakBox) QueryAttribute(key string) (value string, found bool, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	switch key {
+	case "hdlr":
+		handlerType, err := trak.HandlerType()
+		log.PanicIf(err)
+
+		return handlerType, true, nil
+	case "track_id":
+		tkhd, err := trak.Tkhd()
+		log.PanicIf(err)
+
+		return strconv.FormatUint(uint64(tkhd.TrackId()), 10), true, nil
+	}
+
+	return "", false, nil
+}
+
+// EncodeData returns the payload of the box preceding its children. This box
+// only has children.
+func (trak *TrakBox) EncodeData() (data []byte, err error) {
+	return []byte{}, nil
+}
+
 type trakBoxFactory struct {
 }
 
@@ -45,18 +191,17 @@ func (trakBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 		}
 	}()
 
-	// TODO(dustin): Return to this once we have a sample.
-
 	trakBox := &TrakBox{
 		Box: box,
 	}
 
-	return trakBox, -1, nil
+	return trakBox, 0, nil
 }
 
 var (
-	_ bmfcommon.BoxFactory = trakBoxFactory{}
-	_ bmfcommon.CommonBox  = &TrakBox{}
+	_ bmfcommon.BoxFactory      = trakBoxFactory{}
+	_ bmfcommon.CommonBox       = &TrakBox{}
+	_ bmfcommon.QueryAttributer = &TrakBox{}
 )
 
 func init() {
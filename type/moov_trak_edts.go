@@ -22,6 +22,17 @@ func (edts *EdtsBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
 	edts.LoadedBoxIndex = fbi
 }
 
+// GetChildBoxes returns the child boxes of the given type.
+func (edts *EdtsBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return edts.GetChildBoxesOf(edts, name)
+}
+
+// EncodeData returns the payload of the box preceding its children. This box
+// only has children.
+func (edts *EdtsBox) EncodeData() (data []byte, err error) {
+	return []byte{}, nil
+}
+
 type edtsBoxFactory struct {
 }
 
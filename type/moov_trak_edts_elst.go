@@ -6,58 +6,87 @@ import (
 	"github.com/dsoprea/go-iso-bmf/common"
 )
 
+const (
+	// ElstEmptyMediaTime is the media-time of an empty edit, during which
+	// nothing from the media is presented.
+	ElstEmptyMediaTime = -1
+)
+
 // ElstBox is the "Edit List" box.
 type ElstBox struct {
 	bmfcommon.Box
+	bmfcommon.FullBox
 
-	version uint32
-	entries []elstEntry
-}
-
-// Version is the version of this box.
-func (eb *ElstBox) Version() uint32 {
-	return eb.version
+	entries []ElstEntry
 }
 
 // Entries returns the entries.
-func (eb *ElstBox) Entries() []elstEntry {
+func (eb *ElstBox) Entries() []ElstEntry {
 	return eb.entries
 }
 
-type elstEntry struct {
-	// segmentDuration is the duration of this edit segment.
-	segmentDuration uint32
+// ElstEntry is one edit in the edit list.
+type ElstEntry struct {
+	// segmentDuration is the duration of this edit segment in the time-scale
+	// of the movie (see MVHD).
+	segmentDuration uint64
 
-	// mediaTime is the starting time within the media of this edit segment.
-	mediaTime uint32
+	// mediaTime is the starting time within the media of this edit segment in
+	// the time-scale of the media (see MDHD). It is -1 for an empty edit.
+	mediaTime int64
 
 	// mediaRate is the relative rate at which to play the media corresponding
 	// to this segment.
-	mediaRate uint16
+	mediaRate int16
 
-	mediaRateFraction uint16
+	mediaRateFraction int16
 }
 
-// SegmentDuration is the duration of this edit segment.
-func (ee elstEntry) SegmentDuration() uint32 {
+// SegmentDuration is the duration of this edit segment in the time-scale of
+// the movie.
+func (ee ElstEntry) SegmentDuration() uint64 {
 	return ee.segmentDuration
 }
 
-// MediaTime is the starting time within the media of this edit segment.
-func (ee elstEntry) MediaTime() uint32 {
+// MediaTime is the starting time within the media of this edit segment in the
+// time-scale of the media. It is ElstEmptyMediaTime for an empty edit.
+func (ee ElstEntry) MediaTime() int64 {
 	return ee.mediaTime
 }
 
 // MediaRate is the relative rate at which to play the media corresponding to
 // this segment.
-func (ee elstEntry) MediaRate() uint16 {
+func (ee ElstEntry) MediaRate() int16 {
 	return ee.mediaRate
 }
 
-func (ee elstEntry) MediaRateFraction() uint16 {
+// MediaRateFraction is the fractional part of the media-rate. It is normally
+// zero.
+func (ee ElstEntry) MediaRateFraction() int16 {
 	return ee.mediaRateFraction
 }
 
+// IsEmpty returns true if nothing from the media is presented during this
+// segment.
+func (ee ElstEntry) IsEmpty() bool {
+	return ee.mediaTime == ElstEmptyMediaTime
+}
+
+// IsDwell returns true if the media is held at a single time for the duration
+// of this segment.
+func (ee ElstEntry) IsDwell() bool {
+	return ee.IsEmpty() == false && ee.mediaRate == 0 && ee.mediaRateFraction == 0
+}
+
+// entrySize returns the size of one entry, which depends on the version.
+func (eb *ElstBox) entrySize() int {
+	if eb.Version() == 1 {
+		return 20
+	}
+
+	return 12
+}
+
 func (b *ElstBox) parse() (err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
@@ -68,21 +97,78 @@ func (b *ElstBox) parse() (err error) {
 	data, err := b.Data()
 	log.PanicIf(err)
 
-	b.version = bmfcommon.DefaultEndianness.Uint32(data[0:4])
+	if len(data) < 8 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 8, int64(len(data))))
+	}
 
 	entryCount := int(bmfcommon.DefaultEndianness.Uint32(data[4:8]))
-	b.entries = make([]elstEntry, entryCount)
+	entrySize := b.entrySize()
+
+	err = b.CheckEntryCount("entries", int64(entryCount), 8, int64(entrySize))
+	log.PanicIf(err)
+
+	b.entries = make([]ElstEntry, entryCount)
 
 	for i := 0; i < entryCount; i++ {
-		b.entries[i].segmentDuration = bmfcommon.DefaultEndianness.Uint32(data[(8 + 12*i):(12 + 12*i)])
-		b.entries[i].mediaTime = bmfcommon.DefaultEndianness.Uint32(data[(12 + 12*i):(16 + 12*i)])
-		b.entries[i].mediaRate = bmfcommon.DefaultEndianness.Uint16(data[(16 + 12*i):(18 + 12*i)])
-		b.entries[i].mediaRateFraction = bmfcommon.DefaultEndianness.Uint16(data[(18 + 12*i):(20 + 12*i)])
+		entryData := data[8+entrySize*i : 8+entrySize*(i+1)]
+
+		if b.Version() == 1 {
+			b.entries[i].segmentDuration = bmfcommon.DefaultEndianness.Uint64(entryData[0:8])
+			b.entries[i].mediaTime = int64(bmfcommon.DefaultEndianness.Uint64(entryData[8:16]))
+			entryData = entryData[16:]
+		} else {
+			b.entries[i].segmentDuration = uint64(bmfcommon.DefaultEndianness.Uint32(entryData[0:4]))
+			b.entries[i].mediaTime = int64(int32(bmfcommon.DefaultEndianness.Uint32(entryData[4:8])))
+			entryData = entryData[8:]
+		}
+
+		b.entries[i].mediaRate = int16(bmfcommon.DefaultEndianness.Uint16(entryData[0:2]))
+		b.entries[i].mediaRateFraction = int16(bmfcommon.DefaultEndianness.Uint16(entryData[2:4]))
 	}
 
 	return nil
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (eb *ElstBox) ExportFields() (fields map[string]interface{}, err error) {
+	entries := make([]map[string]interface{}, len(eb.entries))
+	for i, ee := range eb.entries {
+		entries[i] = map[string]interface{}{
+			"segment_duration":    ee.segmentDuration,
+			"media_time":          ee.mediaTime,
+			"media_rate":          ee.mediaRate,
+			"media_rate_fraction": ee.mediaRateFraction,
+		}
+	}
+
+	fields = map[string]interface{}{
+		"entries": entries,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (eb *ElstBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, eb.Version(), eb.Flags())
+	bmfcommon.PushBytes(&data, uint32(len(eb.entries)))
+
+	for _, entry := range eb.entries {
+		if eb.Version() == 1 {
+			bmfcommon.PushBytes(&data, entry.segmentDuration)
+			bmfcommon.PushBytes(&data, uint64(entry.mediaTime))
+		} else {
+			bmfcommon.PushBytes(&data, uint32(entry.segmentDuration))
+			bmfcommon.PushBytes(&data, uint32(int32(entry.mediaTime)))
+		}
+
+		bmfcommon.PushBytes(&data, uint16(entry.mediaRate))
+		bmfcommon.PushBytes(&data, uint16(entry.mediaRateFraction))
+	}
+
+	return data, nil
+}
+
 type elstBoxFactory struct {
 }
 
@@ -91,16 +177,25 @@ func (elstBoxFactory) Name() string {
 	return "elst"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (elstBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
 // New returns a new value instance.
-func (elstBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf elstBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	elstBox := &ElstBox{
-		Box: box,
+		Box:     box,
+		FullBox: fb,
 	}
 
 	err = elstBox.parse()
@@ -110,8 +205,9 @@ func (elstBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = elstBoxFactory{}
-	_ bmfcommon.CommonBox  = &ElstBox{}
+	_ bmfcommon.FullBoxFactory = elstBoxFactory{}
+	_ bmfcommon.CommonBox      = &ElstBox{}
+	_ bmfcommon.FieldExporter  = &ElstBox{}
 )
 
 func init() {
@@ -1,6 +1,7 @@
 package bmftype
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -12,7 +13,7 @@ import (
 
 func TestElstBox_Version(t *testing.T) {
 	eb := &ElstBox{
-		version: 11,
+		FullBox: bmfcommon.NewFullBox(11, 0),
 	}
 
 	if eb.Version() != 11 {
@@ -21,7 +22,7 @@ func TestElstBox_Version(t *testing.T) {
 }
 
 func TestElstBox_Entries(t *testing.T) {
-	entries := []elstEntry{
+	entries := []ElstEntry{
 		{segmentDuration: 11, mediaTime: 22, mediaRate: 33, mediaRateFraction: 44},
 		{segmentDuration: 55, mediaTime: 66, mediaRate: 77, mediaRateFraction: 88},
 	}
@@ -36,7 +37,7 @@ func TestElstBox_Entries(t *testing.T) {
 }
 
 func TestElstEntry_SegmentDuration(t *testing.T) {
-	ee := &elstEntry{
+	ee := &ElstEntry{
 		segmentDuration: 11,
 	}
 
@@ -46,7 +47,7 @@ func TestElstEntry_SegmentDuration(t *testing.T) {
 }
 
 func TestElstEntry_MediaTime(t *testing.T) {
-	ee := &elstEntry{
+	ee := &ElstEntry{
 		mediaTime: 11,
 	}
 
@@ -56,7 +57,7 @@ func TestElstEntry_MediaTime(t *testing.T) {
 }
 
 func TestElstEntry_MediaRate(t *testing.T) {
-	ee := &elstEntry{
+	ee := &ElstEntry{
 		mediaRate: 11,
 	}
 
@@ -66,7 +67,7 @@ func TestElstEntry_MediaRate(t *testing.T) {
 }
 
 func TestElstEntry_MediaRateFraction(t *testing.T) {
-	ee := &elstEntry{
+	ee := &ElstEntry{
 		mediaRateFraction: 11,
 	}
 
@@ -88,8 +89,8 @@ func TestElstBoxFactory_New(t *testing.T) {
 
 	var data []byte
 
-	// Version.
-	bmfcommon.PushBytes(&data, uint32(11))
+	// Version and flags.
+	bmfcommon.PushBytes(&data, uint32(0x00000011))
 
 	// Entry count.
 	bmfcommon.PushBytes(&data, uint32(2))
@@ -124,11 +125,13 @@ func TestElstBoxFactory_New(t *testing.T) {
 
 	elst := cb.(*ElstBox)
 
-	if elst.Version() != 11 {
+	if elst.Version() != 0 {
 		t.Fatalf("Version() not correct.")
+	} else if elst.Flags() != 0x11 {
+		t.Fatalf("Flags() not correct.")
 	}
 
-	entries := []elstEntry{
+	entries := []ElstEntry{
 		{segmentDuration: 11, mediaTime: 22, mediaRate: 33, mediaRateFraction: 44},
 		{segmentDuration: 55, mediaTime: 66, mediaRate: 77, mediaRateFraction: 88},
 	}
@@ -137,3 +140,146 @@ func TestElstBoxFactory_New(t *testing.T) {
 		t.Fatalf("Entries() not correct.")
 	}
 }
+
+func TestElstBox_EncodeData(t *testing.T) {
+	var data []byte
+	bmfcommon.PushBytes(&data, uint32(0))
+	bmfcommon.PushBytes(&data, uint32(1))
+	bmfcommon.PushBytes(&data, uint32(1000))
+	bmfcommon.PushBytes(&data, uint32(0))
+	bmfcommon.PushBytes(&data, uint16(1))
+	bmfcommon.PushBytes(&data, uint16(0))
+
+	cb := getTestParsedBox(elstBoxFactory{}, data)
+	assertEncodeData(t, cb, data)
+}
+
+func TestElstEntry_IsEmpty(t *testing.T) {
+	ee := ElstEntry{
+		mediaTime: ElstEmptyMediaTime,
+		mediaRate: 1,
+	}
+
+	if ee.IsEmpty() != true {
+		t.Fatalf("Expected empty edit.")
+	} else if ee.IsDwell() != false {
+		t.Fatalf("Empty edit should not be a dwell.")
+	}
+
+	ee.mediaTime = 0
+
+	if ee.IsEmpty() != false {
+		t.Fatalf("Expected non-empty edit.")
+	}
+}
+
+func TestElstEntry_IsDwell(t *testing.T) {
+	ee := ElstEntry{
+		mediaTime: 100,
+	}
+
+	if ee.IsDwell() != true {
+		t.Fatalf("Expected dwell edit.")
+	}
+
+	ee.mediaRate = 1
+
+	if ee.IsDwell() != false {
+		t.Fatalf("Expected non-dwell edit.")
+	}
+}
+
+func TestElstBoxFactory_New_Version0_EmptyEdit(t *testing.T) {
+	var data []byte
+
+	// Version and flags.
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	// Entry count.
+	bmfcommon.PushBytes(&data, uint32(1))
+
+	bmfcommon.PushBytes(&data, uint32(1000))
+	bmfcommon.PushBytes(&data, uint32(0xffffffff))
+	bmfcommon.PushBytes(&data, uint16(1))
+	bmfcommon.PushBytes(&data, uint16(0))
+
+	cb := getTestParsedBox(elstBoxFactory{}, data)
+	elst := cb.(*ElstBox)
+
+	entries := elst.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected one entry.")
+	} else if entries[0].MediaTime() != -1 {
+		t.Fatalf("MediaTime() not correct: (%d)", entries[0].MediaTime())
+	} else if entries[0].IsEmpty() != true {
+		t.Fatalf("Expected empty edit.")
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestElstBoxFactory_New_Version1(t *testing.T) {
+	var data []byte
+
+	// Version and flags.
+	bmfcommon.PushBytes(&data, uint32(0x01000000))
+
+	// Entry count.
+	bmfcommon.PushBytes(&data, uint32(2))
+
+	// Push entry (1).
+	bmfcommon.PushBytes(&data, uint64(0x123456789))
+	bmfcommon.PushBytes(&data, uint64(0xffffffffffffffff))
+	bmfcommon.PushBytes(&data, uint16(1))
+	bmfcommon.PushBytes(&data, uint16(0))
+
+	// Push entry (2).
+	bmfcommon.PushBytes(&data, uint64(55))
+	bmfcommon.PushBytes(&data, uint64(0x987654321))
+	bmfcommon.PushBytes(&data, uint16(1))
+	bmfcommon.PushBytes(&data, uint16(0x8000))
+
+	cb := getTestParsedBox(elstBoxFactory{}, data)
+	elst := cb.(*ElstBox)
+
+	if elst.Version() != 1 {
+		t.Fatalf("Version() not correct.")
+	}
+
+	entries := []ElstEntry{
+		{segmentDuration: 0x123456789, mediaTime: -1, mediaRate: 1, mediaRateFraction: 0},
+		{segmentDuration: 55, mediaTime: 0x987654321, mediaRate: 1, mediaRateFraction: -0x8000},
+	}
+
+	if reflect.DeepEqual(elst.Entries(), entries) != true {
+		t.Fatalf("Entries() not correct: %v", elst.Entries())
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestElstBoxFactory_New_Truncated(t *testing.T) {
+	var data []byte
+
+	// Version and flags.
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	// Entry count.
+	bmfcommon.PushBytes(&data, uint32(2))
+
+	// Only sixteen of the (24) bytes of entries are present. This fits in the
+	// whole payload but not after the count.
+	bmfcommon.PushBytes(&data, make([]byte, 16))
+
+	_, err := getTestBoxFactoryNew(elstBoxFactory{}, data)
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+
+	var et *bmfcommon.ErrTruncated
+	if errors.As(err, &et) != true {
+		t.Fatalf("Expected ErrTruncated: [%s]", err.Error())
+	} else if et.Size != 24 || et.Available != 16 {
+		t.Fatalf("Truncation not correct: %v", et)
+	}
+}
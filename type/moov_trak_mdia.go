@@ -26,6 +26,17 @@ func (mdia *MdiaBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
 	mdia.LoadedBoxIndex = fbi
 }
 
+// GetChildBoxes returns the child boxes of the given type.
+func (mdia *MdiaBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return mdia.GetChildBoxesOf(mdia, name)
+}
+
+// EncodeData returns the payload of the box preceding its children. This box
+// only has children.
+func (mdia *MdiaBox) EncodeData() (data []byte, err error) {
+	return []byte{}, nil
+}
+
 type mdiaBoxFactory struct {
 }
 
@@ -14,23 +14,12 @@ import (
 // and relevant to characteristics of the media in a track.
 type MdhdBox struct {
 	bmfcommon.Box
+	bmfcommon.FullBox
 	bmfcommon.Standard32TimeSupport
 
-	version  byte
-	flags    uint32
 	language uint16
 }
 
-// Version returns the version.
-func (mb *MdhdBox) Version() byte {
-	return mb.version
-}
-
-// Flags returns the flags.
-func (mb *MdhdBox) Flags() uint32 {
-	return mb.flags
-}
-
 // Language returns the stringified language.
 func (mb *MdhdBox) Language() string {
 	languageString := mb.getLanguageString(mb.language)
@@ -47,7 +36,7 @@ func (mb *MdhdBox) String() string {
 func (mb *MdhdBox) InlineString() string {
 	return fmt.Sprintf(
 		"%s VER=(0x%02x) FLAGS=(0x%08x) LANG=[%s] %s",
-		mb.Box.InlineString(), mb.version, mb.flags, mb.Language(),
+		mb.Box.InlineString(), mb.Version(), mb.Flags(), mb.Language(),
 		mb.Standard32TimeSupport.InlineString())
 }
 
@@ -61,21 +50,44 @@ func (b *MdhdBox) parse() (err error) {
 	data, err := b.Data()
 	log.PanicIf(err)
 
-	b.version = data[0]
-	b.flags = bmfcommon.DefaultEndianness.Uint32(data[0:4])
+	// Version 1 has 64-bit times.
+	size := 22
+	if b.Version() == 1 {
+		size = 34
+	}
 
-	creationEpoch := bmfcommon.DefaultEndianness.Uint32(data[4:8])
-	modificationEpoch := bmfcommon.DefaultEndianness.Uint32(data[8:12])
-	timeScale := bmfcommon.DefaultEndianness.Uint32(data[12:16])
-	duration := bmfcommon.DefaultEndianness.Uint32(data[16:20])
+	if len(data) < size {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), int64(size), int64(len(data))))
+	}
 
-	b.Standard32TimeSupport = bmfcommon.NewStandard32TimeSupport(
-		uint64(creationEpoch),
-		uint64(modificationEpoch),
-		uint64(duration),
-		uint64(timeScale))
+	var creationEpoch uint64
+	var modificationEpoch uint64
+	var timeScale uint64
+	var duration uint64
+
+	if b.Version() == 0 {
+		creationEpoch = uint64(bmfcommon.DefaultEndianness.Uint32(data[4:8]))
+		modificationEpoch = uint64(bmfcommon.DefaultEndianness.Uint32(data[8:12]))
+		timeScale = uint64(bmfcommon.DefaultEndianness.Uint32(data[12:16]))
+		duration = uint64(bmfcommon.DefaultEndianness.Uint32(data[16:20]))
+
+		b.language = bmfcommon.DefaultEndianness.Uint16(data[20:22])
+	} else {
+		// Version 1 has 64-bit times but the time-scale is still 32-bit.
+
+		creationEpoch = bmfcommon.DefaultEndianness.Uint64(data[4:12])
+		modificationEpoch = bmfcommon.DefaultEndianness.Uint64(data[12:20])
+		timeScale = uint64(bmfcommon.DefaultEndianness.Uint32(data[20:24]))
+		duration = bmfcommon.DefaultEndianness.Uint64(data[24:32])
+
+		b.language = bmfcommon.DefaultEndianness.Uint16(data[32:34])
+	}
 
-	b.language = bmfcommon.DefaultEndianness.Uint16(data[20:22])
+	b.Standard32TimeSupport = bmfcommon.NewStandard32TimeSupport(
+		creationEpoch,
+		modificationEpoch,
+		duration,
+		timeScale)
 
 	return nil
 }
@@ -90,6 +102,61 @@ func (b *MdhdBox) getLanguageString(language uint16) string {
 	return string([]byte{l[0] + 0x60, l[1] + 0x60, l[2] + 0x60})
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (mb *MdhdBox) ExportFields() (fields map[string]interface{}, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fields, err = mb.Standard32TimeSupport.ExportFields()
+	log.PanicIf(err)
+
+	fields["language"] = mb.Language()
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. The pre-defined field is carried
+// over.
+func (mb *MdhdBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := mb.Data()
+	log.PanicIf(err)
+
+	data = make([]byte, len(original))
+	copy(data, original)
+
+	var duration uint64
+	if mb.HasDuration() == true {
+		duration = mb.ScaledDuration()
+	}
+
+	bmfcommon.DefaultEndianness.PutUint32(data[0:4], uint32(mb.Version())<<24|mb.Flags()&0x00ffffff)
+
+	if mb.Version() == 0 {
+		bmfcommon.DefaultEndianness.PutUint32(data[4:8], uint32(mb.CreationEpoch()))
+		bmfcommon.DefaultEndianness.PutUint32(data[8:12], uint32(mb.ModificationEpoch()))
+		bmfcommon.DefaultEndianness.PutUint32(data[12:16], uint32(mb.TimeScale()))
+		bmfcommon.DefaultEndianness.PutUint32(data[16:20], uint32(duration))
+		bmfcommon.DefaultEndianness.PutUint16(data[20:22], mb.language)
+	} else {
+		bmfcommon.DefaultEndianness.PutUint64(data[4:12], mb.CreationEpoch())
+		bmfcommon.DefaultEndianness.PutUint64(data[12:20], mb.ModificationEpoch())
+		bmfcommon.DefaultEndianness.PutUint32(data[20:24], uint32(mb.TimeScale()))
+		bmfcommon.DefaultEndianness.PutUint64(data[24:32], duration)
+		bmfcommon.DefaultEndianness.PutUint16(data[32:34], mb.language)
+	}
+
+	return data, nil
+}
+
 type mdhdBoxFactory struct {
 }
 
@@ -98,16 +165,25 @@ func (mdhdBoxFactory) Name() string {
 	return "mdhd"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (mdhdBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
 // New returns a new value instance.
-func (mdhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf mdhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	mdhdBox := &MdhdBox{
-		Box: box,
+		Box:     box,
+		FullBox: fb,
 	}
 
 	err = mdhdBox.parse()
@@ -117,8 +193,9 @@ func (mdhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = mdhdBoxFactory{}
-	_ bmfcommon.CommonBox  = &MdhdBox{}
+	_ bmfcommon.FullBoxFactory = mdhdBoxFactory{}
+	_ bmfcommon.CommonBox      = &MdhdBox{}
+	_ bmfcommon.FieldExporter  = &MdhdBox{}
 )
 
 func init() {
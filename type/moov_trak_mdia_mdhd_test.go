@@ -1,6 +1,7 @@
 package bmftype
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
@@ -12,7 +13,7 @@ import (
 
 func TestMdhdBox_Version(t *testing.T) {
 	mb := MdhdBox{
-		version: 11,
+		FullBox: bmfcommon.NewFullBox(11, 0),
 	}
 
 	if mb.Version() != 11 {
@@ -22,7 +23,7 @@ func TestMdhdBox_Version(t *testing.T) {
 
 func TestMdhdBox_Flags(t *testing.T) {
 	mb := MdhdBox{
-		flags: 22,
+		FullBox: bmfcommon.NewFullBox(0, 22),
 	}
 
 	if mb.Flags() != 22 {
@@ -188,8 +189,7 @@ func TestMdhdBox_String(t *testing.T) {
 
 	mb := MdhdBox{
 		Box:                   box,
-		version:               11,
-		flags:                 22,
+		FullBox:               bmfcommon.NewFullBox(11, 22),
 		Standard32TimeSupport: sts,
 
 		// 00100 00101 00110
@@ -210,8 +210,7 @@ func TestMdhdBox_InlineString(t *testing.T) {
 
 	mb := MdhdBox{
 		Box:                   box,
-		version:               11,
-		flags:                 22,
+		FullBox:               bmfcommon.NewFullBox(11, 22),
 		Standard32TimeSupport: bmfcommon.NewStandard32TimeSupport(epoch, epoch+1, timeScale*10, timeScale),
 
 		// 00100 00101 00110
@@ -233,8 +232,8 @@ func TestMdhdBoxFactory_Name(t *testing.T) {
 func TestMdhdBoxFactory_New(t *testing.T) {
 	data := []byte{}
 
-	// flags
-	bmfcommon.PushBytes(&data, uint32(0x11223344))
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0x00223344))
 
 	// creation and modified epochs
 
@@ -277,11 +276,11 @@ func TestMdhdBoxFactory_New(t *testing.T) {
 
 	mb := cb.(*MdhdBox)
 
-	if mb.Version() != 0x11 {
+	if mb.Version() != 0 {
 		t.Fatalf("Version() not correct: (0x%02x)", mb.Version())
 	}
 
-	if mb.Flags() != 0x11223344 {
+	if mb.Flags() != 0x223344 {
 		t.Fatalf("Flags() not correct: (0x%08x)", mb.Flags())
 	}
 
@@ -311,3 +310,72 @@ func TestMdhdBoxFactory_New(t *testing.T) {
 		t.Fatalf("Language() not correct.")
 	}
 }
+
+func TestMdhdBoxFactory_New_Version1(t *testing.T) {
+	data := []byte{}
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0x01000000))
+
+	epoch := uint64(3677725917)
+	baseTime := bmfcommon.EpochToTime(epoch)
+
+	// creation epoch
+	bmfcommon.PushBytes(&data, epoch)
+
+	// modification epoch
+	bmfcommon.PushBytes(&data, epoch+1)
+
+	// TimeScale()
+	bmfcommon.PushBytes(&data, uint32(30))
+
+	// ScaledDuration()
+	bmfcommon.PushBytes(&data, uint64(300))
+
+	// language
+
+	// 00100 00101 00110
+	bmfcommon.PushBytes(&data, uint16(0b001000010100110))
+
+	// pre-defined
+	bmfcommon.PushBytes(&data, uint16(0))
+
+	b := []byte{}
+	bmfcommon.PushBox(&b, "mdhd", data)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := mdhdBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	mb := cb.(*MdhdBox)
+
+	if mb.Version() != 1 {
+		t.Fatalf("Version() not correct: (0x%02x)", mb.Version())
+	} else if mb.CreationTime() != baseTime {
+		t.Fatalf("CreationTime() not correct: [%s] != [%s]", mb.CreationTime(), baseTime)
+	} else if mb.ModificationTime() != baseTime.Add(1*time.Second) {
+		t.Fatalf("ModificationTime() not correct: %s", mb.ModificationTime())
+	} else if mb.TimeScale() != 30 {
+		t.Fatalf("TimeScale() not correct.")
+	} else if mb.ScaledDuration() != 300 {
+		t.Fatalf("ScaledDuration() not correct.")
+	} else if mb.Language() != "def" {
+		t.Fatalf("Language() not correct.")
+	}
+
+	// The encoding should be identical.
+
+	encoded, err := mb.EncodeData()
+	log.PanicIf(err)
+
+	if bytes.Equal(encoded, data) != true {
+		t.Fatalf("EncodeData() not correct.")
+	}
+}
@@ -25,6 +25,17 @@ func (minf *MinfBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
 	minf.LoadedBoxIndex = fbi
 }
 
+// GetChildBoxes returns the child boxes of the given type.
+func (minf *MinfBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return minf.GetChildBoxesOf(minf, name)
+}
+
+// EncodeData returns the payload of the box preceding its children. This box
+// only has children.
+func (minf *MinfBox) EncodeData() (data []byte, err error) {
+	return []byte{}, nil
+}
+
 type minfBoxFactory struct {
 }
 
@@ -11,18 +11,14 @@ import (
 // Contains general information, independent of the protocol, for hint tracks.
 type HmhdBox struct {
 	bmfcommon.Box
+	bmfcommon.FullBox
 
-	version    byte
 	maxPDUSize uint16
 	avgPDUSize uint16
 	maxBitrate uint32
 	avgBitrate uint32
 }
 
-func (hb *HmhdBox) Version() byte {
-	return hb.version
-}
-
 func (hb *HmhdBox) MaxPDUSize() uint16 {
 	return hb.maxPDUSize
 }
@@ -49,15 +45,54 @@ func (b *HmhdBox) parse() (err error) {
 	data, err := b.Data()
 	log.PanicIf(err)
 
-	b.version = data[0]
-	b.maxPDUSize = bmfcommon.DefaultEndianness.Uint16(data[1:3])
-	b.avgPDUSize = bmfcommon.DefaultEndianness.Uint16(data[3:5])
-	b.maxBitrate = bmfcommon.DefaultEndianness.Uint32(data[5:9])
-	b.avgBitrate = bmfcommon.DefaultEndianness.Uint32(data[9:13])
+	if len(data) < 16 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 16, int64(len(data))))
+	}
+
+	b.maxPDUSize = bmfcommon.DefaultEndianness.Uint16(data[4:6])
+	b.avgPDUSize = bmfcommon.DefaultEndianness.Uint16(data[6:8])
+	b.maxBitrate = bmfcommon.DefaultEndianness.Uint32(data[8:12])
+	b.avgBitrate = bmfcommon.DefaultEndianness.Uint32(data[12:16])
 
 	return nil
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (hb *HmhdBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"max_pdu_size": hb.maxPDUSize,
+		"avg_pdu_size": hb.avgPDUSize,
+		"max_bitrate":  hb.maxBitrate,
+		"avg_bitrate":  hb.avgBitrate,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. Bytes that are not modeled are
+// carried over.
+func (hb *HmhdBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := hb.Data()
+	log.PanicIf(err)
+
+	data = make([]byte, len(original))
+	copy(data, original)
+
+	bmfcommon.DefaultEndianness.PutUint32(data[0:4], uint32(hb.Version())<<24|hb.Flags()&0x00ffffff)
+	bmfcommon.DefaultEndianness.PutUint16(data[4:6], hb.maxPDUSize)
+	bmfcommon.DefaultEndianness.PutUint16(data[6:8], hb.avgPDUSize)
+	bmfcommon.DefaultEndianness.PutUint32(data[8:12], hb.maxBitrate)
+	bmfcommon.DefaultEndianness.PutUint32(data[12:16], hb.avgBitrate)
+
+	return data, nil
+}
+
 type hmhdBoxFactory struct {
 }
 
@@ -66,16 +101,25 @@ func (hmhdBoxFactory) Name() string {
 	return "hmhd"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (hmhdBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
 // New returns a new value instance.
-func (hmhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf hmhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	hmhdBox := &HmhdBox{
-		Box: box,
+		Box:     box,
+		FullBox: fb,
 	}
 
 	err = hmhdBox.parse()
@@ -85,8 +129,9 @@ func (hmhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = hmhdBoxFactory{}
-	_ bmfcommon.CommonBox  = &HmhdBox{}
+	_ bmfcommon.FullBoxFactory = hmhdBoxFactory{}
+	_ bmfcommon.CommonBox      = &HmhdBox{}
+	_ bmfcommon.FieldExporter  = &HmhdBox{}
 )
 
 func init() {
@@ -11,7 +11,7 @@ import (
 
 func TestHmhdBox_Version(t *testing.T) {
 	hb := HmhdBox{
-		version: 11,
+		FullBox: bmfcommon.NewFullBox(11, 0),
 	}
 
 	if hb.Version() != 11 {
@@ -68,8 +68,9 @@ func TestHmhdBoxFactory_Name(t *testing.T) {
 }
 
 func TestHmhdBoxFactory_New(t *testing.T) {
+	// Version and flags.
 	data := []byte{
-		0x11,
+		0x00, 0x00, 0x00, 0x11,
 	}
 
 	bmfcommon.PushBytes(&data, uint16(0x22))
@@ -95,8 +96,10 @@ func TestHmhdBoxFactory_New(t *testing.T) {
 
 	hb := cb.(*HmhdBox)
 
-	if hb.Version() != 0x11 {
+	if hb.Version() != 0 {
 		t.Fatalf("Version() not correct.")
+	} else if hb.Flags() != 0x11 {
+		t.Fatalf("Flags() not correct.")
 	} else if hb.MaxPDUSize() != 0x22 {
 		t.Fatalf("MaxPDUSize() not correct: (0x%04x)", hb.MaxPDUSize())
 	} else if hb.AvgPDUSize() != 0x33 {
@@ -12,6 +12,8 @@ type StblBox struct {
 
 	// LoadedBoxIndex contains this box's children.
 	bmfcommon.LoadedBoxIndex
+
+	sampleTable *SampleTable
 }
 
 // SetLoadedBoxIndex sets the child boxes after a box has been manufactured
@@ -22,6 +24,50 @@ func (stbl *StblBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
 	stbl.LoadedBoxIndex = fbi
 }
 
+// GetChildBoxes returns the child boxes of the given type.
+func (stbl *StblBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return stbl.GetChildBoxesOf(stbl, name)
+}
+
+// SampleTable returns the resolved sample table for this track. It is built on
+// first use and then cached.
+func (stbl *StblBox) SampleTable() (st *SampleTable, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if stbl.sampleTable == nil {
+		stbl.sampleTable, err = NewSampleTable(stbl)
+		log.PanicIf(err)
+	}
+
+	return stbl.sampleTable, nil
+}
+
+// childBox returns the first child with the given name or nil if not present.
+func (stbl *StblBox) childBox(name string) bmfcommon.CommonBox {
+	if _, found := stbl.LoadedBoxIndex[name]; found == false {
+		return nil
+	}
+
+	boxes, err := stbl.GetChildBoxes(name)
+	log.PanicIf(err)
+
+	if len(boxes) == 0 {
+		return nil
+	}
+
+	return boxes[0]
+}
+
+// EncodeData returns the payload of the box preceding its children. This box
+// only has children.
+func (stbl *StblBox) EncodeData() (data []byte, err error) {
+	return []byte{}, nil
+}
+
 type stblBoxFactory struct {
 }
 
@@ -0,0 +1,141 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// Co64Box is the "Chunk Large Offset" box (64-bit offsets).
+type Co64Box struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	chunkOffsets []uint64
+}
+
+// ChunkOffsets returns the file offsets of each chunk.
+func (cb *Co64Box) ChunkOffsets() []uint64 {
+	return cb.chunkOffsets
+}
+
+// ChunkCount returns the number of chunks.
+func (cb *Co64Box) ChunkCount() uint32 {
+	return uint32(len(cb.chunkOffsets))
+}
+
+// ChunkOffsetAt returns the file offset of the chunk with the given
+// (zero-based) index.
+func (cb *Co64Box) ChunkOffsetAt(index uint32) (offset uint64, err error) {
+	if index >= uint32(len(cb.chunkOffsets)) {
+		return 0, fmt.Errorf("chunk index (%d) out of range (%d)", index, len(cb.chunkOffsets))
+	}
+
+	return cb.chunkOffsets[index], nil
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (cb *Co64Box) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) CHUNKS=(%d)",
+		cb.Box.InlineString(), cb.Version(), cb.Flags(), len(cb.chunkOffsets))
+}
+
+func (b *Co64Box) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < 8 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 8, int64(len(data))))
+	}
+
+	count := bmfcommon.DefaultEndianness.Uint32(data[4:8])
+
+	err = b.CheckEntryCount("chunk offsets", int64(count), 8, 8)
+	log.PanicIf(err)
+
+	b.chunkOffsets = make([]uint64, count)
+
+	offset := 8
+	for i := 0; i < int(count); i++ {
+		b.chunkOffsets[i] = bmfcommon.DefaultEndianness.Uint64(data[offset : offset+8])
+		offset += 8
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (cb *Co64Box) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"chunk_offsets": cb.chunkOffsets,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (cb *Co64Box) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, cb.Version(), cb.Flags())
+	bmfcommon.PushBytes(&data, uint32(len(cb.chunkOffsets)))
+
+	for _, chunkOffset := range cb.chunkOffsets {
+		bmfcommon.PushBytes(&data, chunkOffset)
+	}
+
+	return data, nil
+}
+
+type co64BoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (co64BoxFactory) Name() string {
+	return "co64"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (co64BoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf co64BoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	co64Box := &Co64Box{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = co64Box.parse()
+	log.PanicIf(err)
+
+	return co64Box, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = co64BoxFactory{}
+	_ bmfcommon.CommonBox      = &Co64Box{}
+	_ bmfcommon.FieldExporter  = &Co64Box{}
+	_ chunkOffsetSource        = &Co64Box{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(co64BoxFactory{})
+}
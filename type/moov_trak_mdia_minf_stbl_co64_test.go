@@ -0,0 +1,87 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestCo64Box_ChunkOffsetAt(t *testing.T) {
+	cb := Co64Box{
+		chunkOffsets: []uint64{11, 0x100000000},
+	}
+
+	if cb.ChunkCount() != 2 {
+		t.Fatalf("ChunkCount() not correct.")
+	}
+
+	offset, err := cb.ChunkOffsetAt(1)
+	log.PanicIf(err)
+
+	if offset != 0x100000000 {
+		t.Fatalf("ChunkOffsetAt() not correct: (%d)", offset)
+	}
+
+	_, err = cb.ChunkOffsetAt(2)
+	if err == nil {
+		t.Fatalf("Expected error for out-of-range index.")
+	}
+}
+
+func TestCo64BoxFactory_Name(t *testing.T) {
+	name := co64BoxFactory{}.Name()
+
+	if name != "co64" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestCo64BoxFactory_New(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	// entry-count
+	bmfcommon.PushBytes(&data, uint32(2))
+
+	bmfcommon.PushBytes(&data, uint64(0x1000))
+	bmfcommon.PushBytes(&data, uint64(0x100000000))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "co64", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := co64BoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	co64 := cb.(*Co64Box)
+
+	if reflect.DeepEqual(co64.ChunkOffsets(), []uint64{0x1000, 0x100000000}) != true {
+		t.Fatalf("ChunkOffsets() not correct.")
+	}
+}
+
+func TestCo64Box_EncodeData(t *testing.T) {
+	var data []byte
+	bmfcommon.PushBytes(&data, uint32(0))
+	bmfcommon.PushBytes(&data, uint32(2))
+	bmfcommon.PushBytes(&data, uint64(0x100000000))
+	bmfcommon.PushBytes(&data, uint64(0x100001000))
+
+	cb := getTestParsedBox(co64BoxFactory{}, data)
+	assertEncodeData(t, cb, data)
+}
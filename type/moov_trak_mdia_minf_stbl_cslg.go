@@ -0,0 +1,194 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// CslgBox is the "Composition to Decode" box.
+type CslgBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	compositionToDtsShift        int64
+	leastDecodeToDisplayDelta    int64
+	greatestDecodeToDisplayDelta int64
+	compositionStartTime         int64
+	compositionEndTime           int64
+}
+
+// CompositionToDtsShift is the shift that, when added to the composition
+// times, guarantees that CTS >= DTS for every sample.
+func (cb *CslgBox) CompositionToDtsShift() int64 {
+	return cb.compositionToDtsShift
+}
+
+// LeastDecodeToDisplayDelta is the smallest composition offset in the track.
+func (cb *CslgBox) LeastDecodeToDisplayDelta() int64 {
+	return cb.leastDecodeToDisplayDelta
+}
+
+// GreatestDecodeToDisplayDelta is the largest composition offset in the
+// track.
+func (cb *CslgBox) GreatestDecodeToDisplayDelta() int64 {
+	return cb.greatestDecodeToDisplayDelta
+}
+
+// CompositionStartTime is the smallest composition time of any sample.
+func (cb *CslgBox) CompositionStartTime() int64 {
+	return cb.compositionStartTime
+}
+
+// CompositionEndTime is the composition time plus the duration of the sample
+// with the largest composition time.
+func (cb *CslgBox) CompositionEndTime() int64 {
+	return cb.compositionEndTime
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (cb *CslgBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) SHIFT=(%d) LEAST-DELTA=(%d) GREATEST-DELTA=(%d) START=(%d) END=(%d)",
+		cb.Box.InlineString(), cb.Version(), cb.Flags(), cb.compositionToDtsShift,
+		cb.leastDecodeToDisplayDelta, cb.greatestDecodeToDisplayDelta,
+		cb.compositionStartTime, cb.compositionEndTime)
+}
+
+func (b *CslgBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	fields := []*int64{
+		&b.compositionToDtsShift,
+		&b.leastDecodeToDisplayDelta,
+		&b.greatestDecodeToDisplayDelta,
+		&b.compositionStartTime,
+		&b.compositionEndTime,
+	}
+
+	// The fields are 64-bit in version 1.
+	size := 24
+	if b.Version() == 1 {
+		size = 44
+	}
+
+	if len(data) < size {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), int64(size), int64(len(data))))
+	}
+
+	offset := 4
+
+	if b.Version() == 0 {
+		for _, field := range fields {
+			*field = int64(int32(bmfcommon.DefaultEndianness.Uint32(data[offset : offset+4])))
+			offset += 4
+		}
+	} else if b.Version() == 1 {
+		for _, field := range fields {
+			*field = int64(bmfcommon.DefaultEndianness.Uint64(data[offset : offset+8]))
+			offset += 8
+		}
+	} else {
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(b, b.Version(), nil))
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (cb *CslgBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"composition_to_dts_shift":         cb.compositionToDtsShift,
+		"least_decode_to_display_delta":    cb.leastDecodeToDisplayDelta,
+		"greatest_decode_to_display_delta": cb.greatestDecodeToDisplayDelta,
+		"composition_start_time":           cb.compositionStartTime,
+		"composition_end_time":             cb.compositionEndTime,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (cb *CslgBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	pushVersionAndFlags(&data, cb.Version(), cb.Flags())
+
+	fields := []int64{
+		cb.compositionToDtsShift,
+		cb.leastDecodeToDisplayDelta,
+		cb.greatestDecodeToDisplayDelta,
+		cb.compositionStartTime,
+		cb.compositionEndTime,
+	}
+
+	for _, field := range fields {
+		if cb.Version() == 0 {
+			bmfcommon.PushBytes(&data, uint32(field))
+		} else if cb.Version() == 1 {
+			bmfcommon.PushBytes(&data, uint64(field))
+		} else {
+			log.Panic(bmfcommon.NewErrUnsupportedVersion(cb, cb.Version(), nil))
+		}
+	}
+
+	return data, nil
+}
+
+type cslgBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (cslgBoxFactory) Name() string {
+	return "cslg"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (cslgBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
+// New returns a new value instance.
+func (bf cslgBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	cslgBox := &CslgBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = cslgBox.parse()
+	log.PanicIf(err)
+
+	return cslgBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = cslgBoxFactory{}
+	_ bmfcommon.CommonBox      = &CslgBox{}
+	_ bmfcommon.FieldExporter  = &CslgBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(cslgBoxFactory{})
+}
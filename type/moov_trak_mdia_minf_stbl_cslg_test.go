@@ -0,0 +1,122 @@
+package bmftype
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestCslgBoxFactory_Name(t *testing.T) {
+	name := cslgBoxFactory{}.Name()
+
+	if name != "cslg" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestCslgBoxFactory_New_Version0(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	bmfcommon.PushBytes(&data, uint32(11))
+	bmfcommon.PushBytes(&data, uint32(0xfffffffe))
+	bmfcommon.PushBytes(&data, uint32(33))
+	bmfcommon.PushBytes(&data, uint32(44))
+	bmfcommon.PushBytes(&data, uint32(55))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "cslg", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := cslgBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	cslg := cb.(*CslgBox)
+
+	if cslg.CompositionToDtsShift() != 11 {
+		t.Fatalf("CompositionToDtsShift() not correct.")
+	} else if cslg.LeastDecodeToDisplayDelta() != -2 {
+		t.Fatalf("LeastDecodeToDisplayDelta() not correct.")
+	} else if cslg.GreatestDecodeToDisplayDelta() != 33 {
+		t.Fatalf("GreatestDecodeToDisplayDelta() not correct.")
+	} else if cslg.CompositionStartTime() != 44 {
+		t.Fatalf("CompositionStartTime() not correct.")
+	} else if cslg.CompositionEndTime() != 55 {
+		t.Fatalf("CompositionEndTime() not correct.")
+	}
+}
+
+func TestCslgBoxFactory_New_Version1(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0x01000000))
+
+	bmfcommon.PushBytes(&data, uint64(11))
+	bmfcommon.PushBytes(&data, uint64(22))
+	bmfcommon.PushBytes(&data, uint64(33))
+	bmfcommon.PushBytes(&data, uint64(44))
+	bmfcommon.PushBytes(&data, uint64(0x100000000))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "cslg", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := cslgBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	cslg := cb.(*CslgBox)
+
+	if cslg.Version() != 1 {
+		t.Fatalf("Version() not correct.")
+	} else if cslg.CompositionEndTime() != 0x100000000 {
+		t.Fatalf("CompositionEndTime() not correct.")
+	}
+}
+
+func TestCslgBox_EncodeData(t *testing.T) {
+	var data0 []byte
+	bmfcommon.PushBytes(&data0, uint32(0))
+	bmfcommon.PushBytes(&data0, uint32(1))
+	bmfcommon.PushBytes(&data0, uint32(0xfffffffe))
+	bmfcommon.PushBytes(&data0, uint32(3))
+	bmfcommon.PushBytes(&data0, uint32(4))
+	bmfcommon.PushBytes(&data0, uint32(5))
+
+	cb := getTestParsedBox(cslgBoxFactory{}, data0)
+	assertEncodeData(t, cb, data0)
+
+	var data1 []byte
+	bmfcommon.PushBytes(&data1, uint32(0x01000000))
+	bmfcommon.PushBytes(&data1, uint64(1))
+	bmfcommon.PushBytes(&data1, uint64(0xfffffffffffffffe))
+	bmfcommon.PushBytes(&data1, uint64(3))
+	bmfcommon.PushBytes(&data1, uint64(4))
+	bmfcommon.PushBytes(&data1, uint64(5))
+
+	cb = getTestParsedBox(cslgBoxFactory{}, data1)
+	assertEncodeData(t, cb, data1)
+}
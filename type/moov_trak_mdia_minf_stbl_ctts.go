@@ -0,0 +1,160 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// CttsEntry is one run of samples that share the same composition offset.
+type CttsEntry struct {
+	sampleCount  uint32
+	sampleOffset int64
+}
+
+// SampleCount is the number of consecutive samples in this run.
+func (ce CttsEntry) SampleCount() uint32 {
+	return ce.sampleCount
+}
+
+// SampleOffset is the composition-time offset (CT = DT + offset). This is
+// unsigned in version (0) and signed in version (1).
+func (ce CttsEntry) SampleOffset() int64 {
+	return ce.sampleOffset
+}
+
+// CttsBox is the "Composition Time to Sample" box.
+type CttsBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	entries []CttsEntry
+}
+
+// Entries returns the entries.
+func (cb *CttsBox) Entries() []CttsEntry {
+	return cb.entries
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (cb *CttsBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) ENTRIES=(%d)",
+		cb.Box.InlineString(), cb.Version(), cb.Flags(), len(cb.entries))
+}
+
+func (b *CttsBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < 8 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 8, int64(len(data))))
+	}
+
+	count := bmfcommon.DefaultEndianness.Uint32(data[4:8])
+
+	err = b.CheckEntryCount("entries", int64(count), 8, 8)
+	log.PanicIf(err)
+
+	b.entries = make([]CttsEntry, count)
+
+	offset := 8
+	for i := 0; i < int(count); i++ {
+		b.entries[i].sampleCount = bmfcommon.DefaultEndianness.Uint32(data[offset : offset+4])
+
+		rawSampleOffset := bmfcommon.DefaultEndianness.Uint32(data[offset+4 : offset+8])
+
+		if b.Version() == 0 {
+			b.entries[i].sampleOffset = int64(rawSampleOffset)
+		} else {
+			b.entries[i].sampleOffset = int64(int32(rawSampleOffset))
+		}
+
+		offset += 8
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (cb *CttsBox) ExportFields() (fields map[string]interface{}, err error) {
+	entries := make([]map[string]interface{}, len(cb.entries))
+	for i, ce := range cb.entries {
+		entries[i] = map[string]interface{}{
+			"sample_count":  ce.sampleCount,
+			"sample_offset": ce.sampleOffset,
+		}
+	}
+
+	fields = map[string]interface{}{
+		"entries": entries,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (cb *CttsBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, cb.Version(), cb.Flags())
+	bmfcommon.PushBytes(&data, uint32(len(cb.entries)))
+
+	for _, entry := range cb.entries {
+		bmfcommon.PushBytes(&data, entry.sampleCount)
+		bmfcommon.PushBytes(&data, uint32(entry.sampleOffset))
+	}
+
+	return data, nil
+}
+
+type cttsBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (cttsBoxFactory) Name() string {
+	return "ctts"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (cttsBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
+// New returns a new value instance.
+func (bf cttsBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	cttsBox := &CttsBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = cttsBox.parse()
+	log.PanicIf(err)
+
+	return cttsBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = cttsBoxFactory{}
+	_ bmfcommon.CommonBox      = &CttsBox{}
+	_ bmfcommon.FieldExporter  = &CttsBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(cttsBoxFactory{})
+}
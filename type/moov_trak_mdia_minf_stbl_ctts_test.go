@@ -0,0 +1,107 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestCttsEntry_Accessors(t *testing.T) {
+	ce := CttsEntry{
+		sampleCount:  11,
+		sampleOffset: -22,
+	}
+
+	if ce.SampleCount() != 11 {
+		t.Fatalf("SampleCount() not correct.")
+	} else if ce.SampleOffset() != -22 {
+		t.Fatalf("SampleOffset() not correct.")
+	}
+}
+
+func TestCttsBoxFactory_Name(t *testing.T) {
+	name := cttsBoxFactory{}.Name()
+
+	if name != "ctts" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func getTestCttsBox(version byte) *CttsBox {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(version)<<24)
+
+	// entry-count
+	bmfcommon.PushBytes(&data, uint32(2))
+
+	bmfcommon.PushBytes(&data, uint32(3))
+	bmfcommon.PushBytes(&data, uint32(1024))
+
+	bmfcommon.PushBytes(&data, uint32(1))
+	bmfcommon.PushBytes(&data, uint32(0xfffffc00))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "ctts", data)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := cttsBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	return cb.(*CttsBox)
+}
+
+func TestCttsBoxFactory_New_Version0(t *testing.T) {
+	ctts := getTestCttsBox(0)
+
+	expected := []CttsEntry{
+		{sampleCount: 3, sampleOffset: 1024},
+		{sampleCount: 1, sampleOffset: 0xfffffc00},
+	}
+
+	if reflect.DeepEqual(ctts.Entries(), expected) != true {
+		t.Fatalf("Entries() not correct: %v", ctts.Entries())
+	}
+}
+
+func TestCttsBoxFactory_New_Version1(t *testing.T) {
+	ctts := getTestCttsBox(1)
+
+	expected := []CttsEntry{
+		{sampleCount: 3, sampleOffset: 1024},
+		{sampleCount: 1, sampleOffset: -1024},
+	}
+
+	if ctts.Version() != 1 {
+		t.Fatalf("Version() not correct.")
+	}
+
+	if reflect.DeepEqual(ctts.Entries(), expected) != true {
+		t.Fatalf("Entries() not correct: %v", ctts.Entries())
+	}
+}
+
+func TestCttsBox_EncodeData(t *testing.T) {
+	var data []byte
+	bmfcommon.PushBytes(&data, uint32(0x01000000))
+	bmfcommon.PushBytes(&data, uint32(2))
+	bmfcommon.PushBytes(&data, uint32(1))
+	bmfcommon.PushBytes(&data, uint32(512))
+	bmfcommon.PushBytes(&data, uint32(3))
+	bmfcommon.PushBytes(&data, uint32(0xfffffe00))
+
+	cb := getTestParsedBox(cttsBoxFactory{}, data)
+	assertEncodeData(t, cb, data)
+}
@@ -0,0 +1,338 @@
+package bmftype
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+)
+
+var (
+	// ErrSampleIndexOutOfRange indicates that a sample was requested that the
+	// track does not have.
+	ErrSampleIndexOutOfRange = errors.New("sample index out of range")
+)
+
+// sampleSizeSource is implemented by the boxes that describe sample sizes
+// (STSZ and STZ2).
+type sampleSizeSource interface {
+	// SampleCount returns the number of samples in the track.
+	SampleCount() uint32
+
+	// SampleSizeAt returns the size of the sample with the given (zero-based)
+	// index.
+	SampleSizeAt(index uint32) (size uint32, err error)
+}
+
+// chunkOffsetSource is implemented by the boxes that describe chunk offsets
+// (STCO and CO64).
+type chunkOffsetSource interface {
+	// ChunkCount returns the number of chunks.
+	ChunkCount() uint32
+
+	// ChunkOffsetAt returns the file offset of the chunk with the given
+	// (zero-based) index.
+	ChunkOffsetAt(index uint32) (offset uint64, err error)
+}
+
+// SampleInfo describes where one sample is stored and when it is decoded and
+// presented. Times are expressed in the media time-scale (see MDHD).
+type SampleInfo struct {
+	index                  uint32
+	offset                 uint64
+	size                   uint32
+	chunk                  uint32
+	sampleDescriptionIndex uint32
+	dts                    uint64
+	cts                    int64
+	duration               uint32
+	isSync                 bool
+}
+
+// Index returns the (zero-based) index of the sample.
+func (si SampleInfo) Index() uint32 {
+	return si.index
+}
+
+// Offset returns the absolute file offset of the sample data.
+func (si SampleInfo) Offset() uint64 {
+	return si.offset
+}
+
+// Size returns the size of the sample data.
+func (si SampleInfo) Size() uint32 {
+	return si.size
+}
+
+// Chunk returns the (one-based) number of the chunk that stores the sample.
+func (si SampleInfo) Chunk() uint32 {
+	return si.chunk
+}
+
+// SampleDescriptionIndex returns the (one-based) index of the sample-entry in
+// the STSD box that describes this sample.
+func (si SampleInfo) SampleDescriptionIndex() uint32 {
+	return si.sampleDescriptionIndex
+}
+
+// Dts returns the decoding timestamp.
+func (si SampleInfo) Dts() uint64 {
+	return si.dts
+}
+
+// Cts returns the composition (presentation) timestamp. This is the DTS if the
+// track has no composition offsets.
+func (si SampleInfo) Cts() int64 {
+	return si.cts
+}
+
+// Duration returns the decoding duration of the sample.
+func (si SampleInfo) Duration() uint32 {
+	return si.duration
+}
+
+// IsSync returns true if the sample is a sync sample (keyframe).
+func (si SampleInfo) IsSync() bool {
+	return si.isSync
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (si SampleInfo) InlineString() string {
+	return fmt.Sprintf(
+		"INDEX=(%d) OFFSET=(0x%016x) SIZE=(%d) CHUNK=(%d) DESC-INDEX=(%d) DTS=(%d) CTS=(%d) DURATION=(%d) SYNC=[%v]",
+		si.index, si.offset, si.size, si.chunk, si.sampleDescriptionIndex, si.dts, si.cts, si.duration, si.isSync)
+}
+
+// String returns a descriptive string.
+func (si SampleInfo) String() string {
+	return fmt.Sprintf("SampleInfo<%s>", si.InlineString())
+}
+
+// SampleTable resolves every sample in a track by combining the sample-size,
+// sample-to-chunk, chunk-offset, time-to-sample, composition-offset, and
+// sync-sample tables.
+type SampleTable struct {
+	samples []SampleInfo
+}
+
+// stscEntryChunks returns the range of (one-based) chunks that the given STSC
+// entry applies to. The last entry applies to the rest of the chunks.
+func stscEntryChunks(entries []StscEntry, i int, chunkCount uint32) (firstChunk, lastChunk uint32) {
+	firstChunk = entries[i].FirstChunk()
+
+	lastChunk = chunkCount
+	if i+1 < len(entries) {
+		lastChunk = entries[i+1].FirstChunk() - 1
+	}
+
+	return firstChunk, lastChunk
+}
+
+// NewSampleTable builds the sample table from the children of the given STBL
+// box.
+func NewSampleTable(stbl *StblBox) (st *SampleTable, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	var sizes sampleSizeSource
+
+	if stsz, ok := stbl.childBox("stsz").(*StszBox); ok == true {
+		sizes = stsz
+	} else if stz2, ok := stbl.childBox("stz2").(*Stz2Box); ok == true {
+		sizes = stz2
+	} else {
+		log.Panicf("stbl: no sample-size box (stsz/stz2)")
+	}
+
+	var offsets chunkOffsetSource
+
+	if stco, ok := stbl.childBox("stco").(*StcoBox); ok == true {
+		offsets = stco
+	} else if co64, ok := stbl.childBox("co64").(*Co64Box); ok == true {
+		offsets = co64
+	} else {
+		log.Panicf("stbl: no chunk-offset box (stco/co64)")
+	}
+
+	stsc, ok := stbl.childBox("stsc").(*StscBox)
+	if ok == false {
+		log.Panicf("stbl: no sample-to-chunk box (stsc)")
+	}
+
+	stts, ok := stbl.childBox("stts").(*SttsBox)
+	if ok == false {
+		log.Panicf("stbl: no time-to-sample box (stts)")
+	}
+
+	sampleCount := sizes.SampleCount()
+
+	// A fixed sample-size takes no space per sample, so the count is only
+	// bounded by the other tables. Make sure that they describe every sample
+	// before allocating for them.
+
+	chunkCount := offsets.ChunkCount()
+	entries := stsc.Entries()
+
+	chunkedSampleCount := uint64(0)
+	for i, entry := range entries {
+		if entry.FirstChunk() == 0 {
+			log.Panicf("stsc: entry (%d) has a first-chunk of zero", i)
+		}
+
+		firstChunk, lastChunk := stscEntryChunks(entries, i, chunkCount)
+		if lastChunk >= firstChunk && chunkedSampleCount < uint64(sampleCount) {
+			chunkedSampleCount += uint64(lastChunk-firstChunk+1) * uint64(entry.SamplesPerChunk())
+		}
+	}
+
+	if chunkedSampleCount < uint64(sampleCount) {
+		log.Panicf("stbl: chunk tables describe only (%d) of (%d) samples", chunkedSampleCount, sampleCount)
+	}
+
+	timedSampleCount := uint64(0)
+	for _, count := range stts.SampleCounts() {
+		timedSampleCount += uint64(count)
+	}
+
+	if timedSampleCount < uint64(sampleCount) {
+		log.Panicf("stts: describes only (%d) of (%d) samples", timedSampleCount, sampleCount)
+	}
+
+	samples := make([]SampleInfo, sampleCount)
+
+	// Locate each sample using the chunk tables.
+
+	sampleIndex := uint32(0)
+
+	for i, entry := range entries {
+		firstChunk, lastChunk := stscEntryChunks(entries, i, chunkCount)
+
+		for chunk := firstChunk; chunk <= lastChunk && sampleIndex < sampleCount; chunk++ {
+			offset, err := offsets.ChunkOffsetAt(chunk - 1)
+			log.PanicIf(err)
+
+			for j := uint32(0); j < entry.SamplesPerChunk() && sampleIndex < sampleCount; j++ {
+				size, err := sizes.SampleSizeAt(sampleIndex)
+				log.PanicIf(err)
+
+				samples[sampleIndex] = SampleInfo{
+					index:                  sampleIndex,
+					offset:                 offset,
+					size:                   size,
+					chunk:                  chunk,
+					sampleDescriptionIndex: entry.SampleDescriptionIndex(),
+					isSync:                 true,
+				}
+
+				offset += uint64(size)
+				sampleIndex++
+			}
+		}
+	}
+
+	// Assign decoding times.
+
+	sampleDeltas := stts.SampleDeltas()
+	dts := uint64(0)
+	sampleIndex = 0
+
+	for i, count := range stts.SampleCounts() {
+		delta := sampleDeltas[i]
+
+		for j := uint32(0); j < count && sampleIndex < sampleCount; j++ {
+			samples[sampleIndex].dts = dts
+			samples[sampleIndex].cts = int64(dts)
+			samples[sampleIndex].duration = delta
+
+			dts += uint64(delta)
+			sampleIndex++
+		}
+	}
+
+	// Apply composition offsets, if any.
+
+	if ctts, ok := stbl.childBox("ctts").(*CttsBox); ok == true {
+		sampleIndex = 0
+
+		for _, entry := range ctts.Entries() {
+			for j := uint32(0); j < entry.SampleCount() && sampleIndex < sampleCount; j++ {
+				samples[sampleIndex].cts += entry.SampleOffset()
+				sampleIndex++
+			}
+		}
+	}
+
+	// Apply sync flags, if any. If there is no STSS box, every sample is a
+	// sync sample.
+
+	if stss, ok := stbl.childBox("stss").(*StssBox); ok == true {
+		for i := range samples {
+			samples[i].isSync = false
+		}
+
+		for _, sampleNumber := range stss.SampleNumbers() {
+			if sampleNumber == 0 || sampleNumber > sampleCount {
+				log.Panicf("stss: sample number (%d) out of range (%d)", sampleNumber, sampleCount)
+			}
+
+			samples[sampleNumber-1].isSync = true
+		}
+	}
+
+	st = &SampleTable{
+		samples: samples,
+	}
+
+	return st, nil
+}
+
+// SampleCount returns the number of samples in the track.
+func (st *SampleTable) SampleCount() int {
+	return len(st.samples)
+}
+
+// Sample returns the sample with the given (zero-based) index.
+func (st *SampleTable) Sample(index int) (si SampleInfo, err error) {
+	if index < 0 || index >= len(st.samples) {
+		return si, ErrSampleIndexOutOfRange
+	}
+
+	return st.samples[index], nil
+}
+
+// Samples returns all samples in decoding order.
+func (st *SampleTable) Samples() []SampleInfo {
+	return st.samples
+}
+
+// SyncSamples returns only the sync samples (keyframes) in decoding order.
+func (st *SampleTable) SyncSamples() []SampleInfo {
+	syncSamples := make([]SampleInfo, 0)
+
+	for _, si := range st.samples {
+		if si.isSync == true {
+			syncSamples = append(syncSamples, si)
+		}
+	}
+
+	return syncSamples
+}
+
+// SampleVisitorFunc is called for every sample by Iterate. Returning an error
+// stops the iteration and the error is returned from Iterate.
+type SampleVisitorFunc func(si SampleInfo) (err error)
+
+// Iterate calls the callback for every sample in decoding order.
+func (st *SampleTable) Iterate(cb SampleVisitorFunc) (err error) {
+	for _, si := range st.samples {
+		err := cb(si)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
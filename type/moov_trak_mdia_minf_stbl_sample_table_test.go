@@ -0,0 +1,321 @@
+package bmftype
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func getTestStblBox(includeStss bool) *StblBox {
+	var children []byte
+
+	// stts: five samples, each with a duration of (100).
+
+	var sttsData []byte
+	bmfcommon.PushBytes(&sttsData, uint32(0))
+	bmfcommon.PushBytes(&sttsData, uint32(1))
+	bmfcommon.PushBytes(&sttsData, uint32(5))
+	bmfcommon.PushBytes(&sttsData, uint32(100))
+
+	bmfcommon.PushBox(&children, "stts", sttsData)
+
+	// ctts
+
+	var cttsData []byte
+	bmfcommon.PushBytes(&cttsData, uint32(0))
+	bmfcommon.PushBytes(&cttsData, uint32(2))
+	bmfcommon.PushBytes(&cttsData, uint32(1))
+	bmfcommon.PushBytes(&cttsData, uint32(200))
+	bmfcommon.PushBytes(&cttsData, uint32(4))
+	bmfcommon.PushBytes(&cttsData, uint32(100))
+
+	bmfcommon.PushBox(&children, "ctts", cttsData)
+
+	// stss
+
+	if includeStss == true {
+		var stssData []byte
+		bmfcommon.PushBytes(&stssData, uint32(0))
+		bmfcommon.PushBytes(&stssData, uint32(2))
+		bmfcommon.PushBytes(&stssData, uint32(1))
+		bmfcommon.PushBytes(&stssData, uint32(4))
+
+		bmfcommon.PushBox(&children, "stss", stssData)
+	}
+
+	// stsc: the first chunk has three samples and the rest have two.
+
+	var stscData []byte
+	bmfcommon.PushBytes(&stscData, uint32(0))
+	bmfcommon.PushBytes(&stscData, uint32(2))
+	bmfcommon.PushBytes(&stscData, uint32(1))
+	bmfcommon.PushBytes(&stscData, uint32(3))
+	bmfcommon.PushBytes(&stscData, uint32(1))
+	bmfcommon.PushBytes(&stscData, uint32(2))
+	bmfcommon.PushBytes(&stscData, uint32(2))
+	bmfcommon.PushBytes(&stscData, uint32(1))
+
+	bmfcommon.PushBox(&children, "stsc", stscData)
+
+	// stsz
+
+	var stszData []byte
+	bmfcommon.PushBytes(&stszData, uint32(0))
+	bmfcommon.PushBytes(&stszData, uint32(0))
+	bmfcommon.PushBytes(&stszData, uint32(5))
+	bmfcommon.PushBytes(&stszData, uint32(10))
+	bmfcommon.PushBytes(&stszData, uint32(20))
+	bmfcommon.PushBytes(&stszData, uint32(30))
+	bmfcommon.PushBytes(&stszData, uint32(40))
+	bmfcommon.PushBytes(&stszData, uint32(50))
+
+	bmfcommon.PushBox(&children, "stsz", stszData)
+
+	// stco
+
+	var stcoData []byte
+	bmfcommon.PushBytes(&stcoData, uint32(0))
+	bmfcommon.PushBytes(&stcoData, uint32(2))
+	bmfcommon.PushBytes(&stcoData, uint32(1000))
+	bmfcommon.PushBytes(&stcoData, uint32(2000))
+
+	bmfcommon.PushBox(&children, "stco", stcoData)
+
+	var b []byte
+	bmfcommon.PushBox(&b, "stbl", children)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes("stbl")
+	log.PanicIf(err)
+
+	return boxes[0].(*StblBox)
+}
+
+func TestNewSampleTable(t *testing.T) {
+	stbl := getTestStblBox(true)
+
+	st, err := NewSampleTable(stbl)
+	log.PanicIf(err)
+
+	expected := []SampleInfo{
+		{index: 0, offset: 1000, size: 10, chunk: 1, sampleDescriptionIndex: 1, dts: 0, cts: 200, duration: 100, isSync: true},
+		{index: 1, offset: 1010, size: 20, chunk: 1, sampleDescriptionIndex: 1, dts: 100, cts: 200, duration: 100, isSync: false},
+		{index: 2, offset: 1030, size: 30, chunk: 1, sampleDescriptionIndex: 1, dts: 200, cts: 300, duration: 100, isSync: false},
+		{index: 3, offset: 2000, size: 40, chunk: 2, sampleDescriptionIndex: 1, dts: 300, cts: 400, duration: 100, isSync: true},
+		{index: 4, offset: 2040, size: 50, chunk: 2, sampleDescriptionIndex: 1, dts: 400, cts: 500, duration: 100, isSync: false},
+	}
+
+	if reflect.DeepEqual(st.Samples(), expected) != true {
+		for i, si := range st.Samples() {
+			t.Logf("(%d): %s", i, si)
+		}
+
+		t.Fatalf("Samples not correct.")
+	}
+}
+
+func TestNewSampleTable_NoStss(t *testing.T) {
+	stbl := getTestStblBox(false)
+
+	st, err := NewSampleTable(stbl)
+	log.PanicIf(err)
+
+	if len(st.SyncSamples()) != 5 {
+		t.Fatalf("Expected all samples to be sync samples.")
+	}
+}
+
+func TestNewSampleTable_MissingTables(t *testing.T) {
+	stbl := new(StblBox)
+
+	_, err := NewSampleTable(stbl)
+	if err == nil {
+		t.Fatalf("Expected error for missing tables.")
+	}
+}
+
+func TestNewSampleTable_UndescribedSamples(t *testing.T) {
+	// A fixed-size STSZ declares far more samples than the other tables
+	// describe.
+
+	buildStbl := func(sampleCount, timedSampleCount uint32) *StblBox {
+		var children []byte
+
+		var sttsData []byte
+		bmfcommon.PushBytes(&sttsData, uint32(0))
+		bmfcommon.PushBytes(&sttsData, uint32(1))
+		bmfcommon.PushBytes(&sttsData, timedSampleCount)
+		bmfcommon.PushBytes(&sttsData, uint32(100))
+
+		bmfcommon.PushBox(&children, "stts", sttsData)
+
+		var stscData []byte
+		bmfcommon.PushBytes(&stscData, uint32(0))
+		bmfcommon.PushBytes(&stscData, uint32(1))
+		bmfcommon.PushBytes(&stscData, uint32(1))
+		bmfcommon.PushBytes(&stscData, uint32(2))
+		bmfcommon.PushBytes(&stscData, uint32(1))
+
+		bmfcommon.PushBox(&children, "stsc", stscData)
+
+		var stszData []byte
+		bmfcommon.PushBytes(&stszData, uint32(0))
+		bmfcommon.PushBytes(&stszData, uint32(10))
+		bmfcommon.PushBytes(&stszData, sampleCount)
+
+		bmfcommon.PushBox(&children, "stsz", stszData)
+
+		var stcoData []byte
+		bmfcommon.PushBytes(&stcoData, uint32(0))
+		bmfcommon.PushBytes(&stcoData, uint32(2))
+		bmfcommon.PushBytes(&stcoData, uint32(1000))
+		bmfcommon.PushBytes(&stcoData, uint32(2000))
+
+		bmfcommon.PushBox(&children, "stco", stcoData)
+
+		var b []byte
+		bmfcommon.PushBox(&b, "stbl", children)
+
+		sb := rifs.NewSeekableBufferWithBytes(b)
+
+		resource, err := bmfcommon.NewResource(sb, int64(len(b)))
+		log.PanicIf(err)
+
+		boxes, err := resource.GetChildBoxes("stbl")
+		log.PanicIf(err)
+
+		return boxes[0].(*StblBox)
+	}
+
+	st, err := NewSampleTable(buildStbl(4, 4))
+	log.PanicIf(err)
+
+	if len(st.Samples()) != 4 {
+		t.Fatalf("Sample count not correct: (%d)", len(st.Samples()))
+	}
+
+	_, err = NewSampleTable(buildStbl(0xffffff, 0xffffff))
+	if err == nil {
+		t.Fatalf("Expected error for samples not in the chunk tables.")
+	} else if err.Error() != "stbl: chunk tables describe only (4) of (16777215) samples" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	_, err = NewSampleTable(buildStbl(4, 3))
+	if err == nil {
+		t.Fatalf("Expected error for samples without times.")
+	} else if err.Error() != "stts: describes only (3) of (4) samples" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+}
+
+func TestStblBox_SampleTable(t *testing.T) {
+	stbl := getTestStblBox(true)
+
+	st1, err := stbl.SampleTable()
+	log.PanicIf(err)
+
+	st2, err := stbl.SampleTable()
+	log.PanicIf(err)
+
+	if st1 != st2 {
+		t.Fatalf("Sample table was not cached.")
+	}
+}
+
+func TestSampleTable_Sample(t *testing.T) {
+	stbl := getTestStblBox(true)
+
+	st, err := stbl.SampleTable()
+	log.PanicIf(err)
+
+	if st.SampleCount() != 5 {
+		t.Fatalf("SampleCount() not correct: (%d)", st.SampleCount())
+	}
+
+	si, err := st.Sample(3)
+	log.PanicIf(err)
+
+	if si.Offset() != 2000 || si.Size() != 40 || si.Dts() != 300 || si.Cts() != 400 || si.IsSync() != true {
+		t.Fatalf("Sample not correct: %s", si)
+	}
+
+	_, err = st.Sample(5)
+	if err != ErrSampleIndexOutOfRange {
+		t.Fatalf("Expected out-of-range error.")
+	}
+}
+
+func TestSampleTable_SyncSamples(t *testing.T) {
+	stbl := getTestStblBox(true)
+
+	st, err := stbl.SampleTable()
+	log.PanicIf(err)
+
+	syncSamples := st.SyncSamples()
+
+	if len(syncSamples) != 2 {
+		t.Fatalf("Sync-sample count not correct: (%d)", len(syncSamples))
+	} else if syncSamples[0].Index() != 0 || syncSamples[1].Index() != 3 {
+		t.Fatalf("Sync samples not correct.")
+	}
+}
+
+func TestSampleTable_Iterate(t *testing.T) {
+	stbl := getTestStblBox(true)
+
+	st, err := stbl.SampleTable()
+	log.PanicIf(err)
+
+	visited := make([]uint32, 0)
+
+	cb := func(si SampleInfo) (err error) {
+		visited = append(visited, si.Index())
+		return nil
+	}
+
+	err = st.Iterate(cb)
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(visited, []uint32{0, 1, 2, 3, 4}) != true {
+		t.Fatalf("Visited samples not correct: %v", visited)
+	}
+}
+
+func TestSampleTable_Iterate_Stop(t *testing.T) {
+	stbl := getTestStblBox(true)
+
+	st, err := stbl.SampleTable()
+	log.PanicIf(err)
+
+	errStop := errors.New("stop")
+	count := 0
+
+	cb := func(si SampleInfo) (err error) {
+		count++
+
+		if count == 2 {
+			return errStop
+		}
+
+		return nil
+	}
+
+	err = st.Iterate(cb)
+	if err != errStop {
+		t.Fatalf("Expected stop error.")
+	} else if count != 2 {
+		t.Fatalf("Iteration did not stop.")
+	}
+}
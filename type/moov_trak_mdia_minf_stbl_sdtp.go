@@ -0,0 +1,152 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// SdtpEntry is the packed dependency information for one sample.
+type SdtpEntry uint8
+
+// IsLeading returns the two-bit "is_leading" value.
+func (se SdtpEntry) IsLeading() uint8 {
+	return uint8(se>>6) & 0x3
+}
+
+// SampleDependsOn returns the two-bit "sample_depends_on" value. (1) means that
+// the sample depends on others (not an I-picture) and (2) means that it does
+// not.
+func (se SdtpEntry) SampleDependsOn() uint8 {
+	return uint8(se>>4) & 0x3
+}
+
+// SampleIsDependedOn returns the two-bit "sample_is_depended_on" value. (2)
+// means that no other sample depends on this one (disposable).
+func (se SdtpEntry) SampleIsDependedOn() uint8 {
+	return uint8(se>>2) & 0x3
+}
+
+// SampleHasRedundancy returns the two-bit "sample_has_redundancy" value.
+func (se SdtpEntry) SampleHasRedundancy() uint8 {
+	return uint8(se) & 0x3
+}
+
+// SdtpBox is the "Independent and Disposable Samples" box.
+type SdtpBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	entries []SdtpEntry
+}
+
+// Entries returns one entry per sample.
+func (sb *SdtpBox) Entries() []SdtpEntry {
+	return sb.entries
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (sb *SdtpBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) ENTRIES=(%d)",
+		sb.Box.InlineString(), sb.Version(), sb.Flags(), len(sb.entries))
+}
+
+func (b *SdtpBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	// The sample-count is not stored in this box. It is implied by the size of
+	// the box (one byte per sample).
+
+	b.entries = make([]SdtpEntry, len(data)-4)
+
+	for i, x := range data[4:] {
+		b.entries[i] = SdtpEntry(x)
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (sb *SdtpBox) ExportFields() (fields map[string]interface{}, err error) {
+	entries := make([]map[string]interface{}, len(sb.entries))
+	for i, se := range sb.entries {
+		entries[i] = map[string]interface{}{
+			"is_leading":            se.IsLeading(),
+			"sample_depends_on":     se.SampleDependsOn(),
+			"sample_is_depended_on": se.SampleIsDependedOn(),
+			"sample_has_redundancy": se.SampleHasRedundancy(),
+		}
+	}
+
+	fields = map[string]interface{}{
+		"entries": entries,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (sb *SdtpBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, sb.Version(), sb.Flags())
+
+	for _, entry := range sb.entries {
+		data = append(data, byte(entry))
+	}
+
+	return data, nil
+}
+
+type sdtpBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (sdtpBoxFactory) Name() string {
+	return "sdtp"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (sdtpBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf sdtpBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	sdtpBox := &SdtpBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = sdtpBox.parse()
+	log.PanicIf(err)
+
+	return sdtpBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = sdtpBoxFactory{}
+	_ bmfcommon.CommonBox      = &SdtpBox{}
+	_ bmfcommon.FieldExporter  = &SdtpBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(sdtpBoxFactory{})
+}
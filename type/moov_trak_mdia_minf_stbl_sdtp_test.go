@@ -0,0 +1,77 @@
+package bmftype
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestSdtpEntry_Fields(t *testing.T) {
+	se := SdtpEntry(0b01_10_11_00)
+
+	if se.IsLeading() != 1 {
+		t.Fatalf("IsLeading() not correct.")
+	} else if se.SampleDependsOn() != 2 {
+		t.Fatalf("SampleDependsOn() not correct.")
+	} else if se.SampleIsDependedOn() != 3 {
+		t.Fatalf("SampleIsDependedOn() not correct.")
+	} else if se.SampleHasRedundancy() != 0 {
+		t.Fatalf("SampleHasRedundancy() not correct.")
+	}
+}
+
+func TestSdtpBoxFactory_Name(t *testing.T) {
+	name := sdtpBoxFactory{}.Name()
+
+	if name != "sdtp" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestSdtpBoxFactory_New(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	data = append(data, 0x20, 0x10, 0x18)
+
+	var b []byte
+	bmfcommon.PushBox(&b, "sdtp", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := sdtpBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	sdtp := cb.(*SdtpBox)
+	entries := sdtp.Entries()
+
+	if len(entries) != 3 {
+		t.Fatalf("Entry count not correct: (%d)", len(entries))
+	} else if entries[0].SampleDependsOn() != 2 {
+		t.Fatalf("Entry (0) not correct.")
+	} else if entries[2].SampleIsDependedOn() != 2 {
+		t.Fatalf("Entry (2) not correct.")
+	}
+}
+
+func TestSdtpBox_EncodeData(t *testing.T) {
+	var data []byte
+	bmfcommon.PushBytes(&data, uint32(0))
+	data = append(data, 0x20, 0x10, 0x24)
+
+	cb := getTestParsedBox(sdtpBoxFactory{}, data)
+	assertEncodeData(t, cb, data)
+}
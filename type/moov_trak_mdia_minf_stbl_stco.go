@@ -0,0 +1,141 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// StcoBox is the "Chunk Offset" box (32-bit offsets).
+type StcoBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	chunkOffsets []uint32
+}
+
+// ChunkOffsets returns the file offsets of each chunk.
+func (sb *StcoBox) ChunkOffsets() []uint32 {
+	return sb.chunkOffsets
+}
+
+// ChunkCount returns the number of chunks.
+func (sb *StcoBox) ChunkCount() uint32 {
+	return uint32(len(sb.chunkOffsets))
+}
+
+// ChunkOffsetAt returns the file offset of the chunk with the given
+// (zero-based) index.
+func (sb *StcoBox) ChunkOffsetAt(index uint32) (offset uint64, err error) {
+	if index >= uint32(len(sb.chunkOffsets)) {
+		return 0, fmt.Errorf("chunk index (%d) out of range (%d)", index, len(sb.chunkOffsets))
+	}
+
+	return uint64(sb.chunkOffsets[index]), nil
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (sb *StcoBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) CHUNKS=(%d)",
+		sb.Box.InlineString(), sb.Version(), sb.Flags(), len(sb.chunkOffsets))
+}
+
+func (b *StcoBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < 8 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 8, int64(len(data))))
+	}
+
+	count := bmfcommon.DefaultEndianness.Uint32(data[4:8])
+
+	err = b.CheckEntryCount("chunk offsets", int64(count), 8, 4)
+	log.PanicIf(err)
+
+	b.chunkOffsets = make([]uint32, count)
+
+	offset := 8
+	for i := 0; i < int(count); i++ {
+		b.chunkOffsets[i] = bmfcommon.DefaultEndianness.Uint32(data[offset : offset+4])
+		offset += 4
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (sb *StcoBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"chunk_offsets": sb.chunkOffsets,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (sb *StcoBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, sb.Version(), sb.Flags())
+	bmfcommon.PushBytes(&data, uint32(len(sb.chunkOffsets)))
+
+	for _, chunkOffset := range sb.chunkOffsets {
+		bmfcommon.PushBytes(&data, chunkOffset)
+	}
+
+	return data, nil
+}
+
+type stcoBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (stcoBoxFactory) Name() string {
+	return "stco"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (stcoBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf stcoBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	stcoBox := &StcoBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = stcoBox.parse()
+	log.PanicIf(err)
+
+	return stcoBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = stcoBoxFactory{}
+	_ bmfcommon.CommonBox      = &StcoBox{}
+	_ bmfcommon.FieldExporter  = &StcoBox{}
+	_ chunkOffsetSource        = &StcoBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(stcoBoxFactory{})
+}
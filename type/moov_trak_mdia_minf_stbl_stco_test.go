@@ -0,0 +1,76 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestStcoBox_ChunkOffsetAt(t *testing.T) {
+	sb := StcoBox{
+		chunkOffsets: []uint32{11, 22},
+	}
+
+	if sb.ChunkCount() != 2 {
+		t.Fatalf("ChunkCount() not correct.")
+	}
+
+	offset, err := sb.ChunkOffsetAt(1)
+	log.PanicIf(err)
+
+	if offset != 22 {
+		t.Fatalf("ChunkOffsetAt() not correct: (%d)", offset)
+	}
+
+	_, err = sb.ChunkOffsetAt(2)
+	if err == nil {
+		t.Fatalf("Expected error for out-of-range index.")
+	}
+}
+
+func TestStcoBoxFactory_Name(t *testing.T) {
+	name := stcoBoxFactory{}.Name()
+
+	if name != "stco" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestStcoBoxFactory_New(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	// entry-count
+	bmfcommon.PushBytes(&data, uint32(2))
+
+	bmfcommon.PushBytes(&data, uint32(0x1000))
+	bmfcommon.PushBytes(&data, uint32(0x2000))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "stco", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := stcoBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	stco := cb.(*StcoBox)
+
+	if reflect.DeepEqual(stco.ChunkOffsets(), []uint32{0x1000, 0x2000}) != true {
+		t.Fatalf("ChunkOffsets() not correct.")
+	}
+}
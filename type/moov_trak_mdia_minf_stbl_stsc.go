@@ -0,0 +1,169 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// StscEntry is one run of chunks that share the same layout.
+type StscEntry struct {
+	firstChunk             uint32
+	samplesPerChunk        uint32
+	sampleDescriptionIndex uint32
+}
+
+// FirstChunk is the (one-based) index of the first chunk in this run.
+func (se StscEntry) FirstChunk() uint32 {
+	return se.firstChunk
+}
+
+// SamplesPerChunk is the number of samples in each chunk of this run.
+func (se StscEntry) SamplesPerChunk() uint32 {
+	return se.samplesPerChunk
+}
+
+// SampleDescriptionIndex is the (one-based) index of the sample-entry in the
+// STSD box that describes the samples in this run.
+func (se StscEntry) SampleDescriptionIndex() uint32 {
+	return se.sampleDescriptionIndex
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (se StscEntry) InlineString() string {
+	return fmt.Sprintf(
+		"FIRST-CHUNK=(%d) SAMPLES-PER-CHUNK=(%d) SAMPLE-DESCRIPTION-INDEX=(%d)",
+		se.firstChunk, se.samplesPerChunk, se.sampleDescriptionIndex)
+}
+
+// StscBox is the "Sample To Chunk" box.
+type StscBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	entries []StscEntry
+}
+
+// Entries returns the entries.
+func (sb *StscBox) Entries() []StscEntry {
+	return sb.entries
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (sb *StscBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) ENTRIES=(%d)",
+		sb.Box.InlineString(), sb.Version(), sb.Flags(), len(sb.entries))
+}
+
+func (b *StscBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < 8 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 8, int64(len(data))))
+	}
+
+	count := bmfcommon.DefaultEndianness.Uint32(data[4:8])
+
+	err = b.CheckEntryCount("entries", int64(count), 8, 12)
+	log.PanicIf(err)
+
+	b.entries = make([]StscEntry, count)
+
+	offset := 8
+	for i := 0; i < int(count); i++ {
+		b.entries[i].firstChunk = bmfcommon.DefaultEndianness.Uint32(data[offset : offset+4])
+		b.entries[i].samplesPerChunk = bmfcommon.DefaultEndianness.Uint32(data[offset+4 : offset+8])
+		b.entries[i].sampleDescriptionIndex = bmfcommon.DefaultEndianness.Uint32(data[offset+8 : offset+12])
+
+		offset += 12
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (sb *StscBox) ExportFields() (fields map[string]interface{}, err error) {
+	entries := make([]map[string]interface{}, len(sb.entries))
+	for i, se := range sb.entries {
+		entries[i] = map[string]interface{}{
+			"first_chunk":              se.firstChunk,
+			"samples_per_chunk":        se.samplesPerChunk,
+			"sample_description_index": se.sampleDescriptionIndex,
+		}
+	}
+
+	fields = map[string]interface{}{
+		"entries": entries,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (sb *StscBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, sb.Version(), sb.Flags())
+	bmfcommon.PushBytes(&data, uint32(len(sb.entries)))
+
+	for _, entry := range sb.entries {
+		bmfcommon.PushBytes(&data, entry.firstChunk)
+		bmfcommon.PushBytes(&data, entry.samplesPerChunk)
+		bmfcommon.PushBytes(&data, entry.sampleDescriptionIndex)
+	}
+
+	return data, nil
+}
+
+type stscBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (stscBoxFactory) Name() string {
+	return "stsc"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (stscBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf stscBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	stscBox := &StscBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = stscBox.parse()
+	log.PanicIf(err)
+
+	return stscBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = stscBoxFactory{}
+	_ bmfcommon.CommonBox      = &StscBox{}
+	_ bmfcommon.FieldExporter  = &StscBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(stscBoxFactory{})
+}
@@ -0,0 +1,96 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestStscEntry_Accessors(t *testing.T) {
+	se := StscEntry{
+		firstChunk:             11,
+		samplesPerChunk:        22,
+		sampleDescriptionIndex: 33,
+	}
+
+	if se.FirstChunk() != 11 {
+		t.Fatalf("FirstChunk() not correct.")
+	} else if se.SamplesPerChunk() != 22 {
+		t.Fatalf("SamplesPerChunk() not correct.")
+	} else if se.SampleDescriptionIndex() != 33 {
+		t.Fatalf("SampleDescriptionIndex() not correct.")
+	}
+}
+
+func TestStscBox_Entries(t *testing.T) {
+	entries := []StscEntry{
+		{firstChunk: 1, samplesPerChunk: 2, sampleDescriptionIndex: 1},
+	}
+
+	sb := StscBox{
+		entries: entries,
+	}
+
+	if reflect.DeepEqual(sb.Entries(), entries) != true {
+		t.Fatalf("Entries() not correct.")
+	}
+}
+
+func TestStscBoxFactory_Name(t *testing.T) {
+	name := stscBoxFactory{}.Name()
+
+	if name != "stsc" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestStscBoxFactory_New(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	// entry-count
+	bmfcommon.PushBytes(&data, uint32(2))
+
+	// entry 1
+	bmfcommon.PushBytes(&data, uint32(1))
+	bmfcommon.PushBytes(&data, uint32(3))
+	bmfcommon.PushBytes(&data, uint32(1))
+
+	// entry 2
+	bmfcommon.PushBytes(&data, uint32(4))
+	bmfcommon.PushBytes(&data, uint32(2))
+	bmfcommon.PushBytes(&data, uint32(1))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "stsc", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := stscBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	stsc := cb.(*StscBox)
+
+	expected := []StscEntry{
+		{firstChunk: 1, samplesPerChunk: 3, sampleDescriptionIndex: 1},
+		{firstChunk: 4, samplesPerChunk: 2, sampleDescriptionIndex: 1},
+	}
+
+	if reflect.DeepEqual(stsc.Entries(), expected) != true {
+		t.Fatalf("Entries() not correct.")
+	}
+}
@@ -9,46 +9,41 @@ import (
 // StsdBox is the "Sample Description" box.
 type StsdBox struct {
 	bmfcommon.Box
-
-	version byte
-	flags   uint32
+	bmfcommon.FullBox
 
 	// LoadedBoxIndex contains this box's children.
 	bmfcommon.LoadedBoxIndex
 }
 
-// Version() returns the version of the record.
-func (sb *StsdBox) Version() byte {
-	return sb.version
+// SetLoadedBoxIndex sets the child boxes after a box has been manufactured
+// and the children have been parsed. This allows parent boxes to be
+// registered before the child boxes can look for them.
+func (stsd *StsdBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
+	fbi := boxes.Index()
+	stsd.LoadedBoxIndex = fbi
 }
 
-// Flags returns the flags.
-func (sb *StsdBox) Flags() uint32 {
-	return sb.flags
+// GetChildBoxes returns the child boxes of the given type.
+func (stsd *StsdBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return stsd.GetChildBoxesOf(stsd, name)
 }
 
-func (b *StsdBox) parse() (err error) {
+// EncodeData returns the payload of the box preceding its children. The entry
+// count is not modeled and is carried over.
+func (sb *StsdBox) EncodeData() (data []byte, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
-	data, err := b.Data()
+	original, err := sb.Data()
 	log.PanicIf(err)
 
-	b.version = data[0]
-	b.flags = bmfcommon.DefaultEndianness.Uint32(data[0:4])
+	pushVersionAndFlags(&data, sb.Version(), sb.Flags())
+	data = append(data, original[4:8]...)
 
-	return nil
-}
-
-// SetLoadedBoxIndex sets the child boxes after a box has been manufactured
-// and the children have been parsed. This allows parent boxes to be
-// registered before the child boxes can look for them.
-func (stsd *StsdBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
-	fbi := boxes.Index()
-	stsd.LoadedBoxIndex = fbi
+	return data, nil
 }
 
 type stsdBoxFactory struct {
@@ -59,27 +54,33 @@ func (stsdBoxFactory) Name() string {
 	return "stsd"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (stsdBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
 // New returns a new value instance.
-func (stsdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf stsdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	stsdBox := &StsdBox{
-		Box: box,
+		Box:     box,
+		FullBox: fb,
 	}
 
-	err = stsdBox.parse()
-	log.PanicIf(err)
-
 	return stsdBox, 8, nil
 }
 
 var (
-	_ bmfcommon.BoxFactory = stsdBoxFactory{}
-	_ bmfcommon.CommonBox  = &StsdBox{}
+	_ bmfcommon.FullBoxFactory = stsdBoxFactory{}
+	_ bmfcommon.CommonBox      = &StsdBox{}
 )
 
 func init() {
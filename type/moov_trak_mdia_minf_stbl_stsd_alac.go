@@ -0,0 +1,226 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// alacSpecificConfigSize is the size of the ALACSpecificConfig, after the
+	// version and flags.
+	alacSpecificConfigSize = 24
+)
+
+var (
+	// alacChannelLayouts are the default speaker positions by channel-count
+	// (from the ALAC reference implementation).
+	alacChannelLayouts = map[int][]AudioChannel{
+		1: {AudioChannelFrontCenter},
+		2: {AudioChannelFrontLeft, AudioChannelFrontRight},
+		3: {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight},
+		4: {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackCenter},
+		5: {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight},
+		6: {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight, AudioChannelLowFrequency},
+		7: {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight, AudioChannelBackCenter, AudioChannelLowFrequency},
+		8: {AudioChannelFrontCenter, AudioChannelFrontLeftOfCenter, AudioChannelFrontRightOfCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight, AudioChannelLowFrequency},
+	}
+)
+
+// AlacBox is the "alac" box that is the child of the "alac" sample-entry. It
+// has the ALACSpecificConfig (the "magic cookie").
+type AlacBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	frameLength       uint32
+	compatibleVersion uint8
+	bitDepth          uint8
+	pb                uint8
+	mb                uint8
+	kb                uint8
+	numChannels       uint8
+	maxRun            uint16
+	maxFrameBytes     uint32
+	avgBitRate        uint32
+	sampleRate        uint32
+}
+
+// FrameLength returns the number of samples per frame.
+func (ab *AlacBox) FrameLength() uint32 {
+	return ab.frameLength
+}
+
+// CompatibleVersion returns the version of the encoder. It is zero.
+func (ab *AlacBox) CompatibleVersion() uint8 {
+	return ab.compatibleVersion
+}
+
+// BitDepth returns the bit-depth of the decoded samples.
+func (ab *AlacBox) BitDepth() uint8 {
+	return ab.bitDepth
+}
+
+// RiceParameters returns the tuning parameters of the entropy coder.
+func (ab *AlacBox) RiceParameters() (pb, mb, kb uint8) {
+	return ab.pb, ab.mb, ab.kb
+}
+
+// MaxRun returns the maximum run. It is unused.
+func (ab *AlacBox) MaxRun() uint16 {
+	return ab.maxRun
+}
+
+// MaxFrameBytes returns the size of the largest frame or zero if not known.
+func (ab *AlacBox) MaxFrameBytes() uint32 {
+	return ab.maxFrameBytes
+}
+
+// AvgBitRate returns the average bitrate in bits-per-second or zero if not
+// known.
+func (ab *AlacBox) AvgBitRate() uint32 {
+	return ab.avgBitRate
+}
+
+// CodecString returns the RFC 6381 codec string ("alac").
+func (ab *AlacBox) CodecString(sampleEntryName string) string {
+	return "alac"
+}
+
+// ChannelCount returns the number of decoded channels.
+func (ab *AlacBox) ChannelCount() int {
+	return int(ab.numChannels)
+}
+
+// ChannelLayout returns the default speaker positions for the channel-count.
+// A "chan" box may override them, but it is not looked at.
+func (ab *AlacBox) ChannelLayout() []AudioChannel {
+	return alacChannelLayouts[int(ab.numChannels)]
+}
+
+// SampleRate returns the decoded sample-rate.
+func (ab *AlacBox) SampleRate() uint32 {
+	return ab.sampleRate
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (ab *AlacBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s FRAME-LENGTH=(%d) BIT-DEPTH=(%d) CHANNELS=(%d) SAMPLE-RATE=(%d)",
+		ab.Box.InlineString(), ab.frameLength, ab.bitDepth, ab.numChannels, ab.sampleRate)
+}
+
+func (b *AlacBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	// The version and flags were already checked.
+	data = data[4:]
+
+	if len(data) < alacSpecificConfigSize {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize()+4, alacSpecificConfigSize, int64(len(data))))
+	}
+
+	b.frameLength = bmfcommon.DefaultEndianness.Uint32(data[0:4])
+	b.compatibleVersion = data[4]
+	b.bitDepth = data[5]
+	b.pb = data[6]
+	b.mb = data[7]
+	b.kb = data[8]
+	b.numChannels = data[9]
+	b.maxRun = bmfcommon.DefaultEndianness.Uint16(data[10:12])
+	b.maxFrameBytes = bmfcommon.DefaultEndianness.Uint32(data[12:16])
+	b.avgBitRate = bmfcommon.DefaultEndianness.Uint32(data[16:20])
+	b.sampleRate = bmfcommon.DefaultEndianness.Uint32(data[20:24])
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (ab *AlacBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"frame_length":       ab.frameLength,
+		"compatible_version": ab.compatibleVersion,
+		"bit_depth":          ab.bitDepth,
+		"num_channels":       ab.numChannels,
+		"max_frame_bytes":    ab.maxFrameBytes,
+		"avg_bit_rate":       ab.avgBitRate,
+		"sample_rate":        ab.sampleRate,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (ab *AlacBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	bmfcommon.PushBytes(&data, uint32(ab.Version())<<24|ab.Flags()&0x00ffffff)
+	bmfcommon.PushBytes(&data, ab.frameLength)
+	bmfcommon.PushBytes(&data, []byte{ab.compatibleVersion, ab.bitDepth, ab.pb, ab.mb, ab.kb, ab.numChannels})
+	bmfcommon.PushBytes(&data, ab.maxRun)
+	bmfcommon.PushBytes(&data, ab.maxFrameBytes)
+	bmfcommon.PushBytes(&data, ab.avgBitRate)
+	bmfcommon.PushBytes(&data, ab.sampleRate)
+
+	return data, nil
+}
+
+type alacBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (alacBoxFactory) Name() string {
+	return "alac"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (alacBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf alacBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	alacBox := &AlacBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = alacBox.parse()
+	log.PanicIf(err)
+
+	return alacBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = alacBoxFactory{}
+	_ bmfcommon.CommonBox      = &AlacBox{}
+	_ bmfcommon.FieldExporter  = &AlacBox{}
+	_ AudioConfiguration       = &AlacBox{}
+)
+
+func init() {
+	// The sample-entry has the same name, so this only applies within it.
+	bmfcommon.RegisterBoxTypeWithParentPath("alac", alacBoxFactory{})
+}
@@ -0,0 +1,99 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// getTestAlacData returns an "alac" payload for 16-bit stereo at 44.1kHz.
+func getTestAlacData() []byte {
+	var data []byte
+
+	// Version and flags.
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	bmfcommon.PushBytes(&data, uint32(4096))
+	bmfcommon.PushBytes(&data, []byte{0, 16, 40, 10, 14, 2})
+	bmfcommon.PushBytes(&data, uint16(255))
+	bmfcommon.PushBytes(&data, uint32(0))
+	bmfcommon.PushBytes(&data, uint32(1411200))
+	bmfcommon.PushBytes(&data, uint32(44100))
+
+	return data
+}
+
+func TestAlacBoxFactory_Name(t *testing.T) {
+	if (alacBoxFactory{}).Name() != "alac" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestAlacBoxFactory_New(t *testing.T) {
+	data := getTestAlacData()
+
+	cb := getTestParsedBox(alacBoxFactory{}, data)
+	alac := cb.(*AlacBox)
+
+	pb, mb, kb := alac.RiceParameters()
+
+	if alac.FrameLength() != 4096 {
+		t.Fatalf("FrameLength() not correct.")
+	} else if alac.CompatibleVersion() != 0 {
+		t.Fatalf("CompatibleVersion() not correct.")
+	} else if alac.BitDepth() != 16 {
+		t.Fatalf("BitDepth() not correct.")
+	} else if pb != 40 || mb != 10 || kb != 14 {
+		t.Fatalf("RiceParameters() not correct.")
+	} else if alac.ChannelCount() != 2 {
+		t.Fatalf("ChannelCount() not correct.")
+	} else if alac.MaxRun() != 255 {
+		t.Fatalf("MaxRun() not correct.")
+	} else if alac.MaxFrameBytes() != 0 {
+		t.Fatalf("MaxFrameBytes() not correct.")
+	} else if alac.AvgBitRate() != 1411200 {
+		t.Fatalf("AvgBitRate() not correct.")
+	} else if alac.SampleRate() != 44100 {
+		t.Fatalf("SampleRate() not correct.")
+	} else if alac.CodecString("alac") != "alac" {
+		t.Fatalf("CodecString() not correct.")
+	}
+
+	expectedLayout := []AudioChannel{AudioChannelFrontLeft, AudioChannelFrontRight}
+	if reflect.DeepEqual(alac.ChannelLayout(), expectedLayout) != true {
+		t.Fatalf("ChannelLayout() not correct: %v", alac.ChannelLayout())
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestAlacBox_NestedInSampleEntry(t *testing.T) {
+	// The configuration box has the same name as the sample-entry.
+	ase := getTestAudioSampleEntryBox("alac", testMp4aData, "alac", getTestAlacData())
+
+	cc, err := ase.CodecConfiguration()
+	if err != nil {
+		t.Fatalf("CodecConfiguration() failed: [%s]", err.Error())
+	}
+
+	if _, ok := cc.(*AlacBox); ok != true {
+		t.Fatalf("Configuration not correct: [%v]", cc)
+	}
+
+	codec, err := ase.CodecString()
+	if err != nil {
+		t.Fatalf("CodecString() failed: [%s]", err.Error())
+	} else if codec != "alac" {
+		t.Fatalf("CodecString() not correct: [%s]", codec)
+	}
+}
+
+func TestAlacBoxFactory_New_Truncated(t *testing.T) {
+	data := getTestAlacData()
+
+	_, err := getTestBoxFactoryNew(alacBoxFactory{}, data[:len(data)-1])
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+}
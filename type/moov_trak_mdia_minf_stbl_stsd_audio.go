@@ -0,0 +1,383 @@
+package bmftype
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// audioSampleEntrySize is the size of the audio sample-entry fields, which
+	// precede the child boxes.
+	audioSampleEntrySize = sampleEntryHeaderSize + 20
+
+	// audioSampleEntryV1ExtensionSize is the size of the fields that follow in
+	// a QuickTime version-one sound description.
+	audioSampleEntryV1ExtensionSize = 16
+
+	// audioSampleEntryV2ExtensionSize is the size of the fields that follow in
+	// a QuickTime version-two sound description.
+	audioSampleEntryV2ExtensionSize = 36
+)
+
+var (
+	// audioSampleEntryNames are the sample-entry types that are parsed as
+	// audio sample-entries.
+	audioSampleEntryNames = []string{
+		"mp4a",
+		"Opus",
+		"fLaC",
+		"ac-3",
+		"ec-3",
+		"alac",
+	}
+)
+
+// AudioChannel identifies a speaker position.
+type AudioChannel string
+
+// Speaker positions. The names follow the common abbreviations (as used by
+// FFmpeg).
+const (
+	AudioChannelFrontLeft           AudioChannel = "FL"
+	AudioChannelFrontRight          AudioChannel = "FR"
+	AudioChannelFrontCenter         AudioChannel = "FC"
+	AudioChannelLowFrequency        AudioChannel = "LFE"
+	AudioChannelLowFrequency2       AudioChannel = "LFE2"
+	AudioChannelBackLeft            AudioChannel = "BL"
+	AudioChannelBackRight           AudioChannel = "BR"
+	AudioChannelBackCenter          AudioChannel = "BC"
+	AudioChannelFrontLeftOfCenter   AudioChannel = "FLC"
+	AudioChannelFrontRightOfCenter  AudioChannel = "FRC"
+	AudioChannelSideLeft            AudioChannel = "SL"
+	AudioChannelSideRight           AudioChannel = "SR"
+	AudioChannelTopCenter           AudioChannel = "TC"
+	AudioChannelTopFrontLeft        AudioChannel = "TFL"
+	AudioChannelTopFrontCenter      AudioChannel = "TFC"
+	AudioChannelTopFrontRight       AudioChannel = "TFR"
+	AudioChannelWideLeft            AudioChannel = "WL"
+	AudioChannelWideRight           AudioChannel = "WR"
+	AudioChannelSurroundDirectLeft  AudioChannel = "SDL"
+	AudioChannelSurroundDirectRight AudioChannel = "SDR"
+)
+
+// AudioConfiguration is implemented by the codec configurations of audio
+// sample-entries.
+type AudioConfiguration interface {
+	CodecConfiguration
+
+	// ChannelCount returns the number of decoded channels or zero if not
+	// known.
+	ChannelCount() int
+
+	// ChannelLayout returns the speaker positions in the order that the
+	// channels are decoded or nil if not known.
+	ChannelLayout() []AudioChannel
+
+	// SampleRate returns the decoded sample-rate or zero if not known.
+	SampleRate() uint32
+}
+
+// AudioSampleEntryBox is a sample-entry for audio (e.g. "mp4a"). The decoder
+// configuration is stored in a child box (e.g. "esds").
+//
+// The reserved fields are used by QuickTime for the version of the sound
+// description. Versions one and two add fields and are supported since they
+// are common in files from Apple software.
+type AudioSampleEntryBox struct {
+	bmfcommon.Box
+	SampleEntry
+
+	entryVersion uint16
+	channelCount uint32
+	sampleSize   uint16
+	sampleRate   uint32
+
+	// sampleRateFloat is the sample-rate of version-two entries.
+	sampleRateFloat float64
+
+	// LoadedBoxIndex contains this box's children.
+	bmfcommon.LoadedBoxIndex
+}
+
+// EntryVersion returns the QuickTime sound-description version (zero for ISO
+// files).
+func (ase *AudioSampleEntryBox) EntryVersion() uint16 {
+	return ase.entryVersion
+}
+
+// ChannelCount returns the channel-count from the sample-entry. For some
+// codecs this is just a placeholder (e.g. always two); see
+// OutputChannelCount().
+func (ase *AudioSampleEntryBox) ChannelCount() uint32 {
+	return ase.channelCount
+}
+
+// SampleSize returns the sample-size in bits from the sample-entry.
+func (ase *AudioSampleEntryBox) SampleSize() uint16 {
+	return ase.sampleSize
+}
+
+// SampleRate returns the sample-rate from the sample-entry. Rates above 65535
+// can not be represented except in version-two entries; see
+// OutputSampleRate().
+func (ase *AudioSampleEntryBox) SampleRate() uint32 {
+	if ase.entryVersion == 2 {
+		return uint32(math.Round(ase.sampleRateFloat))
+	}
+
+	return ase.sampleRate >> 16
+}
+
+// SetLoadedBoxIndex sets the child boxes after a box has been manufactured
+// and the children have been parsed. This allows parent boxes to be
+// registered before the child boxes can look for them.
+func (ase *AudioSampleEntryBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
+	fbi := boxes.Index()
+	ase.LoadedBoxIndex = fbi
+}
+
+// GetChildBoxes returns the child boxes of the given type.
+func (ase *AudioSampleEntryBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return ase.GetChildBoxesOf(ase, name)
+}
+
+// CodecConfiguration returns the child box that has the codec configuration.
+func (ase *AudioSampleEntryBox) CodecConfiguration() (ac AudioConfiguration, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	cc, err := codecConfiguration(ase.LoadedBoxIndex)
+	if err != nil {
+		if err == ErrNoCodecConfiguration {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	ac, ok := cc.(AudioConfiguration)
+	if ok != true {
+		return nil, ErrNoCodecConfiguration
+	}
+
+	return ac, nil
+}
+
+// CodecString returns the RFC 6381 codec string (e.g. "mp4a.40.2").
+func (ase *AudioSampleEntryBox) CodecString() (codec string, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	ac, err := ase.CodecConfiguration()
+	log.PanicIf(err)
+
+	return ac.CodecString(ase.Name()), nil
+}
+
+// OutputChannelCount returns the number of decoded channels. The codec
+// configuration is preferred over the sample-entry.
+func (ase *AudioSampleEntryBox) OutputChannelCount() int {
+	if ac, err := ase.CodecConfiguration(); err == nil {
+		if count := ac.ChannelCount(); count != 0 {
+			return count
+		}
+	}
+
+	return int(ase.channelCount)
+}
+
+// OutputSampleRate returns the decoded sample-rate. The codec configuration is
+// preferred over the sample-entry.
+func (ase *AudioSampleEntryBox) OutputSampleRate() uint32 {
+	if ac, err := ase.CodecConfiguration(); err == nil {
+		if sampleRate := ac.SampleRate(); sampleRate != 0 {
+			return sampleRate
+		}
+	}
+
+	return ase.SampleRate()
+}
+
+// ChannelLayout returns the speaker positions in the order that the channels
+// are decoded or nil if not known.
+func (ase *AudioSampleEntryBox) ChannelLayout() []AudioChannel {
+	if ac, err := ase.CodecConfiguration(); err == nil {
+		return ac.ChannelLayout()
+	}
+
+	return nil
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (ase *AudioSampleEntryBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s DATA-REF-INDEX=(%d) CHANNELS=(%d) SAMPLE-SIZE=(%d) SAMPLE-RATE=(%d)",
+		ase.Box.InlineString(), ase.dataReferenceIndex, ase.channelCount, ase.sampleSize, ase.SampleRate())
+}
+
+// entrySize returns the size of the fields preceding the child boxes.
+func (ase *AudioSampleEntryBox) entrySize() int {
+	switch ase.entryVersion {
+	case 1:
+		return audioSampleEntrySize + audioSampleEntryV1ExtensionSize
+	case 2:
+		return audioSampleEntrySize + audioSampleEntryV2ExtensionSize
+	}
+
+	return audioSampleEntrySize
+}
+
+func (b *AudioSampleEntryBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < audioSampleEntrySize {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), audioSampleEntrySize, int64(len(data))))
+	}
+
+	b.SampleEntry, err = parseSampleEntry(data)
+	log.PanicIf(err)
+
+	fields := data[sampleEntryHeaderSize:]
+
+	b.entryVersion = bmfcommon.DefaultEndianness.Uint16(fields[0:2])
+	if b.entryVersion > 2 {
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(b, uint8(b.entryVersion), []byte{0, 1, 2}))
+	}
+
+	b.channelCount = uint32(bmfcommon.DefaultEndianness.Uint16(fields[8:10]))
+	b.sampleSize = bmfcommon.DefaultEndianness.Uint16(fields[10:12])
+	b.sampleRate = bmfcommon.DefaultEndianness.Uint32(fields[16:20])
+
+	entrySize := b.entrySize()
+	if len(data) < entrySize {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize()+audioSampleEntrySize, int64(entrySize-audioSampleEntrySize), int64(len(data)-audioSampleEntrySize)))
+	}
+
+	if b.entryVersion == 2 {
+		extension := data[audioSampleEntrySize:]
+
+		b.sampleRateFloat = math.Float64frombits(bmfcommon.DefaultEndianness.Uint64(extension[4:12]))
+		b.channelCount = bmfcommon.DefaultEndianness.Uint32(extension[12:16])
+		b.sampleSize = uint16(bmfcommon.DefaultEndianness.Uint32(extension[20:24]))
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (ase *AudioSampleEntryBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"data_reference_index": ase.dataReferenceIndex,
+		"entry_version":        ase.entryVersion,
+		"channel_count":        ase.channelCount,
+		"sample_size":          ase.sampleSize,
+		"sample_rate":          ase.SampleRate(),
+	}
+
+	if codec, err := ase.CodecString(); err == nil {
+		fields["codec"] = codec
+		fields["output_channel_count"] = ase.OutputChannelCount()
+		fields["output_sample_rate"] = ase.OutputSampleRate()
+
+		if layout := ase.ChannelLayout(); layout != nil {
+			fields["channel_layout"] = layout
+		}
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box preceding its children. Bytes that
+// are not modeled are carried over.
+func (ase *AudioSampleEntryBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := ase.Data()
+	log.PanicIf(err)
+
+	entrySize := ase.entrySize()
+
+	data = make([]byte, entrySize)
+	copy(data, original)
+
+	bmfcommon.DefaultEndianness.PutUint16(data[6:8], ase.dataReferenceIndex)
+
+	fields := data[sampleEntryHeaderSize:]
+
+	bmfcommon.DefaultEndianness.PutUint16(fields[0:2], ase.entryVersion)
+
+	if ase.entryVersion == 2 {
+		extension := data[audioSampleEntrySize:]
+
+		bmfcommon.DefaultEndianness.PutUint64(extension[4:12], math.Float64bits(ase.sampleRateFloat))
+		bmfcommon.DefaultEndianness.PutUint32(extension[12:16], ase.channelCount)
+		bmfcommon.DefaultEndianness.PutUint32(extension[20:24], uint32(ase.sampleSize))
+	} else {
+		bmfcommon.DefaultEndianness.PutUint16(fields[8:10], uint16(ase.channelCount))
+		bmfcommon.DefaultEndianness.PutUint16(fields[10:12], ase.sampleSize)
+		bmfcommon.DefaultEndianness.PutUint32(fields[16:20], ase.sampleRate)
+	}
+
+	return data, nil
+}
+
+type audioSampleEntryBoxFactory struct {
+	name string
+}
+
+// Name returns the name of the type.
+func (bf audioSampleEntryBoxFactory) Name() string {
+	return bf.name
+}
+
+// New returns a new value instance.
+func (audioSampleEntryBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	aseBox := &AudioSampleEntryBox{
+		Box: box,
+	}
+
+	err = aseBox.parse()
+	log.PanicIf(err)
+
+	return aseBox, aseBox.entrySize(), nil
+}
+
+var (
+	_ bmfcommon.BoxFactory          = audioSampleEntryBoxFactory{}
+	_ bmfcommon.CommonBox           = &AudioSampleEntryBox{}
+	_ bmfcommon.FieldExporter       = &AudioSampleEntryBox{}
+	_ bmfcommon.ChildBoxIndexSetter = &AudioSampleEntryBox{}
+)
+
+func init() {
+	for _, name := range audioSampleEntryNames {
+		bmfcommon.RegisterBoxType(audioSampleEntryBoxFactory{name: name})
+	}
+}
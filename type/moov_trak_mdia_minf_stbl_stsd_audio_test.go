@@ -0,0 +1,274 @@
+package bmftype
+
+import (
+	"encoding/hex"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+var (
+	// testMp4aData is the "mp4a" payload preceding the child boxes from the
+	// tears-of-steel asset.
+	testMp4aData, _ = hex.DecodeString("000000000000000100000000000000000002001000000000ac440000")
+)
+
+// getTestAudioSampleEntryBox parses a sample-entry with the given name, fields,
+// and configuration box.
+func getTestAudioSampleEntryBox(name string, entryData []byte, configurationName string, configurationData []byte) *AudioSampleEntryBox {
+	data := make([]byte, len(entryData))
+	copy(data, entryData)
+
+	if configurationName != "" {
+		bmfcommon.PushBox(&data, configurationName, configurationData)
+	}
+
+	var b []byte
+	bmfcommon.PushBox(&b, name, data)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes(name)
+	log.PanicIf(err)
+
+	return boxes[0].(*AudioSampleEntryBox)
+}
+
+func TestAudioSampleEntryBoxFactory_Name(t *testing.T) {
+	for _, name := range audioSampleEntryNames {
+		if bmfcommon.GetFactory(name) == nil {
+			t.Fatalf("Factory not registered: [%s]", name)
+		}
+	}
+
+	if (audioSampleEntryBoxFactory{name: "ec-3"}).Name() != "ec-3" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestAudioSampleEntryBoxFactory_New(t *testing.T) {
+	ase := getTestAudioSampleEntryBox("mp4a", testMp4aData, "esds", testEsdsData)
+
+	if ase.DataReferenceIndex() != 1 {
+		t.Fatalf("DataReferenceIndex() not correct.")
+	} else if ase.EntryVersion() != 0 {
+		t.Fatalf("EntryVersion() not correct.")
+	} else if ase.ChannelCount() != 2 {
+		t.Fatalf("ChannelCount() not correct.")
+	} else if ase.SampleSize() != 16 {
+		t.Fatalf("SampleSize() not correct.")
+	} else if ase.SampleRate() != 44100 {
+		t.Fatalf("SampleRate() not correct.")
+	}
+
+	cc, err := ase.CodecConfiguration()
+	log.PanicIf(err)
+
+	if _, ok := cc.(*EsdsBox); ok != true {
+		t.Fatalf("CodecConfiguration() not correct.")
+	}
+
+	codec, err := ase.CodecString()
+	log.PanicIf(err)
+
+	if codec != "mp4a.40.2" {
+		t.Fatalf("CodecString() not correct: [%s]", codec)
+	} else if ase.OutputChannelCount() != 2 {
+		t.Fatalf("OutputChannelCount() not correct.")
+	} else if ase.OutputSampleRate() != 44100 {
+		t.Fatalf("OutputSampleRate() not correct.")
+	}
+
+	expectedLayout := []AudioChannel{AudioChannelFrontLeft, AudioChannelFrontRight}
+	if reflect.DeepEqual(ase.ChannelLayout(), expectedLayout) != true {
+		t.Fatalf("ChannelLayout() not correct: %v", ase.ChannelLayout())
+	}
+
+	assertEncodeData(t, ase, testMp4aData)
+}
+
+func TestAudioSampleEntryBoxFactory_New_Ac3(t *testing.T) {
+	// 48kHz, 3/2 with LFE.
+	ase := getTestAudioSampleEntryBox("ac-3", testMp4aData, "dac3", []byte{0x10, 0x3d, 0xc0})
+
+	cc, err := ase.CodecConfiguration()
+	log.PanicIf(err)
+
+	if _, ok := cc.(*Dac3Box); ok != true {
+		t.Fatalf("CodecConfiguration() not correct.")
+	}
+
+	codec, err := ase.CodecString()
+	log.PanicIf(err)
+
+	if codec != "ac-3" {
+		t.Fatalf("CodecString() not correct: [%s]", codec)
+	} else if ase.OutputChannelCount() != 6 {
+		t.Fatalf("OutputChannelCount() not correct: (%d)", ase.OutputChannelCount())
+	}
+}
+
+func TestAudioSampleEntryBox_InlineString(t *testing.T) {
+	ase := getTestAudioSampleEntryBox("mp4a", testMp4aData, "", nil)
+
+	if ase.InlineString() != "NAME=[mp4a] PARENT=[ROOT] START=(0x0000000000000000) SIZE=(36) DATA-REF-INDEX=(1) CHANNELS=(2) SAMPLE-SIZE=(16) SAMPLE-RATE=(44100)" {
+		t.Fatalf("InlineString() not correct: [%s]", ase.InlineString())
+	}
+}
+
+func TestAudioSampleEntryBox_NoConfiguration(t *testing.T) {
+	ase := getTestAudioSampleEntryBox("mp4a", testMp4aData, "", nil)
+
+	_, err := ase.CodecString()
+	if err == nil {
+		t.Fatalf("Expected error.")
+	} else if log.Is(err, ErrNoCodecConfiguration) != true {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	// Falls back to the sample-entry.
+	if ase.OutputChannelCount() != 2 {
+		t.Fatalf("OutputChannelCount() not correct.")
+	} else if ase.OutputSampleRate() != 44100 {
+		t.Fatalf("OutputSampleRate() not correct.")
+	} else if ase.ChannelLayout() != nil {
+		t.Fatalf("ChannelLayout() not correct.")
+	}
+}
+
+func TestAudioSampleEntryBox_Version1(t *testing.T) {
+	data := make([]byte, len(testMp4aData))
+	copy(data, testMp4aData)
+
+	data[9] = 1
+
+	// samplesPerPacket, bytesPerPacket, bytesPerFrame, bytesPerSample
+	bmfcommon.PushBytes(&data, []byte{0, 0, 4, 0, 0, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0, 2})
+
+	ase := getTestAudioSampleEntryBox("mp4a", data, "esds", testEsdsData)
+
+	if ase.EntryVersion() != 1 {
+		t.Fatalf("EntryVersion() not correct.")
+	} else if ase.SampleRate() != 44100 {
+		t.Fatalf("SampleRate() not correct.")
+	}
+
+	// The child must have been found after the extension.
+	codec, err := ase.CodecString()
+	log.PanicIf(err)
+
+	if codec != "mp4a.40.2" {
+		t.Fatalf("CodecString() not correct: [%s]", codec)
+	}
+
+	assertEncodeData(t, ase, data)
+}
+
+func TestAudioSampleEntryBox_Version2(t *testing.T) {
+	data := make([]byte, len(testMp4aData))
+	copy(data, testMp4aData)
+
+	data[9] = 2
+
+	// sizeOfStructOnly
+	bmfcommon.PushBytes(&data, uint32(72))
+
+	// audioSampleRate
+	bmfcommon.PushBytes(&data, math.Float64bits(96000))
+
+	// numAudioChannels
+	bmfcommon.PushBytes(&data, uint32(6))
+
+	// always7F000000
+	bmfcommon.PushBytes(&data, uint32(0x7f000000))
+
+	// constBitsPerChannel
+	bmfcommon.PushBytes(&data, uint32(24))
+
+	// formatSpecificFlags, constBytesPerAudioPacket,
+	// constLPCMFramesPerAudioPacket
+	bmfcommon.PushBytes(&data, make([]byte, 12))
+
+	ase := getTestAudioSampleEntryBox("alac", data, "", nil)
+
+	if ase.EntryVersion() != 2 {
+		t.Fatalf("EntryVersion() not correct.")
+	} else if ase.SampleRate() != 96000 {
+		t.Fatalf("SampleRate() not correct: (%d)", ase.SampleRate())
+	} else if ase.ChannelCount() != 6 {
+		t.Fatalf("ChannelCount() not correct.")
+	} else if ase.SampleSize() != 24 {
+		t.Fatalf("SampleSize() not correct.")
+	}
+
+	assertEncodeData(t, ase, data)
+}
+
+func TestAudioSampleEntryBoxFactory_New_Invalid(t *testing.T) {
+	_, err := getTestBoxFactoryNew(audioSampleEntryBoxFactory{name: "mp4a"}, testMp4aData[:20])
+	if err == nil {
+		t.Fatalf("Expected error for truncated entry.")
+	}
+
+	data := make([]byte, len(testMp4aData))
+	copy(data, testMp4aData)
+
+	data[9] = 1
+
+	_, err = getTestBoxFactoryNew(audioSampleEntryBoxFactory{name: "mp4a"}, data)
+	if err == nil {
+		t.Fatalf("Expected error for truncated version-one entry.")
+	}
+
+	data[9] = 3
+
+	_, err = getTestBoxFactoryNew(audioSampleEntryBoxFactory{name: "mp4a"}, data)
+	if err == nil {
+		t.Fatalf("Expected error for unsupported version.")
+	}
+}
+
+func TestAudioSampleEntryBox_Asset(t *testing.T) {
+	f, err := os.Open(getTestAssetFilepath("tears-of-steel.mp4"))
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+	log.PanicIf(err)
+
+	resource, err := bmfcommon.NewResource(f, fi.Size())
+	log.PanicIf(err)
+
+	cb, found := resource.Index()[bmfcommon.IndexedBoxEntry{NamePhrase: "moov.trak.mdia.minf.stbl.stsd.mp4a", SequenceNumber: 0}]
+	if found != true {
+		t.Fatalf("Sample-entry not found.")
+	}
+
+	ase := cb.(*AudioSampleEntryBox)
+
+	codec, err := ase.CodecString()
+	log.PanicIf(err)
+
+	if codec != "mp4a.40.2" {
+		t.Fatalf("CodecString() not correct: [%s]", codec)
+	}
+
+	fields, err := ase.ExportFields()
+	log.PanicIf(err)
+
+	if fields["codec"] != "mp4a.40.2" {
+		t.Fatalf("Exported codec not correct: %v", fields["codec"])
+	} else if fields["output_channel_count"] != 2 {
+		t.Fatalf("Exported channel-count not correct: %v", fields["output_channel_count"])
+	}
+}
@@ -0,0 +1,232 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// av1CHeaderSize is the size of the fixed part of the record.
+	av1CHeaderSize = 4
+)
+
+// Av1CBox is the "AV1 Codec Configuration" box. It has the
+// AV1CodecConfigurationRecord from the AV1 ISOBMFF binding.
+type Av1CBox struct {
+	bmfcommon.Box
+
+	version                        uint8
+	profile                        uint8
+	level                          uint8
+	tier                           uint8
+	highBitDepth                   bool
+	twelveBit                      bool
+	monochrome                     bool
+	chromaSubsamplingX             bool
+	chromaSubsamplingY             bool
+	chromaSamplePosition           uint8
+	hasInitialPresentationDelay    bool
+	initialPresentationDelayMinus1 uint8
+	configObus                     []byte
+}
+
+// Version returns the version of the record. It is always one.
+func (ab *Av1CBox) Version() uint8 {
+	return ab.version
+}
+
+// Profile returns the sequence profile (seq_profile).
+func (ab *Av1CBox) Profile() uint8 {
+	return ab.profile
+}
+
+// Level returns the level of the first operating point (seq_level_idx_0).
+func (ab *Av1CBox) Level() uint8 {
+	return ab.level
+}
+
+// Tier returns the tier of the first operating point (seq_tier_0).
+func (ab *Av1CBox) Tier() uint8 {
+	return ab.tier
+}
+
+// BitDepth returns the bit-depth (8, 10, or 12).
+func (ab *Av1CBox) BitDepth() int {
+	if ab.highBitDepth == false {
+		return 8
+	} else if ab.twelveBit == true {
+		return 12
+	}
+
+	return 10
+}
+
+// Monochrome returns true if there is no chroma.
+func (ab *Av1CBox) Monochrome() bool {
+	return ab.monochrome
+}
+
+// ChromaSubsamplingX returns true if chroma is subsampled horizontally.
+func (ab *Av1CBox) ChromaSubsamplingX() bool {
+	return ab.chromaSubsamplingX
+}
+
+// ChromaSubsamplingY returns true if chroma is subsampled vertically.
+func (ab *Av1CBox) ChromaSubsamplingY() bool {
+	return ab.chromaSubsamplingY
+}
+
+// ChromaSamplePosition returns the chroma_sample_position.
+func (ab *Av1CBox) ChromaSamplePosition() uint8 {
+	return ab.chromaSamplePosition
+}
+
+// InitialPresentationDelay returns the number of samples to buffer before
+// presenting the first one and whether it is known.
+func (ab *Av1CBox) InitialPresentationDelay() (delay int, found bool) {
+	if ab.hasInitialPresentationDelay == false {
+		return 0, false
+	}
+
+	return int(ab.initialPresentationDelayMinus1) + 1, true
+}
+
+// ConfigObus returns the configuration OBUs (e.g. the sequence header), if
+// any.
+func (ab *Av1CBox) ConfigObus() []byte {
+	return ab.configObus
+}
+
+// CodecString returns the RFC 6381 codec string in the short form from the AV1
+// ISOBMFF binding (e.g. "av01.0.04M.08").
+func (ab *Av1CBox) CodecString(sampleEntryName string) string {
+	tier := "M"
+	if ab.tier == 1 {
+		tier = "H"
+	}
+
+	return fmt.Sprintf("%s.%d.%02d%s.%02d", sampleEntryName, ab.profile, ab.level, tier, ab.BitDepth())
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (ab *Av1CBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s PROFILE=(%d) LEVEL=(%d) TIER=(%d) BIT-DEPTH=(%d) MONO=[%v]",
+		ab.Box.InlineString(), ab.profile, ab.level, ab.tier, ab.BitDepth(), ab.monochrome)
+}
+
+func (b *Av1CBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < av1CHeaderSize {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), av1CHeaderSize, int64(len(data))))
+	}
+
+	if data[0]&0x80 == 0 {
+		log.Panicf("av1C: marker bit not set")
+	}
+
+	b.version = data[0] & 0x7f
+	b.profile = data[1] >> 5
+	b.level = data[1] & 0x1f
+	b.tier = data[2] >> 7
+	b.highBitDepth = data[2]&0x40 != 0
+	b.twelveBit = data[2]&0x20 != 0
+	b.monochrome = data[2]&0x10 != 0
+	b.chromaSubsamplingX = data[2]&0x08 != 0
+	b.chromaSubsamplingY = data[2]&0x04 != 0
+	b.chromaSamplePosition = data[2] & 0x03
+	b.hasInitialPresentationDelay = data[3]&0x10 != 0
+	b.initialPresentationDelayMinus1 = data[3] & 0x0f
+
+	b.configObus = data[av1CHeaderSize:]
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (ab *Av1CBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"version":                ab.version,
+		"profile":                ab.profile,
+		"level":                  ab.level,
+		"tier":                   ab.tier,
+		"bit_depth":              ab.BitDepth(),
+		"monochrome":             ab.monochrome,
+		"chroma_subsampling_x":   ab.chromaSubsamplingX,
+		"chroma_subsampling_y":   ab.chromaSubsamplingY,
+		"chroma_sample_position": ab.chromaSamplePosition,
+		"config_obus_size":       len(ab.configObus),
+	}
+
+	if delay, found := ab.InitialPresentationDelay(); found == true {
+		fields["initial_presentation_delay"] = delay
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. The record is carried over as
+// read.
+func (ab *Av1CBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := ab.Data()
+	log.PanicIf(err)
+
+	data = make([]byte, len(original))
+	copy(data, original)
+
+	return data, nil
+}
+
+type av1CBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (av1CBoxFactory) Name() string {
+	return "av1C"
+}
+
+// New returns a new value instance.
+func (av1CBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	av1CBox := &Av1CBox{
+		Box: box,
+	}
+
+	err = av1CBox.parse()
+	log.PanicIf(err)
+
+	return av1CBox, -1, nil
+}
+
+var (
+	_ bmfcommon.BoxFactory    = av1CBoxFactory{}
+	_ bmfcommon.CommonBox     = &Av1CBox{}
+	_ bmfcommon.FieldExporter = &Av1CBox{}
+	_ CodecConfiguration      = &Av1CBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(av1CBoxFactory{})
+}
@@ -0,0 +1,90 @@
+package bmftype
+
+import (
+	"testing"
+)
+
+func TestAv1CBoxFactory_Name(t *testing.T) {
+	if (av1CBoxFactory{}).Name() != "av1C" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestAv1CBoxFactory_New(t *testing.T) {
+	// Main profile, level 4 (3.0), Main tier, 8-bit 4:2:0, and an initial
+	// presentation-delay of three. One byte of config OBUs follows.
+	data := []byte{0x81, 0x04, 0x0c, 0x12, 0x0a}
+
+	cb := getTestParsedBox(av1CBoxFactory{}, data)
+	av1C := cb.(*Av1CBox)
+
+	if av1C.Version() != 1 {
+		t.Fatalf("Version() not correct.")
+	} else if av1C.Profile() != 0 {
+		t.Fatalf("Profile() not correct.")
+	} else if av1C.Level() != 4 {
+		t.Fatalf("Level() not correct.")
+	} else if av1C.Tier() != 0 {
+		t.Fatalf("Tier() not correct.")
+	} else if av1C.BitDepth() != 8 {
+		t.Fatalf("BitDepth() not correct.")
+	} else if av1C.Monochrome() != false {
+		t.Fatalf("Monochrome() not correct.")
+	} else if av1C.ChromaSubsamplingX() != true || av1C.ChromaSubsamplingY() != true {
+		t.Fatalf("Chroma subsampling not correct.")
+	} else if av1C.ChromaSamplePosition() != 0 {
+		t.Fatalf("ChromaSamplePosition() not correct.")
+	} else if len(av1C.ConfigObus()) != 1 {
+		t.Fatalf("ConfigObus() not correct.")
+	}
+
+	delay, found := av1C.InitialPresentationDelay()
+	if found != true || delay != 3 {
+		t.Fatalf("InitialPresentationDelay() not correct: (%d) [%v]", delay, found)
+	}
+
+	if av1C.CodecString("av01") != "av01.0.04M.08" {
+		t.Fatalf("CodecString() not correct: [%s]", av1C.CodecString("av01"))
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestAv1CBox_BitDepth(t *testing.T) {
+	ab := &Av1CBox{
+		highBitDepth: true,
+	}
+
+	if ab.BitDepth() != 10 {
+		t.Fatalf("10-bit not correct.")
+	}
+
+	ab.twelveBit = true
+
+	if ab.BitDepth() != 12 {
+		t.Fatalf("12-bit not correct.")
+	}
+}
+
+func TestAv1CBox_CodecString_HighTier(t *testing.T) {
+	// High profile, level 13 (5.1), High tier, 10-bit.
+	data := []byte{0x81, 0x2d, 0xc0, 0x00}
+
+	av1C := getTestParsedBox(av1CBoxFactory{}, data).(*Av1CBox)
+
+	if av1C.CodecString("av01") != "av01.1.13H.10" {
+		t.Fatalf("CodecString() not correct: [%s]", av1C.CodecString("av01"))
+	}
+}
+
+func TestAv1CBoxFactory_New_Invalid(t *testing.T) {
+	_, err := getTestBoxFactoryNew(av1CBoxFactory{}, []byte{0x81, 0x04})
+	if err == nil {
+		t.Fatalf("Expected error for truncated record.")
+	}
+
+	_, err = getTestBoxFactoryNew(av1CBoxFactory{}, []byte{0x01, 0x04, 0x0c, 0x00})
+	if err == nil {
+		t.Fatalf("Expected error for missing marker.")
+	}
+}
@@ -0,0 +1,297 @@
+package bmftype
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// AvcCBox is the "AVC Configuration" box. It has the
+// AVCDecoderConfigurationRecord for H.264 (ISO/IEC 14496-15).
+type AvcCBox struct {
+	bmfcommon.Box
+
+	configurationVersion uint8
+	profile              uint8
+	profileCompatibility uint8
+	level                uint8
+	lengthSizeMinusOne   uint8
+
+	sequenceParameterSets [][]byte
+	pictureParameterSets  [][]byte
+
+	hasExtension                   bool
+	chromaFormat                   uint8
+	bitDepthLumaMinus8             uint8
+	bitDepthChromaMinus8           uint8
+	sequenceParameterSetExtensions [][]byte
+}
+
+// ConfigurationVersion returns the version of the record. It is always one.
+func (ab *AvcCBox) ConfigurationVersion() uint8 {
+	return ab.configurationVersion
+}
+
+// Profile returns the profile (profile_idc, e.g. 100 for High).
+func (ab *AvcCBox) Profile() uint8 {
+	return ab.profile
+}
+
+// ProfileCompatibility returns the byte with the constraint-set flags that
+// follows the profile in the SPS.
+func (ab *AvcCBox) ProfileCompatibility() uint8 {
+	return ab.profileCompatibility
+}
+
+// Level returns the level (level_idc, e.g. 31 for 3.1).
+func (ab *AvcCBox) Level() uint8 {
+	return ab.level
+}
+
+// NalUnitLengthSize returns the size of the length that precedes each NAL
+// unit in the samples.
+func (ab *AvcCBox) NalUnitLengthSize() int {
+	return int(ab.lengthSizeMinusOne) + 1
+}
+
+// SequenceParameterSets returns the SPS NAL units.
+func (ab *AvcCBox) SequenceParameterSets() [][]byte {
+	return ab.sequenceParameterSets
+}
+
+// PictureParameterSets returns the PPS NAL units.
+func (ab *AvcCBox) PictureParameterSets() [][]byte {
+	return ab.pictureParameterSets
+}
+
+// HasExtension returns true if the record has the chroma-format, bit-depth, and
+// SPS-extension fields that can follow for the High profiles.
+func (ab *AvcCBox) HasExtension() bool {
+	return ab.hasExtension
+}
+
+// ChromaFormat returns the chroma format (chroma_format_idc). Without the
+// extension, this is one (4:2:0).
+func (ab *AvcCBox) ChromaFormat() uint8 {
+	if ab.hasExtension == false {
+		return 1
+	}
+
+	return ab.chromaFormat
+}
+
+// BitDepthLuma returns the bit-depth of luma samples. Without the extension,
+// this is eight.
+func (ab *AvcCBox) BitDepthLuma() int {
+	return int(ab.bitDepthLumaMinus8) + 8
+}
+
+// BitDepthChroma returns the bit-depth of chroma samples. Without the
+// extension, this is eight.
+func (ab *AvcCBox) BitDepthChroma() int {
+	return int(ab.bitDepthChromaMinus8) + 8
+}
+
+// SequenceParameterSetExtensions returns the SPS-extension NAL units.
+func (ab *AvcCBox) SequenceParameterSetExtensions() [][]byte {
+	return ab.sequenceParameterSetExtensions
+}
+
+// CodecString returns the RFC 6381 codec string (e.g. "avc1.64001f").
+func (ab *AvcCBox) CodecString(sampleEntryName string) string {
+	return fmt.Sprintf("%s.%02x%02x%02x", sampleEntryName, ab.profile, ab.profileCompatibility, ab.level)
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (ab *AvcCBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s PROFILE=(%d) COMPAT=(0x%02x) LEVEL=(%d) SPS=(%d) PPS=(%d)",
+		ab.Box.InlineString(), ab.profile, ab.profileCompatibility, ab.level,
+		len(ab.sequenceParameterSets), len(ab.pictureParameterSets))
+}
+
+// hasAvcExtension returns true for the profiles whose configuration may be
+// followed by the extension fields.
+func hasAvcExtension(profile uint8) bool {
+	return profile == 100 || profile == 110 || profile == 122 || profile == 144
+}
+
+// readNalUnits reads the given number of NAL units, each preceded by a 16-bit
+// length.
+func readNalUnits(r *bytes.Reader, count int) (nalUnits [][]byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	// Each unit has at least its length.
+	if count*2 > r.Len() {
+		log.Panicf("(%d) NAL units can not fit in (%d) bytes", count, r.Len())
+	}
+
+	nalUnits = make([][]byte, count)
+
+	for i := 0; i < count; i++ {
+		var length uint16
+
+		err := binary.Read(r, bmfcommon.DefaultEndianness, &length)
+		log.PanicIf(err)
+
+		if int(length) > r.Len() {
+			log.Panicf("NAL unit (%d) of (%d) bytes is truncated: (%d)", i, length, r.Len())
+		}
+
+		nalUnits[i] = make([]byte, length)
+
+		_, err = io.ReadFull(r, nalUnits[i])
+		log.PanicIf(err)
+	}
+
+	return nalUnits, nil
+}
+
+// hexStrings returns the bytes of each unit as a hex string.
+func hexStrings(units [][]byte) []string {
+	encoded := make([]string, len(units))
+	for i, unit := range units {
+		encoded[i] = hex.EncodeToString(unit)
+	}
+
+	return encoded
+}
+
+func (b *AvcCBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	r := bytes.NewReader(data)
+
+	var header [6]byte
+
+	_, err = io.ReadFull(r, header[:])
+	log.PanicIf(err)
+
+	b.configurationVersion = header[0]
+	b.profile = header[1]
+	b.profileCompatibility = header[2]
+	b.level = header[3]
+	b.lengthSizeMinusOne = header[4] & 0x03
+
+	b.sequenceParameterSets, err = readNalUnits(r, int(header[5]&0x1f))
+	log.PanicIf(err)
+
+	ppsCount, err := r.ReadByte()
+	log.PanicIf(err)
+
+	b.pictureParameterSets, err = readNalUnits(r, int(ppsCount))
+	log.PanicIf(err)
+
+	// Many encoders omit the extension even for the profiles that allow it.
+
+	if hasAvcExtension(b.profile) == true && r.Len() >= 4 {
+		var extension [4]byte
+
+		_, err = io.ReadFull(r, extension[:])
+		log.PanicIf(err)
+
+		b.hasExtension = true
+		b.chromaFormat = extension[0] & 0x03
+		b.bitDepthLumaMinus8 = extension[1] & 0x07
+		b.bitDepthChromaMinus8 = extension[2] & 0x07
+
+		b.sequenceParameterSetExtensions, err = readNalUnits(r, int(extension[3]))
+		log.PanicIf(err)
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (ab *AvcCBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"configuration_version":   ab.configurationVersion,
+		"profile":                 ab.profile,
+		"profile_compatibility":   ab.profileCompatibility,
+		"level":                   ab.level,
+		"nal_unit_length_size":    ab.NalUnitLengthSize(),
+		"sequence_parameter_sets": hexStrings(ab.sequenceParameterSets),
+		"picture_parameter_sets":  hexStrings(ab.pictureParameterSets),
+		"chroma_format":           ab.ChromaFormat(),
+		"bit_depth_luma":          ab.BitDepthLuma(),
+		"bit_depth_chroma":        ab.BitDepthChroma(),
+	}
+
+	if ab.hasExtension == true {
+		fields["sequence_parameter_set_extensions"] = hexStrings(ab.sequenceParameterSetExtensions)
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. The record is carried over as
+// read.
+func (ab *AvcCBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := ab.Data()
+	log.PanicIf(err)
+
+	data = make([]byte, len(original))
+	copy(data, original)
+
+	return data, nil
+}
+
+type avcCBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (avcCBoxFactory) Name() string {
+	return "avcC"
+}
+
+// New returns a new value instance.
+func (avcCBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	avcCBox := &AvcCBox{
+		Box: box,
+	}
+
+	err = avcCBox.parse()
+	log.PanicIf(err)
+
+	return avcCBox, -1, nil
+}
+
+var (
+	_ bmfcommon.BoxFactory    = avcCBoxFactory{}
+	_ bmfcommon.CommonBox     = &AvcCBox{}
+	_ bmfcommon.FieldExporter = &AvcCBox{}
+	_ CodecConfiguration      = &AvcCBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(avcCBoxFactory{})
+}
@@ -0,0 +1,119 @@
+package bmftype
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+)
+
+var (
+	// testAvcCData is the "avcC" payload from the tears-of-steel asset.
+	testAvcCData, _ = hex.DecodeString("01640028ffe1001a67640028acd94078065b011000000300100000030300f183196001000568caecb22c")
+)
+
+func TestAvcCBoxFactory_Name(t *testing.T) {
+	if (avcCBoxFactory{}).Name() != "avcC" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestAvcCBoxFactory_New(t *testing.T) {
+	cb := getTestParsedBox(avcCBoxFactory{}, testAvcCData)
+	avcC := cb.(*AvcCBox)
+
+	if avcC.ConfigurationVersion() != 1 {
+		t.Fatalf("ConfigurationVersion() not correct.")
+	} else if avcC.Profile() != 100 {
+		t.Fatalf("Profile() not correct.")
+	} else if avcC.ProfileCompatibility() != 0 {
+		t.Fatalf("ProfileCompatibility() not correct.")
+	} else if avcC.Level() != 40 {
+		t.Fatalf("Level() not correct.")
+	} else if avcC.NalUnitLengthSize() != 4 {
+		t.Fatalf("NalUnitLengthSize() not correct.")
+	} else if avcC.HasExtension() != false {
+		t.Fatalf("HasExtension() not correct.")
+	} else if avcC.ChromaFormat() != 1 {
+		t.Fatalf("ChromaFormat() not correct.")
+	} else if avcC.BitDepthLuma() != 8 || avcC.BitDepthChroma() != 8 {
+		t.Fatalf("Bit-depths not correct.")
+	}
+
+	spss := avcC.SequenceParameterSets()
+	if len(spss) != 1 {
+		t.Fatalf("Expected one SPS.")
+	} else if len(spss[0]) != 26 || spss[0][0] != 0x67 {
+		t.Fatalf("SPS not correct: %x", spss[0])
+	}
+
+	ppss := avcC.PictureParameterSets()
+	if len(ppss) != 1 {
+		t.Fatalf("Expected one PPS.")
+	} else if bytes.Equal(ppss[0], []byte{0x68, 0xca, 0xec, 0xb2, 0x2c}) != true {
+		t.Fatalf("PPS not correct: %x", ppss[0])
+	}
+
+	if avcC.CodecString("avc1") != "avc1.640028" {
+		t.Fatalf("CodecString() not correct: [%s]", avcC.CodecString("avc1"))
+	}
+
+	assertEncodeData(t, cb, testAvcCData)
+}
+
+func TestAvcCBoxFactory_New_Extension(t *testing.T) {
+	data := make([]byte, len(testAvcCData))
+	copy(data, testAvcCData)
+
+	// 4:2:2 with 10-bit luma and chroma, and no SPS extensions.
+	data = append(data, 0xfe, 0xfa, 0xfa, 0x00)
+
+	cb := getTestParsedBox(avcCBoxFactory{}, data)
+	avcC := cb.(*AvcCBox)
+
+	if avcC.HasExtension() != true {
+		t.Fatalf("HasExtension() not correct.")
+	} else if avcC.ChromaFormat() != 2 {
+		t.Fatalf("ChromaFormat() not correct.")
+	} else if avcC.BitDepthLuma() != 10 || avcC.BitDepthChroma() != 10 {
+		t.Fatalf("Bit-depths not correct.")
+	} else if len(avcC.SequenceParameterSetExtensions()) != 0 {
+		t.Fatalf("Expected no SPS extensions.")
+	}
+}
+
+func TestAvcCBoxFactory_New_Truncated(t *testing.T) {
+	data := testAvcCData[:20]
+
+	_, err := getTestBoxFactoryNew(avcCBoxFactory{}, data)
+	if err == nil {
+		t.Fatalf("Expected error for truncated SPS.")
+	}
+}
+
+func TestReadNalUnits_TooMany(t *testing.T) {
+	r := bytes.NewReader([]byte{0, 1, 0xaa})
+
+	_, err := readNalUnits(r, 2)
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+
+	r = bytes.NewReader([]byte{0, 1, 0xaa})
+
+	nalUnits, err := readNalUnits(r, 1)
+	log.PanicIf(err)
+
+	if len(nalUnits) != 1 || bytes.Equal(nalUnits[0], []byte{0xaa}) != true {
+		t.Fatalf("NAL units not correct.")
+	}
+}
+
+func TestHexStrings(t *testing.T) {
+	encoded := hexStrings([][]byte{{0x01, 0xab}, {}})
+
+	if len(encoded) != 2 || encoded[0] != "01ab" || encoded[1] != "" {
+		t.Fatalf("Encoding not correct: %v", encoded)
+	}
+}
@@ -0,0 +1,241 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// dac3Size is the size of the AC3SpecificBox payload.
+	dac3Size = 3
+)
+
+var (
+	// ac3SampleRates are the sample-rates by fscod.
+	ac3SampleRates = []uint32{48000, 44100, 32000}
+
+	// ac3BitRates are the nominal bitrates in kbit/s by bit_rate_code.
+	ac3BitRates = []uint32{
+		32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 448, 512, 576, 640,
+	}
+
+	// ac3ChannelLayouts are the speaker positions by acmod, not including the
+	// LFE. Mode zero is dual-mono (1+1) and has no positions.
+	ac3ChannelLayouts = [][]AudioChannel{
+		nil,
+		{AudioChannelFrontCenter},
+		{AudioChannelFrontLeft, AudioChannelFrontRight},
+		{AudioChannelFrontLeft, AudioChannelFrontCenter, AudioChannelFrontRight},
+		{AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackCenter},
+		{AudioChannelFrontLeft, AudioChannelFrontCenter, AudioChannelFrontRight, AudioChannelBackCenter},
+		{AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelSideLeft, AudioChannelSideRight},
+		{AudioChannelFrontLeft, AudioChannelFrontCenter, AudioChannelFrontRight, AudioChannelSideLeft, AudioChannelSideRight},
+	}
+)
+
+// ac3ChannelLayout returns the speaker positions for the audio coding-mode
+// and LFE flag or nil for dual-mono.
+func ac3ChannelLayout(acmod uint8, lfeon bool) []AudioChannel {
+	if acmod == 0 {
+		return nil
+	}
+
+	layout := make([]AudioChannel, len(ac3ChannelLayouts[acmod]))
+	copy(layout, ac3ChannelLayouts[acmod])
+
+	if lfeon == true {
+		layout = append(layout, AudioChannelLowFrequency)
+	}
+
+	return layout
+}
+
+// ac3ChannelCount returns the number of channels for the audio coding-mode and
+// LFE flag.
+func ac3ChannelCount(acmod uint8, lfeon bool) int {
+	count := len(ac3ChannelLayouts[acmod])
+	if acmod == 0 {
+		count = 2
+	}
+
+	if lfeon == true {
+		count++
+	}
+
+	return count
+}
+
+// Dac3Box is the "AC-3 Specific" box from ETSI TS 102 366 Annex F.
+type Dac3Box struct {
+	bmfcommon.Box
+
+	fscod       uint8
+	bsid        uint8
+	bsmod       uint8
+	acmod       uint8
+	lfeon       bool
+	bitRateCode uint8
+}
+
+// Fscod returns the sample-rate code.
+func (db *Dac3Box) Fscod() uint8 {
+	return db.fscod
+}
+
+// Bsid returns the bit-stream identification.
+func (db *Dac3Box) Bsid() uint8 {
+	return db.bsid
+}
+
+// Bsmod returns the bit-stream mode (e.g. zero for complete main).
+func (db *Dac3Box) Bsmod() uint8 {
+	return db.bsmod
+}
+
+// Acmod returns the audio coding-mode, which gives the main channels.
+func (db *Dac3Box) Acmod() uint8 {
+	return db.acmod
+}
+
+// Lfeon returns true if there is an LFE channel.
+func (db *Dac3Box) Lfeon() bool {
+	return db.lfeon
+}
+
+// BitRate returns the nominal bitrate in kbit/s or zero if the code is not
+// valid.
+func (db *Dac3Box) BitRate() uint32 {
+	if int(db.bitRateCode) >= len(ac3BitRates) {
+		return 0
+	}
+
+	return ac3BitRates[db.bitRateCode]
+}
+
+// CodecString returns the RFC 6381 codec string ("ac-3").
+func (db *Dac3Box) CodecString(sampleEntryName string) string {
+	return "ac-3"
+}
+
+// ChannelCount returns the number of decoded channels.
+func (db *Dac3Box) ChannelCount() int {
+	return ac3ChannelCount(db.acmod, db.lfeon)
+}
+
+// ChannelLayout returns the decoded speaker positions or nil for dual-mono.
+func (db *Dac3Box) ChannelLayout() []AudioChannel {
+	return ac3ChannelLayout(db.acmod, db.lfeon)
+}
+
+// SampleRate returns the decoded sample-rate.
+func (db *Dac3Box) SampleRate() uint32 {
+	return ac3SampleRates[db.fscod]
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (db *Dac3Box) InlineString() string {
+	return fmt.Sprintf(
+		"%s FSCOD=(%d) BSID=(%d) BSMOD=(%d) ACMOD=(%d) LFEON=[%v] BITRATE=(%d)",
+		db.Box.InlineString(), db.fscod, db.bsid, db.bsmod, db.acmod, db.lfeon, db.BitRate())
+}
+
+func (b *Dac3Box) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < dac3Size {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), dac3Size, int64(len(data))))
+	}
+
+	packed := uint32(data[0])<<16 | uint32(data[1])<<8 | uint32(data[2])
+
+	b.fscod = uint8(packed >> 22)
+	b.bsid = uint8(packed>>17) & 0x1f
+	b.bsmod = uint8(packed>>14) & 0x07
+	b.acmod = uint8(packed>>11) & 0x07
+	b.lfeon = packed>>10&0x01 != 0
+	b.bitRateCode = uint8(packed>>5) & 0x1f
+
+	if int(b.fscod) >= len(ac3SampleRates) {
+		log.Panicf("dac3: fscod not valid: (%d)", b.fscod)
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (db *Dac3Box) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"fscod":    db.fscod,
+		"bsid":     db.bsid,
+		"bsmod":    db.bsmod,
+		"acmod":    db.acmod,
+		"lfeon":    db.lfeon,
+		"bit_rate": db.BitRate(),
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (db *Dac3Box) EncodeData() (data []byte, err error) {
+	packed := uint32(db.fscod)<<22 |
+		uint32(db.bsid)<<17 |
+		uint32(db.bsmod)<<14 |
+		uint32(db.acmod)<<11 |
+		uint32(db.bitRateCode)<<5
+
+	if db.lfeon == true {
+		packed |= 1 << 10
+	}
+
+	data = []byte{byte(packed >> 16), byte(packed >> 8), byte(packed)}
+
+	return data, nil
+}
+
+type dac3BoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (dac3BoxFactory) Name() string {
+	return "dac3"
+}
+
+// New returns a new value instance.
+func (dac3BoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	dac3Box := &Dac3Box{
+		Box: box,
+	}
+
+	err = dac3Box.parse()
+	log.PanicIf(err)
+
+	return dac3Box, -1, nil
+}
+
+var (
+	_ bmfcommon.BoxFactory    = dac3BoxFactory{}
+	_ bmfcommon.CommonBox     = &Dac3Box{}
+	_ bmfcommon.FieldExporter = &Dac3Box{}
+	_ AudioConfiguration      = &Dac3Box{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(dac3BoxFactory{})
+}
@@ -0,0 +1,79 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDac3BoxFactory_Name(t *testing.T) {
+	if (dac3BoxFactory{}).Name() != "dac3" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestDac3BoxFactory_New(t *testing.T) {
+	// 48kHz, bsid 8, complete main, 3/2 with LFE, 384 kbit/s.
+	data := []byte{0x10, 0x3d, 0xc0}
+
+	cb := getTestParsedBox(dac3BoxFactory{}, data)
+	dac3 := cb.(*Dac3Box)
+
+	if dac3.Fscod() != 0 {
+		t.Fatalf("Fscod() not correct.")
+	} else if dac3.Bsid() != 8 {
+		t.Fatalf("Bsid() not correct.")
+	} else if dac3.Bsmod() != 0 {
+		t.Fatalf("Bsmod() not correct.")
+	} else if dac3.Acmod() != 7 {
+		t.Fatalf("Acmod() not correct.")
+	} else if dac3.Lfeon() != true {
+		t.Fatalf("Lfeon() not correct.")
+	} else if dac3.BitRate() != 384 {
+		t.Fatalf("BitRate() not correct: (%d)", dac3.BitRate())
+	} else if dac3.SampleRate() != 48000 {
+		t.Fatalf("SampleRate() not correct.")
+	} else if dac3.ChannelCount() != 6 {
+		t.Fatalf("ChannelCount() not correct.")
+	} else if dac3.CodecString("ac-3") != "ac-3" {
+		t.Fatalf("CodecString() not correct.")
+	}
+
+	expectedLayout := []AudioChannel{
+		AudioChannelFrontLeft,
+		AudioChannelFrontCenter,
+		AudioChannelFrontRight,
+		AudioChannelSideLeft,
+		AudioChannelSideRight,
+		AudioChannelLowFrequency,
+	}
+
+	if reflect.DeepEqual(dac3.ChannelLayout(), expectedLayout) != true {
+		t.Fatalf("ChannelLayout() not correct: %v", dac3.ChannelLayout())
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestDac3Box_DualMono(t *testing.T) {
+	dac3 := &Dac3Box{
+		acmod: 0,
+	}
+
+	if dac3.ChannelLayout() != nil {
+		t.Fatalf("ChannelLayout() should not be known.")
+	} else if dac3.ChannelCount() != 2 {
+		t.Fatalf("ChannelCount() not correct.")
+	}
+}
+
+func TestDac3BoxFactory_New_Invalid(t *testing.T) {
+	_, err := getTestBoxFactoryNew(dac3BoxFactory{}, []byte{0x10, 0x3d})
+	if err == nil {
+		t.Fatalf("Expected error for truncated box.")
+	}
+
+	_, err = getTestBoxFactoryNew(dac3BoxFactory{}, []byte{0xd0, 0x3d, 0xc0})
+	if err == nil {
+		t.Fatalf("Expected error for reserved fscod.")
+	}
+}
@@ -0,0 +1,343 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+var (
+	// ec3ChannelLocations are the speaker positions of the chan_loc bits, the
+	// first bit in the stream first.
+	ec3ChannelLocations = [][]AudioChannel{
+		{AudioChannelFrontLeftOfCenter, AudioChannelFrontRightOfCenter},
+		{AudioChannelBackLeft, AudioChannelBackRight},
+		{AudioChannelBackCenter},
+		{AudioChannelTopCenter},
+		{AudioChannelSurroundDirectLeft, AudioChannelSurroundDirectRight},
+		{AudioChannelWideLeft, AudioChannelWideRight},
+		{AudioChannelTopFrontLeft, AudioChannelTopFrontRight},
+		{AudioChannelTopFrontCenter},
+		{AudioChannelLowFrequency2},
+	}
+)
+
+// Ec3IndependentSubstream describes one of the independent substreams in a
+// "dec3" box.
+type Ec3IndependentSubstream struct {
+	fscod     uint8
+	bsid      uint8
+	asvc      bool
+	bsmod     uint8
+	acmod     uint8
+	lfeon     bool
+	numDepSub uint8
+	chanLoc   uint16
+}
+
+// Fscod returns the sample-rate code.
+func (eis Ec3IndependentSubstream) Fscod() uint8 {
+	return eis.fscod
+}
+
+// Bsid returns the bit-stream identification.
+func (eis Ec3IndependentSubstream) Bsid() uint8 {
+	return eis.bsid
+}
+
+// Asvc returns true if this is an associated service rather than a main
+// service.
+func (eis Ec3IndependentSubstream) Asvc() bool {
+	return eis.asvc
+}
+
+// Bsmod returns the bit-stream mode.
+func (eis Ec3IndependentSubstream) Bsmod() uint8 {
+	return eis.bsmod
+}
+
+// Acmod returns the audio coding-mode, which gives the main channels.
+func (eis Ec3IndependentSubstream) Acmod() uint8 {
+	return eis.acmod
+}
+
+// Lfeon returns true if there is an LFE channel.
+func (eis Ec3IndependentSubstream) Lfeon() bool {
+	return eis.lfeon
+}
+
+// NumDepSub returns the number of dependent substreams that add channels.
+func (eis Ec3IndependentSubstream) NumDepSub() uint8 {
+	return eis.numDepSub
+}
+
+// ChanLoc returns the channel-location bits of the dependent substreams. This
+// is only present if there are dependent substreams.
+func (eis Ec3IndependentSubstream) ChanLoc() uint16 {
+	return eis.chanLoc
+}
+
+// ChannelLayout returns the speaker positions of the substream including those
+// of its dependent substreams or nil for dual-mono.
+func (eis Ec3IndependentSubstream) ChannelLayout() []AudioChannel {
+	layout := ac3ChannelLayout(eis.acmod, eis.lfeon)
+	if layout == nil {
+		return nil
+	}
+
+	if eis.numDepSub > 0 {
+		for i, channels := range ec3ChannelLocations {
+			if eis.chanLoc&(1<<uint(len(ec3ChannelLocations)-1-i)) != 0 {
+				layout = append(layout, channels...)
+			}
+		}
+	}
+
+	return layout
+}
+
+// ChannelCount returns the number of channels of the substream including those
+// of its dependent substreams.
+func (eis Ec3IndependentSubstream) ChannelCount() int {
+	if eis.acmod == 0 {
+		return ac3ChannelCount(eis.acmod, eis.lfeon)
+	}
+
+	return len(eis.ChannelLayout())
+}
+
+// Dec3Box is the "E-AC-3 Specific" box from ETSI TS 102 366 Annex F.
+type Dec3Box struct {
+	bmfcommon.Box
+
+	dataRate           uint16
+	substreams         []Ec3IndependentSubstream
+	hasExtension       bool
+	jocComplexityIndex uint8
+}
+
+// DataRate returns the data-rate in kbit/s.
+func (db *Dec3Box) DataRate() uint16 {
+	return db.dataRate
+}
+
+// Substreams returns the independent substreams. The first is the main
+// program.
+func (db *Dec3Box) Substreams() []Ec3IndependentSubstream {
+	return db.substreams
+}
+
+// JointObjectCoding returns the complexity index of the Joint Object Coding
+// (Dolby Atmos) extension and whether it is present.
+func (db *Dec3Box) JointObjectCoding() (complexityIndex uint8, found bool) {
+	return db.jocComplexityIndex, db.hasExtension
+}
+
+// CodecString returns the RFC 6381 codec string ("ec-3").
+func (db *Dec3Box) CodecString(sampleEntryName string) string {
+	return "ec-3"
+}
+
+// ChannelCount returns the number of decoded channels of the main program.
+func (db *Dec3Box) ChannelCount() int {
+	return db.substreams[0].ChannelCount()
+}
+
+// ChannelLayout returns the decoded speaker positions of the main program or
+// nil for dual-mono.
+func (db *Dec3Box) ChannelLayout() []AudioChannel {
+	return db.substreams[0].ChannelLayout()
+}
+
+// SampleRate returns the decoded sample-rate.
+func (db *Dec3Box) SampleRate() uint32 {
+	return ac3SampleRates[db.substreams[0].fscod]
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (db *Dec3Box) InlineString() string {
+	return fmt.Sprintf(
+		"%s DATA-RATE=(%d) SUBSTREAMS=(%d) CHANNELS=(%d)",
+		db.Box.InlineString(), db.dataRate, len(db.substreams), db.ChannelCount())
+}
+
+func (b *Dec3Box) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	br := bmfcommon.NewBitReader(data)
+
+	dataRate, err := br.ReadBits(13)
+	log.PanicIf(err)
+
+	b.dataRate = uint16(dataRate)
+
+	numIndSub, err := br.ReadBits(3)
+	log.PanicIf(err)
+
+	b.substreams = make([]Ec3IndependentSubstream, int(numIndSub)+1)
+	for i := range b.substreams {
+		eis := Ec3IndependentSubstream{}
+
+		fscod, err := br.ReadBits(2)
+		log.PanicIf(err)
+
+		eis.fscod = uint8(fscod)
+
+		bsid, err := br.ReadBits(5)
+		log.PanicIf(err)
+
+		eis.bsid = uint8(bsid)
+
+		// reserved
+		err = br.Skip(1)
+		log.PanicIf(err)
+
+		eis.asvc, err = br.ReadFlag()
+		log.PanicIf(err)
+
+		bsmod, err := br.ReadBits(3)
+		log.PanicIf(err)
+
+		eis.bsmod = uint8(bsmod)
+
+		acmod, err := br.ReadBits(3)
+		log.PanicIf(err)
+
+		eis.acmod = uint8(acmod)
+
+		eis.lfeon, err = br.ReadFlag()
+		log.PanicIf(err)
+
+		// reserved
+		err = br.Skip(3)
+		log.PanicIf(err)
+
+		numDepSub, err := br.ReadBits(4)
+		log.PanicIf(err)
+
+		eis.numDepSub = uint8(numDepSub)
+
+		if eis.numDepSub > 0 {
+			chanLoc, err := br.ReadBits(9)
+			log.PanicIf(err)
+
+			eis.chanLoc = uint16(chanLoc)
+		} else {
+			// reserved
+			err = br.Skip(1)
+			log.PanicIf(err)
+		}
+
+		if int(eis.fscod) >= len(ac3SampleRates) {
+			log.Panicf("dec3: fscod not valid: (%d)", eis.fscod)
+		}
+
+		b.substreams[i] = eis
+	}
+
+	// The JOC extension follows a reserved byte.
+	if br.Remaining() >= 16 {
+		// reserved
+		err = br.Skip(7)
+		log.PanicIf(err)
+
+		b.hasExtension, err = br.ReadFlag()
+		log.PanicIf(err)
+
+		if b.hasExtension == true {
+			jocComplexityIndex, err := br.ReadBits(8)
+			log.PanicIf(err)
+
+			b.jocComplexityIndex = uint8(jocComplexityIndex)
+		}
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (db *Dec3Box) ExportFields() (fields map[string]interface{}, err error) {
+	main := db.substreams[0]
+
+	fields = map[string]interface{}{
+		"data_rate":       db.dataRate,
+		"substream_count": len(db.substreams),
+		"fscod":           main.fscod,
+		"bsid":            main.bsid,
+		"bsmod":           main.bsmod,
+		"acmod":           main.acmod,
+		"lfeon":           main.lfeon,
+		"num_dep_sub":     main.numDepSub,
+		"chan_loc":        main.chanLoc,
+	}
+
+	if complexityIndex, found := db.JointObjectCoding(); found == true {
+		fields["joc_complexity_index"] = complexityIndex
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. The bit-fields are carried over
+// as read.
+func (db *Dec3Box) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := db.Data()
+	log.PanicIf(err)
+
+	data = make([]byte, len(original))
+	copy(data, original)
+
+	return data, nil
+}
+
+type dec3BoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (dec3BoxFactory) Name() string {
+	return "dec3"
+}
+
+// New returns a new value instance.
+func (dec3BoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	dec3Box := &Dec3Box{
+		Box: box,
+	}
+
+	err = dec3Box.parse()
+	log.PanicIf(err)
+
+	return dec3Box, -1, nil
+}
+
+var (
+	_ bmfcommon.BoxFactory    = dec3BoxFactory{}
+	_ bmfcommon.CommonBox     = &Dec3Box{}
+	_ bmfcommon.FieldExporter = &Dec3Box{}
+	_ AudioConfiguration      = &Dec3Box{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(dec3BoxFactory{})
+}
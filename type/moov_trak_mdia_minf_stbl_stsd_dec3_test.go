@@ -0,0 +1,95 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDec3BoxFactory_Name(t *testing.T) {
+	if (dec3BoxFactory{}).Name() != "dec3" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestDec3BoxFactory_New(t *testing.T) {
+	// 640 kbit/s, 48kHz, 3/2 with LFE, and one dependent substream that adds
+	// the rear-surround pair (7.1).
+	data := []byte{0x14, 0x00, 0x20, 0x0f, 0x02, 0x80}
+
+	cb := getTestParsedBox(dec3BoxFactory{}, data)
+	dec3 := cb.(*Dec3Box)
+
+	if dec3.DataRate() != 640 {
+		t.Fatalf("DataRate() not correct.")
+	} else if len(dec3.Substreams()) != 1 {
+		t.Fatalf("Substreams() not correct.")
+	}
+
+	eis := dec3.Substreams()[0]
+
+	if eis.Fscod() != 0 {
+		t.Fatalf("Fscod() not correct.")
+	} else if eis.Bsid() != 16 {
+		t.Fatalf("Bsid() not correct.")
+	} else if eis.Asvc() != false {
+		t.Fatalf("Asvc() not correct.")
+	} else if eis.Bsmod() != 0 {
+		t.Fatalf("Bsmod() not correct.")
+	} else if eis.Acmod() != 7 {
+		t.Fatalf("Acmod() not correct.")
+	} else if eis.Lfeon() != true {
+		t.Fatalf("Lfeon() not correct.")
+	} else if eis.NumDepSub() != 1 {
+		t.Fatalf("NumDepSub() not correct.")
+	} else if eis.ChanLoc() != 0x80 {
+		t.Fatalf("ChanLoc() not correct: (0x%x)", eis.ChanLoc())
+	}
+
+	if _, found := dec3.JointObjectCoding(); found != false {
+		t.Fatalf("JointObjectCoding() not correct.")
+	} else if dec3.SampleRate() != 48000 {
+		t.Fatalf("SampleRate() not correct.")
+	} else if dec3.ChannelCount() != 8 {
+		t.Fatalf("ChannelCount() not correct: (%d)", dec3.ChannelCount())
+	} else if dec3.CodecString("ec-3") != "ec-3" {
+		t.Fatalf("CodecString() not correct.")
+	}
+
+	expectedLayout := []AudioChannel{
+		AudioChannelFrontLeft,
+		AudioChannelFrontCenter,
+		AudioChannelFrontRight,
+		AudioChannelSideLeft,
+		AudioChannelSideRight,
+		AudioChannelLowFrequency,
+		AudioChannelBackLeft,
+		AudioChannelBackRight,
+	}
+
+	if reflect.DeepEqual(dec3.ChannelLayout(), expectedLayout) != true {
+		t.Fatalf("ChannelLayout() not correct: %v", dec3.ChannelLayout())
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestDec3BoxFactory_New_JointObjectCoding(t *testing.T) {
+	// 768 kbit/s 5.1 with the Atmos extension (complexity index 16).
+	data := []byte{0x18, 0x00, 0x20, 0x0f, 0x00, 0x01, 0x10}
+
+	dec3 := getTestParsedBox(dec3BoxFactory{}, data).(*Dec3Box)
+
+	complexityIndex, found := dec3.JointObjectCoding()
+	if found != true || complexityIndex != 16 {
+		t.Fatalf("JointObjectCoding() not correct: (%d) [%v]", complexityIndex, found)
+	} else if dec3.ChannelCount() != 6 {
+		t.Fatalf("ChannelCount() not correct.")
+	}
+}
+
+func TestDec3BoxFactory_New_Truncated(t *testing.T) {
+	_, err := getTestBoxFactoryNew(dec3BoxFactory{}, []byte{0x14, 0x00, 0x20})
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+}
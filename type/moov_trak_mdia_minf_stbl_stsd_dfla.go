@@ -0,0 +1,306 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// flacMetadataBlockHeaderSize is the size of a metadata-block header.
+	flacMetadataBlockHeaderSize = 4
+
+	// flacStreamInfoType is the metadata-block type of the STREAMINFO block.
+	flacStreamInfoType = 0
+
+	// flacStreamInfoSize is the size of the STREAMINFO block.
+	flacStreamInfoSize = 34
+)
+
+var (
+	// flacChannelLayouts are the speaker positions by channel-count (from the
+	// FLAC format specification).
+	flacChannelLayouts = map[int][]AudioChannel{
+		1: {AudioChannelFrontCenter},
+		2: {AudioChannelFrontLeft, AudioChannelFrontRight},
+		3: {AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelFrontCenter},
+		4: {AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight},
+		5: {AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelFrontCenter, AudioChannelBackLeft, AudioChannelBackRight},
+		6: {AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelFrontCenter, AudioChannelLowFrequency, AudioChannelBackLeft, AudioChannelBackRight},
+		7: {AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelFrontCenter, AudioChannelLowFrequency, AudioChannelBackCenter, AudioChannelSideLeft, AudioChannelSideRight},
+		8: {AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelFrontCenter, AudioChannelLowFrequency, AudioChannelBackLeft, AudioChannelBackRight, AudioChannelSideLeft, AudioChannelSideRight},
+	}
+)
+
+// FlacMetadataBlock is one of the FLAC metadata-blocks in a "dfLa" box.
+type FlacMetadataBlock struct {
+	blockType uint8
+	isLast    bool
+	blockData []byte
+}
+
+// BlockType returns the metadata-block type (e.g. zero for STREAMINFO).
+func (fmb FlacMetadataBlock) BlockType() uint8 {
+	return fmb.blockType
+}
+
+// IsLast returns true if this is flagged as the last metadata-block.
+func (fmb FlacMetadataBlock) IsLast() bool {
+	return fmb.isLast
+}
+
+// Data returns the body of the metadata-block.
+func (fmb FlacMetadataBlock) Data() []byte {
+	return fmb.blockData
+}
+
+// DfLaBox is the "FLAC Specific" box from the FLAC ISOBMFF encapsulation. It
+// has the FLAC metadata-blocks, the first of which must be STREAMINFO.
+type DfLaBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	blocks []FlacMetadataBlock
+
+	minimumBlockSize uint16
+	maximumBlockSize uint16
+	minimumFrameSize uint32
+	maximumFrameSize uint32
+	sampleRate       uint32
+	channels         uint8
+	bitsPerSample    uint8
+	totalSamples     uint64
+	md5              []byte
+}
+
+// Blocks returns the metadata-blocks.
+func (db *DfLaBox) Blocks() []FlacMetadataBlock {
+	return db.blocks
+}
+
+// MinimumBlockSize returns the minimum block-size in samples.
+func (db *DfLaBox) MinimumBlockSize() uint16 {
+	return db.minimumBlockSize
+}
+
+// MaximumBlockSize returns the maximum block-size in samples.
+func (db *DfLaBox) MaximumBlockSize() uint16 {
+	return db.maximumBlockSize
+}
+
+// MinimumFrameSize returns the minimum frame-size in bytes or zero if not
+// known.
+func (db *DfLaBox) MinimumFrameSize() uint32 {
+	return db.minimumFrameSize
+}
+
+// MaximumFrameSize returns the maximum frame-size in bytes or zero if not
+// known.
+func (db *DfLaBox) MaximumFrameSize() uint32 {
+	return db.maximumFrameSize
+}
+
+// BitsPerSample returns the bits-per-sample.
+func (db *DfLaBox) BitsPerSample() uint8 {
+	return db.bitsPerSample
+}
+
+// TotalSamples returns the number of inter-channel samples or zero if not
+// known.
+func (db *DfLaBox) TotalSamples() uint64 {
+	return db.totalSamples
+}
+
+// Md5 returns the MD5 signature of the unencoded audio.
+func (db *DfLaBox) Md5() []byte {
+	return db.md5
+}
+
+// CodecString returns the RFC 6381 codec string ("flac").
+func (db *DfLaBox) CodecString(sampleEntryName string) string {
+	return "flac"
+}
+
+// ChannelCount returns the number of decoded channels.
+func (db *DfLaBox) ChannelCount() int {
+	return int(db.channels)
+}
+
+// ChannelLayout returns the decoded speaker positions or nil if not known.
+func (db *DfLaBox) ChannelLayout() []AudioChannel {
+	return flacChannelLayouts[int(db.channels)]
+}
+
+// SampleRate returns the decoded sample-rate.
+func (db *DfLaBox) SampleRate() uint32 {
+	return db.sampleRate
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (db *DfLaBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s BLOCKS=(%d) SAMPLE-RATE=(%d) CHANNELS=(%d) BPS=(%d) TOTAL-SAMPLES=(%d)",
+		db.Box.InlineString(), len(db.blocks), db.sampleRate, db.channels, db.bitsPerSample, db.totalSamples)
+}
+
+func (b *DfLaBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	// The version and flags were already checked.
+	data = data[4:]
+
+	b.blocks = make([]FlacMetadataBlock, 0)
+
+	offset := 0
+	for offset < len(data) {
+		if len(data)-offset < flacMetadataBlockHeaderSize {
+			log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize()+4+int64(offset), flacMetadataBlockHeaderSize, int64(len(data)-offset)))
+		}
+
+		header := bmfcommon.DefaultEndianness.Uint32(data[offset : offset+4])
+		length := int(header & 0x00ffffff)
+
+		offset += flacMetadataBlockHeaderSize
+
+		if len(data)-offset < length {
+			log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize()+4+int64(offset), int64(length), int64(len(data)-offset)))
+		}
+
+		fmb := FlacMetadataBlock{
+			blockType: uint8(header>>24) & 0x7f,
+			isLast:    header&0x80000000 != 0,
+			blockData: data[offset : offset+length],
+		}
+
+		b.blocks = append(b.blocks, fmb)
+
+		offset += length
+
+		if fmb.isLast == true {
+			break
+		}
+	}
+
+	if len(b.blocks) == 0 || b.blocks[0].blockType != flacStreamInfoType {
+		log.Panicf("dfLa: first metadata-block is not STREAMINFO")
+	}
+
+	si := b.blocks[0].blockData
+	if len(si) < flacStreamInfoSize {
+		log.Panicf("dfLa: STREAMINFO too short: (%d)", len(si))
+	}
+
+	b.minimumBlockSize = bmfcommon.DefaultEndianness.Uint16(si[0:2])
+	b.maximumBlockSize = bmfcommon.DefaultEndianness.Uint16(si[2:4])
+	b.minimumFrameSize = bmfcommon.DefaultEndianness.Uint32(si[3:7]) & 0x00ffffff
+	b.maximumFrameSize = bmfcommon.DefaultEndianness.Uint32(si[6:10]) & 0x00ffffff
+
+	// Sample-rate (20), channels minus one (3), bits-per-sample minus one (5),
+	// and total samples (36).
+	packed := bmfcommon.DefaultEndianness.Uint64(si[10:18])
+
+	b.sampleRate = uint32(packed >> 44)
+	b.channels = uint8(packed>>41&0x07) + 1
+	b.bitsPerSample = uint8(packed>>36&0x1f) + 1
+	b.totalSamples = packed & 0x0000000fffffffff
+
+	b.md5 = si[18:34]
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (db *DfLaBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"block_count":        len(db.blocks),
+		"minimum_block_size": db.minimumBlockSize,
+		"maximum_block_size": db.maximumBlockSize,
+		"minimum_frame_size": db.minimumFrameSize,
+		"maximum_frame_size": db.maximumFrameSize,
+		"sample_rate":        db.sampleRate,
+		"channels":           db.channels,
+		"bits_per_sample":    db.bitsPerSample,
+		"total_samples":      db.totalSamples,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. The metadata-blocks are carried
+// over as read.
+func (db *DfLaBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	bmfcommon.PushBytes(&data, uint32(db.Version())<<24|db.Flags()&0x00ffffff)
+
+	for _, fmb := range db.blocks {
+		header := uint32(fmb.blockType&0x7f)<<24 | uint32(len(fmb.blockData))
+		if fmb.isLast == true {
+			header |= 0x80000000
+		}
+
+		bmfcommon.PushBytes(&data, header)
+		bmfcommon.PushBytes(&data, fmb.blockData)
+	}
+
+	return data, nil
+}
+
+type dfLaBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (dfLaBoxFactory) Name() string {
+	return "dfLa"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (dfLaBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf dfLaBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	dfLaBox := &DfLaBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = dfLaBox.parse()
+	log.PanicIf(err)
+
+	return dfLaBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = dfLaBoxFactory{}
+	_ bmfcommon.CommonBox      = &DfLaBox{}
+	_ bmfcommon.FieldExporter  = &DfLaBox{}
+	_ AudioConfiguration       = &DfLaBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(dfLaBoxFactory{})
+}
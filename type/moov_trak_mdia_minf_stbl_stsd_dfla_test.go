@@ -0,0 +1,111 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// getTestDfLaData returns a "dfLa" payload with a STREAMINFO block for 96kHz,
+// 24-bit 5.1 audio and a padding block.
+func getTestDfLaData() []byte {
+	var data []byte
+
+	// Version and flags.
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	// STREAMINFO
+	bmfcommon.PushBytes(&data, uint32(flacStreamInfoSize))
+
+	bmfcommon.PushBytes(&data, uint16(4096))
+	bmfcommon.PushBytes(&data, uint16(4096))
+	bmfcommon.PushBytes(&data, []byte{0x00, 0x00, 0x10})
+	bmfcommon.PushBytes(&data, []byte{0x00, 0x40, 0x00})
+
+	// Sample-rate (20), channels minus one (3), bits-per-sample minus one (5),
+	// and total samples (36).
+	bmfcommon.PushBytes(&data, uint64(96000)<<44|uint64(5)<<41|uint64(23)<<36|uint64(960000))
+
+	bmfcommon.PushBytes(&data, make([]byte, 16))
+
+	// PADDING (last)
+	bmfcommon.PushBytes(&data, uint32(0x81000002))
+	bmfcommon.PushBytes(&data, []byte{0, 0})
+
+	return data
+}
+
+func TestDfLaBoxFactory_Name(t *testing.T) {
+	if (dfLaBoxFactory{}).Name() != "dfLa" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestDfLaBoxFactory_New(t *testing.T) {
+	data := getTestDfLaData()
+
+	cb := getTestParsedBox(dfLaBoxFactory{}, data)
+	dfLa := cb.(*DfLaBox)
+
+	blocks := dfLa.Blocks()
+	if len(blocks) != 2 {
+		t.Fatalf("Blocks() not correct.")
+	} else if blocks[0].BlockType() != flacStreamInfoType || blocks[0].IsLast() != false {
+		t.Fatalf("First block not correct.")
+	} else if blocks[1].BlockType() != 1 || blocks[1].IsLast() != true || len(blocks[1].Data()) != 2 {
+		t.Fatalf("Second block not correct.")
+	}
+
+	if dfLa.MinimumBlockSize() != 4096 || dfLa.MaximumBlockSize() != 4096 {
+		t.Fatalf("Block-sizes not correct.")
+	} else if dfLa.MinimumFrameSize() != 16 {
+		t.Fatalf("MinimumFrameSize() not correct.")
+	} else if dfLa.MaximumFrameSize() != 0x4000 {
+		t.Fatalf("MaximumFrameSize() not correct.")
+	} else if dfLa.SampleRate() != 96000 {
+		t.Fatalf("SampleRate() not correct: (%d)", dfLa.SampleRate())
+	} else if dfLa.ChannelCount() != 6 {
+		t.Fatalf("ChannelCount() not correct.")
+	} else if dfLa.BitsPerSample() != 24 {
+		t.Fatalf("BitsPerSample() not correct.")
+	} else if dfLa.TotalSamples() != 960000 {
+		t.Fatalf("TotalSamples() not correct.")
+	} else if len(dfLa.Md5()) != 16 {
+		t.Fatalf("Md5() not correct.")
+	} else if dfLa.CodecString("fLaC") != "flac" {
+		t.Fatalf("CodecString() not correct.")
+	}
+
+	expectedLayout := []AudioChannel{
+		AudioChannelFrontLeft,
+		AudioChannelFrontRight,
+		AudioChannelFrontCenter,
+		AudioChannelLowFrequency,
+		AudioChannelBackLeft,
+		AudioChannelBackRight,
+	}
+
+	if reflect.DeepEqual(dfLa.ChannelLayout(), expectedLayout) != true {
+		t.Fatalf("ChannelLayout() not correct: %v", dfLa.ChannelLayout())
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestDfLaBoxFactory_New_Invalid(t *testing.T) {
+	data := getTestDfLaData()
+
+	_, err := getTestBoxFactoryNew(dfLaBoxFactory{}, data[:20])
+	if err == nil {
+		t.Fatalf("Expected error for truncated block.")
+	}
+
+	// Make the first block a PADDING block.
+	data[4] = 1
+
+	_, err = getTestBoxFactoryNew(dfLaBoxFactory{}, data)
+	if err == nil {
+		t.Fatalf("Expected error for missing STREAMINFO.")
+	}
+}
@@ -0,0 +1,253 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// dOpsHeaderSize is the size of the fields that precede the channel
+	// mapping table.
+	dOpsHeaderSize = 11
+
+	// opusSampleRate is the rate that Opus always decodes at.
+	opusSampleRate = 48000
+)
+
+var (
+	// vorbisChannelLayouts are the speaker positions of channel-mapping family
+	// one by channel-count (from the Vorbis I specification).
+	vorbisChannelLayouts = map[int][]AudioChannel{
+		1: {AudioChannelFrontCenter},
+		2: {AudioChannelFrontLeft, AudioChannelFrontRight},
+		3: {AudioChannelFrontLeft, AudioChannelFrontCenter, AudioChannelFrontRight},
+		4: {AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight},
+		5: {AudioChannelFrontLeft, AudioChannelFrontCenter, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight},
+		6: {AudioChannelFrontLeft, AudioChannelFrontCenter, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight, AudioChannelLowFrequency},
+		7: {AudioChannelFrontLeft, AudioChannelFrontCenter, AudioChannelFrontRight, AudioChannelSideLeft, AudioChannelSideRight, AudioChannelBackCenter, AudioChannelLowFrequency},
+		8: {AudioChannelFrontLeft, AudioChannelFrontCenter, AudioChannelFrontRight, AudioChannelSideLeft, AudioChannelSideRight, AudioChannelBackLeft, AudioChannelBackRight, AudioChannelLowFrequency},
+	}
+)
+
+// DOpsBox is the "Opus Specific" box from the Opus ISOBMFF encapsulation. Unlike
+// the Ogg identification header, the fields are big-endian.
+type DOpsBox struct {
+	bmfcommon.Box
+
+	version              uint8
+	outputChannelCount   uint8
+	preSkip              uint16
+	inputSampleRate      uint32
+	outputGain           int16
+	channelMappingFamily uint8
+	streamCount          uint8
+	coupledCount         uint8
+	channelMapping       []byte
+}
+
+// Version returns the version of the box. It is always zero.
+func (db *DOpsBox) Version() uint8 {
+	return db.version
+}
+
+// OutputChannelCount returns the number of decoded channels.
+func (db *DOpsBox) OutputChannelCount() uint8 {
+	return db.outputChannelCount
+}
+
+// PreSkip returns the number of samples (at 48kHz) to discard from the start.
+func (db *DOpsBox) PreSkip() uint16 {
+	return db.preSkip
+}
+
+// InputSampleRate returns the sample-rate of the original input. This is only
+// informational; Opus always decodes at 48kHz.
+func (db *DOpsBox) InputSampleRate() uint32 {
+	return db.inputSampleRate
+}
+
+// OutputGain returns the gain to apply in Q7.8 decibels.
+func (db *DOpsBox) OutputGain() int16 {
+	return db.outputGain
+}
+
+// ChannelMappingFamily returns the channel-mapping family.
+func (db *DOpsBox) ChannelMappingFamily() uint8 {
+	return db.channelMappingFamily
+}
+
+// StreamCount returns the number of Opus streams. This is only present for
+// mapping families other than zero.
+func (db *DOpsBox) StreamCount() uint8 {
+	return db.streamCount
+}
+
+// CoupledCount returns the number of streams that are coupled (stereo). This
+// is only present for mapping families other than zero.
+func (db *DOpsBox) CoupledCount() uint8 {
+	return db.coupledCount
+}
+
+// ChannelMapping returns the stream index of each output channel. This is only
+// present for mapping families other than zero.
+func (db *DOpsBox) ChannelMapping() []byte {
+	return db.channelMapping
+}
+
+// CodecString returns the RFC 6381 codec string ("opus").
+func (db *DOpsBox) CodecString(sampleEntryName string) string {
+	return "opus"
+}
+
+// ChannelCount returns the number of decoded channels.
+func (db *DOpsBox) ChannelCount() int {
+	return int(db.outputChannelCount)
+}
+
+// ChannelLayout returns the decoded speaker positions or nil if not known.
+// Only families zero and one have defined positions.
+func (db *DOpsBox) ChannelLayout() []AudioChannel {
+	if db.channelMappingFamily == 0 && db.outputChannelCount > 2 {
+		return nil
+	} else if db.channelMappingFamily > 1 {
+		return nil
+	}
+
+	return vorbisChannelLayouts[int(db.outputChannelCount)]
+}
+
+// SampleRate returns the decoded sample-rate.
+func (db *DOpsBox) SampleRate() uint32 {
+	return opusSampleRate
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (db *DOpsBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s CHANNELS=(%d) PRE-SKIP=(%d) INPUT-RATE=(%d) GAIN=(%d) FAMILY=(%d)",
+		db.Box.InlineString(), db.outputChannelCount, db.preSkip, db.inputSampleRate, db.outputGain,
+		db.channelMappingFamily)
+}
+
+func (b *DOpsBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < dOpsHeaderSize {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), dOpsHeaderSize, int64(len(data))))
+	}
+
+	b.version = data[0]
+	if b.version != 0 {
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(b, b.version, []byte{0}))
+	}
+
+	b.outputChannelCount = data[1]
+	b.preSkip = bmfcommon.DefaultEndianness.Uint16(data[2:4])
+	b.inputSampleRate = bmfcommon.DefaultEndianness.Uint32(data[4:8])
+	b.outputGain = int16(bmfcommon.DefaultEndianness.Uint16(data[8:10]))
+	b.channelMappingFamily = data[10]
+
+	if b.channelMappingFamily != 0 {
+		tableSize := 2 + int(b.outputChannelCount)
+		if len(data)-dOpsHeaderSize < tableSize {
+			log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize()+dOpsHeaderSize, int64(tableSize), int64(len(data)-dOpsHeaderSize)))
+		}
+
+		b.streamCount = data[11]
+		b.coupledCount = data[12]
+		b.channelMapping = data[13 : 13+int(b.outputChannelCount)]
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (db *DOpsBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"version":                db.version,
+		"output_channel_count":   db.outputChannelCount,
+		"pre_skip":               db.preSkip,
+		"input_sample_rate":      db.inputSampleRate,
+		"output_gain":            db.outputGain,
+		"channel_mapping_family": db.channelMappingFamily,
+	}
+
+	if db.channelMappingFamily != 0 {
+		fields["stream_count"] = db.streamCount
+		fields["coupled_count"] = db.coupledCount
+		fields["channel_mapping"] = db.channelMapping
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (db *DOpsBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	bmfcommon.PushBytes(&data, db.version)
+	bmfcommon.PushBytes(&data, db.outputChannelCount)
+	bmfcommon.PushBytes(&data, db.preSkip)
+	bmfcommon.PushBytes(&data, db.inputSampleRate)
+	bmfcommon.PushBytes(&data, uint16(db.outputGain))
+	bmfcommon.PushBytes(&data, db.channelMappingFamily)
+
+	if db.channelMappingFamily != 0 {
+		bmfcommon.PushBytes(&data, db.streamCount)
+		bmfcommon.PushBytes(&data, db.coupledCount)
+		bmfcommon.PushBytes(&data, db.channelMapping)
+	}
+
+	return data, nil
+}
+
+type dOpsBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (dOpsBoxFactory) Name() string {
+	return "dOps"
+}
+
+// New returns a new value instance.
+func (dOpsBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	dOpsBox := &DOpsBox{
+		Box: box,
+	}
+
+	err = dOpsBox.parse()
+	log.PanicIf(err)
+
+	return dOpsBox, -1, nil
+}
+
+var (
+	_ bmfcommon.BoxFactory    = dOpsBoxFactory{}
+	_ bmfcommon.CommonBox     = &DOpsBox{}
+	_ bmfcommon.FieldExporter = &DOpsBox{}
+	_ AudioConfiguration      = &DOpsBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(dOpsBoxFactory{})
+}
@@ -0,0 +1,138 @@
+package bmftype
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// getTestDOpsData returns a "dOps" payload with the given channel-count and
+// mapping family.
+func getTestDOpsData(channels uint8, family uint8) []byte {
+	var data []byte
+
+	bmfcommon.PushBytes(&data, uint8(0))
+	bmfcommon.PushBytes(&data, channels)
+
+	// PreSkip, InputSampleRate, and OutputGain (-1.5dB)
+	bmfcommon.PushBytes(&data, uint16(312))
+	bmfcommon.PushBytes(&data, uint32(44100))
+	bmfcommon.PushBytes(&data, uint16(0xfe80))
+
+	bmfcommon.PushBytes(&data, family)
+
+	if family != 0 {
+		mapping := make([]byte, channels)
+		for i := range mapping {
+			mapping[i] = byte(i)
+		}
+
+		bmfcommon.PushBytes(&data, []byte{channels - 2, 2})
+		bmfcommon.PushBytes(&data, mapping)
+	}
+
+	return data
+}
+
+func TestDOpsBoxFactory_Name(t *testing.T) {
+	if (dOpsBoxFactory{}).Name() != "dOps" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestDOpsBoxFactory_New(t *testing.T) {
+	data := getTestDOpsData(2, 0)
+
+	cb := getTestParsedBox(dOpsBoxFactory{}, data)
+	dOps := cb.(*DOpsBox)
+
+	if dOps.Version() != 0 {
+		t.Fatalf("Version() not correct.")
+	} else if dOps.OutputChannelCount() != 2 {
+		t.Fatalf("OutputChannelCount() not correct.")
+	} else if dOps.PreSkip() != 312 {
+		t.Fatalf("PreSkip() not correct.")
+	} else if dOps.InputSampleRate() != 44100 {
+		t.Fatalf("InputSampleRate() not correct.")
+	} else if dOps.OutputGain() != -384 {
+		t.Fatalf("OutputGain() not correct: (%d)", dOps.OutputGain())
+	} else if dOps.ChannelMappingFamily() != 0 {
+		t.Fatalf("ChannelMappingFamily() not correct.")
+	} else if dOps.SampleRate() != 48000 {
+		t.Fatalf("SampleRate() not correct.")
+	} else if dOps.CodecString("Opus") != "opus" {
+		t.Fatalf("CodecString() not correct.")
+	}
+
+	expectedLayout := []AudioChannel{AudioChannelFrontLeft, AudioChannelFrontRight}
+	if reflect.DeepEqual(dOps.ChannelLayout(), expectedLayout) != true {
+		t.Fatalf("ChannelLayout() not correct: %v", dOps.ChannelLayout())
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestDOpsBoxFactory_New_Family1(t *testing.T) {
+	data := getTestDOpsData(6, 1)
+
+	cb := getTestParsedBox(dOpsBoxFactory{}, data)
+	dOps := cb.(*DOpsBox)
+
+	if dOps.StreamCount() != 4 {
+		t.Fatalf("StreamCount() not correct.")
+	} else if dOps.CoupledCount() != 2 {
+		t.Fatalf("CoupledCount() not correct.")
+	} else if bytes.Equal(dOps.ChannelMapping(), []byte{0, 1, 2, 3, 4, 5}) != true {
+		t.Fatalf("ChannelMapping() not correct.")
+	} else if dOps.ChannelCount() != 6 {
+		t.Fatalf("ChannelCount() not correct.")
+	}
+
+	expectedLayout := []AudioChannel{
+		AudioChannelFrontLeft,
+		AudioChannelFrontCenter,
+		AudioChannelFrontRight,
+		AudioChannelBackLeft,
+		AudioChannelBackRight,
+		AudioChannelLowFrequency,
+	}
+
+	if reflect.DeepEqual(dOps.ChannelLayout(), expectedLayout) != true {
+		t.Fatalf("ChannelLayout() not correct: %v", dOps.ChannelLayout())
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestDOpsBox_ChannelLayout_Unknown(t *testing.T) {
+	dOps := getTestParsedBox(dOpsBoxFactory{}, getTestDOpsData(4, 255)).(*DOpsBox)
+
+	if dOps.ChannelLayout() != nil {
+		t.Fatalf("ChannelLayout() should not be known.")
+	} else if dOps.ChannelCount() != 4 {
+		t.Fatalf("ChannelCount() not correct.")
+	}
+}
+
+func TestDOpsBoxFactory_New_Invalid(t *testing.T) {
+	data := getTestDOpsData(6, 1)
+
+	_, err := getTestBoxFactoryNew(dOpsBoxFactory{}, data[:8])
+	if err == nil {
+		t.Fatalf("Expected error for truncated header.")
+	}
+
+	_, err = getTestBoxFactoryNew(dOpsBoxFactory{}, data[:len(data)-1])
+	if err == nil {
+		t.Fatalf("Expected error for truncated mapping table.")
+	}
+
+	data[0] = 1
+
+	_, err = getTestBoxFactoryNew(dOpsBoxFactory{}, data)
+	if err == nil {
+		t.Fatalf("Expected error for unsupported version.")
+	}
+}
@@ -0,0 +1,510 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// ObjectTypeMpeg4Audio is the object-type indication for MPEG-4 audio,
+	// whose decoder-specific info is an AudioSpecificConfig.
+	ObjectTypeMpeg4Audio = 0x40
+)
+
+// Audio object-types from ISO 14496-3.
+const (
+	AudioObjectTypeAacMain = 1
+	AudioObjectTypeAacLc   = 2
+	AudioObjectTypeAacSsr  = 3
+	AudioObjectTypeAacLtp  = 4
+	AudioObjectTypeSbr     = 5
+	AudioObjectTypeAacLd   = 23
+	AudioObjectTypePs      = 29
+	AudioObjectTypeEscape  = 31
+)
+
+const (
+	// syncExtensionTypeSbr introduces backward-compatible SBR signaling at the
+	// end of an AudioSpecificConfig.
+	syncExtensionTypeSbr = 0x2b7
+
+	// syncExtensionTypePs introduces backward-compatible PS signaling.
+	syncExtensionTypePs = 0x548
+)
+
+var (
+	// aacSamplingFrequencies are the sample-rates by samplingFrequencyIndex.
+	aacSamplingFrequencies = []uint32{
+		96000, 88200, 64000, 48000, 44100, 32000, 24000, 22050, 16000, 12000, 11025, 8000, 7350,
+	}
+
+	// aacChannelLayouts are the speaker positions by channelConfiguration, in
+	// the order of the syntactic elements.
+	aacChannelLayouts = map[uint8][]AudioChannel{
+		1:  {AudioChannelFrontCenter},
+		2:  {AudioChannelFrontLeft, AudioChannelFrontRight},
+		3:  {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight},
+		4:  {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackCenter},
+		5:  {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight},
+		6:  {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight, AudioChannelLowFrequency},
+		7:  {AudioChannelFrontCenter, AudioChannelFrontLeftOfCenter, AudioChannelFrontRightOfCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight, AudioChannelLowFrequency},
+		11: {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight, AudioChannelBackCenter, AudioChannelLowFrequency},
+		12: {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelSideLeft, AudioChannelSideRight, AudioChannelBackLeft, AudioChannelBackRight, AudioChannelLowFrequency},
+		14: {AudioChannelFrontCenter, AudioChannelFrontLeft, AudioChannelFrontRight, AudioChannelBackLeft, AudioChannelBackRight, AudioChannelLowFrequency, AudioChannelTopFrontLeft, AudioChannelTopFrontRight},
+	}
+)
+
+// AudioSpecificConfig is the decoder configuration of MPEG-4 audio (ISO
+// 14496-3). Both the explicit (hierarchical) and backward-compatible SBR and PS
+// signaling are recognized.
+type AudioSpecificConfig struct {
+	audioObjectType      uint8
+	coreAudioObjectType  uint8
+	samplingFrequency    uint32
+	channelConfiguration uint8
+
+	sbrPresent                 bool
+	psPresent                  bool
+	extensionSamplingFrequency uint32
+}
+
+// AudioObjectType returns the audio object-type as signaled first. This is SBR
+// (5) or PS (29) if they are signaled explicitly.
+func (asc *AudioSpecificConfig) AudioObjectType() uint8 {
+	return asc.audioObjectType
+}
+
+// CoreAudioObjectType returns the object-type of the core codec (e.g. AAC-LC
+// under SBR).
+func (asc *AudioSpecificConfig) CoreAudioObjectType() uint8 {
+	return asc.coreAudioObjectType
+}
+
+// SamplingFrequency returns the sample-rate of the core codec.
+func (asc *AudioSpecificConfig) SamplingFrequency() uint32 {
+	return asc.samplingFrequency
+}
+
+// ChannelConfiguration returns the channelConfiguration. Zero means that the
+// channels are described by a program-config element, which is not parsed.
+func (asc *AudioSpecificConfig) ChannelConfiguration() uint8 {
+	return asc.channelConfiguration
+}
+
+// SbrPresent returns true if spectral-band replication (HE-AAC) is signaled.
+func (asc *AudioSpecificConfig) SbrPresent() bool {
+	return asc.sbrPresent
+}
+
+// PsPresent returns true if parametric-stereo (HE-AACv2) is signaled.
+func (asc *AudioSpecificConfig) PsPresent() bool {
+	return asc.psPresent
+}
+
+// ExtensionSamplingFrequency returns the sample-rate of the SBR extension or
+// zero if there is no SBR.
+func (asc *AudioSpecificConfig) ExtensionSamplingFrequency() uint32 {
+	return asc.extensionSamplingFrequency
+}
+
+// SampleRate returns the decoded sample-rate.
+func (asc *AudioSpecificConfig) SampleRate() uint32 {
+	if asc.sbrPresent == true && asc.extensionSamplingFrequency != 0 {
+		return asc.extensionSamplingFrequency
+	}
+
+	return asc.samplingFrequency
+}
+
+// ChannelLayout returns the decoded speaker positions or nil if not known. A
+// mono core with PS decodes to stereo.
+func (asc *AudioSpecificConfig) ChannelLayout() []AudioChannel {
+	if asc.psPresent == true && asc.channelConfiguration == 1 {
+		return aacChannelLayouts[2]
+	}
+
+	return aacChannelLayouts[asc.channelConfiguration]
+}
+
+// ChannelCount returns the number of decoded channels or zero if not known.
+func (asc *AudioSpecificConfig) ChannelCount() int {
+	if asc.channelConfiguration == 13 {
+		// 22.2
+		return 24
+	}
+
+	return len(asc.ChannelLayout())
+}
+
+// String returns a descriptive string.
+func (asc *AudioSpecificConfig) String() string {
+	return fmt.Sprintf(
+		"AudioSpecificConfig<AOT=(%d) CORE-AOT=(%d) FREQ=(%d) CHANNEL-CONFIG=(%d) SBR=[%v] PS=[%v]>",
+		asc.audioObjectType, asc.coreAudioObjectType, asc.samplingFrequency, asc.channelConfiguration,
+		asc.sbrPresent, asc.psPresent)
+}
+
+// readAudioObjectType reads an audioObjectType with its escape.
+func readAudioObjectType(br *bmfcommon.BitReader) uint8 {
+	aot, err := br.ReadBits(5)
+	log.PanicIf(err)
+
+	if aot == AudioObjectTypeEscape {
+		extension, err := br.ReadBits(6)
+		log.PanicIf(err)
+
+		aot = 32 + extension
+	}
+
+	return uint8(aot)
+}
+
+// readSamplingFrequency reads a samplingFrequencyIndex with its escape.
+func readSamplingFrequency(br *bmfcommon.BitReader) uint32 {
+	index, err := br.ReadBits(4)
+	log.PanicIf(err)
+
+	if index == 0xf {
+		frequency, err := br.ReadBits(24)
+		log.PanicIf(err)
+
+		return uint32(frequency)
+	} else if int(index) >= len(aacSamplingFrequencies) {
+		log.Panicf("sampling-frequency index not valid: (%d)", index)
+	}
+
+	return aacSamplingFrequencies[index]
+}
+
+// ParseAudioSpecificConfig parses an AudioSpecificConfig. The fields after
+// the GASpecificConfig are only looked at for the general-audio object-types
+// that do not use a program-config element.
+func ParseAudioSpecificConfig(data []byte) (asc *AudioSpecificConfig, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	br := bmfcommon.NewBitReader(data)
+
+	asc = new(AudioSpecificConfig)
+
+	asc.audioObjectType = readAudioObjectType(br)
+	asc.samplingFrequency = readSamplingFrequency(br)
+
+	channelConfiguration, err := br.ReadBits(4)
+	log.PanicIf(err)
+
+	asc.channelConfiguration = uint8(channelConfiguration)
+
+	asc.coreAudioObjectType = asc.audioObjectType
+
+	if asc.audioObjectType == AudioObjectTypeSbr || asc.audioObjectType == AudioObjectTypePs {
+		asc.sbrPresent = true
+		asc.psPresent = asc.audioObjectType == AudioObjectTypePs
+		asc.extensionSamplingFrequency = readSamplingFrequency(br)
+		asc.coreAudioObjectType = readAudioObjectType(br)
+	}
+
+	switch asc.coreAudioObjectType {
+	case AudioObjectTypeAacMain, AudioObjectTypeAacLc, AudioObjectTypeAacSsr, AudioObjectTypeAacLtp:
+	default:
+		return asc, nil
+	}
+
+	// GASpecificConfig
+
+	// frameLengthFlag
+	err = br.Skip(1)
+	log.PanicIf(err)
+
+	dependsOnCoreCoder, err := br.ReadFlag()
+	log.PanicIf(err)
+
+	if dependsOnCoreCoder == true {
+		// coreCoderDelay
+		err = br.Skip(14)
+		log.PanicIf(err)
+	}
+
+	// extensionFlag
+	err = br.Skip(1)
+	log.PanicIf(err)
+
+	if asc.channelConfiguration == 0 || asc.sbrPresent == true {
+		return asc, nil
+	}
+
+	// Backward-compatible signaling.
+
+	if br.Remaining() < 16 {
+		return asc, nil
+	}
+
+	syncExtensionType, err := br.ReadBits(11)
+	log.PanicIf(err)
+
+	if syncExtensionType != syncExtensionTypeSbr {
+		return asc, nil
+	}
+
+	if readAudioObjectType(br) != AudioObjectTypeSbr {
+		return asc, nil
+	}
+
+	sbrPresent, err := br.ReadFlag()
+	log.PanicIf(err)
+
+	if sbrPresent == false {
+		return asc, nil
+	}
+
+	asc.sbrPresent = true
+	asc.extensionSamplingFrequency = readSamplingFrequency(br)
+
+	if br.Remaining() < 12 {
+		return asc, nil
+	}
+
+	syncExtensionType, err = br.ReadBits(11)
+	log.PanicIf(err)
+
+	if syncExtensionType == syncExtensionTypePs {
+		asc.psPresent, err = br.ReadFlag()
+		log.PanicIf(err)
+	}
+
+	return asc, nil
+}
+
+// EsdsBox is the "Elementary Stream Descriptor" box. It has the ES_Descriptor
+// from ISO 14496-1 for MPEG-4 audio and video sample-entries.
+type EsdsBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	esDescriptor        *bmfcommon.EsDescriptor
+	audioSpecificConfig *AudioSpecificConfig
+}
+
+// EsDescriptor returns the ES_Descriptor.
+func (eb *EsdsBox) EsDescriptor() *bmfcommon.EsDescriptor {
+	return eb.esDescriptor
+}
+
+// EsId returns the ID of the elementary stream.
+func (eb *EsdsBox) EsId() uint16 {
+	return eb.esDescriptor.EsId
+}
+
+// ObjectTypeIndication returns the object-type (e.g. 0x40 for MPEG-4 audio).
+func (eb *EsdsBox) ObjectTypeIndication() uint8 {
+	return eb.esDescriptor.DecoderConfig.ObjectTypeIndication
+}
+
+// StreamType returns the stream-type (e.g. 5 for audio).
+func (eb *EsdsBox) StreamType() uint8 {
+	return eb.esDescriptor.DecoderConfig.StreamType
+}
+
+// BufferSizeDb returns the size of the decoding buffer in bytes.
+func (eb *EsdsBox) BufferSizeDb() uint32 {
+	return eb.esDescriptor.DecoderConfig.BufferSizeDb
+}
+
+// MaxBitrate returns the maximum bitrate in bits-per-second.
+func (eb *EsdsBox) MaxBitrate() uint32 {
+	return eb.esDescriptor.DecoderConfig.MaxBitrate
+}
+
+// AvgBitrate returns the average bitrate in bits-per-second or zero if
+// variable.
+func (eb *EsdsBox) AvgBitrate() uint32 {
+	return eb.esDescriptor.DecoderConfig.AvgBitrate
+}
+
+// DecoderSpecificInfo returns the raw decoder-specific info, if any.
+func (eb *EsdsBox) DecoderSpecificInfo() []byte {
+	dsi := eb.esDescriptor.DecoderConfig.DecoderSpecificInfo
+	if dsi == nil {
+		return nil
+	}
+
+	return dsi.Data
+}
+
+// AudioSpecificConfig returns the parsed decoder-specific info for MPEG-4
+// audio or nil.
+func (eb *EsdsBox) AudioSpecificConfig() *AudioSpecificConfig {
+	return eb.audioSpecificConfig
+}
+
+// CodecString returns the RFC 6381 codec string (e.g. "mp4a.40.2").
+func (eb *EsdsBox) CodecString(sampleEntryName string) string {
+	codec := fmt.Sprintf("%s.%02X", sampleEntryName, eb.ObjectTypeIndication())
+
+	if eb.audioSpecificConfig != nil {
+		codec = fmt.Sprintf("%s.%d", codec, eb.audioSpecificConfig.AudioObjectType())
+	}
+
+	return codec
+}
+
+// ChannelCount returns the number of decoded channels or zero if not known.
+func (eb *EsdsBox) ChannelCount() int {
+	if eb.audioSpecificConfig == nil {
+		return 0
+	}
+
+	return eb.audioSpecificConfig.ChannelCount()
+}
+
+// ChannelLayout returns the decoded speaker positions or nil if not known.
+func (eb *EsdsBox) ChannelLayout() []AudioChannel {
+	if eb.audioSpecificConfig == nil {
+		return nil
+	}
+
+	return eb.audioSpecificConfig.ChannelLayout()
+}
+
+// SampleRate returns the decoded sample-rate or zero if not known.
+func (eb *EsdsBox) SampleRate() uint32 {
+	if eb.audioSpecificConfig == nil {
+		return 0
+	}
+
+	return eb.audioSpecificConfig.SampleRate()
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (eb *EsdsBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s ES-ID=(%d) OTI=(0x%02x) STREAM-TYPE=(%d) MAX-BITRATE=(%d) AVG-BITRATE=(%d)",
+		eb.Box.InlineString(), eb.EsId(), eb.ObjectTypeIndication(), eb.StreamType(), eb.MaxBitrate(),
+		eb.AvgBitrate())
+}
+
+func (b *EsdsBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	// The version and flags were already checked.
+	data = data[4:]
+
+	d, _, err := bmfcommon.ReadDescriptor(data, b.Start()+b.HeaderSize()+4)
+	log.PanicIf(err)
+
+	es, ok := d.(*bmfcommon.EsDescriptor)
+	if ok != true {
+		log.Panicf("esds: descriptor is not an ES_Descriptor: (0x%02x)", d.DescriptorTag())
+	} else if es.DecoderConfig == nil {
+		log.Panicf("esds: no DecoderConfigDescriptor")
+	}
+
+	b.esDescriptor = es
+
+	dsi := es.DecoderConfig.DecoderSpecificInfo
+	if es.DecoderConfig.ObjectTypeIndication == ObjectTypeMpeg4Audio && dsi != nil {
+		b.audioSpecificConfig, err = ParseAudioSpecificConfig(dsi.Data)
+		log.PanicIf(err)
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (eb *EsdsBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"es_id":                  eb.EsId(),
+		"object_type_indication": eb.ObjectTypeIndication(),
+		"stream_type":            eb.StreamType(),
+		"buffer_size_db":         eb.BufferSizeDb(),
+		"max_bitrate":            eb.MaxBitrate(),
+		"avg_bitrate":            eb.AvgBitrate(),
+	}
+
+	if asc := eb.audioSpecificConfig; asc != nil {
+		fields["audio_object_type"] = asc.AudioObjectType()
+		fields["sampling_frequency"] = asc.SamplingFrequency()
+		fields["channel_configuration"] = asc.ChannelConfiguration()
+		fields["sbr_present"] = asc.SbrPresent()
+		fields["ps_present"] = asc.PsPresent()
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (eb *EsdsBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	bmfcommon.PushBytes(&data, uint32(eb.Version())<<24|eb.Flags()&0x00ffffff)
+
+	encoded, err := bmfcommon.EncodeDescriptor(eb.esDescriptor)
+	log.PanicIf(err)
+
+	bmfcommon.PushBytes(&data, encoded)
+
+	return data, nil
+}
+
+type esdsBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (esdsBoxFactory) Name() string {
+	return "esds"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (esdsBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf esdsBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	esdsBox := &EsdsBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = esdsBox.parse()
+	log.PanicIf(err)
+
+	return esdsBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = esdsBoxFactory{}
+	_ bmfcommon.CommonBox      = &EsdsBox{}
+	_ bmfcommon.FieldExporter  = &EsdsBox{}
+	_ AudioConfiguration       = &EsdsBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(esdsBoxFactory{})
+}
@@ -0,0 +1,189 @@
+package bmftype
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+var (
+	// testEsdsData is the "esds" payload from the tears-of-steel asset (AAC-LC,
+	// 44.1kHz, stereo). The sizes use the padded four-byte encoding.
+	testEsdsData, _ = hex.DecodeString("0000000003808080250002000480808017401500000000" + "02e3fd0002e3fd0580808005121056e5000680808001" + "02")
+)
+
+func TestEsdsBoxFactory_Name(t *testing.T) {
+	if (esdsBoxFactory{}).Name() != "esds" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestEsdsBoxFactory_New(t *testing.T) {
+	cb := getTestParsedBox(esdsBoxFactory{}, testEsdsData)
+	esds := cb.(*EsdsBox)
+
+	if esds.EsId() != 2 {
+		t.Fatalf("EsId() not correct.")
+	} else if esds.ObjectTypeIndication() != ObjectTypeMpeg4Audio {
+		t.Fatalf("ObjectTypeIndication() not correct.")
+	} else if esds.StreamType() != 5 {
+		t.Fatalf("StreamType() not correct.")
+	} else if esds.BufferSizeDb() != 0 {
+		t.Fatalf("BufferSizeDb() not correct.")
+	} else if esds.MaxBitrate() != 189437 {
+		t.Fatalf("MaxBitrate() not correct.")
+	} else if esds.AvgBitrate() != 189437 {
+		t.Fatalf("AvgBitrate() not correct.")
+	} else if bytes.Equal(esds.DecoderSpecificInfo(), []byte{0x12, 0x10, 0x56, 0xe5, 0x00}) != true {
+		t.Fatalf("DecoderSpecificInfo() not correct.")
+	}
+
+	asc := esds.AudioSpecificConfig()
+	if asc == nil {
+		t.Fatalf("AudioSpecificConfig() not parsed.")
+	}
+
+	// The backward-compatible SBR signaling is present but off.
+	if asc.AudioObjectType() != AudioObjectTypeAacLc {
+		t.Fatalf("AudioObjectType() not correct.")
+	} else if asc.SamplingFrequency() != 44100 {
+		t.Fatalf("SamplingFrequency() not correct.")
+	} else if asc.ChannelConfiguration() != 2 {
+		t.Fatalf("ChannelConfiguration() not correct.")
+	} else if asc.SbrPresent() != false {
+		t.Fatalf("SbrPresent() not correct.")
+	}
+
+	if esds.CodecString("mp4a") != "mp4a.40.2" {
+		t.Fatalf("CodecString() not correct: [%s]", esds.CodecString("mp4a"))
+	} else if esds.ChannelCount() != 2 {
+		t.Fatalf("ChannelCount() not correct.")
+	} else if esds.SampleRate() != 44100 {
+		t.Fatalf("SampleRate() not correct.")
+	}
+
+	assertEncodeData(t, cb, testEsdsData)
+}
+
+func TestEsdsBox_CodecString_NotMpeg4Audio(t *testing.T) {
+	// MP3 (0x6b) with no decoder-specific info and minimal size encoding.
+	data, _ := hex.DecodeString("00000000" + "0312" + "000100" + "040d" + "6b150000000001f4000001f400")
+
+	esds := getTestParsedBox(esdsBoxFactory{}, data).(*EsdsBox)
+
+	if esds.EsId() != 1 {
+		t.Fatalf("EsId() not correct.")
+	} else if esds.AudioSpecificConfig() != nil {
+		t.Fatalf("AudioSpecificConfig() not correct.")
+	} else if esds.CodecString("mp4a") != "mp4a.6B" {
+		t.Fatalf("CodecString() not correct: [%s]", esds.CodecString("mp4a"))
+	} else if esds.ChannelCount() != 0 || esds.ChannelLayout() != nil || esds.SampleRate() != 0 {
+		t.Fatalf("Audio properties should not be known.")
+	}
+}
+
+func TestEsdsBoxFactory_New_Invalid(t *testing.T) {
+	// No ES_Descriptor.
+	_, err := getTestBoxFactoryNew(esdsBoxFactory{}, []byte{0, 0, 0, 0, 0x06, 0x01, 0x02})
+	if err == nil {
+		t.Fatalf("Expected error for missing ES_Descriptor.")
+	}
+
+	// Descriptor size exceeds the box.
+	_, err = getTestBoxFactoryNew(esdsBoxFactory{}, testEsdsData[:20])
+	if err == nil {
+		t.Fatalf("Expected error for truncated descriptor.")
+	}
+}
+
+func TestParseAudioSpecificConfig(t *testing.T) {
+	cases := []struct {
+		description         string
+		hexData             string
+		audioObjectType     uint8
+		coreAudioObjectType uint8
+		sbrPresent          bool
+		psPresent           bool
+		sampleRate          uint32
+		channelCount        int
+	}{
+		{"Explicit SBR", "2b118800", AudioObjectTypeSbr, AudioObjectTypeAacLc, true, false, 48000, 2},
+		{"Explicit PS", "eb098800", AudioObjectTypePs, AudioObjectTypeAacLc, true, true, 48000, 2},
+		{"Backward-compatible SBR and PS", "130856e59d4880", AudioObjectTypeAacLc, AudioObjectTypeAacLc, true, true, 48000, 2},
+		{"Escaped frequency", "1780560c30", AudioObjectTypeAacLc, AudioObjectTypeAacLc, false, false, 44056, 6},
+		{"Escaped object-type", "f94640", 42, 42, false, false, 48000, 2},
+	}
+
+	for _, c := range cases {
+		data, err := hex.DecodeString(c.hexData)
+		log.PanicIf(err)
+
+		asc, err := ParseAudioSpecificConfig(data)
+		log.PanicIf(err)
+
+		if asc.AudioObjectType() != c.audioObjectType {
+			t.Fatalf("%s: AudioObjectType() not correct: (%d)", c.description, asc.AudioObjectType())
+		} else if asc.CoreAudioObjectType() != c.coreAudioObjectType {
+			t.Fatalf("%s: CoreAudioObjectType() not correct: (%d)", c.description, asc.CoreAudioObjectType())
+		} else if asc.SbrPresent() != c.sbrPresent {
+			t.Fatalf("%s: SbrPresent() not correct.", c.description)
+		} else if asc.PsPresent() != c.psPresent {
+			t.Fatalf("%s: PsPresent() not correct.", c.description)
+		} else if asc.SampleRate() != c.sampleRate {
+			t.Fatalf("%s: SampleRate() not correct: (%d)", c.description, asc.SampleRate())
+		} else if asc.ChannelCount() != c.channelCount {
+			t.Fatalf("%s: ChannelCount() not correct: (%d)", c.description, asc.ChannelCount())
+		}
+	}
+}
+
+func TestAudioSpecificConfig_ChannelLayout(t *testing.T) {
+	asc := &AudioSpecificConfig{
+		channelConfiguration: 6,
+	}
+
+	expected := []AudioChannel{
+		AudioChannelFrontCenter,
+		AudioChannelFrontLeft,
+		AudioChannelFrontRight,
+		AudioChannelBackLeft,
+		AudioChannelBackRight,
+		AudioChannelLowFrequency,
+	}
+
+	if reflect.DeepEqual(asc.ChannelLayout(), expected) != true {
+		t.Fatalf("5.1 layout not correct: %v", asc.ChannelLayout())
+	}
+
+	asc.channelConfiguration = 0
+
+	if asc.ChannelLayout() != nil || asc.ChannelCount() != 0 {
+		t.Fatalf("Program-config layout should not be known.")
+	}
+
+	asc.channelConfiguration = 13
+
+	if asc.ChannelCount() != 24 {
+		t.Fatalf("22.2 channel-count not correct.")
+	}
+}
+
+func TestParseAudioSpecificConfig_Invalid(t *testing.T) {
+	_, err := ParseAudioSpecificConfig([]byte{0x12})
+	if err == nil {
+		t.Fatalf("Expected error for truncated config.")
+	} else if log.Is(err, bmfcommon.ErrBitsExhausted) != true {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	// Sampling-frequency index 13 is reserved.
+	_, err = ParseAudioSpecificConfig([]byte{0x16, 0x90})
+	if err == nil {
+		t.Fatalf("Expected error for reserved frequency index.")
+	}
+}
@@ -0,0 +1,402 @@
+package bmftype
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// HevcNalUnitTypeVps is the NAL unit type of a video parameter set.
+	HevcNalUnitTypeVps = 32
+
+	// HevcNalUnitTypeSps is the NAL unit type of a sequence parameter set.
+	HevcNalUnitTypeSps = 33
+
+	// HevcNalUnitTypePps is the NAL unit type of a picture parameter set.
+	HevcNalUnitTypePps = 34
+)
+
+const (
+	// hvcCHeaderSize is the size of the fixed part of the record.
+	hvcCHeaderSize = 23
+)
+
+// HvccNalUnitArray is the set of NAL units of one type in the configuration.
+type HvccNalUnitArray struct {
+	isComplete  bool
+	nalUnitType uint8
+	nalUnits    [][]byte
+}
+
+// IsComplete returns true if all NAL units of this type are in the array and
+// none are in the stream.
+func (hna HvccNalUnitArray) IsComplete() bool {
+	return hna.isComplete
+}
+
+// NalUnitType returns the type of the NAL units (e.g. HevcNalUnitTypeSps).
+func (hna HvccNalUnitArray) NalUnitType() uint8 {
+	return hna.nalUnitType
+}
+
+// NalUnits returns the NAL units.
+func (hna HvccNalUnitArray) NalUnits() [][]byte {
+	return hna.nalUnits
+}
+
+// HvcCBox is the "HEVC Configuration" box. It has the
+// HEVCDecoderConfigurationRecord for H.265 (ISO/IEC 14496-15).
+type HvcCBox struct {
+	bmfcommon.Box
+
+	configurationVersion     uint8
+	profileSpace             uint8
+	tierFlag                 bool
+	profile                  uint8
+	profileCompatibility     uint32
+	constraintIndicatorFlags uint64
+	level                    uint8
+	minSpatialSegmentation   uint16
+	parallelismType          uint8
+	chromaFormat             uint8
+	bitDepthLumaMinus8       uint8
+	bitDepthChromaMinus8     uint8
+	averageFrameRate         uint16
+	constantFrameRate        uint8
+	numTemporalLayers        uint8
+	temporalIdNested         bool
+	lengthSizeMinusOne       uint8
+
+	arrays []HvccNalUnitArray
+}
+
+// ConfigurationVersion returns the version of the record. It is always one.
+func (hb *HvcCBox) ConfigurationVersion() uint8 {
+	return hb.configurationVersion
+}
+
+// ProfileSpace returns the general profile space. It is normally zero.
+func (hb *HvcCBox) ProfileSpace() uint8 {
+	return hb.profileSpace
+}
+
+// TierFlag returns true for the High tier and false for the Main tier.
+func (hb *HvcCBox) TierFlag() bool {
+	return hb.tierFlag
+}
+
+// Profile returns the general profile (e.g. 1 for Main or 2 for Main 10).
+func (hb *HvcCBox) Profile() uint8 {
+	return hb.profile
+}
+
+// ProfileCompatibility returns the 32 general profile-compatibility flags.
+func (hb *HvcCBox) ProfileCompatibility() uint32 {
+	return hb.profileCompatibility
+}
+
+// ConstraintIndicatorFlags returns the 48 general constraint-indicator flags.
+func (hb *HvcCBox) ConstraintIndicatorFlags() uint64 {
+	return hb.constraintIndicatorFlags
+}
+
+// Level returns the general level, which is 30 times the level number (e.g.
+// 93 for 3.1).
+func (hb *HvcCBox) Level() uint8 {
+	return hb.level
+}
+
+// MinSpatialSegmentation returns the min_spatial_segmentation_idc.
+func (hb *HvcCBox) MinSpatialSegmentation() uint16 {
+	return hb.minSpatialSegmentation
+}
+
+// ParallelismType returns the type of parallel decoding that is supported.
+func (hb *HvcCBox) ParallelismType() uint8 {
+	return hb.parallelismType
+}
+
+// ChromaFormat returns the chroma format (chroma_format_idc, e.g. 1 for
+// 4:2:0).
+func (hb *HvcCBox) ChromaFormat() uint8 {
+	return hb.chromaFormat
+}
+
+// BitDepthLuma returns the bit-depth of luma samples.
+func (hb *HvcCBox) BitDepthLuma() int {
+	return int(hb.bitDepthLumaMinus8) + 8
+}
+
+// BitDepthChroma returns the bit-depth of chroma samples.
+func (hb *HvcCBox) BitDepthChroma() int {
+	return int(hb.bitDepthChromaMinus8) + 8
+}
+
+// AverageFrameRate returns the average frame-rate in frames per 256 seconds,
+// or zero if not specified.
+func (hb *HvcCBox) AverageFrameRate() uint16 {
+	return hb.averageFrameRate
+}
+
+// ConstantFrameRate returns whether the stream has a constant frame-rate (one
+// or two) or may not (zero).
+func (hb *HvcCBox) ConstantFrameRate() uint8 {
+	return hb.constantFrameRate
+}
+
+// NumTemporalLayers returns the number of temporal layers, or zero if not
+// known.
+func (hb *HvcCBox) NumTemporalLayers() uint8 {
+	return hb.numTemporalLayers
+}
+
+// TemporalIdNested returns the temporal_id_nesting_flag.
+func (hb *HvcCBox) TemporalIdNested() bool {
+	return hb.temporalIdNested
+}
+
+// NalUnitLengthSize returns the size of the length that precedes each NAL
+// unit in the samples.
+func (hb *HvcCBox) NalUnitLengthSize() int {
+	return int(hb.lengthSizeMinusOne) + 1
+}
+
+// Arrays returns the NAL-unit arrays.
+func (hb *HvcCBox) Arrays() []HvccNalUnitArray {
+	return hb.arrays
+}
+
+// nalUnitsOfType returns the NAL units in all arrays of the given type.
+func (hb *HvcCBox) nalUnitsOfType(nalUnitType uint8) (nalUnits [][]byte) {
+	for _, hna := range hb.arrays {
+		if hna.nalUnitType == nalUnitType {
+			nalUnits = append(nalUnits, hna.nalUnits...)
+		}
+	}
+
+	return nalUnits
+}
+
+// VideoParameterSets returns the VPS NAL units.
+func (hb *HvcCBox) VideoParameterSets() [][]byte {
+	return hb.nalUnitsOfType(HevcNalUnitTypeVps)
+}
+
+// SequenceParameterSets returns the SPS NAL units.
+func (hb *HvcCBox) SequenceParameterSets() [][]byte {
+	return hb.nalUnitsOfType(HevcNalUnitTypeSps)
+}
+
+// PictureParameterSets returns the PPS NAL units.
+func (hb *HvcCBox) PictureParameterSets() [][]byte {
+	return hb.nalUnitsOfType(HevcNalUnitTypePps)
+}
+
+// CodecString returns the RFC 6381 codec string as described in ISO/IEC
+// 14496-15, Annex E (e.g. "hvc1.1.6.L93.B0").
+func (hb *HvcCBox) CodecString(sampleEntryName string) string {
+	parts := make([]string, 0, 10)
+
+	profileSpace := ""
+	if hb.profileSpace > 0 {
+		profileSpace = string(rune('A' + hb.profileSpace - 1))
+	}
+
+	parts = append(parts, sampleEntryName)
+	parts = append(parts, fmt.Sprintf("%s%d", profileSpace, hb.profile))
+
+	// The compatibility flags are written in reverse bit-order.
+	parts = append(parts, fmt.Sprintf("%X", bits.Reverse32(hb.profileCompatibility)))
+
+	tier := "L"
+	if hb.tierFlag == true {
+		tier = "H"
+	}
+
+	parts = append(parts, fmt.Sprintf("%s%d", tier, hb.level))
+
+	// Each byte of the constraint flags is written, but trailing zero bytes
+	// are omitted.
+
+	constraints := make([]byte, 6)
+	for i := range constraints {
+		constraints[i] = byte(hb.constraintIndicatorFlags >> uint(40-8*i))
+	}
+
+	for len(constraints) > 0 && constraints[len(constraints)-1] == 0 {
+		constraints = constraints[:len(constraints)-1]
+	}
+
+	for _, constraint := range constraints {
+		parts = append(parts, fmt.Sprintf("%X", constraint))
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (hb *HvcCBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s PROFILE=(%d) TIER=[%v] LEVEL=(%d) CHROMA=(%d) BIT-DEPTH=(%d) ARRAYS=(%d)",
+		hb.Box.InlineString(), hb.profile, hb.tierFlag, hb.level, hb.chromaFormat,
+		hb.BitDepthLuma(), len(hb.arrays))
+}
+
+func (b *HvcCBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < hvcCHeaderSize {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), hvcCHeaderSize, int64(len(data))))
+	}
+
+	b.configurationVersion = data[0]
+	b.profileSpace = data[1] >> 6
+	b.tierFlag = data[1]&0x20 != 0
+	b.profile = data[1] & 0x1f
+	b.profileCompatibility = bmfcommon.DefaultEndianness.Uint32(data[2:6])
+	b.constraintIndicatorFlags = uint64(bmfcommon.DefaultEndianness.Uint16(data[6:8]))<<32 | uint64(bmfcommon.DefaultEndianness.Uint32(data[8:12]))
+	b.level = data[12]
+	b.minSpatialSegmentation = bmfcommon.DefaultEndianness.Uint16(data[13:15]) & 0x0fff
+	b.parallelismType = data[15] & 0x03
+	b.chromaFormat = data[16] & 0x03
+	b.bitDepthLumaMinus8 = data[17] & 0x07
+	b.bitDepthChromaMinus8 = data[18] & 0x07
+	b.averageFrameRate = bmfcommon.DefaultEndianness.Uint16(data[19:21])
+	b.constantFrameRate = data[21] >> 6
+	b.numTemporalLayers = (data[21] >> 3) & 0x07
+	b.temporalIdNested = data[21]&0x04 != 0
+	b.lengthSizeMinusOne = data[21] & 0x03
+
+	arrayCount := int(data[22])
+
+	r := bytes.NewReader(data[hvcCHeaderSize:])
+
+	// Each array has at least its type and count.
+	if arrayCount*3 > r.Len() {
+		log.Panicf("hvcC: (%d) arrays can not fit in (%d) bytes", arrayCount, r.Len())
+	}
+
+	b.arrays = make([]HvccNalUnitArray, arrayCount)
+
+	for i := 0; i < arrayCount; i++ {
+		var arrayHeader [3]byte
+
+		_, err := io.ReadFull(r, arrayHeader[:])
+		log.PanicIf(err)
+
+		nalUnits, err := readNalUnits(r, int(bmfcommon.DefaultEndianness.Uint16(arrayHeader[1:3])))
+		log.PanicIf(err)
+
+		b.arrays[i] = HvccNalUnitArray{
+			isComplete:  arrayHeader[0]&0x80 != 0,
+			nalUnitType: arrayHeader[0] & 0x3f,
+			nalUnits:    nalUnits,
+		}
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (hb *HvcCBox) ExportFields() (fields map[string]interface{}, err error) {
+	arrays := make([]map[string]interface{}, len(hb.arrays))
+	for i, hna := range hb.arrays {
+		arrays[i] = map[string]interface{}{
+			"is_complete":   hna.isComplete,
+			"nal_unit_type": hna.nalUnitType,
+			"nal_units":     hexStrings(hna.nalUnits),
+		}
+	}
+
+	fields = map[string]interface{}{
+		"configuration_version":      hb.configurationVersion,
+		"profile_space":              hb.profileSpace,
+		"tier_flag":                  hb.tierFlag,
+		"profile":                    hb.profile,
+		"profile_compatibility":      hb.profileCompatibility,
+		"constraint_indicator_flags": hb.constraintIndicatorFlags,
+		"level":                      hb.level,
+		"min_spatial_segmentation":   hb.minSpatialSegmentation,
+		"parallelism_type":           hb.parallelismType,
+		"chroma_format":              hb.chromaFormat,
+		"bit_depth_luma":             hb.BitDepthLuma(),
+		"bit_depth_chroma":           hb.BitDepthChroma(),
+		"average_frame_rate":         hb.averageFrameRate,
+		"constant_frame_rate":        hb.constantFrameRate,
+		"num_temporal_layers":        hb.numTemporalLayers,
+		"temporal_id_nested":         hb.temporalIdNested,
+		"nal_unit_length_size":       hb.NalUnitLengthSize(),
+		"arrays":                     arrays,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. The record is carried over as
+// read.
+func (hb *HvcCBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := hb.Data()
+	log.PanicIf(err)
+
+	data = make([]byte, len(original))
+	copy(data, original)
+
+	return data, nil
+}
+
+type hvcCBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (hvcCBoxFactory) Name() string {
+	return "hvcC"
+}
+
+// New returns a new value instance.
+func (hvcCBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	hvcCBox := &HvcCBox{
+		Box: box,
+	}
+
+	err = hvcCBox.parse()
+	log.PanicIf(err)
+
+	return hvcCBox, -1, nil
+}
+
+var (
+	_ bmfcommon.BoxFactory    = hvcCBoxFactory{}
+	_ bmfcommon.CommonBox     = &HvcCBox{}
+	_ bmfcommon.FieldExporter = &HvcCBox{}
+	_ CodecConfiguration      = &HvcCBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(hvcCBoxFactory{})
+}
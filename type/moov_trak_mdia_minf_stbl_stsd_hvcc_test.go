@@ -0,0 +1,165 @@
+package bmftype
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// getTestHvcCData returns an "hvcC" payload for 10-bit 4:2:0 with one VPS,
+// SPS, and PPS.
+func getTestHvcCData(profileByte byte, compatibility uint32, constraints []byte, level byte) []byte {
+	var data []byte
+
+	bmfcommon.PushBytes(&data, uint8(1))
+	bmfcommon.PushBytes(&data, profileByte)
+	bmfcommon.PushBytes(&data, compatibility)
+	bmfcommon.PushBytes(&data, constraints)
+	bmfcommon.PushBytes(&data, level)
+
+	// min_spatial_segmentation_idc
+	bmfcommon.PushBytes(&data, uint16(0xf000))
+
+	// parallelismType
+	bmfcommon.PushBytes(&data, uint8(0xfc))
+
+	// chromaFormat (4:2:0)
+	bmfcommon.PushBytes(&data, uint8(0xfd))
+
+	// bitDepthLumaMinus8 and bitDepthChromaMinus8 (10-bit)
+	bmfcommon.PushBytes(&data, uint8(0xfa))
+	bmfcommon.PushBytes(&data, uint8(0xfa))
+
+	// avgFrameRate
+	bmfcommon.PushBytes(&data, uint16(0))
+
+	// constantFrameRate (0), numTemporalLayers (1), temporalIdNested (1),
+	// lengthSizeMinusOne (3)
+	bmfcommon.PushBytes(&data, uint8(0x0f))
+
+	// numOfArrays
+	bmfcommon.PushBytes(&data, uint8(3))
+
+	nalUnits := []struct {
+		nalUnitType byte
+		nalUnit     []byte
+	}{
+		{HevcNalUnitTypeVps, []byte{0x40, 0x01}},
+		{HevcNalUnitTypeSps, []byte{0x42, 0x01, 0x01}},
+		{HevcNalUnitTypePps, []byte{0x44, 0x01}},
+	}
+
+	for _, nu := range nalUnits {
+		bmfcommon.PushBytes(&data, uint8(0x80|nu.nalUnitType))
+		bmfcommon.PushBytes(&data, uint16(1))
+		bmfcommon.PushBytes(&data, uint16(len(nu.nalUnit)))
+		bmfcommon.PushBytes(&data, nu.nalUnit)
+	}
+
+	return data
+}
+
+func TestHvcCBoxFactory_Name(t *testing.T) {
+	if (hvcCBoxFactory{}).Name() != "hvcC" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestHvcCBoxFactory_New(t *testing.T) {
+	data := getTestHvcCData(0x01, 0x60000000, []byte{0xb0, 0, 0, 0, 0, 0}, 93)
+
+	cb := getTestParsedBox(hvcCBoxFactory{}, data)
+	hvcC := cb.(*HvcCBox)
+
+	if hvcC.ConfigurationVersion() != 1 {
+		t.Fatalf("ConfigurationVersion() not correct.")
+	} else if hvcC.ProfileSpace() != 0 {
+		t.Fatalf("ProfileSpace() not correct.")
+	} else if hvcC.TierFlag() != false {
+		t.Fatalf("TierFlag() not correct.")
+	} else if hvcC.Profile() != 1 {
+		t.Fatalf("Profile() not correct.")
+	} else if hvcC.ProfileCompatibility() != 0x60000000 {
+		t.Fatalf("ProfileCompatibility() not correct.")
+	} else if hvcC.ConstraintIndicatorFlags() != 0xb00000000000 {
+		t.Fatalf("ConstraintIndicatorFlags() not correct: (0x%x)", hvcC.ConstraintIndicatorFlags())
+	} else if hvcC.Level() != 93 {
+		t.Fatalf("Level() not correct.")
+	} else if hvcC.ChromaFormat() != 1 {
+		t.Fatalf("ChromaFormat() not correct.")
+	} else if hvcC.BitDepthLuma() != 10 || hvcC.BitDepthChroma() != 10 {
+		t.Fatalf("Bit-depths not correct.")
+	} else if hvcC.NumTemporalLayers() != 1 {
+		t.Fatalf("NumTemporalLayers() not correct.")
+	} else if hvcC.TemporalIdNested() != true {
+		t.Fatalf("TemporalIdNested() not correct.")
+	} else if hvcC.NalUnitLengthSize() != 4 {
+		t.Fatalf("NalUnitLengthSize() not correct.")
+	}
+
+	arrays := hvcC.Arrays()
+	if len(arrays) != 3 {
+		t.Fatalf("Expected three arrays.")
+	} else if arrays[0].IsComplete() != true {
+		t.Fatalf("IsComplete() not correct.")
+	}
+
+	if len(hvcC.VideoParameterSets()) != 1 || bytes.Equal(hvcC.VideoParameterSets()[0], []byte{0x40, 0x01}) != true {
+		t.Fatalf("VideoParameterSets() not correct.")
+	} else if len(hvcC.SequenceParameterSets()) != 1 || bytes.Equal(hvcC.SequenceParameterSets()[0], []byte{0x42, 0x01, 0x01}) != true {
+		t.Fatalf("SequenceParameterSets() not correct.")
+	} else if len(hvcC.PictureParameterSets()) != 1 || bytes.Equal(hvcC.PictureParameterSets()[0], []byte{0x44, 0x01}) != true {
+		t.Fatalf("PictureParameterSets() not correct.")
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestHvcCBox_CodecString(t *testing.T) {
+	cases := []struct {
+		sampleEntryName string
+		profileByte     byte
+		compatibility   uint32
+		constraints     []byte
+		level           byte
+		expected        string
+	}{
+		// Main, Main tier, level 3.1.
+		{"hvc1", 0x01, 0x60000000, []byte{0xb0, 0, 0, 0, 0, 0}, 93, "hvc1.1.6.L93.B0"},
+
+		// Main 10, High tier, level 4.
+		{"hev1", 0x22, 0x20000000, []byte{0x90, 0, 0, 0, 0, 0}, 120, "hev1.2.4.H120.90"},
+
+		// Profile-space 1 and more than one constraint byte.
+		{"hvc1", 0x41, 0x60000000, []byte{0xb0, 0, 0x01, 0, 0, 0}, 93, "hvc1.A1.6.L93.B0.0.1"},
+
+		// No constraints.
+		{"hvc1", 0x01, 0x60000000, []byte{0, 0, 0, 0, 0, 0}, 93, "hvc1.1.6.L93"},
+	}
+
+	for _, c := range cases {
+		data := getTestHvcCData(c.profileByte, c.compatibility, c.constraints, c.level)
+
+		hvcC := getTestParsedBox(hvcCBoxFactory{}, data).(*HvcCBox)
+
+		codec := hvcC.CodecString(c.sampleEntryName)
+		if codec != c.expected {
+			t.Fatalf("CodecString() not correct: [%s] != [%s]", codec, c.expected)
+		}
+	}
+}
+
+func TestHvcCBoxFactory_New_Truncated(t *testing.T) {
+	data := getTestHvcCData(0x01, 0x60000000, []byte{0xb0, 0, 0, 0, 0, 0}, 93)
+
+	_, err := getTestBoxFactoryNew(hvcCBoxFactory{}, data[:10])
+	if err == nil {
+		t.Fatalf("Expected error for truncated header.")
+	}
+
+	_, err = getTestBoxFactoryNew(hvcCBoxFactory{}, data[:len(data)-1])
+	if err == nil {
+		t.Fatalf("Expected error for truncated NAL unit.")
+	}
+}
@@ -0,0 +1,83 @@
+package bmftype
+
+import (
+	"errors"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+var (
+	// ErrNoCodecConfiguration indicates that a sample-entry does not have a
+	// child box with a codec configuration that we can read.
+	ErrNoCodecConfiguration = errors.New("no codec configuration")
+)
+
+const (
+	// sampleEntryHeaderSize is the size of the fields that are common to all
+	// sample-entries: six reserved bytes and the data-reference index.
+	sampleEntryHeaderSize = 8
+)
+
+// CodecConfiguration is implemented by the boxes that hold the decoder
+// configuration of a sample-entry (e.g. "avcC" in "avc1").
+type CodecConfiguration interface {
+	bmfcommon.CommonBox
+
+	// CodecString returns the RFC 6381 codec string (as used in HLS and DASH
+	// manifests) for the given sample-entry type.
+	CodecString(sampleEntryName string) string
+}
+
+// SampleEntry has the fields that are common to all sample-entries.
+type SampleEntry struct {
+	dataReferenceIndex uint16
+}
+
+// DataReferenceIndex returns the (one-based) index of the data reference that
+// describes where the samples are stored.
+func (se SampleEntry) DataReferenceIndex() uint16 {
+	return se.dataReferenceIndex
+}
+
+// parseSampleEntry parses the fields that are common to all sample-entries.
+func parseSampleEntry(data []byte) (se SampleEntry, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if len(data) < sampleEntryHeaderSize {
+		log.Panicf("sample-entry too short: (%d)", len(data))
+	}
+
+	se = SampleEntry{
+		dataReferenceIndex: bmfcommon.DefaultEndianness.Uint16(data[6:8]),
+	}
+
+	return se, nil
+}
+
+// codecConfiguration returns the first child that is a codec configuration.
+func codecConfiguration(lbi bmfcommon.LoadedBoxIndex) (cc CodecConfiguration, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	for _, name := range lbi.ChildrenTypes() {
+		boxes, err := lbi.GetChildBoxes(name)
+		log.PanicIf(err)
+
+		for _, cb := range boxes {
+			if cc, ok := cb.(CodecConfiguration); ok == true {
+				return cc, nil
+			}
+		}
+	}
+
+	return nil, ErrNoCodecConfiguration
+}
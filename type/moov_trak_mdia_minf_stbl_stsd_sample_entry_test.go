@@ -0,0 +1,39 @@
+package bmftype
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+)
+
+func TestParseSampleEntry(t *testing.T) {
+	se, err := parseSampleEntry([]byte{0, 0, 0, 0, 0, 0, 0x12, 0x34})
+	log.PanicIf(err)
+
+	if se.DataReferenceIndex() != 0x1234 {
+		t.Fatalf("DataReferenceIndex() not correct.")
+	}
+
+	_, err = parseSampleEntry([]byte{0, 0, 0})
+	if err == nil {
+		t.Fatalf("Expected error for short sample-entry.")
+	}
+}
+
+func TestCodecConfiguration(t *testing.T) {
+	vse := getTestVisualSampleEntryBox("av01", "av1C", []byte{0x81, 0x04, 0x0c, 0x00})
+
+	cc, err := codecConfiguration(vse.LoadedBoxIndex)
+	log.PanicIf(err)
+
+	if cc.Name() != "av1C" {
+		t.Fatalf("Configuration not correct: [%s]", cc.Name())
+	}
+
+	vse = getTestVisualSampleEntryBox("av01", "", nil)
+
+	_, err = codecConfiguration(vse.LoadedBoxIndex)
+	if err != ErrNoCodecConfiguration {
+		t.Fatalf("Expected ErrNoCodecConfiguration: %v", err)
+	}
+}
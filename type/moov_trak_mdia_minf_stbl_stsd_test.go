@@ -23,7 +23,7 @@ func TestStsdBox_SetLoadedBoxIndex(t *testing.T) {
 
 func TestStsdBox_Version(t *testing.T) {
 	sb := StsdBox{
-		version: 0x11,
+		FullBox: bmfcommon.NewFullBox(0x11, 0),
 	}
 
 	if sb.Version() != 0x11 {
@@ -33,7 +33,7 @@ func TestStsdBox_Version(t *testing.T) {
 
 func TestStsdBox_Flags(t *testing.T) {
 	sb := StsdBox{
-		flags: 0x22,
+		FullBox: bmfcommon.NewFullBox(0, 0x22),
 	}
 
 	if sb.Flags() != 0x22 {
@@ -61,8 +61,8 @@ func TestStsdBoxFactory_New(t *testing.T) {
 
 	var data []byte
 
-	// flags
-	bmfcommon.PushBytes(&data, uint32(0x11223344))
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0x00223344))
 
 	// The child boxes are read at offset (8) in the content. Since this test
 	// doesn't add any actual boxes, the content will be exactly eight bytes.
@@ -88,11 +88,11 @@ func TestStsdBoxFactory_New(t *testing.T) {
 
 	mb := cb.(*StsdBox)
 
-	if mb.Version() != 0x11 {
+	if mb.Version() != 0 {
 		t.Fatalf("Version() not correct: (0x%02x)", mb.Version())
 	}
 
-	if mb.Flags() != 0x11223344 {
+	if mb.Flags() != 0x223344 {
 		t.Fatalf("Flags() not correct: (0x%08x)", mb.Flags())
 	}
 }
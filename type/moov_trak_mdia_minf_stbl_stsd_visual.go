@@ -0,0 +1,251 @@
+package bmftype
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// visualSampleEntrySize is the size of the visual sample-entry fields,
+	// which precede the child boxes.
+	visualSampleEntrySize = sampleEntryHeaderSize + 70
+)
+
+var (
+	// visualSampleEntryNames are the sample-entry types that are parsed as
+	// visual sample-entries.
+	visualSampleEntryNames = []string{
+		"avc1",
+		"avc3",
+		"hvc1",
+		"hev1",
+		"av01",
+		"vp09",
+	}
+)
+
+// VisualSampleEntryBox is a sample-entry for video (e.g. "avc1"). The decoder
+// configuration is stored in a child box (e.g. "avcC").
+type VisualSampleEntryBox struct {
+	bmfcommon.Box
+	SampleEntry
+
+	width                uint16
+	height               uint16
+	horizontalResolution uint32
+	verticalResolution   uint32
+	frameCount           uint16
+	compressorName       string
+	depth                uint16
+
+	// LoadedBoxIndex contains this box's children.
+	bmfcommon.LoadedBoxIndex
+}
+
+// Width returns the width in pixels.
+func (vse *VisualSampleEntryBox) Width() uint16 {
+	return vse.width
+}
+
+// Height returns the height in pixels.
+func (vse *VisualSampleEntryBox) Height() uint16 {
+	return vse.height
+}
+
+// HorizontalResolution returns the horizontal resolution in pixels-per-inch.
+func (vse *VisualSampleEntryBox) HorizontalResolution() bmfcommon.FixedPoint32 {
+	return bmfcommon.Uint32ToFixedPoint32(vse.horizontalResolution, 16, 16)
+}
+
+// VerticalResolution returns the vertical resolution in pixels-per-inch.
+func (vse *VisualSampleEntryBox) VerticalResolution() bmfcommon.FixedPoint32 {
+	return bmfcommon.Uint32ToFixedPoint32(vse.verticalResolution, 16, 16)
+}
+
+// FrameCount returns the number of frames stored in each sample.
+func (vse *VisualSampleEntryBox) FrameCount() uint16 {
+	return vse.frameCount
+}
+
+// CompressorName returns the informative name of the compressor, if any.
+func (vse *VisualSampleEntryBox) CompressorName() string {
+	return vse.compressorName
+}
+
+// Depth returns the color depth.
+func (vse *VisualSampleEntryBox) Depth() uint16 {
+	return vse.depth
+}
+
+// SetLoadedBoxIndex sets the child boxes after a box has been manufactured
+// and the children have been parsed. This allows parent boxes to be
+// registered before the child boxes can look for them.
+func (vse *VisualSampleEntryBox) SetLoadedBoxIndex(boxes bmfcommon.Boxes) {
+	fbi := boxes.Index()
+	vse.LoadedBoxIndex = fbi
+}
+
+// GetChildBoxes returns the child boxes of the given type.
+func (vse *VisualSampleEntryBox) GetChildBoxes(name string) (boxes []bmfcommon.CommonBox, err error) {
+	return vse.GetChildBoxesOf(vse, name)
+}
+
+// CodecConfiguration returns the child box with the decoder configuration.
+func (vse *VisualSampleEntryBox) CodecConfiguration() (cc CodecConfiguration, err error) {
+	return codecConfiguration(vse.LoadedBoxIndex)
+}
+
+// CodecString returns the RFC 6381 codec string (e.g. "avc1.64001f").
+func (vse *VisualSampleEntryBox) CodecString() (codec string, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	cc, err := vse.CodecConfiguration()
+	log.PanicIf(err)
+
+	return cc.CodecString(vse.Name()), nil
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (vse *VisualSampleEntryBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s DATA-REF-INDEX=(%d) WIDTH=(%d) HEIGHT=(%d) DEPTH=(%d) COMPRESSOR=[%s]",
+		vse.Box.InlineString(), vse.dataReferenceIndex, vse.width, vse.height,
+		vse.depth, vse.compressorName)
+}
+
+func (b *VisualSampleEntryBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < visualSampleEntrySize {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), visualSampleEntrySize, int64(len(data))))
+	}
+
+	b.SampleEntry, err = parseSampleEntry(data)
+	log.PanicIf(err)
+
+	// Skip pre-defined and reserved fields.
+	data = data[sampleEntryHeaderSize+16:]
+
+	b.width = bmfcommon.DefaultEndianness.Uint16(data[0:2])
+	b.height = bmfcommon.DefaultEndianness.Uint16(data[2:4])
+	b.horizontalResolution = bmfcommon.DefaultEndianness.Uint32(data[4:8])
+	b.verticalResolution = bmfcommon.DefaultEndianness.Uint32(data[8:12])
+	b.frameCount = bmfcommon.DefaultEndianness.Uint16(data[16:18])
+
+	// The compressor-name is a Pascal string padded to 32 bytes.
+
+	compressorName := data[18:50]
+	length := int(compressorName[0])
+	if length > 31 {
+		length = 31
+	}
+
+	b.compressorName = string(bytes.TrimRight(compressorName[1:1+length], "\x00"))
+	b.depth = bmfcommon.DefaultEndianness.Uint16(data[50:52])
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (vse *VisualSampleEntryBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"data_reference_index":  vse.dataReferenceIndex,
+		"width":                 vse.width,
+		"height":                vse.height,
+		"horizontal_resolution": float64(vse.horizontalResolution) / 0x10000,
+		"vertical_resolution":   float64(vse.verticalResolution) / 0x10000,
+		"frame_count":           vse.frameCount,
+		"compressor_name":       vse.compressorName,
+		"depth":                 vse.depth,
+	}
+
+	if codec, err := vse.CodecString(); err == nil {
+		fields["codec"] = codec
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box preceding its children. Bytes that
+// are not modeled are carried over.
+func (vse *VisualSampleEntryBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := vse.Data()
+	log.PanicIf(err)
+
+	data = make([]byte, visualSampleEntrySize)
+	copy(data, original)
+
+	bmfcommon.DefaultEndianness.PutUint16(data[6:8], vse.dataReferenceIndex)
+
+	fields := data[sampleEntryHeaderSize+16:]
+
+	bmfcommon.DefaultEndianness.PutUint16(fields[0:2], vse.width)
+	bmfcommon.DefaultEndianness.PutUint16(fields[2:4], vse.height)
+	bmfcommon.DefaultEndianness.PutUint32(fields[4:8], vse.horizontalResolution)
+	bmfcommon.DefaultEndianness.PutUint32(fields[8:12], vse.verticalResolution)
+	bmfcommon.DefaultEndianness.PutUint16(fields[16:18], vse.frameCount)
+	bmfcommon.DefaultEndianness.PutUint16(fields[50:52], vse.depth)
+
+	return data, nil
+}
+
+type visualSampleEntryBoxFactory struct {
+	name string
+}
+
+// Name returns the name of the type.
+func (bf visualSampleEntryBoxFactory) Name() string {
+	return bf.name
+}
+
+// New returns a new value instance.
+func (visualSampleEntryBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	vseBox := &VisualSampleEntryBox{
+		Box: box,
+	}
+
+	err = vseBox.parse()
+	log.PanicIf(err)
+
+	return vseBox, visualSampleEntrySize, nil
+}
+
+var (
+	_ bmfcommon.BoxFactory          = visualSampleEntryBoxFactory{}
+	_ bmfcommon.CommonBox           = &VisualSampleEntryBox{}
+	_ bmfcommon.FieldExporter       = &VisualSampleEntryBox{}
+	_ bmfcommon.ChildBoxIndexSetter = &VisualSampleEntryBox{}
+)
+
+func init() {
+	for _, name := range visualSampleEntryNames {
+		bmfcommon.RegisterBoxType(visualSampleEntryBoxFactory{name: name})
+	}
+}
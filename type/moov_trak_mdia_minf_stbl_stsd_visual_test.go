@@ -0,0 +1,197 @@
+package bmftype
+
+import (
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+var (
+	// testAvc1Data is the "avc1" payload preceding the child boxes from the
+	// tears-of-steel asset.
+	testAvc1Data, _ = hex.DecodeString("00000000000000010000000000000000000000000000000007800320004800000048000000000000000100000000000000000000000000000000000000000000000000000000000000000018ffff")
+)
+
+// getTestVisualSampleEntryBox parses a sample-entry with the given name and
+// configuration box.
+func getTestVisualSampleEntryBox(name string, configurationName string, configurationData []byte) *VisualSampleEntryBox {
+	data := make([]byte, len(testAvc1Data))
+	copy(data, testAvc1Data)
+
+	if configurationName != "" {
+		bmfcommon.PushBox(&data, configurationName, configurationData)
+	}
+
+	var b []byte
+	bmfcommon.PushBox(&b, name, data)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	boxes, err := resource.GetChildBoxes(name)
+	log.PanicIf(err)
+
+	return boxes[0].(*VisualSampleEntryBox)
+}
+
+func TestVisualSampleEntryBoxFactory_Name(t *testing.T) {
+	for _, name := range visualSampleEntryNames {
+		if bmfcommon.GetFactory(name) == nil {
+			t.Fatalf("Factory not registered: [%s]", name)
+		}
+	}
+
+	if (visualSampleEntryBoxFactory{name: "hev1"}).Name() != "hev1" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestVisualSampleEntryBoxFactory_New(t *testing.T) {
+	vse := getTestVisualSampleEntryBox("avc1", "avcC", testAvcCData)
+
+	if vse.DataReferenceIndex() != 1 {
+		t.Fatalf("DataReferenceIndex() not correct.")
+	} else if vse.Width() != 1920 {
+		t.Fatalf("Width() not correct.")
+	} else if vse.Height() != 800 {
+		t.Fatalf("Height() not correct.")
+	} else if vse.FrameCount() != 1 {
+		t.Fatalf("FrameCount() not correct.")
+	} else if vse.CompressorName() != "" {
+		t.Fatalf("CompressorName() not correct.")
+	} else if vse.Depth() != 24 {
+		t.Fatalf("Depth() not correct.")
+	}
+
+	if n, _ := vse.HorizontalResolution().Rational(); n != 72 {
+		t.Fatalf("HorizontalResolution() not correct.")
+	} else if n, _ := vse.VerticalResolution().Rational(); n != 72 {
+		t.Fatalf("VerticalResolution() not correct.")
+	}
+
+	cc, err := vse.CodecConfiguration()
+	log.PanicIf(err)
+
+	if _, ok := cc.(*AvcCBox); ok != true {
+		t.Fatalf("CodecConfiguration() not correct.")
+	}
+
+	codec, err := vse.CodecString()
+	log.PanicIf(err)
+
+	if codec != "avc1.640028" {
+		t.Fatalf("CodecString() not correct: [%s]", codec)
+	}
+
+	assertEncodeData(t, vse, testAvc1Data)
+}
+
+func TestVisualSampleEntryBox_CodecString_Hevc(t *testing.T) {
+	data := getTestHvcCData(0x01, 0x60000000, []byte{0xb0, 0, 0, 0, 0, 0}, 93)
+	vse := getTestVisualSampleEntryBox("hev1", "hvcC", data)
+
+	codec, err := vse.CodecString()
+	log.PanicIf(err)
+
+	if codec != "hev1.1.6.L93.B0" {
+		t.Fatalf("CodecString() not correct: [%s]", codec)
+	}
+}
+
+func TestVisualSampleEntryBox_CodecString_NoConfiguration(t *testing.T) {
+	vse := getTestVisualSampleEntryBox("avc1", "", nil)
+
+	_, err := vse.CodecString()
+	if err == nil {
+		t.Fatalf("Expected error.")
+	} else if log.Is(err, ErrNoCodecConfiguration) != true {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+}
+
+func TestVisualSampleEntryBox_CompressorName(t *testing.T) {
+	data := make([]byte, len(testAvc1Data))
+	copy(data, testAvc1Data)
+
+	// Set a Pascal string at the start of the 32-byte field.
+	compressorName := data[sampleEntryHeaderSize+16+18:]
+	compressorName[0] = 4
+	copy(compressorName[1:], "test")
+
+	vse := getTestParsedBox(visualSampleEntryBoxFactory{name: "avc1"}, data).(*VisualSampleEntryBox)
+
+	if vse.CompressorName() != "test" {
+		t.Fatalf("CompressorName() not correct: [%s]", vse.CompressorName())
+	}
+}
+
+func TestVisualSampleEntryBox_InlineString(t *testing.T) {
+	vse := getTestVisualSampleEntryBox("avc1", "", nil)
+
+	if vse.InlineString() != "NAME=[avc1] PARENT=[ROOT] START=(0x0000000000000000) SIZE=(86) DATA-REF-INDEX=(1) WIDTH=(1920) HEIGHT=(800) DEPTH=(24) COMPRESSOR=[]" {
+		t.Fatalf("InlineString() not correct: [%s]", vse.InlineString())
+	}
+}
+
+func TestVisualSampleEntryBox_ExportFields(t *testing.T) {
+	vse := getTestVisualSampleEntryBox("avc1", "", nil)
+
+	fields, err := vse.ExportFields()
+	log.PanicIf(err)
+
+	if fields["horizontal_resolution"] != 72.0 {
+		t.Fatalf("Horizontal resolution not correct: %v", fields["horizontal_resolution"])
+	} else if fields["vertical_resolution"] != 72.0 {
+		t.Fatalf("Vertical resolution not correct: %v", fields["vertical_resolution"])
+	} else if fields["width"] != uint16(1920) {
+		t.Fatalf("Width not correct: %v", fields["width"])
+	}
+}
+
+func TestVisualSampleEntryBoxFactory_New_Truncated(t *testing.T) {
+	_, err := getTestBoxFactoryNew(visualSampleEntryBoxFactory{name: "avc1"}, testAvc1Data[:40])
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+}
+
+func TestVisualSampleEntryBox_Asset(t *testing.T) {
+	f, err := os.Open(getTestAssetFilepath("tears-of-steel.mp4"))
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+	log.PanicIf(err)
+
+	resource, err := bmfcommon.NewResource(f, fi.Size())
+	log.PanicIf(err)
+
+	cb, found := resource.Index()[bmfcommon.IndexedBoxEntry{NamePhrase: "moov.trak.mdia.minf.stbl.stsd.avc1", SequenceNumber: 0}]
+	if found != true {
+		t.Fatalf("Sample-entry not found.")
+	}
+
+	vse := cb.(*VisualSampleEntryBox)
+
+	codec, err := vse.CodecString()
+	log.PanicIf(err)
+
+	if codec != "avc1.640028" {
+		t.Fatalf("CodecString() not correct: [%s]", codec)
+	}
+
+	fields, err := vse.ExportFields()
+	log.PanicIf(err)
+
+	if fields["codec"] != "avc1.640028" {
+		t.Fatalf("Exported codec not correct: %v", fields["codec"])
+	}
+}
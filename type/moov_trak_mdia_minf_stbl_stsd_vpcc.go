@@ -0,0 +1,229 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+const (
+	// vpcCHeaderSize is the size of the fixed part of the record, after the
+	// version and flags.
+	vpcCHeaderSize = 8
+)
+
+// VpcCBox is the "VP Codec Configuration" box. It has the
+// VPCodecConfigurationRecord from the VP9 ISOBMFF binding.
+type VpcCBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	profile                 uint8
+	level                   uint8
+	bitDepth                uint8
+	chromaSubsampling       uint8
+	videoFullRangeFlag      bool
+	colourPrimaries         uint8
+	transferCharacteristics uint8
+	matrixCoefficients      uint8
+	codecInitializationData []byte
+}
+
+// Profile returns the VP9 profile.
+func (vb *VpcCBox) Profile() uint8 {
+	return vb.profile
+}
+
+// Level returns the VP9 level, which is ten times the level number (e.g. 31
+// for 3.1).
+func (vb *VpcCBox) Level() uint8 {
+	return vb.level
+}
+
+// BitDepth returns the bit-depth (8, 10, or 12).
+func (vb *VpcCBox) BitDepth() uint8 {
+	return vb.bitDepth
+}
+
+// ChromaSubsampling returns the chroma subsampling (e.g. 1 for 4:2:0 with
+// colocated chroma).
+func (vb *VpcCBox) ChromaSubsampling() uint8 {
+	return vb.chromaSubsampling
+}
+
+// VideoFullRangeFlag returns true if the full range of values is used.
+func (vb *VpcCBox) VideoFullRangeFlag() bool {
+	return vb.videoFullRangeFlag
+}
+
+// ColourPrimaries returns the colour primaries (ISO/IEC 23091-2).
+func (vb *VpcCBox) ColourPrimaries() uint8 {
+	return vb.colourPrimaries
+}
+
+// TransferCharacteristics returns the transfer characteristics (ISO/IEC
+// 23091-2).
+func (vb *VpcCBox) TransferCharacteristics() uint8 {
+	return vb.transferCharacteristics
+}
+
+// MatrixCoefficients returns the matrix coefficients (ISO/IEC 23091-2).
+func (vb *VpcCBox) MatrixCoefficients() uint8 {
+	return vb.matrixCoefficients
+}
+
+// CodecInitializationData returns the codec initialization data. It is empty
+// for VP9.
+func (vb *VpcCBox) CodecInitializationData() []byte {
+	return vb.codecInitializationData
+}
+
+// CodecString returns the RFC 6381 codec string (e.g. "vp09.00.10.08"). The
+// optional fields are only included if any differ from their defaults.
+func (vb *VpcCBox) CodecString(sampleEntryName string) string {
+	codec := fmt.Sprintf("%s.%02d.%02d.%02d", sampleEntryName, vb.profile, vb.level, vb.bitDepth)
+
+	videoFullRange := 0
+	if vb.videoFullRangeFlag == true {
+		videoFullRange = 1
+	}
+
+	if vb.chromaSubsampling == 1 && vb.colourPrimaries == 1 && vb.transferCharacteristics == 1 && vb.matrixCoefficients == 1 && videoFullRange == 0 {
+		return codec
+	}
+
+	return fmt.Sprintf(
+		"%s.%02d.%02d.%02d.%02d.%02d",
+		codec, vb.chromaSubsampling, vb.colourPrimaries, vb.transferCharacteristics,
+		vb.matrixCoefficients, videoFullRange)
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (vb *VpcCBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) PROFILE=(%d) LEVEL=(%d) BIT-DEPTH=(%d) CHROMA=(%d)",
+		vb.Box.InlineString(), vb.Version(), vb.Flags(), vb.profile, vb.level,
+		vb.bitDepth, vb.chromaSubsampling)
+}
+
+func (b *VpcCBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	// The version and flags were already checked.
+	data = data[4:]
+
+	if len(data) < vpcCHeaderSize {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize()+4, vpcCHeaderSize, int64(len(data))))
+	}
+
+	b.profile = data[0]
+	b.level = data[1]
+	b.bitDepth = data[2] >> 4
+	b.chromaSubsampling = (data[2] >> 1) & 0x07
+	b.videoFullRangeFlag = data[2]&0x01 != 0
+	b.colourPrimaries = data[3]
+	b.transferCharacteristics = data[4]
+	b.matrixCoefficients = data[5]
+
+	size := int(bmfcommon.DefaultEndianness.Uint16(data[6:8]))
+	if size > len(data)-vpcCHeaderSize {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize()+4+vpcCHeaderSize, int64(size), int64(len(data)-vpcCHeaderSize)))
+	}
+
+	b.codecInitializationData = data[vpcCHeaderSize : vpcCHeaderSize+size]
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (vb *VpcCBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"profile":                   vb.profile,
+		"level":                     vb.level,
+		"bit_depth":                 vb.bitDepth,
+		"chroma_subsampling":        vb.chromaSubsampling,
+		"video_full_range_flag":     vb.videoFullRangeFlag,
+		"colour_primaries":          vb.colourPrimaries,
+		"transfer_characteristics":  vb.transferCharacteristics,
+		"matrix_coefficients":       vb.matrixCoefficients,
+		"codec_initialization_size": len(vb.codecInitializationData),
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. The record is carried over as
+// read.
+func (vb *VpcCBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := vb.Data()
+	log.PanicIf(err)
+
+	data = make([]byte, len(original))
+	copy(data, original)
+
+	bmfcommon.DefaultEndianness.PutUint32(data[0:4], uint32(vb.Version())<<24|vb.Flags()&0x00ffffff)
+
+	return data, nil
+}
+
+type vpcCBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (vpcCBoxFactory) Name() string {
+	return "vpcC"
+}
+
+// SupportedVersions returns the versions that the factory can parse. Version
+// zero was only used by drafts of the binding.
+func (vpcCBoxFactory) SupportedVersions() []byte {
+	return []byte{1}
+}
+
+// New returns a new value instance.
+func (bf vpcCBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	vpcCBox := &VpcCBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = vpcCBox.parse()
+	log.PanicIf(err)
+
+	return vpcCBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = vpcCBoxFactory{}
+	_ bmfcommon.CommonBox      = &VpcCBox{}
+	_ bmfcommon.FieldExporter  = &VpcCBox{}
+	_ CodecConfiguration       = &VpcCBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(vpcCBoxFactory{})
+}
@@ -0,0 +1,108 @@
+package bmftype
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// getTestVpcCData returns a version-one "vpcC" payload.
+func getTestVpcCData(profile, level, bitDepth, chromaSubsampling byte, fullRange bool, colourPrimaries, transferCharacteristics, matrixCoefficients byte) []byte {
+	var data []byte
+
+	// Version and flags.
+	bmfcommon.PushBytes(&data, uint32(0x01000000))
+
+	fullRangeBit := byte(0)
+	if fullRange == true {
+		fullRangeBit = 1
+	}
+
+	bmfcommon.PushBytes(&data, []byte{profile, level, bitDepth<<4 | chromaSubsampling<<1 | fullRangeBit})
+	bmfcommon.PushBytes(&data, []byte{colourPrimaries, transferCharacteristics, matrixCoefficients})
+
+	// codecInitializationDataSize
+	bmfcommon.PushBytes(&data, uint16(0))
+
+	return data
+}
+
+func TestVpcCBoxFactory_Name(t *testing.T) {
+	if (vpcCBoxFactory{}).Name() != "vpcC" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestVpcCBoxFactory_New(t *testing.T) {
+	data := getTestVpcCData(2, 40, 10, 1, true, 9, 16, 9)
+
+	cb := getTestParsedBox(vpcCBoxFactory{}, data)
+	vpcC := cb.(*VpcCBox)
+
+	if vpcC.Version() != 1 {
+		t.Fatalf("Version() not correct.")
+	} else if vpcC.Profile() != 2 {
+		t.Fatalf("Profile() not correct.")
+	} else if vpcC.Level() != 40 {
+		t.Fatalf("Level() not correct.")
+	} else if vpcC.BitDepth() != 10 {
+		t.Fatalf("BitDepth() not correct.")
+	} else if vpcC.ChromaSubsampling() != 1 {
+		t.Fatalf("ChromaSubsampling() not correct.")
+	} else if vpcC.VideoFullRangeFlag() != true {
+		t.Fatalf("VideoFullRangeFlag() not correct.")
+	} else if vpcC.ColourPrimaries() != 9 {
+		t.Fatalf("ColourPrimaries() not correct.")
+	} else if vpcC.TransferCharacteristics() != 16 {
+		t.Fatalf("TransferCharacteristics() not correct.")
+	} else if vpcC.MatrixCoefficients() != 9 {
+		t.Fatalf("MatrixCoefficients() not correct.")
+	} else if len(vpcC.CodecInitializationData()) != 0 {
+		t.Fatalf("CodecInitializationData() not correct.")
+	}
+
+	assertEncodeData(t, cb, data)
+}
+
+func TestVpcCBox_CodecString(t *testing.T) {
+	data := getTestVpcCData(0, 10, 8, 1, false, 1, 1, 1)
+	vpcC := getTestParsedBox(vpcCBoxFactory{}, data).(*VpcCBox)
+
+	if vpcC.CodecString("vp09") != "vp09.00.10.08" {
+		t.Fatalf("Short CodecString() not correct: [%s]", vpcC.CodecString("vp09"))
+	}
+
+	data = getTestVpcCData(2, 40, 10, 1, false, 9, 16, 9)
+	vpcC = getTestParsedBox(vpcCBoxFactory{}, data).(*VpcCBox)
+
+	if vpcC.CodecString("vp09") != "vp09.02.40.10.01.09.16.09.00" {
+		t.Fatalf("Full CodecString() not correct: [%s]", vpcC.CodecString("vp09"))
+	}
+}
+
+func TestVpcCBoxFactory_New_Truncated(t *testing.T) {
+	data := getTestVpcCData(0, 10, 8, 1, false, 1, 1, 1)
+
+	_, err := getTestBoxFactoryNew(vpcCBoxFactory{}, data[:8])
+	if err == nil {
+		t.Fatalf("Expected error for truncated record.")
+	}
+
+	// Declare initialization data that is not there.
+	data[len(data)-1] = 4
+
+	_, err = getTestBoxFactoryNew(vpcCBoxFactory{}, data)
+	if err == nil {
+		t.Fatalf("Expected error for truncated initialization data.")
+	}
+}
+
+func TestVpcCBoxFactory_New_UnsupportedVersion(t *testing.T) {
+	data := getTestVpcCData(0, 10, 8, 1, false, 1, 1, 1)
+	data[0] = 0
+
+	_, err := getTestBoxFactoryNew(vpcCBoxFactory{}, data)
+	if err == nil {
+		t.Fatalf("Expected error for version zero.")
+	}
+}
@@ -0,0 +1,138 @@
+package bmftype
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// StssBox is the "Sync Sample" box. If this box is not present, every sample is
+// a sync sample.
+type StssBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	sampleNumbers []uint32
+}
+
+// SampleNumbers returns the (one-based) numbers of the sync samples, in
+// ascending order.
+func (sb *StssBox) SampleNumbers() []uint32 {
+	return sb.sampleNumbers
+}
+
+// IsSyncSample returns true if the sample with the given (one-based) number is a
+// sync sample.
+func (sb *StssBox) IsSyncSample(sampleNumber uint32) bool {
+	i := sort.Search(len(sb.sampleNumbers), func(i int) bool {
+		return sb.sampleNumbers[i] >= sampleNumber
+	})
+
+	return i < len(sb.sampleNumbers) && sb.sampleNumbers[i] == sampleNumber
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (sb *StssBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) SYNC-SAMPLES=(%d)",
+		sb.Box.InlineString(), sb.Version(), sb.Flags(), len(sb.sampleNumbers))
+}
+
+func (b *StssBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < 8 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 8, int64(len(data))))
+	}
+
+	count := bmfcommon.DefaultEndianness.Uint32(data[4:8])
+
+	err = b.CheckEntryCount("sample numbers", int64(count), 8, 4)
+	log.PanicIf(err)
+
+	b.sampleNumbers = make([]uint32, count)
+
+	offset := 8
+	for i := 0; i < int(count); i++ {
+		b.sampleNumbers[i] = bmfcommon.DefaultEndianness.Uint32(data[offset : offset+4])
+		offset += 4
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (sb *StssBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"sample_numbers": sb.sampleNumbers,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (sb *StssBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, sb.Version(), sb.Flags())
+	bmfcommon.PushBytes(&data, uint32(len(sb.sampleNumbers)))
+
+	for _, sampleNumber := range sb.sampleNumbers {
+		bmfcommon.PushBytes(&data, sampleNumber)
+	}
+
+	return data, nil
+}
+
+type stssBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (stssBoxFactory) Name() string {
+	return "stss"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (stssBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf stssBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	stssBox := &StssBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = stssBox.parse()
+	log.PanicIf(err)
+
+	return stssBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = stssBoxFactory{}
+	_ bmfcommon.CommonBox      = &StssBox{}
+	_ bmfcommon.FieldExporter  = &StssBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(stssBoxFactory{})
+}
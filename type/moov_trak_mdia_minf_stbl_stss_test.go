@@ -0,0 +1,68 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestStssBox_IsSyncSample(t *testing.T) {
+	sb := StssBox{
+		sampleNumbers: []uint32{1, 5, 9},
+	}
+
+	if sb.IsSyncSample(5) != true {
+		t.Fatalf("Expected sample (5) to be a sync sample.")
+	} else if sb.IsSyncSample(6) != false {
+		t.Fatalf("Expected sample (6) to not be a sync sample.")
+	} else if sb.IsSyncSample(10) != false {
+		t.Fatalf("Expected sample (10) to not be a sync sample.")
+	}
+}
+
+func TestStssBoxFactory_Name(t *testing.T) {
+	name := stssBoxFactory{}.Name()
+
+	if name != "stss" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestStssBoxFactory_New(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	// entry-count
+	bmfcommon.PushBytes(&data, uint32(2))
+
+	bmfcommon.PushBytes(&data, uint32(1))
+	bmfcommon.PushBytes(&data, uint32(4))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "stss", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := stssBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	stss := cb.(*StssBox)
+
+	if reflect.DeepEqual(stss.SampleNumbers(), []uint32{1, 4}) != true {
+		t.Fatalf("SampleNumbers() not correct.")
+	}
+}
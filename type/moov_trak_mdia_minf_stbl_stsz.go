@@ -0,0 +1,171 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// StszBox is the "Sample Size" box.
+type StszBox struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	sampleSize  uint32
+	sampleCount uint32
+	entrySizes  []uint32
+}
+
+// SampleSize returns the default sample-size. If this is zero, the samples have
+// different sizes and they are stored in the entry table.
+func (sb *StszBox) SampleSize() uint32 {
+	return sb.sampleSize
+}
+
+// SampleCount returns the number of samples in the track.
+func (sb *StszBox) SampleCount() uint32 {
+	return sb.sampleCount
+}
+
+// EntrySizes returns the individual sample-sizes. This will be empty if all
+// samples have the same size.
+func (sb *StszBox) EntrySizes() []uint32 {
+	return sb.entrySizes
+}
+
+// SampleSizeAt returns the size of the sample with the given (zero-based)
+// index.
+func (sb *StszBox) SampleSizeAt(index uint32) (size uint32, err error) {
+	if index >= sb.sampleCount {
+		return 0, fmt.Errorf("sample index (%d) out of range (%d)", index, sb.sampleCount)
+	}
+
+	if sb.sampleSize != 0 {
+		return sb.sampleSize, nil
+	}
+
+	return sb.entrySizes[index], nil
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (sb *StszBox) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) SAMPLE-SIZE=(%d) SAMPLE-COUNT=(%d)",
+		sb.Box.InlineString(), sb.Version(), sb.Flags(), sb.sampleSize, sb.sampleCount)
+}
+
+func (b *StszBox) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < 12 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 12, int64(len(data))))
+	}
+
+	b.sampleSize = bmfcommon.DefaultEndianness.Uint32(data[4:8])
+	b.sampleCount = bmfcommon.DefaultEndianness.Uint32(data[8:12])
+
+	// The count is checked even when the sizes are not stored since the
+	// sample table is allocated from it.
+
+	err = b.CheckEntryCount("samples", int64(b.sampleCount), 12, 0)
+	log.PanicIf(err)
+
+	if b.sampleSize != 0 {
+		return nil
+	}
+
+	if uint64(len(data)-12) < uint64(b.sampleCount)*4 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize()+12, int64(b.sampleCount)*4, int64(len(data)-12)))
+	}
+
+	b.entrySizes = make([]uint32, b.sampleCount)
+
+	offset := 12
+	for i := 0; i < int(b.sampleCount); i++ {
+		b.entrySizes[i] = bmfcommon.DefaultEndianness.Uint32(data[offset : offset+4])
+		offset += 4
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (sb *StszBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"sample_size":  sb.sampleSize,
+		"sample_count": sb.sampleCount,
+		"entry_sizes":  sb.entrySizes,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (sb *StszBox) EncodeData() (data []byte, err error) {
+	pushVersionAndFlags(&data, sb.Version(), sb.Flags())
+	bmfcommon.PushBytes(&data, sb.sampleSize)
+	bmfcommon.PushBytes(&data, sb.sampleCount)
+
+	if sb.sampleSize == 0 {
+		for _, entrySize := range sb.entrySizes {
+			bmfcommon.PushBytes(&data, entrySize)
+		}
+	}
+
+	return data, nil
+}
+
+type stszBoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (stszBoxFactory) Name() string {
+	return "stsz"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (stszBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf stszBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	stszBox := &StszBox{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = stszBox.parse()
+	log.PanicIf(err)
+
+	return stszBox, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = stszBoxFactory{}
+	_ bmfcommon.CommonBox      = &StszBox{}
+	_ bmfcommon.FieldExporter  = &StszBox{}
+	_ sampleSizeSource         = &StszBox{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(stszBoxFactory{})
+}
@@ -0,0 +1,186 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestStszBox_Version(t *testing.T) {
+	sb := StszBox{
+		FullBox: bmfcommon.NewFullBox(11, 0),
+	}
+
+	if sb.Version() != 11 {
+		t.Fatalf("Version() not correct.")
+	}
+}
+
+func TestStszBox_Flags(t *testing.T) {
+	sb := StszBox{
+		FullBox: bmfcommon.NewFullBox(0, 22),
+	}
+
+	if sb.Flags() != 22 {
+		t.Fatalf("Flags() not correct.")
+	}
+}
+
+func TestStszBox_SampleSizeAt_Constant(t *testing.T) {
+	sb := StszBox{
+		sampleSize:  33,
+		sampleCount: 2,
+	}
+
+	size, err := sb.SampleSizeAt(1)
+	log.PanicIf(err)
+
+	if size != 33 {
+		t.Fatalf("SampleSizeAt() not correct: (%d)", size)
+	}
+
+	_, err = sb.SampleSizeAt(2)
+	if err == nil {
+		t.Fatalf("Expected error for out-of-range index.")
+	}
+}
+
+func TestStszBox_SampleSizeAt_Table(t *testing.T) {
+	sb := StszBox{
+		sampleCount: 2,
+		entrySizes:  []uint32{44, 55},
+	}
+
+	size, err := sb.SampleSizeAt(1)
+	log.PanicIf(err)
+
+	if size != 55 {
+		t.Fatalf("SampleSizeAt() not correct: (%d)", size)
+	}
+}
+
+func TestStszBoxFactory_Name(t *testing.T) {
+	name := stszBoxFactory{}.Name()
+
+	if name != "stsz" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func TestStszBoxFactory_New(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0x00223344))
+
+	// sample-size
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	// sample-count
+	bmfcommon.PushBytes(&data, uint32(3))
+
+	// entries
+	bmfcommon.PushBytes(&data, uint32(11))
+	bmfcommon.PushBytes(&data, uint32(22))
+	bmfcommon.PushBytes(&data, uint32(33))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "stsz", data)
+
+	// Parse.
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := stszBoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	stsz := cb.(*StszBox)
+
+	if stsz.Version() != 0 {
+		t.Fatalf("Version() not correct: (0x%02x)", stsz.Version())
+	} else if stsz.Flags() != 0x223344 {
+		t.Fatalf("Flags() not correct: (0x%08x)", stsz.Flags())
+	} else if stsz.SampleSize() != 0 {
+		t.Fatalf("SampleSize() not correct.")
+	} else if stsz.SampleCount() != 3 {
+		t.Fatalf("SampleCount() not correct.")
+	}
+
+	if reflect.DeepEqual(stsz.EntrySizes(), []uint32{11, 22, 33}) != true {
+		t.Fatalf("EntrySizes() not correct: %v", stsz.EntrySizes())
+	}
+}
+
+func TestStszBoxFactory_New_Truncated(t *testing.T) {
+	var data []byte
+
+	bmfcommon.PushBytes(&data, uint32(0))
+	bmfcommon.PushBytes(&data, uint32(0))
+	bmfcommon.PushBytes(&data, uint32(3))
+	bmfcommon.PushBytes(&data, uint32(11))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "stsz", data)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, 0)
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	_, _, err = stszBoxFactory{}.New(box)
+	if err == nil {
+		t.Fatalf("Expected error for truncated table.")
+	}
+}
+
+func TestStszBoxFactory_New_MaxTableEntries(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	// sample-size (constant, so no table is stored)
+	bmfcommon.PushBytes(&data, uint32(100))
+
+	// sample-count
+	bmfcommon.PushBytes(&data, uint32(11))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "stsz", data)
+
+	options := bmfcommon.ParseOptions{
+		Strict:   true,
+		Registry: bmfcommon.NewRegistry(),
+		Limits: bmfcommon.ParseLimits{
+			MaxTableEntries: 10,
+		},
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResourceWithOptions(sb, int64(len(b)), options)
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	_, _, err = stszBoxFactory{}.New(box)
+	if err == nil {
+		t.Fatalf("Expected limit error.")
+	} else if err.Error() != "MaxTableEntries exceeded: (11) > (10)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+}
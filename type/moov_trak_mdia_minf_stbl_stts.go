@@ -9,23 +9,12 @@ import (
 // SttsBox is the "Decoding Time to Sample" box.
 type SttsBox struct {
 	bmfcommon.Box
+	bmfcommon.FullBox
 
-	version      byte
-	flags        uint32
 	sampleCounts []uint32
 	sampleDeltas []uint32
 }
 
-// Version returns the version of the record.
-func (sb *SttsBox) Version() byte {
-	return sb.version
-}
-
-// Flags returns the flags.
-func (sb *SttsBox) Flags() uint32 {
-	return sb.flags
-}
-
 // SampleCounts returns the samples counts.
 func (sb *SttsBox) SampleCounts() []uint32 {
 	return sb.sampleCounts
@@ -46,10 +35,15 @@ func (b *SttsBox) parse() (err error) {
 	data, err := b.Data()
 	log.PanicIf(err)
 
-	b.version = data[0]
-	b.flags = bmfcommon.DefaultEndianness.Uint32(data[0:4])
+	if len(data) < 8 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 8, int64(len(data))))
+	}
 
 	count := bmfcommon.DefaultEndianness.Uint32(data[4:8])
+
+	err = b.CheckEntryCount("entries", int64(count), 8, 8)
+	log.PanicIf(err)
+
 	b.sampleCounts = make([]uint32, count)
 	b.sampleDeltas = make([]uint32, count)
 
@@ -64,6 +58,46 @@ func (b *SttsBox) parse() (err error) {
 	return nil
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (sb *SttsBox) ExportFields() (fields map[string]interface{}, err error) {
+	entries := make([]map[string]interface{}, len(sb.sampleCounts))
+	for i, sampleCount := range sb.sampleCounts {
+		entries[i] = map[string]interface{}{
+			"sample_count": sampleCount,
+			"sample_delta": sb.sampleDeltas[i],
+		}
+	}
+
+	fields = map[string]interface{}{
+		"entries": entries,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (sb *SttsBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if len(sb.sampleCounts) != len(sb.sampleDeltas) {
+		log.Panicf("stts: sample-count and sample-delta lists differ in length")
+	}
+
+	pushVersionAndFlags(&data, sb.Version(), sb.Flags())
+	bmfcommon.PushBytes(&data, uint32(len(sb.sampleCounts)))
+
+	for i, sampleCount := range sb.sampleCounts {
+		bmfcommon.PushBytes(&data, sampleCount)
+		bmfcommon.PushBytes(&data, sb.sampleDeltas[i])
+	}
+
+	return data, nil
+}
+
 type sttsBoxFactory struct {
 }
 
@@ -72,16 +106,25 @@ func (sttsBoxFactory) Name() string {
 	return "stts"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (sttsBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
 // New returns a new value instance.
-func (sttsBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf sttsBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	sttsBox := &SttsBox{
-		Box: box,
+		Box:     box,
+		FullBox: fb,
 	}
 
 	err = sttsBox.parse()
@@ -91,8 +134,9 @@ func (sttsBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = sttsBoxFactory{}
-	_ bmfcommon.CommonBox  = &SttsBox{}
+	_ bmfcommon.FullBoxFactory = sttsBoxFactory{}
+	_ bmfcommon.CommonBox      = &SttsBox{}
+	_ bmfcommon.FieldExporter  = &SttsBox{}
 )
 
 func init() {
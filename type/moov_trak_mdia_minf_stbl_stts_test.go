@@ -1,6 +1,7 @@
 package bmftype
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -12,7 +13,7 @@ import (
 
 func TestSttsBox_Version(t *testing.T) {
 	mb := SttsBox{
-		version: 11,
+		FullBox: bmfcommon.NewFullBox(11, 0),
 	}
 
 	if mb.Version() != 11 {
@@ -22,7 +23,7 @@ func TestSttsBox_Version(t *testing.T) {
 
 func TestSttsBox_Flags(t *testing.T) {
 	mb := SttsBox{
-		flags: 22,
+		FullBox: bmfcommon.NewFullBox(0, 22),
 	}
 
 	if mb.Flags() != 22 {
@@ -54,6 +55,27 @@ func TestSttsBox_SampleDeltas(t *testing.T) {
 	}
 }
 
+func TestSttsBox_ExportFields(t *testing.T) {
+	sb := SttsBox{
+		sampleCounts: []uint32{11, 33},
+		sampleDeltas: []uint32{22, 44},
+	}
+
+	fields, err := sb.ExportFields()
+	log.PanicIf(err)
+
+	expected := map[string]interface{}{
+		"entries": []map[string]interface{}{
+			{"sample_count": uint32(11), "sample_delta": uint32(22)},
+			{"sample_count": uint32(33), "sample_delta": uint32(44)},
+		},
+	}
+
+	if reflect.DeepEqual(fields, expected) != true {
+		t.Fatalf("ExportFields() not correct: %v", fields)
+	}
+}
+
 func TestSttsBoxFactory_Name(t *testing.T) {
 	name := sttsBoxFactory{}.Name()
 	if name != "stts" {
@@ -65,7 +87,7 @@ func TestSttsBoxFactory_New(t *testing.T) {
 	data := []byte{}
 
 	// flags
-	bmfcommon.PushBytes(&data, uint32(0x11223344))
+	bmfcommon.PushBytes(&data, uint32(0x00223344))
 
 	// count
 	bmfcommon.PushBytes(&data, uint32(3))
@@ -106,11 +128,11 @@ func TestSttsBoxFactory_New(t *testing.T) {
 
 	sd := cb.(*SttsBox)
 
-	if sd.Version() != 0x11 {
+	if sd.Version() != 0 {
 		t.Fatalf("Version() not correct: (0x%02x)", sd.Version())
 	}
 
-	if sd.Flags() != 0x11223344 {
+	if sd.Flags() != 0x223344 {
 		t.Fatalf("Flags() not correct: (0x%08x)", sd.Flags())
 	}
 
@@ -134,3 +156,44 @@ func TestSttsBoxFactory_New(t *testing.T) {
 		t.Fatalf("SampleDeltas not correct.")
 	}
 }
+
+func TestSttsBoxFactory_New_TooManyEntries(t *testing.T) {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	// entry-count
+	bmfcommon.PushBytes(&data, uint32(1000))
+
+	bmfcommon.PushBytes(&data, uint32(1))
+	bmfcommon.PushBytes(&data, uint32(2))
+
+	var b []byte
+	bmfcommon.PushBox(&b, "stts", data)
+
+	options := bmfcommon.ParseOptions{
+		Strict:   true,
+		Registry: bmfcommon.NewRegistry(),
+	}
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResourceWithOptions(sb, int64(len(b)), options)
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	_, _, err = sttsBoxFactory{}.New(box)
+	if err == nil {
+		t.Fatalf("Expected error for entries that can not fit.")
+	} else if err.Error() != "box [stts]: read of (8000) bytes at offset (0x0000000000000010) is truncated: (8)" {
+		t.Fatalf("Error not correct: [%s]", err.Error())
+	}
+
+	var et *bmfcommon.ErrTruncated
+	if errors.As(err, &et) != true {
+		t.Fatalf("Expected ErrTruncated.")
+	}
+}
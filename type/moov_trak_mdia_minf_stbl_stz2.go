@@ -0,0 +1,193 @@
+package bmftype
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+// Stz2Box is the "Compact Sample Size" box.
+type Stz2Box struct {
+	bmfcommon.Box
+	bmfcommon.FullBox
+
+	fieldSize   uint8
+	sampleCount uint32
+	entrySizes  []uint32
+}
+
+// FieldSize returns the number of bits used to store each entry (4, 8, or 16).
+func (sb *Stz2Box) FieldSize() uint8 {
+	return sb.fieldSize
+}
+
+// SampleCount returns the number of samples in the track.
+func (sb *Stz2Box) SampleCount() uint32 {
+	return sb.sampleCount
+}
+
+// EntrySizes returns the individual sample-sizes.
+func (sb *Stz2Box) EntrySizes() []uint32 {
+	return sb.entrySizes
+}
+
+// SampleSizeAt returns the size of the sample with the given (zero-based)
+// index.
+func (sb *Stz2Box) SampleSizeAt(index uint32) (size uint32, err error) {
+	if index >= sb.sampleCount {
+		return 0, fmt.Errorf("sample index (%d) out of range (%d)", index, sb.sampleCount)
+	}
+
+	return sb.entrySizes[index], nil
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (sb *Stz2Box) InlineString() string {
+	return fmt.Sprintf(
+		"%s VER=(0x%02x) FLAGS=(0x%08x) FIELD-SIZE=(%d) SAMPLE-COUNT=(%d)",
+		sb.Box.InlineString(), sb.Version(), sb.Flags(), sb.fieldSize, sb.sampleCount)
+}
+
+func (b *Stz2Box) parse() (err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	data, err := b.Data()
+	log.PanicIf(err)
+
+	if len(data) < 12 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 12, int64(len(data))))
+	}
+
+	// Bytes 4:7 are reserved.
+
+	b.fieldSize = data[7]
+	b.sampleCount = bmfcommon.DefaultEndianness.Uint32(data[8:12])
+
+	if b.fieldSize != 4 && b.fieldSize != 8 && b.fieldSize != 16 {
+		log.Panicf("stz2: field-size not valid: (%d)", b.fieldSize)
+	}
+
+	tableSize := (uint64(b.sampleCount)*uint64(b.fieldSize) + 7) / 8
+	if uint64(len(data)-12) < tableSize {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize()+12, int64(tableSize), int64(len(data)-12)))
+	}
+
+	err = b.CheckEntryCount("entry sizes", int64(b.sampleCount), 12, 0)
+	log.PanicIf(err)
+
+	b.entrySizes = make([]uint32, b.sampleCount)
+
+	table := data[12:]
+	for i := 0; i < int(b.sampleCount); i++ {
+		switch b.fieldSize {
+		case 4:
+			// Two entries per byte, with the first in the upper nibble.
+			if i%2 == 0 {
+				b.entrySizes[i] = uint32(table[i/2] >> 4)
+			} else {
+				b.entrySizes[i] = uint32(table[i/2] & 0x0f)
+			}
+		case 8:
+			b.entrySizes[i] = uint32(table[i])
+		case 16:
+			b.entrySizes[i] = uint32(bmfcommon.DefaultEndianness.Uint16(table[i*2 : i*2+2]))
+		}
+	}
+
+	return nil
+}
+
+// ExportFields returns the parsed fields for structured exports.
+func (sb *Stz2Box) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"field_size":   sb.fieldSize,
+		"sample_count": sb.sampleCount,
+		"entry_sizes":  sb.entrySizes,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box.
+func (sb *Stz2Box) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	pushVersionAndFlags(&data, sb.Version(), sb.Flags())
+	data = append(data, 0, 0, 0, sb.fieldSize)
+	bmfcommon.PushBytes(&data, uint32(len(sb.entrySizes)))
+
+	for i, entrySize := range sb.entrySizes {
+		switch sb.fieldSize {
+		case 4:
+			if i%2 == 0 {
+				data = append(data, byte(entrySize&0x0f)<<4)
+			} else {
+				data[len(data)-1] |= byte(entrySize & 0x0f)
+			}
+		case 8:
+			data = append(data, byte(entrySize))
+		case 16:
+			bmfcommon.PushBytes(&data, uint16(entrySize))
+		default:
+			log.Panicf("stz2: field-size not valid: (%d)", sb.fieldSize)
+		}
+	}
+
+	return data, nil
+}
+
+type stz2BoxFactory struct {
+}
+
+// Name returns the name of the type.
+func (stz2BoxFactory) Name() string {
+	return "stz2"
+}
+
+// SupportedVersions returns the versions that the factory can parse.
+func (stz2BoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
+// New returns a new value instance.
+func (bf stz2BoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
+	stz2Box := &Stz2Box{
+		Box:     box,
+		FullBox: fb,
+	}
+
+	err = stz2Box.parse()
+	log.PanicIf(err)
+
+	return stz2Box, -1, nil
+}
+
+var (
+	_ bmfcommon.FullBoxFactory = stz2BoxFactory{}
+	_ bmfcommon.CommonBox      = &Stz2Box{}
+	_ bmfcommon.FieldExporter  = &Stz2Box{}
+	_ sampleSizeSource         = &Stz2Box{}
+)
+
+func init() {
+	bmfcommon.RegisterBoxType(stz2BoxFactory{})
+}
@@ -0,0 +1,130 @@
+package bmftype
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestStz2Box_FieldSize(t *testing.T) {
+	sb := Stz2Box{
+		fieldSize: 8,
+	}
+
+	if sb.FieldSize() != 8 {
+		t.Fatalf("FieldSize() not correct.")
+	}
+}
+
+func TestStz2Box_SampleSizeAt(t *testing.T) {
+	sb := Stz2Box{
+		sampleCount: 2,
+		entrySizes:  []uint32{44, 55},
+	}
+
+	size, err := sb.SampleSizeAt(1)
+	log.PanicIf(err)
+
+	if size != 55 {
+		t.Fatalf("SampleSizeAt() not correct: (%d)", size)
+	}
+
+	_, err = sb.SampleSizeAt(2)
+	if err == nil {
+		t.Fatalf("Expected error for out-of-range index.")
+	}
+}
+
+func TestStz2BoxFactory_Name(t *testing.T) {
+	name := stz2BoxFactory{}.Name()
+
+	if name != "stz2" {
+		t.Fatalf("Name() not correct.")
+	}
+}
+
+func getTestStz2Box(fieldSize uint8, sampleCount uint32, table []byte) *Stz2Box {
+	var data []byte
+
+	// version and flags
+	bmfcommon.PushBytes(&data, uint32(0))
+
+	// reserved and field-size
+	data = append(data, 0, 0, 0, fieldSize)
+
+	// sample-count
+	bmfcommon.PushBytes(&data, sampleCount)
+
+	data = append(data, table...)
+
+	var b []byte
+	bmfcommon.PushBox(&b, "stz2", data)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	file, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	box, err := file.ReadBaseBox(0)
+	log.PanicIf(err)
+
+	cb, _, err := stz2BoxFactory{}.New(box)
+	log.PanicIf(err)
+
+	return cb.(*Stz2Box)
+}
+
+func TestStz2BoxFactory_New_4(t *testing.T) {
+	stz2 := getTestStz2Box(4, 3, []byte{0x12, 0x30})
+
+	if reflect.DeepEqual(stz2.EntrySizes(), []uint32{1, 2, 3}) != true {
+		t.Fatalf("EntrySizes() not correct: %v", stz2.EntrySizes())
+	}
+}
+
+func TestStz2BoxFactory_New_8(t *testing.T) {
+	stz2 := getTestStz2Box(8, 3, []byte{11, 22, 33})
+
+	if stz2.FieldSize() != 8 {
+		t.Fatalf("FieldSize() not correct.")
+	} else if stz2.SampleCount() != 3 {
+		t.Fatalf("SampleCount() not correct.")
+	}
+
+	if reflect.DeepEqual(stz2.EntrySizes(), []uint32{11, 22, 33}) != true {
+		t.Fatalf("EntrySizes() not correct: %v", stz2.EntrySizes())
+	}
+}
+
+func TestStz2BoxFactory_New_16(t *testing.T) {
+	stz2 := getTestStz2Box(16, 2, []byte{0x01, 0x02, 0x03, 0x04})
+
+	if reflect.DeepEqual(stz2.EntrySizes(), []uint32{0x0102, 0x0304}) != true {
+		t.Fatalf("EntrySizes() not correct: %v", stz2.EntrySizes())
+	}
+}
+
+func TestStz2Box_EncodeData(t *testing.T) {
+	for _, fieldSize := range []uint8{4, 8, 16} {
+		var data []byte
+		bmfcommon.PushBytes(&data, uint32(0))
+		data = append(data, 0, 0, 0, fieldSize)
+		bmfcommon.PushBytes(&data, uint32(3))
+
+		switch fieldSize {
+		case 4:
+			data = append(data, 0x12, 0x30)
+		case 8:
+			data = append(data, 1, 2, 3)
+		case 16:
+			data = append(data, 0, 1, 0, 2, 0, 3)
+		}
+
+		cb := getTestParsedBox(stz2BoxFactory{}, data)
+		assertEncodeData(t, cb, data)
+	}
+}
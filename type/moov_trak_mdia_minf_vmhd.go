@@ -9,23 +9,12 @@ import (
 // VmhdBox is the "Video Media Header" box.
 type VmhdBox struct {
 	bmfcommon.Box
+	bmfcommon.FullBox
 
-	version      byte
-	flags        uint32
 	graphicsMode uint16
 	opColor      uint16
 }
 
-// Version returns the version of the record.
-func (vb *VmhdBox) Version() byte {
-	return vb.version
-}
-
-// Flags returns the flags.
-func (vb *VmhdBox) Flags() uint32 {
-	return vb.flags
-}
-
 // GraphicsMode returns the graphics mode.
 func (vb *VmhdBox) GraphicsMode() uint16 {
 	return vb.graphicsMode
@@ -46,14 +35,48 @@ func (b *VmhdBox) parse() (err error) {
 	data, err := b.Data()
 	log.PanicIf(err)
 
-	b.version = data[0]
-	b.flags = bmfcommon.DefaultEndianness.Uint32(data[0:4])
+	if len(data) < 8 {
+		log.Panic(bmfcommon.NewErrTruncated(b, b.Start()+b.HeaderSize(), 8, int64(len(data))))
+	}
+
 	b.graphicsMode = bmfcommon.DefaultEndianness.Uint16(data[4:6])
 	b.opColor = bmfcommon.DefaultEndianness.Uint16(data[6:8])
 
 	return nil
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (vb *VmhdBox) ExportFields() (fields map[string]interface{}, err error) {
+	fields = map[string]interface{}{
+		"graphics_mode": vb.graphicsMode,
+		"op_color":      vb.opColor,
+	}
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. Bytes that are not modeled are
+// carried over.
+func (vb *VmhdBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := vb.Data()
+	log.PanicIf(err)
+
+	data = make([]byte, len(original))
+	copy(data, original)
+
+	bmfcommon.DefaultEndianness.PutUint32(data[0:4], uint32(vb.Version())<<24|vb.Flags()&0x00ffffff)
+	bmfcommon.DefaultEndianness.PutUint16(data[4:6], vb.graphicsMode)
+	bmfcommon.DefaultEndianness.PutUint16(data[6:8], vb.opColor)
+
+	return data, nil
+}
+
 type vmhdBoxFactory struct {
 }
 
@@ -62,16 +85,25 @@ func (vmhdBoxFactory) Name() string {
 	return "vmhd"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (vmhdBoxFactory) SupportedVersions() []byte {
+	return []byte{0}
+}
+
 // New returns a new value instance.
-func (vmhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf vmhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	vmhdBox := &VmhdBox{
-		Box: box,
+		Box:     box,
+		FullBox: fb,
 	}
 
 	err = vmhdBox.parse()
@@ -81,8 +113,9 @@ func (vmhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = vmhdBoxFactory{}
-	_ bmfcommon.CommonBox  = &VmhdBox{}
+	_ bmfcommon.FullBoxFactory = vmhdBoxFactory{}
+	_ bmfcommon.CommonBox      = &VmhdBox{}
+	_ bmfcommon.FieldExporter  = &VmhdBox{}
 )
 
 func init() {
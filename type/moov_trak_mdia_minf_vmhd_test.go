@@ -11,7 +11,7 @@ import (
 
 func TestVmhdBox_Version(t *testing.T) {
 	vb := VmhdBox{
-		version: 0x11,
+		FullBox: bmfcommon.NewFullBox(0x11, 0),
 	}
 
 	if vb.Version() != 0x11 {
@@ -21,10 +21,10 @@ func TestVmhdBox_Version(t *testing.T) {
 
 func TestVmhdBox_Flags(t *testing.T) {
 	vb := VmhdBox{
-		flags: 0x11223344,
+		FullBox: bmfcommon.NewFullBox(0, 0x11223344),
 	}
 
-	if vb.Flags() != 0x11223344 {
+	if vb.Flags() != 0x223344 {
 		t.Fatalf("Flags() not correct.")
 	}
 }
@@ -60,7 +60,7 @@ func TestVmhdBoxFactory_New(t *testing.T) {
 	var data []byte
 
 	// flags
-	bmfcommon.PushBytes(&data, uint32(0x11223344))
+	bmfcommon.PushBytes(&data, uint32(0x00223344))
 
 	// graphicsMode
 	bmfcommon.PushBytes(&data, uint16(0x55))
@@ -86,11 +86,11 @@ func TestVmhdBoxFactory_New(t *testing.T) {
 
 	vb := cb.(*VmhdBox)
 
-	if vb.Version() != 0x11 {
+	if vb.Version() != 0 {
 		t.Fatalf("Version() not correct: (0x%02x)", vb.Version())
 	}
 
-	if vb.Flags() != 0x11223344 {
+	if vb.Flags() != 0x223344 {
 		t.Fatalf("Flags() not correct.")
 	}
 
@@ -1,6 +1,7 @@
 package bmftype
 
 import (
+	"os"
 	"reflect"
 	"testing"
 
@@ -21,6 +22,204 @@ func TestTrakBox_SetLoadedBoxIndex(t *testing.T) {
 	}
 }
 
+func getTestTrakBox() *TrakBox {
+	hdlr := &HdlrBox{
+		handler: "vide",
+	}
+
+	mdhd := &MdhdBox{
+		Standard32TimeSupport: bmfcommon.NewStandard32TimeSupport(0, 0, 0, 600),
+	}
+
+	mdia := &MdiaBox{
+		LoadedBoxIndex: bmfcommon.LoadedBoxIndex{
+			"hdlr": []bmfcommon.CommonBox{hdlr},
+			"mdhd": []bmfcommon.CommonBox{mdhd},
+		},
+	}
+
+	tkhd := &TkhdBox{
+		trackId: 11,
+	}
+
+	trak := &TrakBox{
+		LoadedBoxIndex: bmfcommon.LoadedBoxIndex{
+			"tkhd": []bmfcommon.CommonBox{tkhd},
+			"mdia": []bmfcommon.CommonBox{mdia},
+		},
+	}
+
+	return trak
+}
+
+func TestTrakBox_Tkhd(t *testing.T) {
+	trak := getTestTrakBox()
+
+	tkhd, err := trak.Tkhd()
+	log.PanicIf(err)
+
+	if tkhd.TrackId() != 11 {
+		t.Fatalf("Tkhd() not correct.")
+	}
+}
+
+func TestTrakBox_Tkhd_Missing(t *testing.T) {
+	trak := new(TrakBox)
+
+	_, err := trak.Tkhd()
+	if err == nil {
+		t.Fatalf("Expected error for missing TKHD.")
+	}
+}
+
+func TestTrakBox_Mdia(t *testing.T) {
+	trak := getTestTrakBox()
+
+	mdia, err := trak.Mdia()
+	log.PanicIf(err)
+
+	if mdia != trak.LoadedBoxIndex["mdia"][0] {
+		t.Fatalf("Mdia() not correct.")
+	}
+}
+
+func TestTrakBox_Edts(t *testing.T) {
+	trak := getTestTrakBox()
+
+	edts, err := trak.Edts()
+	log.PanicIf(err)
+
+	if edts != nil {
+		t.Fatalf("Edts() should be nil when not present.")
+	}
+
+	expected := new(EdtsBox)
+	trak.LoadedBoxIndex["edts"] = []bmfcommon.CommonBox{expected}
+
+	edts, err = trak.Edts()
+	log.PanicIf(err)
+
+	if edts != expected {
+		t.Fatalf("Edts() not correct.")
+	}
+}
+
+func TestTrakBox_Edts_WrongType(t *testing.T) {
+	trak := getTestTrakBox()
+
+	// A registry override could produce a different type.
+	trak.LoadedBoxIndex["edts"] = []bmfcommon.CommonBox{new(ElstBox)}
+
+	_, err := trak.Edts()
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+}
+
+func TestTrakBox_HandlerType(t *testing.T) {
+	trak := getTestTrakBox()
+
+	handlerType, err := trak.HandlerType()
+	log.PanicIf(err)
+
+	if handlerType != "vide" {
+		t.Fatalf("HandlerType() not correct: [%s]", handlerType)
+	}
+}
+
+func TestTrakBox_HandlerType_WrongType(t *testing.T) {
+	trak := getTestTrakBox()
+
+	mdia, err := trak.Mdia()
+	log.PanicIf(err)
+
+	mdia.LoadedBoxIndex["hdlr"] = []bmfcommon.CommonBox{new(MdhdBox)}
+
+	_, err = trak.HandlerType()
+	if err == nil {
+		t.Fatalf("Expected error.")
+	}
+}
+
+func TestTrakBox_MediaTimeScale(t *testing.T) {
+	trak := getTestTrakBox()
+
+	timeScale, err := trak.MediaTimeScale()
+	log.PanicIf(err)
+
+	if timeScale != 600 {
+		t.Fatalf("MediaTimeScale() not correct: (%d)", timeScale)
+	}
+}
+
+func TestTrakBox_QueryAttribute(t *testing.T) {
+	trak := getTestTrakBox()
+
+	value, found, err := trak.QueryAttribute("hdlr")
+	log.PanicIf(err)
+
+	if found != true || value != "vide" {
+		t.Fatalf("Handler-type attribute not correct: [%s]", value)
+	}
+
+	value, found, err = trak.QueryAttribute("track_id")
+	log.PanicIf(err)
+
+	if found != true || value != "11" {
+		t.Fatalf("Track-ID attribute not correct: [%s]", value)
+	}
+
+	_, found, err = trak.QueryAttribute("invalid")
+	log.PanicIf(err)
+
+	if found != false {
+		t.Fatalf("Expected unknown attribute to not be found.")
+	}
+}
+
+func TestTrakBox_Find_Asset(t *testing.T) {
+	f, err := os.Open(getTestAssetFilepath("tears-of-steel.mp4"))
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+	log.PanicIf(err)
+
+	resource, err := bmfcommon.NewResource(f, fi.Size())
+	log.PanicIf(err)
+
+	cb, err := resource.Find("moov/trak[hdlr=soun]/tkhd")
+	log.PanicIf(err)
+
+	if cb.(*TkhdBox).TrackId() != 2 {
+		t.Fatalf("Audio track not correct.")
+	}
+
+	boxes, err := resource.FindAll("moov/trak[hdlr=vide]/mdia/minf/stbl/*")
+	log.PanicIf(err)
+
+	if len(boxes) == 0 {
+		t.Fatalf("Expected STBL children.")
+	}
+
+	for _, cb := range boxes {
+		trak := cb.Parent().Parent().Parent().Parent().(*TrakBox)
+
+		tkhd, err := trak.Tkhd()
+		log.PanicIf(err)
+
+		if tkhd.TrackId() != 1 {
+			t.Fatalf("Track not correct.")
+		}
+	}
+
+	_, err = resource.Find("moov/trak[track_id=3]")
+	if err != bmfcommon.ErrNoBoxesFound {
+		t.Fatalf("Expected no match for missing track: %v", err)
+	}
+}
+
 func TestTrakBoxFactory_Name(t *testing.T) {
 	name := trakBoxFactory{}.Name()
 
@@ -53,3 +252,103 @@ func TestTrakBoxFactory_New(t *testing.T) {
 		t.Fatalf("Expected an 'trak' box.")
 	}
 }
+
+func TestTrakBox_Asset(t *testing.T) {
+	f, err := os.Open(getTestAssetFilepath("tears-of-steel.mp4"))
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+	log.PanicIf(err)
+
+	resource, err := bmfcommon.NewResource(f, fi.Size())
+	log.PanicIf(err)
+
+	mdatBoxes, err := resource.GetChildBoxes("mdat")
+	log.PanicIf(err)
+
+	mdat := mdatBoxes[0].(*MdatBox)
+	mdatStart := uint64(mdat.Start() + mdat.HeaderSize())
+	mdatEnd := uint64(mdat.Start() + mdat.Size())
+
+	moovBoxes, err := resource.GetChildBoxes("moov")
+	log.PanicIf(err)
+
+	moov := moovBoxes[0].(*MoovBox)
+
+	trakBoxes, err := moov.GetChildBoxes("trak")
+	log.PanicIf(err)
+
+	expected := []struct {
+		trackId         uint32
+		handlerType     string
+		timeScale       uint64
+		sampleCount     int
+		syncSampleCount int
+	}{
+		{1, "vide", 12288, 59, 4},
+		{2, "soun", 44100, 107, 107},
+	}
+
+	if len(trakBoxes) != len(expected) {
+		t.Fatalf("Track count not correct: (%d)", len(trakBoxes))
+	}
+
+	for i, cb := range trakBoxes {
+		trak := cb.(*TrakBox)
+		e := expected[i]
+
+		tkhd, err := trak.Tkhd()
+		log.PanicIf(err)
+
+		if tkhd.TrackId() != e.trackId {
+			t.Fatalf("Track (%d) ID not correct: (%d)", i, tkhd.TrackId())
+		}
+
+		handlerType, err := trak.HandlerType()
+		log.PanicIf(err)
+
+		if handlerType != e.handlerType {
+			t.Fatalf("Track (%d) handler-type not correct: [%s]", i, handlerType)
+		}
+
+		timeScale, err := trak.MediaTimeScale()
+		log.PanicIf(err)
+
+		if timeScale != e.timeScale {
+			t.Fatalf("Track (%d) time-scale not correct: (%d)", i, timeScale)
+		}
+
+		edts, err := trak.Edts()
+		log.PanicIf(err)
+
+		if edts == nil {
+			t.Fatalf("Track (%d) should have an EDTS box.", i)
+		}
+
+		mdia, err := trak.Mdia()
+		log.PanicIf(err)
+
+		minfBoxes, err := mdia.GetChildBoxes("minf")
+		log.PanicIf(err)
+
+		stblBoxes, err := minfBoxes[0].(*MinfBox).GetChildBoxes("stbl")
+		log.PanicIf(err)
+
+		st, err := stblBoxes[0].(*StblBox).SampleTable()
+		log.PanicIf(err)
+
+		if st.SampleCount() != e.sampleCount {
+			t.Fatalf("Track (%d) sample-count not correct: (%d)", i, st.SampleCount())
+		} else if len(st.SyncSamples()) != e.syncSampleCount {
+			t.Fatalf("Track (%d) sync-sample count not correct: (%d)", i, len(st.SyncSamples()))
+		}
+
+		for _, si := range st.Samples() {
+			if si.Offset() < mdatStart || si.Offset()+uint64(si.Size()) > mdatEnd {
+				t.Fatalf("Track (%d) sample not within MDAT: %s", i, si)
+			}
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package bmftype
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dsoprea/go-logging"
+)
+
+var (
+	// ErrTimeNotMapped indicates that a time falls outside of the edits or in
+	// an empty edit, or that a media time is never presented.
+	ErrTimeNotMapped = errors.New("time not mapped by edit list")
+)
+
+const (
+	// timelineRateOne is a media-rate of 1.0 as a 16.16 fixed-point value.
+	timelineRateOne = 1 << 16
+)
+
+// TimelineSegment is one edit on the presentation timeline. Presentation times
+// and durations are in the time-scale of the movie (see MVHD) and media times
+// are in the time-scale of the media (see MDHD).
+type TimelineSegment struct {
+	presentationStart uint64
+	duration          uint64
+	isOpenEnded       bool
+	mediaTime         int64
+	rate              int64
+}
+
+// PresentationStart returns the time at which the segment starts on the
+// presentation timeline.
+func (ts TimelineSegment) PresentationStart() uint64 {
+	return ts.presentationStart
+}
+
+// Duration returns the duration of the segment on the presentation timeline.
+// It is zero if the segment is open-ended.
+func (ts TimelineSegment) Duration() uint64 {
+	return ts.duration
+}
+
+// IsOpenEnded returns true if the segment extends to the end of the media. This
+// is the case for a track without an edit list and for a final edit with a
+// duration of zero (as written for fragmented files).
+func (ts TimelineSegment) IsOpenEnded() bool {
+	return ts.isOpenEnded
+}
+
+// MediaTime returns the media time presented at the start of the segment. It
+// is ElstEmptyMediaTime for an empty edit.
+func (ts TimelineSegment) MediaTime() int64 {
+	return ts.mediaTime
+}
+
+// Rate returns the media-rate as a 16.16 fixed-point value.
+func (ts TimelineSegment) Rate() int64 {
+	return ts.rate
+}
+
+// IsEmpty returns true if nothing from the media is presented during this
+// segment.
+func (ts TimelineSegment) IsEmpty() bool {
+	return ts.mediaTime == ElstEmptyMediaTime
+}
+
+// IsDwell returns true if the media is held at a single time for the duration
+// of this segment.
+func (ts TimelineSegment) IsDwell() bool {
+	return ts.IsEmpty() == false && ts.rate == 0
+}
+
+// InlineString returns an undecorated string of field names and values.
+func (ts TimelineSegment) InlineString() string {
+	return fmt.Sprintf(
+		"START=(%d) DURATION=(%d) OPEN=[%v] MEDIA-TIME=(%d) RATE=(0x%08x)",
+		ts.presentationStart, ts.duration, ts.isOpenEnded, ts.mediaTime, ts.rate)
+}
+
+// String returns a descriptive string.
+func (ts TimelineSegment) String() string {
+	return fmt.Sprintf("TimelineSegment<%s>", ts.InlineString())
+}
+
+// Timeline maps between the presentation timeline of a track and the times of
+// its media using the edit list. Without an edit list, the media is presented
+// from its start.
+type Timeline struct {
+	movieTimeScale uint64
+	mediaTimeScale uint64
+	segments       []TimelineSegment
+}
+
+// NewTimeline returns a timeline for the given time-scales and edit list. The
+// edit list may be nil.
+func NewTimeline(movieTimeScale, mediaTimeScale uint64, elst *ElstBox) (timeline *Timeline, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	if movieTimeScale == 0 {
+		log.Panicf("movie time-scale is zero")
+	} else if mediaTimeScale == 0 {
+		log.Panicf("media time-scale is zero")
+	}
+
+	var entries []ElstEntry
+	if elst != nil {
+		entries = elst.Entries()
+	}
+
+	var segments []TimelineSegment
+
+	if len(entries) == 0 {
+		segments = []TimelineSegment{
+			{
+				isOpenEnded: true,
+				rate:        timelineRateOne,
+			},
+		}
+	} else {
+		segments = make([]TimelineSegment, len(entries))
+		presentationStart := uint64(0)
+
+		for i, ee := range entries {
+			if ee.MediaTime() < ElstEmptyMediaTime {
+				log.Panicf("elst: entry (%d) has an invalid media-time: (%d)", i, ee.MediaTime())
+			}
+
+			rate := int64(ee.MediaRate())<<16 | int64(uint16(ee.MediaRateFraction()))
+			if ee.IsEmpty() == false && rate < 0 {
+				log.Panicf("elst: entry (%d) has a negative media-rate, which is not supported", i)
+			}
+
+			segments[i] = TimelineSegment{
+				presentationStart: presentationStart,
+				duration:          ee.SegmentDuration(),
+				isOpenEnded:       i == len(entries)-1 && ee.SegmentDuration() == 0 && ee.IsEmpty() == false,
+				mediaTime:         ee.MediaTime(),
+				rate:              rate,
+			}
+
+			presentationStart += ee.SegmentDuration()
+		}
+	}
+
+	timeline = &Timeline{
+		movieTimeScale: movieTimeScale,
+		mediaTimeScale: mediaTimeScale,
+		segments:       segments,
+	}
+
+	return timeline, nil
+}
+
+// MovieTimeScale returns the time-scale of presentation times.
+func (timeline *Timeline) MovieTimeScale() uint64 {
+	return timeline.movieTimeScale
+}
+
+// MediaTimeScale returns the time-scale of media times.
+func (timeline *Timeline) MediaTimeScale() uint64 {
+	return timeline.mediaTimeScale
+}
+
+// Segments returns the segments in presentation order.
+func (timeline *Timeline) Segments() []TimelineSegment {
+	return timeline.segments
+}
+
+// scaleTime returns `value * numerator / denominator`, rounded down, without
+// overflowing along the way.
+func scaleTime(value, numerator, denominator uint64) uint64 {
+	x := new(big.Int).SetUint64(value)
+	x.Mul(x, new(big.Int).SetUint64(numerator))
+	x.Quo(x, new(big.Int).SetUint64(denominator))
+
+	return x.Uint64()
+}
+
+// MediaTime returns the media time that is presented at the given
+// presentation time. ErrTimeNotMapped is returned if the presentation time is
+// in an empty edit or after the last edit.
+func (timeline *Timeline) MediaTime(presentationTime uint64) (mediaTime int64, err error) {
+	for _, ts := range timeline.segments {
+		if ts.isOpenEnded == false && presentationTime >= ts.presentationStart+ts.duration {
+			continue
+		} else if ts.IsEmpty() == true {
+			return 0, ErrTimeNotMapped
+		} else if ts.IsDwell() == true {
+			return ts.mediaTime, nil
+		}
+
+		// The elapsed presentation time in the media time-scale, at the rate
+		// of the edit.
+
+		elapsed := presentationTime - ts.presentationStart
+		offset := scaleTime(elapsed, timeline.mediaTimeScale*uint64(ts.rate), timeline.movieTimeScale*timelineRateOne)
+
+		return ts.mediaTime + int64(offset), nil
+	}
+
+	return 0, ErrTimeNotMapped
+}
+
+// PresentationTime returns the first presentation time at which the given
+// media time is presented. ErrTimeNotMapped is returned if the media time is
+// never presented (e.g. encoder priming that is edited out).
+func (timeline *Timeline) PresentationTime(mediaTime int64) (presentationTime uint64, err error) {
+	for _, ts := range timeline.segments {
+		if ts.IsEmpty() == true || mediaTime < ts.mediaTime {
+			continue
+		} else if ts.IsDwell() == true {
+			if mediaTime == ts.mediaTime {
+				return ts.presentationStart, nil
+			}
+
+			continue
+		}
+
+		elapsed := scaleTime(uint64(mediaTime-ts.mediaTime), timeline.movieTimeScale*timelineRateOne, timeline.mediaTimeScale*uint64(ts.rate))
+
+		if ts.isOpenEnded == true || elapsed < ts.duration {
+			return ts.presentationStart + elapsed, nil
+		}
+	}
+
+	return 0, ErrTimeNotMapped
+}
+
+// Timeline returns the timeline of this track, which uses the time-scale of
+// the movie for presentation times and the time-scale of the media for media
+// times (as used by SampleTable).
+func (trak *TrakBox) Timeline() (timeline *Timeline, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	moov, ok := trak.Parent().(*MoovBox)
+	if ok == false {
+		log.Panicf("trak: not in a moov box")
+	}
+
+	boxes, err := moov.GetChildBoxes("mvhd")
+	log.PanicIf(err)
+
+	mvhd := boxes[0].(*MvhdBox)
+
+	mediaTimeScale, err := trak.MediaTimeScale()
+	log.PanicIf(err)
+
+	var elst *ElstBox
+
+	edts, err := trak.Edts()
+	log.PanicIf(err)
+
+	if edts != nil {
+		if _, found := edts.LoadedBoxIndex["elst"]; found == true {
+			boxes, err := edts.GetChildBoxes("elst")
+			log.PanicIf(err)
+
+			elst = boxes[0].(*ElstBox)
+		}
+	}
+
+	timeline, err = NewTimeline(mvhd.TimeScale(), mediaTimeScale, elst)
+	log.PanicIf(err)
+
+	return timeline, nil
+}
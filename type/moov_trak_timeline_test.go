@@ -0,0 +1,386 @@
+package bmftype
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/filesystem"
+
+	"github.com/dsoprea/go-iso-bmf/common"
+)
+
+func TestNewTimeline_NoEdits(t *testing.T) {
+	timeline, err := NewTimeline(1000, 44100, nil)
+	log.PanicIf(err)
+
+	segments := timeline.Segments()
+	if len(segments) != 1 {
+		t.Fatalf("Expected one segment.")
+	} else if segments[0].IsOpenEnded() != true {
+		t.Fatalf("Expected open-ended segment.")
+	}
+
+	mediaTime, err := timeline.MediaTime(1000)
+	log.PanicIf(err)
+
+	if mediaTime != 44100 {
+		t.Fatalf("MediaTime() not correct: (%d)", mediaTime)
+	}
+
+	presentationTime, err := timeline.PresentationTime(44100)
+	log.PanicIf(err)
+
+	if presentationTime != 1000 {
+		t.Fatalf("PresentationTime() not correct: (%d)", presentationTime)
+	}
+}
+
+func TestNewTimeline_InvalidTimeScale(t *testing.T) {
+	_, err := NewTimeline(0, 44100, nil)
+	if err == nil {
+		t.Fatalf("Expected error for movie time-scale.")
+	}
+
+	_, err = NewTimeline(1000, 0, nil)
+	if err == nil {
+		t.Fatalf("Expected error for media time-scale.")
+	}
+}
+
+func TestNewTimeline_NegativeRate(t *testing.T) {
+	elst := &ElstBox{
+		entries: []ElstEntry{
+			{segmentDuration: 1000, mediaTime: 0, mediaRate: -1},
+		},
+	}
+
+	_, err := NewTimeline(1000, 1000, elst)
+	if err == nil {
+		t.Fatalf("Expected error for negative rate.")
+	}
+}
+
+func TestTimeline_Priming(t *testing.T) {
+	// The first 1024 samples of audio are encoder priming and are not
+	// presented.
+
+	elst := &ElstBox{
+		entries: []ElstEntry{
+			{segmentDuration: 2000, mediaTime: 1024, mediaRate: 1},
+		},
+	}
+
+	timeline, err := NewTimeline(1000, 44100, elst)
+	log.PanicIf(err)
+
+	mediaTime, err := timeline.MediaTime(0)
+	log.PanicIf(err)
+
+	if mediaTime != 1024 {
+		t.Fatalf("MediaTime() not correct at start: (%d)", mediaTime)
+	}
+
+	mediaTime, err = timeline.MediaTime(1000)
+	log.PanicIf(err)
+
+	if mediaTime != 1024+44100 {
+		t.Fatalf("MediaTime() not correct: (%d)", mediaTime)
+	}
+
+	_, err = timeline.MediaTime(2000)
+	if err != ErrTimeNotMapped {
+		t.Fatalf("Expected ErrTimeNotMapped after the last edit: %v", err)
+	}
+
+	presentationTime, err := timeline.PresentationTime(1024)
+	log.PanicIf(err)
+
+	if presentationTime != 0 {
+		t.Fatalf("PresentationTime() not correct: (%d)", presentationTime)
+	}
+
+	_, err = timeline.PresentationTime(0)
+	if err != ErrTimeNotMapped {
+		t.Fatalf("Expected ErrTimeNotMapped for priming: %v", err)
+	}
+}
+
+func TestTimeline_EmptyEdit(t *testing.T) {
+	elst := &ElstBox{
+		entries: []ElstEntry{
+			{segmentDuration: 500, mediaTime: ElstEmptyMediaTime, mediaRate: 1},
+			{segmentDuration: 1000, mediaTime: 0, mediaRate: 1},
+		},
+	}
+
+	timeline, err := NewTimeline(1000, 1000, elst)
+	log.PanicIf(err)
+
+	if timeline.Segments()[0].IsEmpty() != true {
+		t.Fatalf("Expected first segment to be empty.")
+	} else if timeline.Segments()[1].PresentationStart() != 500 {
+		t.Fatalf("Second segment start not correct.")
+	}
+
+	_, err = timeline.MediaTime(100)
+	if err != ErrTimeNotMapped {
+		t.Fatalf("Expected ErrTimeNotMapped in empty edit: %v", err)
+	}
+
+	mediaTime, err := timeline.MediaTime(600)
+	log.PanicIf(err)
+
+	if mediaTime != 100 {
+		t.Fatalf("MediaTime() not correct: (%d)", mediaTime)
+	}
+
+	presentationTime, err := timeline.PresentationTime(100)
+	log.PanicIf(err)
+
+	if presentationTime != 600 {
+		t.Fatalf("PresentationTime() not correct: (%d)", presentationTime)
+	}
+}
+
+func TestTimeline_DwellEdit(t *testing.T) {
+	elst := &ElstBox{
+		entries: []ElstEntry{
+			{segmentDuration: 500, mediaTime: 3000, mediaRate: 0},
+			{segmentDuration: 1000, mediaTime: 3000, mediaRate: 1},
+		},
+	}
+
+	timeline, err := NewTimeline(1000, 1000, elst)
+	log.PanicIf(err)
+
+	if timeline.Segments()[0].IsDwell() != true {
+		t.Fatalf("Expected first segment to be a dwell.")
+	}
+
+	mediaTime, err := timeline.MediaTime(200)
+	log.PanicIf(err)
+
+	if mediaTime != 3000 {
+		t.Fatalf("MediaTime() not correct in dwell: (%d)", mediaTime)
+	}
+
+	presentationTime, err := timeline.PresentationTime(3000)
+	log.PanicIf(err)
+
+	if presentationTime != 0 {
+		t.Fatalf("PresentationTime() not correct for dwell: (%d)", presentationTime)
+	}
+
+	presentationTime, err = timeline.PresentationTime(3500)
+	log.PanicIf(err)
+
+	if presentationTime != 1000 {
+		t.Fatalf("PresentationTime() not correct: (%d)", presentationTime)
+	}
+}
+
+func TestTimeline_Rate(t *testing.T) {
+	elst := &ElstBox{
+		entries: []ElstEntry{
+			{segmentDuration: 1000, mediaTime: 0, mediaRate: 2},
+		},
+	}
+
+	timeline, err := NewTimeline(1000, 1000, elst)
+	log.PanicIf(err)
+
+	mediaTime, err := timeline.MediaTime(100)
+	log.PanicIf(err)
+
+	if mediaTime != 200 {
+		t.Fatalf("MediaTime() not correct: (%d)", mediaTime)
+	}
+
+	presentationTime, err := timeline.PresentationTime(200)
+	log.PanicIf(err)
+
+	if presentationTime != 100 {
+		t.Fatalf("PresentationTime() not correct: (%d)", presentationTime)
+	}
+
+	_, err = timeline.PresentationTime(2000)
+	if err != ErrTimeNotMapped {
+		t.Fatalf("Expected ErrTimeNotMapped past the edit: %v", err)
+	}
+}
+
+func TestTimeline_OpenEnded(t *testing.T) {
+	elst := &ElstBox{
+		entries: []ElstEntry{
+			{segmentDuration: 0, mediaTime: 1024, mediaRate: 1},
+		},
+	}
+
+	timeline, err := NewTimeline(1000, 1000, elst)
+	log.PanicIf(err)
+
+	if timeline.Segments()[0].IsOpenEnded() != true {
+		t.Fatalf("Expected open-ended segment.")
+	}
+
+	mediaTime, err := timeline.MediaTime(5000)
+	log.PanicIf(err)
+
+	if mediaTime != 6024 {
+		t.Fatalf("MediaTime() not correct: (%d)", mediaTime)
+	}
+}
+
+func TestTrakBox_Timeline(t *testing.T) {
+	f, err := os.Open(getTestAssetFilepath("tears-of-steel.mp4"))
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+	log.PanicIf(err)
+
+	resource, err := bmfcommon.NewResource(f, fi.Size())
+	log.PanicIf(err)
+
+	moovBoxes, err := resource.GetChildBoxes("moov")
+	log.PanicIf(err)
+
+	moov := moovBoxes[0].(*MoovBox)
+
+	trakBoxes, err := moov.GetChildBoxes("trak")
+	log.PanicIf(err)
+
+	expected := []struct {
+		mediaTimeScale uint64
+		firstMediaTime int64
+	}{
+		{12288, 5632},
+		{44100, 21160},
+	}
+
+	for i, cb := range trakBoxes {
+		trak := cb.(*TrakBox)
+
+		timeline, err := trak.Timeline()
+		log.PanicIf(err)
+
+		if timeline.MovieTimeScale() != 1000 {
+			t.Fatalf("Movie time-scale not correct: (%d)", timeline.MovieTimeScale())
+		} else if timeline.MediaTimeScale() != expected[i].mediaTimeScale {
+			t.Fatalf("Media time-scale not correct: (%d)", timeline.MediaTimeScale())
+		}
+
+		mediaTime, err := timeline.MediaTime(0)
+		log.PanicIf(err)
+
+		if mediaTime != expected[i].firstMediaTime {
+			t.Fatalf("First media-time not correct for track (%d): (%d)", i, mediaTime)
+		}
+
+		presentationTime, err := timeline.PresentationTime(expected[i].firstMediaTime)
+		log.PanicIf(err)
+
+		if presentationTime != 0 {
+			t.Fatalf("Presentation-time not correct for track (%d): (%d)", i, presentationTime)
+		}
+	}
+}
+
+func TestTrakBox_Timeline_Version1(t *testing.T) {
+	// mvhd (version 1) with a 600 time-scale.
+
+	var mvhdData []byte
+	bmfcommon.PushBytes(&mvhdData, uint32(0x01000000))
+	bmfcommon.PushBytes(&mvhdData, uint64(0))
+	bmfcommon.PushBytes(&mvhdData, uint64(0))
+	bmfcommon.PushBytes(&mvhdData, uint32(600))
+	bmfcommon.PushBytes(&mvhdData, uint64(6000))
+	bmfcommon.PushBytes(&mvhdData, uint32(0x00010000))
+	bmfcommon.PushBytes(&mvhdData, uint16(0x0100))
+	bmfcommon.PushBytes(&mvhdData, make([]byte, 10+36+24))
+	bmfcommon.PushBytes(&mvhdData, uint32(2))
+
+	// elst (version 1): an empty edit of one second followed by the media
+	// from 1024.
+
+	var elstData []byte
+	bmfcommon.PushBytes(&elstData, uint32(0x01000000))
+	bmfcommon.PushBytes(&elstData, uint32(2))
+
+	bmfcommon.PushBytes(&elstData, uint64(600))
+	bmfcommon.PushBytes(&elstData, uint64(0xffffffffffffffff))
+	bmfcommon.PushBytes(&elstData, uint32(0x00010000))
+
+	bmfcommon.PushBytes(&elstData, uint64(5400))
+	bmfcommon.PushBytes(&elstData, uint64(1024))
+	bmfcommon.PushBytes(&elstData, uint32(0x00010000))
+
+	var edtsData []byte
+	bmfcommon.PushBox(&edtsData, "elst", elstData)
+
+	// mdhd (version 1) with a 48000 time-scale.
+
+	var mdhdData []byte
+	bmfcommon.PushBytes(&mdhdData, uint32(0x01000000))
+	bmfcommon.PushBytes(&mdhdData, uint64(0))
+	bmfcommon.PushBytes(&mdhdData, uint64(0))
+	bmfcommon.PushBytes(&mdhdData, uint32(48000))
+	bmfcommon.PushBytes(&mdhdData, uint64(480000))
+	bmfcommon.PushBytes(&mdhdData, uint16(0x55c4))
+	bmfcommon.PushBytes(&mdhdData, uint16(0))
+
+	var mdiaData []byte
+	bmfcommon.PushBox(&mdiaData, "mdhd", mdhdData)
+
+	var trakData []byte
+	bmfcommon.PushBox(&trakData, "edts", edtsData)
+	bmfcommon.PushBox(&trakData, "mdia", mdiaData)
+
+	var moovData []byte
+	bmfcommon.PushBox(&moovData, "mvhd", mvhdData)
+	bmfcommon.PushBox(&moovData, "trak", trakData)
+
+	var b []byte
+	bmfcommon.PushBox(&b, "moov", moovData)
+
+	sb := rifs.NewSeekableBufferWithBytes(b)
+
+	resource, err := bmfcommon.NewResource(sb, int64(len(b)))
+	log.PanicIf(err)
+
+	cb, found := resource.Index()[bmfcommon.IndexedBoxEntry{NamePhrase: "moov.trak", SequenceNumber: 0}]
+	if found != true {
+		t.Fatalf("trak not found.")
+	}
+
+	timeline, err := cb.(*TrakBox).Timeline()
+	log.PanicIf(err)
+
+	if timeline.MovieTimeScale() != 600 {
+		t.Fatalf("Movie time-scale not correct: (%d)", timeline.MovieTimeScale())
+	} else if timeline.MediaTimeScale() != 48000 {
+		t.Fatalf("Media time-scale not correct: (%d)", timeline.MediaTimeScale())
+	}
+
+	_, err = timeline.MediaTime(300)
+	if err != ErrTimeNotMapped {
+		t.Fatalf("Expected ErrTimeNotMapped in the empty edit: %v", err)
+	}
+
+	// Two seconds into the presentation is one second into the media edit.
+	mediaTime, err := timeline.MediaTime(1200)
+	log.PanicIf(err)
+
+	if mediaTime != 1024+48000 {
+		t.Fatalf("MediaTime() not correct: (%d)", mediaTime)
+	}
+
+	presentationTime, err := timeline.PresentationTime(1024)
+	log.PanicIf(err)
+
+	if presentationTime != 600 {
+		t.Fatalf("PresentationTime() not correct: (%d)", presentationTime)
+	}
+}
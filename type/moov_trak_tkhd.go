@@ -15,10 +15,9 @@ import (
 // TkhdBox is the "Track Header" box.
 type TkhdBox struct {
 	bmfcommon.Box
+	bmfcommon.FullBox
 	bmfcommon.Standard32TimeSupport
 
-	version        byte
-	flags          uint32
 	trackId        uint32
 	layer          uint16
 	alternateGroup uint16
@@ -28,16 +27,6 @@ type TkhdBox struct {
 	height         uint32
 }
 
-// Version returns the version of the box
-func (tb *TkhdBox) Version() byte {
-	return tb.version
-}
-
-// Flags returns the flags of the box. The first byte is the version.
-func (tb *TkhdBox) Flags() uint32 {
-	return tb.flags
-}
-
 // TrackId returns the track-ID.
 func (tb *TkhdBox) TrackId() uint32 {
 	return tb.trackId
@@ -77,7 +66,7 @@ func (tb *TkhdBox) Height() uint32 {
 func (tb *TkhdBox) InlineString() string {
 	return fmt.Sprintf(
 		"%s VER=(0x%02x) FLAGS=(0x%08x) TRACK-ID=(%d) LAYER=(%d) ALT-GROUP=(%d) VOLUME=[%s] MATRIX=(%d) W=(%d) H=(%d) %s",
-		tb.Box.InlineString(), tb.version, tb.flags, tb.trackId, tb.layer,
+		tb.Box.InlineString(), tb.Version(), tb.Flags(), tb.trackId, tb.layer,
 		tb.AlternateGroup(), tb.volume, len(tb.matrix), tb.width, tb.height,
 		tb.Standard32TimeSupport.InlineString())
 }
@@ -92,16 +81,13 @@ func (b *TkhdBox) parse(timeScale uint64) (err error) {
 	data, err := b.Data()
 	log.PanicIf(err)
 
-	b.version = data[0]
-	b.flags = bmfcommon.DefaultEndianness.Uint32(data[0:4])
-
 	s := bytes.NewBuffer(data[4:])
 
 	var creationEpoch uint64
 	var modificationEpoch uint64
 	var duration uint64
 
-	if b.version == 0 {
+	if b.Version() == 0 {
 		var creationEpoch32 uint32
 
 		err := binary.Read(s, bmfcommon.DefaultEndianness, &creationEpoch32)
@@ -130,7 +116,7 @@ func (b *TkhdBox) parse(timeScale uint64) (err error) {
 		log.PanicIf(err)
 
 		duration = uint64(duration32)
-	} else if b.version == 1 {
+	} else if b.Version() == 1 {
 		err = binary.Read(s, bmfcommon.DefaultEndianness, &creationEpoch)
 		log.PanicIf(err)
 
@@ -148,7 +134,7 @@ func (b *TkhdBox) parse(timeScale uint64) (err error) {
 		err = binary.Read(s, bmfcommon.DefaultEndianness, &duration)
 		log.PanicIf(err)
 	} else {
-		log.Panicf("tkhd: version (%d) not supported", b.version)
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(b, b.Version(), nil))
 	}
 
 	var reserved8 uint64
@@ -204,6 +190,97 @@ func (b *TkhdBox) parse(timeScale uint64) (err error) {
 	return nil
 }
 
+// ExportFields returns the parsed fields for structured exports.
+func (tb *TkhdBox) ExportFields() (fields map[string]interface{}, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	fields, err = tb.Standard32TimeSupport.ExportFields()
+	log.PanicIf(err)
+
+	matrix := make([]int32, len(tb.matrix)/4)
+	for i := range matrix {
+		matrix[i] = int32(bmfcommon.DefaultEndianness.Uint32(tb.matrix[i*4 : i*4+4]))
+	}
+
+	fields["track_id"] = tb.trackId
+	fields["layer"] = tb.layer
+	fields["alternate_group"] = tb.alternateGroup
+	fields["volume"] = float64(tb.volume) / 0x100
+	fields["matrix"] = matrix
+	fields["width"] = tb.width
+	fields["height"] = tb.height
+
+	return fields, nil
+}
+
+// EncodeData returns the payload of the box. Reserved fields and the
+// fractional parts of the width and height are carried over.
+func (tb *TkhdBox) EncodeData() (data []byte, err error) {
+	defer func() {
+		if errRaw := recover(); errRaw != nil {
+			err = log.Wrap(errRaw.(error))
+		}
+	}()
+
+	original, err := tb.Data()
+	log.PanicIf(err)
+
+	data = make([]byte, len(original))
+	copy(data, original)
+
+	bmfcommon.DefaultEndianness.PutUint32(data[0:4], uint32(tb.Version())<<24|tb.Flags()&0x00ffffff)
+
+	var duration uint64
+	if tb.HasDuration() == true {
+		duration = tb.ScaledDuration()
+	}
+
+	var offset int
+
+	if tb.Version() == 0 {
+		bmfcommon.DefaultEndianness.PutUint32(data[4:8], uint32(tb.CreationEpoch()))
+		bmfcommon.DefaultEndianness.PutUint32(data[8:12], uint32(tb.ModificationEpoch()))
+		bmfcommon.DefaultEndianness.PutUint32(data[12:16], tb.trackId)
+		bmfcommon.DefaultEndianness.PutUint32(data[20:24], uint32(duration))
+
+		offset = 24
+	} else if tb.Version() == 1 {
+		bmfcommon.DefaultEndianness.PutUint64(data[4:12], tb.CreationEpoch())
+		bmfcommon.DefaultEndianness.PutUint64(data[12:20], tb.ModificationEpoch())
+		bmfcommon.DefaultEndianness.PutUint32(data[20:24], tb.trackId)
+		bmfcommon.DefaultEndianness.PutUint64(data[28:36], duration)
+
+		offset = 36
+	} else {
+		log.Panic(bmfcommon.NewErrUnsupportedVersion(tb, tb.Version(), nil))
+	}
+
+	// Skip eight reserved bytes.
+	offset += 8
+
+	bmfcommon.DefaultEndianness.PutUint16(data[offset:offset+2], tb.layer)
+	bmfcommon.DefaultEndianness.PutUint16(data[offset+2:offset+4], tb.alternateGroup)
+	bmfcommon.DefaultEndianness.PutUint16(data[offset+4:offset+6], uint16(tb.volume))
+
+	// Skip two reserved bytes.
+	offset += 8
+
+	copy(data[offset:offset+36], tb.matrix)
+	offset += 36
+
+	widthRaw := bmfcommon.DefaultEndianness.Uint32(original[offset : offset+4])
+	bmfcommon.DefaultEndianness.PutUint32(data[offset:offset+4], tb.width<<16|widthRaw&0xffff)
+
+	heightRaw := bmfcommon.DefaultEndianness.Uint32(original[offset+4 : offset+8])
+	bmfcommon.DefaultEndianness.PutUint32(data[offset+4:offset+8], tb.height<<16|heightRaw&0xffff)
+
+	return data, nil
+}
+
 type tkhdBoxFactory struct {
 }
 
@@ -212,14 +289,22 @@ func (tkhdBoxFactory) Name() string {
 	return "tkhd"
 }
 
+// SupportedVersions returns the versions that the factory can parse.
+func (tkhdBoxFactory) SupportedVersions() []byte {
+	return []byte{0, 1}
+}
+
 // New returns a new value instance.
-func (tkhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
+func (bf tkhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSeriesOffset int, err error) {
 	defer func() {
 		if errRaw := recover(); errRaw != nil {
 			err = log.Wrap(errRaw.(error))
 		}
 	}()
 
+	fb, err := bmfcommon.ReadFullBox(box, bf)
+	log.PanicIf(err)
+
 	fbi := box.Index()
 
 	mvhdCommonBox, found := fbi[bmfcommon.IndexedBoxEntry{"moov.mvhd", 0}]
@@ -231,7 +316,8 @@ func (tkhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 	timeScale := mvhd.TimeScale()
 
 	tkhdBox := &TkhdBox{
-		Box: box,
+		Box:     box,
+		FullBox: fb,
 	}
 
 	err = tkhdBox.parse(timeScale)
@@ -241,8 +327,9 @@ func (tkhdBoxFactory) New(box bmfcommon.Box) (cb bmfcommon.CommonBox, childBoxSe
 }
 
 var (
-	_ bmfcommon.BoxFactory = tkhdBoxFactory{}
-	_ bmfcommon.CommonBox  = &TkhdBox{}
+	_ bmfcommon.FullBoxFactory = tkhdBoxFactory{}
+	_ bmfcommon.CommonBox      = &TkhdBox{}
+	_ bmfcommon.FieldExporter  = &TkhdBox{}
 )
 
 func init() {
@@ -2,6 +2,7 @@ package bmftype
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
 	"github.com/dsoprea/go-logging"
@@ -12,7 +13,7 @@ import (
 
 func TestTkhdBox_Version(t *testing.T) {
 	tb := TkhdBox{
-		version: 0x11,
+		FullBox: bmfcommon.NewFullBox(0x11, 0),
 	}
 
 	if tb.Version() != 0x11 {
@@ -22,10 +23,10 @@ func TestTkhdBox_Version(t *testing.T) {
 
 func TestTkhdBox_Flags(t *testing.T) {
 	tb := TkhdBox{
-		flags: 0x11223344,
+		FullBox: bmfcommon.NewFullBox(0, 0x223344),
 	}
 
-	if tb.Flags() != 0x11223344 {
+	if tb.Flags() != 0x223344 {
 		t.Fatalf("Flags() not correct.")
 	}
 }
@@ -115,8 +116,7 @@ func TestTkhdBox_InlineString(t *testing.T) {
 	tb := TkhdBox{
 		Standard32TimeSupport: sts,
 
-		version:        0x11,
-		flags:          0x11223344,
+		FullBox:        bmfcommon.NewFullBox(0x11, 0x223344),
 		trackId:        0x55,
 		layer:          0x66,
 		alternateGroup: 0x77,
@@ -126,11 +126,50 @@ func TestTkhdBox_InlineString(t *testing.T) {
 		height:         0x109,
 	}
 
-	if tb.InlineString() != "NAME=[] PARENT=[ROOT] START=(0x0000000000000000) SIZE=(0) VER=(0x11) FLAGS=(0x11223344) TRACK-ID=(85) LAYER=(102) ALT-GROUP=(119) VOLUME=[OFF] MATRIX=(3) W=(153) H=(265) DUR-S=[60.00]" {
+	if tb.InlineString() != "NAME=[] PARENT=[ROOT] START=(0x0000000000000000) SIZE=(0) VER=(0x11) FLAGS=(0x00223344) TRACK-ID=(85) LAYER=(102) ALT-GROUP=(119) VOLUME=[OFF] MATRIX=(3) W=(153) H=(265) DUR-S=[60.00]" {
 		t.Fatalf("InlineString() not correct: [%s]", tb.InlineString())
 	}
 }
 
+func TestTkhdBox_ExportFields(t *testing.T) {
+	var matrix []byte
+	bmfcommon.PushBytes(&matrix, uint32(0x00010000))
+	bmfcommon.PushBytes(&matrix, uint32(0xffffffff))
+	bmfcommon.PushBytes(&matrix, uint32(0x40000000))
+
+	tb := TkhdBox{
+		Standard32TimeSupport: bmfcommon.NewStandard32TimeSupport(1, 2, 3, 4),
+		trackId:               5,
+		layer:                 6,
+		alternateGroup:        7,
+		volume:                0x0180,
+		matrix:                matrix,
+		width:                 1920,
+		height:                800,
+	}
+
+	fields, err := tb.ExportFields()
+	log.PanicIf(err)
+
+	expected := map[string]interface{}{
+		"creation_epoch":     uint64(1),
+		"modification_epoch": uint64(2),
+		"scaled_duration":    uint64(3),
+		"time_scale":         uint64(4),
+		"track_id":           uint32(5),
+		"layer":              uint16(6),
+		"alternate_group":    uint16(7),
+		"volume":             1.5,
+		"matrix":             []int32{0x00010000, -1, 0x40000000},
+		"width":              uint32(1920),
+		"height":             uint32(800),
+	}
+
+	if reflect.DeepEqual(fields, expected) != true {
+		t.Fatalf("ExportFields() not correct: %v", fields)
+	}
+}
+
 func TestTkhdBoxFactory_Name(t *testing.T) {
 	tbf := tkhdBoxFactory{}
 	if tbf.Name() != "tkhd" {
@@ -386,7 +425,7 @@ func TestTkhdBoxFactory_New_Version1(t *testing.T) {
 		t.Fatalf("Version() not correct: (0x%02x)", tb.Version())
 	}
 
-	if tb.Flags() != 0x01223344 {
+	if tb.Flags() != 0x223344 {
 		t.Fatalf("Flags() not correct.")
 	}
 
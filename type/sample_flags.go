@@ -0,0 +1,64 @@
+package bmftype
+
+import (
+	"fmt"
+)
+
+// SampleFlags is the packed sample-flags field used by the TREX, TFHD, and TRUN
+// boxes.
+type SampleFlags uint32
+
+// IsLeading returns the two-bit "is_leading" value.
+func (sf SampleFlags) IsLeading() uint8 {
+	return uint8(sf>>26) & 0x3
+}
+
+// SampleDependsOn returns the two-bit "sample_depends_on" value.
+func (sf SampleFlags) SampleDependsOn() uint8 {
+	return uint8(sf>>24) & 0x3
+}
+
+// SampleIsDependedOn returns the two-bit "sample_is_depended_on" value.
+func (sf SampleFlags) SampleIsDependedOn() uint8 {
+	return uint8(sf>>22) & 0x3
+}
+
+// SampleHasRedundancy returns the two-bit "sample_has_redundancy" value.
+func (sf SampleFlags) SampleHasRedundancy() uint8 {
+	return uint8(sf>>20) & 0x3
+}
+
+// SamplePaddingValue returns the three-bit "sample_padding_value" value.
+func (sf SampleFlags) SamplePaddingValue() uint8 {
+	return uint8(sf>>17) & 0x7
+}
+
+// IsNonSyncSample returns the "sample_is_non_sync_sample" bit.
+func (sf SampleFlags) IsNonSyncSample() bool {
+	return sf&0x00010000 != 0
+}
+
+// DegradationPriority returns the "sample_degradation_priority" value.
+func (sf SampleFlags) DegradationPriority() uint16 {
+	return uint16(sf)
+}
+
+// String returns a descriptive string.
+func (sf SampleFlags) String() string {
+	return fmt.Sprintf(
+		"SampleFlags<LEADING=(%d) DEPENDS-ON=(%d) DEPENDED-ON=(%d) REDUNDANCY=(%d) NON-SYNC=[%v]>",
+		sf.IsLeading(), sf.SampleDependsOn(), sf.SampleIsDependedOn(), sf.SampleHasRedundancy(), sf.IsNonSyncSample())
+}
+
+// exportFields returns the decoded values for structured exports.
+func (sf SampleFlags) exportFields() map[string]interface{} {
+	return map[string]interface{}{
+		"is_leading":            sf.IsLeading(),
+		"sample_depends_on":     sf.SampleDependsOn(),
+		"sample_is_depended_on": sf.SampleIsDependedOn(),
+		"sample_has_redundancy": sf.SampleHasRedundancy(),
+		"sample_padding_value":  sf.SamplePaddingValue(),
+		"is_non_sync_sample":    sf.IsNonSyncSample(),
+		"degradation_priority":  sf.DegradationPriority(),
+	}
+}
@@ -0,0 +1,35 @@
+package bmftype
+
+import (
+	"testing"
+)
+
+func TestSampleFlags(t *testing.T) {
+	// is_leading=(1), depends_on=(2), is_depended_on=(1), has_redundancy=(2),
+	// padding=(5), non-sync=(1), degradation-priority=(0x1234)
+	sf := SampleFlags(0x04000000 | 0x02000000 | 0x00400000 | 0x00200000 | 0x000a0000 | 0x00010000 | 0x1234)
+
+	if sf.IsLeading() != 1 {
+		t.Fatalf("IsLeading() not correct.")
+	} else if sf.SampleDependsOn() != 2 {
+		t.Fatalf("SampleDependsOn() not correct.")
+	} else if sf.SampleIsDependedOn() != 1 {
+		t.Fatalf("SampleIsDependedOn() not correct.")
+	} else if sf.SampleHasRedundancy() != 2 {
+		t.Fatalf("SampleHasRedundancy() not correct.")
+	} else if sf.SamplePaddingValue() != 5 {
+		t.Fatalf("SamplePaddingValue() not correct.")
+	} else if sf.IsNonSyncSample() != true {
+		t.Fatalf("IsNonSyncSample() not correct.")
+	} else if sf.DegradationPriority() != 0x1234 {
+		t.Fatalf("DegradationPriority() not correct.")
+	}
+}
+
+func TestSampleFlags_String(t *testing.T) {
+	sf := SampleFlags(0x02000000)
+
+	if sf.String() != "SampleFlags<LEADING=(0) DEPENDS-ON=(2) DEPENDED-ON=(0) REDUNDANCY=(0) NON-SYNC=[false]>" {
+		t.Fatalf("String() not correct: [%s]", sf.String())
+	}
+}